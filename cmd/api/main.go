@@ -40,7 +40,7 @@ func main() {
 	redisClient := redis.NewRedisClient(cfg)
 	defer redisClient.Close()
 	if err := redisClient.Ping(context.Background()).Err(); err != nil {
-		apiLogger.Fatal("Redis ping: %v", err)
+		apiLogger.Fatalf("Redis ping: %v", err)
 	}
 	apiLogger.Info("Redis connected")
 
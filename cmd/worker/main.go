@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/worker"
+	"github.com/mrizkisaputra/expenses-api/pkg/db/postgres"
+	"github.com/mrizkisaputra/expenses-api/pkg/db/redis"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"log"
+	"os"
+)
+
+func main() {
+	// -----------------------------------------------------------------------------------------------------------
+	// initializing app config
+	cfg, err := config.NewAppConfig(os.Getenv("config"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// -----------------------------------------------------------------------------------------------------------
+	// initializing Logger
+	workerLogger := logger.NewLogrusLogger(cfg)
+
+	// -----------------------------------------------------------------------------------------------------------
+	// initializing postgreSQL connection
+	psqlDB, err := postgres.NewPostgresConn(cfg)
+	if err != nil {
+		workerLogger.Fatalf("Postgresql initialize: %v", err)
+	}
+	workerLogger.Info("PostgreSQL connected")
+
+	// -----------------------------------------------------------------------------------------------------------
+	// initializing redis client
+	redisClient := redis.NewRedisClient(cfg)
+	defer redisClient.Close()
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		workerLogger.Fatalf("Redis ping: %v", err)
+	}
+	workerLogger.Info("Redis connected")
+
+	// -----------------------------------------------------------------------------------------------------------
+	// create a new instance worker
+	w := worker.NewWorker(&worker.WorkerConfig{
+		Cfg:         cfg,
+		Logger:      workerLogger,
+		Db:          psqlDB,
+		RedisClient: redisClient,
+	})
+
+	if err := w.Run(); err != nil {
+		workerLogger.Fatal(err)
+	}
+}
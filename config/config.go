@@ -8,11 +8,19 @@ import (
 
 // App config
 type Config struct {
-	Server   ServerConfig
-	Postgres PostgresConfig
-	Logger   LoggerConfig
-	Redis    RedisConfig
-	AWS      AwsConfig
+	Server       ServerConfig
+	Postgres     PostgresConfig
+	Logger       LoggerConfig
+	Redis        RedisConfig
+	AWS          AwsConfig
+	Currency     CurrencyConfig
+	Idempotency  IdempotencyConfig
+	Concurrency  ConcurrencyConfig
+	Notification NotificationConfig
+	Events       EventsConfig
+	Webhook      WebhookConfig
+	Outbox       OutboxConfig
+	Worker       WorkerConfig
 }
 
 // Server config
@@ -24,6 +32,7 @@ type ServerConfig struct {
 	Mode         string
 	SSL          bool
 	JWTSecretKey string
+	AdminEmails  []string
 }
 
 // Postgresql config
@@ -51,6 +60,71 @@ type AwsConfig struct {
 	UseSSL         bool
 }
 
+// Currency config
+type CurrencyConfig struct {
+	RatesFile string
+}
+
+// Idempotency config, TTL is in seconds
+type IdempotencyConfig struct {
+	TTL time.Duration
+}
+
+// Concurrency config, RequireIfMatch rejects updates and deletes sent without If-Match
+type ConcurrencyConfig struct {
+	RequireIfMatch bool
+}
+
+// Notification config, the email channel is enabled with SmtpHost and the webhook
+// channel with WebhookURL, Timeout of a webhook delivery is in seconds
+type NotificationConfig struct {
+	SmtpHost     string
+	SmtpPort     int
+	SmtpUsername string
+	SmtpPassword string
+	From         string
+	WebhookURL   string
+	Timeout      time.Duration
+}
+
+// Events config, Heartbeat is the seconds between two heartbeats of an event stream
+// and History the number of past events kept per user to resume a stream
+type EventsConfig struct {
+	Heartbeat time.Duration
+	History   int64
+}
+
+// Webhook config of the outgoing webhooks, the due deliveries are sent every Interval seconds,
+// a delivery may take Timeout seconds and a failed one is retried MaxAttempts times at most,
+// the first retry after Backoff seconds and every next one twice later
+type WebhookConfig struct {
+	Interval    time.Duration
+	Timeout     time.Duration
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// Outbox config of the dispatcher of the domain events, the pending events are dispatched every
+// Interval seconds and an event failing to dispatch is retried after Backoff seconds, doubled at every retry.
+// an event is dead after MaxAttempts failed dispatches
+type OutboxConfig struct {
+	Interval    time.Duration
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// Worker config of the background job worker, every job type runs Concurrency jobs at once and the queue
+// is polled every PollInterval seconds. a failed job is retried MaxAttempts times at most, the first retry
+// after Backoff seconds and every next one twice later. the outbox events and webhook deliveries
+// done for RetentionDays days are purged every day
+type WorkerConfig struct {
+	Concurrency   int
+	PollInterval  time.Duration
+	MaxAttempts   int
+	Backoff       time.Duration
+	RetentionDays int
+}
+
 type RedisConfig struct {
 	Addr         string
 	DB           int
@@ -0,0 +1,27 @@
+package account
+
+import "github.com/gin-gonic/gin"
+
+// AccountController defines methods the routes expects
+// any controllers it interacts with to implement
+type AccountController interface {
+	CreateAccount() gin.HandlerFunc
+
+	UpdateAccount() gin.HandlerFunc
+
+	DeleteAccount() gin.HandlerFunc
+
+	GetAccountById() gin.HandlerFunc
+
+	GetAllAccount() gin.HandlerFunc
+
+	CreateTransfer() gin.HandlerFunc
+
+	GetAllTransfer() gin.HandlerFunc
+
+	DeleteTransfer() gin.HandlerFunc
+
+	GetBalances() gin.HandlerFunc
+
+	GetAccountBalance() gin.HandlerFunc
+}
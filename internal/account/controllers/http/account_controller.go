@@ -0,0 +1,378 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/account"
+	"github.com/mrizkisaputra/expenses-api/internal/account/model"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/sirupsen/logrus"
+	"math"
+	"net/http"
+)
+
+type ControllerConfig struct {
+	AccountService account.AccountService
+	Logger         *logrus.Logger
+}
+
+// accountController acts as a struct for injecting an implementation of AccountController interface
+// for use in controller methods
+type accountController struct {
+	accountService account.AccountService
+	logger         *logrus.Logger
+}
+
+// NewAccountController is a factory function
+// initializing a accountController with its service layer dependencies
+func NewAccountController(config *ControllerConfig) account.AccountController {
+	return &accountController{
+		accountService: config.AccountService,
+		logger:         config.Logger,
+	}
+}
+
+func (ac accountController) CreateAccount() gin.HandlerFunc {
+	type CreateAccountRequest struct {
+		Name           string        `json:"name" validate:"required,max=100"`
+		Type           string        `json:"type" validate:"omitempty,oneof=cash bank credit_card e_wallet other"`
+		Currency       string        `json:"currency" validate:"omitempty,iso4217"`
+		OpeningBalance *money.Amount `json:"opening_balance"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(CreateAccountRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Account{
+			UserId:         auth.Id,
+			Name:           request.Name,
+			Type:           request.Type,
+			Currency:       request.Currency,
+			OpeningBalance: request.OpeningBalance,
+		}
+		response, err := ac.accountService.Create(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, &expenseModel.ApiResponse{
+			Status:  http.StatusCreated,
+			Message: "Created",
+			Data:    response,
+		})
+	}
+}
+
+func (ac accountController) UpdateAccount() gin.HandlerFunc {
+	type UpdateAccountRequest struct {
+		Id             string        `json:"-" validate:"required,uuid"`
+		Name           string        `json:"name" validate:"omitempty,max=100"`
+		Type           string        `json:"type" validate:"omitempty,oneof=cash bank credit_card e_wallet other"`
+		OpeningBalance *money.Amount `json:"opening_balance"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(UpdateAccountRequest)
+		request.Id = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Account{
+			Id:             uuid.MustParse(request.Id),
+			UserId:         auth.Id,
+			Name:           request.Name,
+			Type:           request.Type,
+			OpeningBalance: request.OpeningBalance,
+		}
+		response, err := ac.accountService.Update(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, &expenseModel.ApiResponse{
+			Status:  http.StatusAccepted,
+			Message: "Accepted",
+			Data:    response,
+		})
+	}
+}
+
+func (ac accountController) DeleteAccount() gin.HandlerFunc {
+	type DeleteAccountRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &DeleteAccountRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if err := ac.accountService.Delete(ctx, request.Id, auth.Id.String()); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusNoContent, &expenseModel.ApiResponse{
+			Status:  http.StatusNoContent,
+			Message: "No Content",
+		})
+	}
+}
+
+func (ac accountController) GetAccountById() gin.HandlerFunc {
+	type GetAccountRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &GetAccountRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := ac.accountService.GetById(ctx, request.Id, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (ac accountController) GetAllAccount() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		accounts, err := ac.accountService.GetAll(ctx, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       accounts,
+			TotalItems: int64(len(accounts)),
+		})
+	}
+}
+
+func (ac accountController) CreateTransfer() gin.HandlerFunc {
+	type CreateTransferRequest struct {
+		FromAccountId string        `json:"from_account_id" validate:"required,uuid"`
+		ToAccountId   string        `json:"to_account_id" validate:"required,uuid"`
+		Amount        *money.Amount `json:"amount" validate:"required,gt=0"`
+		ToAmount      *money.Amount `json:"to_amount" validate:"omitempty,gt=0"`
+		Note          string        `json:"note" validate:"omitempty,max=200"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(CreateTransferRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Transfer{
+			UserId:        auth.Id,
+			FromAccountId: uuid.MustParse(request.FromAccountId),
+			ToAccountId:   uuid.MustParse(request.ToAccountId),
+			Amount:        request.Amount,
+			ToAmount:      request.ToAmount,
+			Note:          request.Note,
+		}
+		response, err := ac.accountService.Transfer(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, &expenseModel.ApiResponse{
+			Status:  http.StatusCreated,
+			Message: "Created",
+			Data:    response,
+		})
+	}
+}
+
+func (ac accountController) GetAllTransfer() gin.HandlerFunc {
+	type GetAllTransferRequest struct {
+		Page  int `form:"page" validate:"omitempty,numeric,min=1"`
+		Limit int `form:"limit" validate:"omitempty,numeric,min=10"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(GetAllTransferRequest)
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if request.Page == 0 {
+			request.Page = 1
+		}
+		if request.Limit == 0 {
+			request.Limit = 10
+		}
+
+		transfers, total, err := ac.accountService.GetTransfers(ctx, auth.Id.String(), request.Page, request.Limit)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       transfers,
+			Page:       request.Page,
+			Limit:      request.Limit,
+			TotalItems: total,
+			TotalPages: int64(math.Ceil(float64(total) / float64(request.Limit))),
+		})
+	}
+}
+
+func (ac accountController) DeleteTransfer() gin.HandlerFunc {
+	type DeleteTransferRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &DeleteTransferRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if err := ac.accountService.DeleteTransfer(ctx, request.Id, auth.Id.String()); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusNoContent, &expenseModel.ApiResponse{
+			Status:  http.StatusNoContent,
+			Message: "No Content",
+		})
+	}
+}
+
+func (ac accountController) GetBalances() gin.HandlerFunc {
+	type GetBalancesRequest struct {
+		AsOf string `form:"as_of" validate:"omitempty,datetime=2006-01-02"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(GetBalancesRequest)
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := ac.accountService.GetBalances(ctx, auth.Id.String(), request.AsOf)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (ac accountController) GetAccountBalance() gin.HandlerFunc {
+	type GetAccountBalanceRequest struct {
+		Id   string `form:"-" validate:"required,uuid"`
+		AsOf string `form:"as_of" validate:"omitempty,datetime=2006-01-02"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(GetAccountBalanceRequest)
+		request.Id = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := ac.accountService.GetBalance(ctx, request.Id, auth.Id.String(), request.AsOf)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
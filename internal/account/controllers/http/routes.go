@@ -0,0 +1,21 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/account"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+)
+
+func MapAccountRoutes(accountGroup *gin.RouterGroup, controller account.AccountController, mw *middleware.MiddlewareManager) {
+	accountGroup.Use(mw.AuthJwtMiddleware())
+	accountGroup.POST("/", mw.IdempotencyMiddleware(), controller.CreateAccount())
+	accountGroup.GET("/", controller.GetAllAccount())
+	accountGroup.GET("/balances", controller.GetBalances())
+	accountGroup.POST("/transfers", mw.IdempotencyMiddleware(), controller.CreateTransfer())
+	accountGroup.GET("/transfers", controller.GetAllTransfer())
+	accountGroup.DELETE("/transfers/:id", mw.IdempotencyMiddleware(), controller.DeleteTransfer())
+	accountGroup.GET("/:id", controller.GetAccountById())
+	accountGroup.GET("/:id/balance", controller.GetAccountBalance())
+	accountGroup.PATCH("/:id", mw.IdempotencyMiddleware(), controller.UpdateAccount())
+	accountGroup.DELETE("/:id", mw.IdempotencyMiddleware(), controller.DeleteAccount())
+}
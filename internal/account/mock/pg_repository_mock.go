@@ -0,0 +1,90 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/account/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPostgresRepository is a mock type for account.PostgresRepository
+type MockPostgresRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostgresRepository) Create(ctx context.Context, account *model.Account) error {
+	args := m.Called(ctx, account)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Account, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Account), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Account, error) {
+	args := m.Called(ctx, name, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Account), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindAllByUserId(ctx context.Context, userId string) ([]model.Account, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Account), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) Update(ctx context.Context, account *model.Account) error {
+	args := m.Called(ctx, account)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) Remove(ctx context.Context, account *model.Account) error {
+	args := m.Called(ctx, account)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) CountTransfersByAccountId(ctx context.Context, accountId string) (int64, error) {
+	args := m.Called(ctx, accountId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPostgresRepository) CreateTransfer(ctx context.Context, transfer *model.Transfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindTransferByIdAndUserId(ctx context.Context, id, userId string) (*model.Transfer, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Transfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindTransfersByUserId(ctx context.Context, userId string, page, limit int) ([]model.Transfer, int64, error) {
+	args := m.Called(ctx, userId, page, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Transfer), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockPostgresRepository) RemoveTransfer(ctx context.Context, transfer *model.Transfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindBalances(ctx context.Context, userId, accountId string, asOf int64) ([]model.AccountBalance, error) {
+	args := m.Called(ctx, userId, accountId, asOf)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.AccountBalance), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
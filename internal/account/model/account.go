@@ -0,0 +1,110 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"strings"
+)
+
+// types of account
+const (
+	TypeCash       = "cash"
+	TypeBank       = "bank"
+	TypeCreditCard = "credit_card"
+	TypeEWallet    = "e_wallet"
+	TypeOther      = "other"
+)
+
+// Account mapping table 'accounts'.
+// an account (wallet) the user spends from and receives on, like a debit card, cash or an e-wallet.
+// the currency is fixed once created, the opening balance is the balance before any transaction
+type Account struct {
+	Id             uuid.UUID     `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	UserId         uuid.UUID     `json:"user_id" gorm:"column:id_user"`
+	Name           string        `json:"name" gorm:"column:name"`
+	Type           string        `json:"type" gorm:"column:type"`
+	Currency       string        `json:"currency" gorm:"column:currency;<-:create"` // allow read and create
+	OpeningBalance *money.Amount `json:"opening_balance" gorm:"column:opening_balance"`
+	CreatedAt      int64         `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt      int64         `json:"updated_at" gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (account *Account) TableName() string {
+	return "accounts"
+}
+
+func (account *Account) PrepareCreate() {
+	account.Name = strings.Join(strings.Fields(account.Name), " ")
+	account.Currency = strings.ToUpper(strings.TrimSpace(account.Currency))
+	if account.Type == "" {
+		account.Type = TypeOther
+	}
+	if account.OpeningBalance == nil {
+		account.OpeningBalance = new(money.Amount)
+	}
+}
+
+func (account *Account) PrepareUpdate(oldAccount *Account) {
+	if account.Name != "" {
+		oldAccount.Name = strings.Join(strings.Fields(account.Name), " ")
+	}
+
+	if account.Type != "" {
+		oldAccount.Type = account.Type
+	}
+
+	if account.OpeningBalance != nil {
+		oldAccount.OpeningBalance = account.OpeningBalance
+	}
+}
+
+// Transfer mapping table 'transfers'.
+// money moved between two accounts of the user, it is neither an expense nor an income.
+// Amount leaves the source account in its currency and ToAmount arrives on the destination
+// account in its currency, both are equal between accounts of the same currency
+type Transfer struct {
+	Id            uuid.UUID     `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	UserId        uuid.UUID     `json:"user_id" gorm:"column:id_user"`
+	FromAccountId uuid.UUID     `json:"from_account_id" gorm:"column:id_from_account"`
+	ToAccountId   uuid.UUID     `json:"to_account_id" gorm:"column:id_to_account"`
+	Amount        *money.Amount `json:"amount" gorm:"column:amount"`
+	ToAmount      *money.Amount `json:"to_amount" gorm:"column:to_amount"`
+	Note          string        `json:"note" gorm:"column:note"`
+	CreatedAt     int64         `json:"created_at" gorm:"column:created_at;autoCreateTime;<-:create"` // allow read and create
+	UpdatedAt     int64         `json:"updated_at" gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (transfer *Transfer) TableName() string {
+	return "transfers"
+}
+
+// AccountBalance is the balance of an account at a point in time with the movements it is made of,
+// Balance = OpeningBalance + Incomes - Expenses + TransfersIn - TransfersOut
+type AccountBalance struct {
+	AccountId      uuid.UUID    `json:"account_id" gorm:"column:id_account"`
+	Name           string       `json:"name" gorm:"column:name"`
+	Type           string       `json:"type" gorm:"column:type"`
+	Currency       string       `json:"currency" gorm:"column:currency"`
+	OpeningBalance money.Amount `json:"opening_balance" gorm:"column:opening_balance"`
+	Incomes        money.Amount `json:"incomes" gorm:"column:incomes"`
+	Expenses       money.Amount `json:"expenses" gorm:"column:expenses"`
+	TransfersIn    money.Amount `json:"transfers_in" gorm:"column:transfers_in"`
+	TransfersOut   money.Amount `json:"transfers_out" gorm:"column:transfers_out"`
+	Balance        money.Amount `json:"balance" gorm:"-"`
+}
+
+// Compute sets the balance from its movements
+func (balance *AccountBalance) Compute() {
+	balance.Balance = balance.OpeningBalance + balance.Incomes - balance.Expenses + balance.TransfersIn - balance.TransfersOut
+}
+
+// Balances are the balances of every account of the user at the end of the day AsOf (UTC),
+// Total is their sum in the base currency, balances without exchange rate are left out of it
+// and their currencies listed in MissingRates
+type Balances struct {
+	AsOf         string           `json:"as_of"`
+	Accounts     []AccountBalance `json:"accounts"`
+	Currency     string           `json:"currency"`
+	Total        money.Amount     `json:"total"`
+	MissingRates []string         `json:"missing_rates,omitempty"`
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/account"
+	"github.com/mrizkisaputra/expenses-api/internal/account/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// balanceColumns sums the movements of an account up to a point in time, the four
+// placeholders are the same unix time
+const balanceColumns = `accounts.id AS id_account, accounts.name, accounts.type, accounts.currency, accounts.opening_balance,
+(SELECT COALESCE(SUM(incomes.amount), 0) FROM incomes
+ WHERE incomes.id_account = accounts.id AND incomes.deleted_at IS NULL AND incomes.created_at <= ?) AS incomes,
+(SELECT COALESCE(SUM(expenses.amount), 0) FROM expenses
+ WHERE expenses.id_account = accounts.id AND expenses.deleted_at IS NULL AND expenses.created_at <= ?) AS expenses,
+(SELECT COALESCE(SUM(transfers.to_amount), 0) FROM transfers
+ WHERE transfers.id_to_account = accounts.id AND transfers.created_at <= ?) AS transfers_in,
+(SELECT COALESCE(SUM(transfers.amount), 0) FROM transfers
+ WHERE transfers.id_from_account = accounts.id AND transfers.created_at <= ?) AS transfers_out`
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountPgRepository(db *gorm.DB) account.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (p *postgresRepository) Create(ctx context.Context, account *model.Account) error {
+	/**
+	SQL: INSERT INTO "accounts" ("id_user","name","type","currency","opening_balance","created_at","updated_at")
+	VALUES (?,?,?,?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(account).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Create")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Account, error) {
+	/**
+	SQL: SELECT * FROM "accounts" WHERE id = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	account := new(model.Account)
+	if err := DB.Where("id = ? AND id_user = ?", id, userId).Take(account).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByIdAndUserId")
+	}
+	return account, nil
+}
+
+func (p *postgresRepository) FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Account, error) {
+	/**
+	SQL: SELECT * FROM "accounts" WHERE name = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	account := new(model.Account)
+	if err := DB.Where("name = ? AND id_user = ?", name, userId).Take(account).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByNameAndUserId")
+	}
+	return account, nil
+}
+
+func (p *postgresRepository) FindAllByUserId(ctx context.Context, userId string) ([]model.Account, error) {
+	/**
+	SQL: SELECT * FROM "accounts" WHERE id_user = ? ORDER BY name
+	*/
+	DB := p.db.WithContext(ctx)
+	var accounts []model.Account
+	if err := DB.Where("id_user = ?", userId).Order("name").Find(&accounts).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindAllByUserId")
+	}
+	return accounts, nil
+}
+
+func (p *postgresRepository) Update(ctx context.Context, account *model.Account) error {
+	/**
+	SQL: UPDATE "accounts" SET "id_user"=?,"name"=?,"type"=?,"opening_balance"=?,"updated_at"=?
+	WHERE (id = ? AND id_user = ?) AND "id" = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", account.Id, account.UserId).Updates(account).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Update")
+	}
+	return nil
+}
+
+func (p *postgresRepository) Remove(ctx context.Context, account *model.Account) error {
+	/**
+	SQL: DELETE FROM "accounts" WHERE id = ? AND id_user = ?
+	the expenses and incomes of the account are kept without account (ON DELETE SET NULL)
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", account.Id, account.UserId).Delete(&model.Account{}).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Remove")
+	}
+	return nil
+}
+
+func (p *postgresRepository) CountTransfersByAccountId(ctx context.Context, accountId string) (int64, error) {
+	/**
+	SQL: SELECT count(*) FROM "transfers" WHERE id_from_account = ? OR id_to_account = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	var total int64
+	if err := DB.Model(&model.Transfer{}).
+		Where("id_from_account = ? OR id_to_account = ?", accountId, accountId).
+		Count(&total).Error; err != nil {
+		return 0, errors.Wrap(err, "postgresRepository.CountTransfersByAccountId")
+	}
+	return total, nil
+}
+
+func (p *postgresRepository) CreateTransfer(ctx context.Context, transfer *model.Transfer) error {
+	/**
+	SQL: INSERT INTO "transfers" ("id_user","id_from_account","id_to_account","amount","to_amount","note","created_at","updated_at")
+	VALUES (?,?,?,?,?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(transfer).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.CreateTransfer")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindTransferByIdAndUserId(ctx context.Context, id, userId string) (*model.Transfer, error) {
+	/**
+	SQL: SELECT * FROM "transfers" WHERE id = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	transfer := new(model.Transfer)
+	if err := DB.Where("id = ? AND id_user = ?", id, userId).Take(transfer).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindTransferByIdAndUserId")
+	}
+	return transfer, nil
+}
+
+func (p *postgresRepository) FindTransfersByUserId(ctx context.Context, userId string, page, limit int) ([]model.Transfer, int64, error) {
+	DB := p.db.WithContext(ctx)
+
+	/**
+	SQL: SELECT * FROM "transfers" WHERE id_user = ? ORDER BY created_at DESC, id LIMIT ? OFFSET ?
+	*/
+	var transfers []model.Transfer
+	if err := DB.Where("id_user = ?", userId).
+		Order("created_at DESC, id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&transfers).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindTransfersByUserId.Find")
+	}
+
+	/**
+	SQL: SELECT count(*) FROM "transfers" WHERE id_user = ?
+	*/
+	var total int64
+	if err := DB.Model(&model.Transfer{}).
+		Where("id_user = ?", userId).
+		Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindTransfersByUserId.Count")
+	}
+
+	return transfers, total, nil
+}
+
+func (p *postgresRepository) RemoveTransfer(ctx context.Context, transfer *model.Transfer) error {
+	/**
+	SQL: DELETE FROM "transfers" WHERE id = ? AND id_user = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", transfer.Id, transfer.UserId).Delete(&model.Transfer{}).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.RemoveTransfer")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindBalances(ctx context.Context, userId, accountId string, asOf int64) ([]model.AccountBalance, error) {
+	/**
+	SQL: SELECT accounts.id AS id_account, ..., (SELECT COALESCE(SUM(incomes.amount), 0) FROM incomes WHERE ...) AS incomes, ...
+	FROM "accounts" WHERE accounts.id_user = ? [AND accounts.id = ?] ORDER BY accounts.name
+	*/
+	DB := p.db.WithContext(ctx)
+	query := DB.Table("accounts").
+		Select(balanceColumns, asOf, asOf, asOf, asOf).
+		Where("accounts.id_user = ?", userId)
+	if accountId != "" {
+		query = query.Where("accounts.id = ?", accountId)
+	}
+
+	var balances []model.AccountBalance
+	if err := query.Order("accounts.name").Scan(&balances).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindBalances")
+	}
+	return balances, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/account"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+)
+
+var (
+	repository account.PostgresRepository
+	mock       sqlmock.Sqlmock
+)
+
+// Test Before and After
+func TestMain(m *testing.M) {
+	// initialize mock sql db
+	mockDb, mocking, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("Failed to open mock sql db, got error %v", err)
+	}
+	defer mockDb.Close()
+	mock = mocking
+
+	// initialize db connection
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDb}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database, got error %v", err)
+	}
+
+	// initialize repository
+	repository = NewAccountPgRepository(db)
+
+	os.Exit(m.Run())
+}
+
+func TestAccountPostgresRepository_FindBalances(t *testing.T) {
+	userId := uuid.New()
+	accountId := uuid.New()
+	asOf := int64(1711929599)
+
+	// define expect sql
+	rows := sqlmock.NewRows([]string{"id_account", "name", "type", "currency", "opening_balance", "incomes", "expenses", "transfers_in", "transfers_out"}).
+		AddRow(accountId, "bank", "bank", "IDR", 1000000, 500000, 200000, 0, 100000)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT accounts.id AS id_account`)+`(?s).*`+
+		regexp.QuoteMeta(`FROM "accounts" WHERE accounts.id_user = $5 AND accounts.id = $6 ORDER BY accounts.name`)).
+		WithArgs(asOf, asOf, asOf, asOf, userId.String(), accountId.String()).
+		WillReturnRows(rows)
+
+	balances, err := repository.FindBalances(context.Background(), userId.String(), accountId.String(), asOf)
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	require.Equal(t, accountId, balances[0].AccountId)
+	require.Equal(t, money.Amount(200000), balances[0].Expenses)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccountPostgresRepository_CountTransfersByAccountId(t *testing.T) {
+	accountId := uuid.New()
+
+	// define expect sql
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "transfers" WHERE id_from_account = $1 OR id_to_account = $2`)).
+		WithArgs(accountId.String(), accountId.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	total, err := repository.CountTransfersByAccountId(context.Background(), accountId.String())
+	require.NoError(t, err)
+	require.Equal(t, int64(3), total)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,36 @@
+package account
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/account/model"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	Create(ctx context.Context, account *model.Account) error
+
+	FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Account, error)
+
+	FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Account, error)
+
+	FindAllByUserId(ctx context.Context, userId string) ([]model.Account, error)
+
+	Update(ctx context.Context, account *model.Account) error
+
+	Remove(ctx context.Context, account *model.Account) error
+
+	CountTransfersByAccountId(ctx context.Context, accountId string) (int64, error)
+
+	CreateTransfer(ctx context.Context, transfer *model.Transfer) error
+
+	FindTransferByIdAndUserId(ctx context.Context, id, userId string) (*model.Transfer, error)
+
+	FindTransfersByUserId(ctx context.Context, userId string, page, limit int) ([]model.Transfer, int64, error)
+
+	RemoveTransfer(ctx context.Context, transfer *model.Transfer) error
+
+	// FindBalances sums the movements of the accounts of the user created up to asOf (unix seconds),
+	// only of the account accountId when it is not empty
+	FindBalances(ctx context.Context, userId, accountId string, asOf int64) ([]model.AccountBalance, error)
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/account"
+	"github.com/mrizkisaputra/expenses-api/internal/account/model"
+	"github.com/mrizkisaputra/expenses-api/internal/currency"
+	"github.com/mrizkisaputra/expenses-api/internal/user"
+	userModel "github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ServiceConfig struct {
+	PgRepo              account.PostgresRepository
+	UserPgRepo          user.UserPostgresRepository
+	ExchangeRateService currency.ExchangeRateService
+	Config              *config.Config
+	Logger              *logrus.Logger
+}
+
+// accountService acts as a struct for injecting an implementation of AccountService interface
+// for use in service methods.
+type accountService struct {
+	pgRepo              account.PostgresRepository
+	userPgRepo          user.UserPostgresRepository
+	exchangeRateService currency.ExchangeRateService
+	logger              *logrus.Logger
+}
+
+// NewAccountService is a factory function for
+// initializing a accountService with its repository layer dependencies
+func NewAccountService(config *ServiceConfig) account.AccountService {
+	return &accountService{
+		pgRepo:              config.PgRepo,
+		userPgRepo:          config.UserPgRepo,
+		exchangeRateService: config.ExchangeRateService,
+		logger:              config.Logger,
+	}
+}
+
+func (a *accountService) Create(ctx context.Context, request *model.Account) (*model.Account, error) {
+	request.PrepareCreate()
+
+	if err := a.ensureUniqueName(ctx, request); err != nil {
+		return nil, err
+	}
+
+	// an account without currency is in the user's base currency
+	if request.Currency == "" {
+		baseCurrency, err := a.baseCurrency(ctx, request.UserId)
+		if err != nil {
+			return nil, err
+		}
+		request.Currency = baseCurrency
+	}
+
+	if err := a.pgRepo.Create(ctx, request); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return request, nil
+}
+
+func (a *accountService) Update(ctx context.Context, request *model.Account) (*model.Account, error) {
+	found, err := a.pgRepo.FindByIdAndUserId(ctx, request.Id.String(), request.UserId.String())
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+
+	request.PrepareUpdate(found)
+	if err := a.ensureUniqueName(ctx, found); err != nil {
+		return nil, err
+	}
+
+	if err := a.pgRepo.Update(ctx, found); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return found, nil
+}
+
+// Delete deletes an account without transfers, its expenses and incomes are kept without account
+func (a *accountService) Delete(ctx context.Context, id, userId string) error {
+	found, err := a.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return httpErrors.NewNotFoundError(err)
+	}
+
+	transfers, err := a.pgRepo.CountTransfersByAccountId(ctx, id)
+	if err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	if transfers > 0 {
+		return httpErrors.NewError(http.StatusConflict, httpErrors.AccountInUseMsg, nil)
+	}
+
+	if err := a.pgRepo.Remove(ctx, found); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+func (a *accountService) GetById(ctx context.Context, id, userId string) (*model.Account, error) {
+	found, err := a.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	return found, nil
+}
+
+func (a *accountService) GetAll(ctx context.Context, userId string) ([]model.Account, error) {
+	accounts, err := a.pgRepo.FindAllByUserId(ctx, userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return accounts, nil
+}
+
+// Transfer moves money between two accounts of the user. without ToAmount the amount
+// arrives unchanged between accounts of the same currency, or converted at today's rate
+func (a *accountService) Transfer(ctx context.Context, request *model.Transfer) (*model.Transfer, error) {
+	if request.FromAccountId == request.ToAccountId {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidTransferMsg, nil)
+	}
+
+	from, err := a.pgRepo.FindByIdAndUserId(ctx, request.FromAccountId.String(), request.UserId.String())
+	if err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.UnknownAccountMsg, err)
+	}
+	to, err := a.pgRepo.FindByIdAndUserId(ctx, request.ToAccountId.String(), request.UserId.String())
+	if err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.UnknownAccountMsg, err)
+	}
+
+	if request.ToAmount == nil {
+		converted, err := a.exchangeRateService.Convert(ctx, *request.Amount, from.Currency, to.Currency, time.Now())
+		if err != nil {
+			return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidTransferMsg, err)
+		}
+		request.ToAmount = &converted
+	}
+	request.Note = strings.TrimSpace(request.Note)
+
+	if err := a.pgRepo.CreateTransfer(ctx, request); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return request, nil
+}
+
+func (a *accountService) GetTransfers(ctx context.Context, userId string, page, limit int) ([]model.Transfer, int64, error) {
+	transfers, total, err := a.pgRepo.FindTransfersByUserId(ctx, userId, page, limit)
+	if err != nil {
+		return nil, 0, httpErrors.NewInternalServerError(err)
+	}
+	return transfers, total, nil
+}
+
+func (a *accountService) DeleteTransfer(ctx context.Context, id, userId string) error {
+	found, err := a.pgRepo.FindTransferByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return httpErrors.NewNotFoundError(err)
+	}
+
+	if err := a.pgRepo.RemoveTransfer(ctx, found); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+// GetBalances computes the balance of every account and their total in the base currency
+// converted at the rate of the day asOf
+func (a *accountService) GetBalances(ctx context.Context, userId, asOf string) (*model.Balances, error) {
+	day, err := parseAsOf(asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := a.pgRepo.FindBalances(ctx, userId, "", endOfDay(day).Unix())
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+
+	parsedId, err := uuid.Parse(userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "accountService.GetBalances.uuid.Parse"))
+	}
+	baseCurrency, err := a.baseCurrency(ctx, parsedId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.Balances{
+		AsOf:     day.Format("2006-01-02"),
+		Accounts: []model.AccountBalance{},
+		Currency: baseCurrency,
+	}
+	missing := map[string]bool{}
+	for _, balance := range balances {
+		balance.Compute()
+		response.Accounts = append(response.Accounts, balance)
+
+		converted, err := a.exchangeRateService.Convert(ctx, balance.Balance, balance.Currency, baseCurrency, day)
+		if err != nil {
+			a.logger.WithError(err).WithField("userId", userId).Debug("accountService.GetBalances.Convert")
+			if !missing[balance.Currency] {
+				missing[balance.Currency] = true
+				response.MissingRates = append(response.MissingRates, balance.Currency)
+			}
+			continue
+		}
+		response.Total += converted
+	}
+	return response, nil
+}
+
+func (a *accountService) GetBalance(ctx context.Context, id, userId, asOf string) (*model.AccountBalance, error) {
+	day, err := parseAsOf(asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := a.pgRepo.FindBalances(ctx, userId, id, endOfDay(day).Unix())
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	if len(balances) == 0 {
+		return nil, httpErrors.NewNotFoundError(errors.New("accountService.GetBalance: account not found"))
+	}
+
+	balances[0].Compute()
+	return &balances[0], nil
+}
+
+// parseAsOf parses the day (2006-01-02) of a balance, today when empty
+func parseAsOf(asOf string) (time.Time, error) {
+	if asOf == "" {
+		year, month, day := time.Now().UTC().Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), nil
+	}
+	day, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return day, httpErrors.NewBadRequestError(errors.Wrap(err, "accountService.parseAsOf.time.Parse"))
+	}
+	return day, nil
+}
+
+func endOfDay(day time.Time) time.Time {
+	return day.AddDate(0, 0, 1).Add(-time.Second)
+}
+
+func (a *accountService) ensureUniqueName(ctx context.Context, request *model.Account) error {
+	found, err := a.pgRepo.FindByNameAndUserId(ctx, request.Name, request.UserId.String())
+	if err == nil && found != nil && found.Id != request.Id {
+		return httpErrors.NewError(http.StatusConflict, httpErrors.AccountAlreadyExistsMsg, nil)
+	}
+	return nil
+}
+
+func (a *accountService) baseCurrency(ctx context.Context, userId uuid.UUID) (string, error) {
+	found, err := a.userPgRepo.FindById(ctx, &userModel.User{Id: userId})
+	if err != nil {
+		return "", httpErrors.NewNotFoundError(errors.Wrap(err, "accountService.baseCurrency.FindById"))
+	}
+	if found.BaseCurrency == "" {
+		return userModel.DefaultBaseCurrency, nil
+	}
+	return found.BaseCurrency, nil
+}
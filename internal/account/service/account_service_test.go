@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/account/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/account/model"
+	currencyMock "github.com/mrizkisaputra/expenses-api/internal/currency/mock"
+	currencyModel "github.com/mrizkisaputra/expenses-api/internal/currency/model"
+	userMock "github.com/mrizkisaputra/expenses-api/internal/user/mock"
+	userModel "github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var cfg = &config.Config{
+	Logger: config.LoggerConfig{Level: "panic"},
+}
+
+func TestAccountService_Transfer(t *testing.T) {
+	userId := uuid.New()
+	fromId := uuid.New()
+	toId := uuid.New()
+	amount := money.Amount(5000000)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Transfer without to amount is converted between the accounts currencies", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockRateService := new(currencyMock.ExchangeRateServiceMock)
+		sv := NewAccountService(&ServiceConfig{PgRepo: mockRepo, ExchangeRateService: mockRateService})
+
+		mockRepo.On("FindByIdAndUserId", mock.Anything, fromId.String(), userId.String()).
+			Return(&model.Account{Id: fromId, UserId: userId, Currency: "IDR"}, nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, toId.String(), userId.String()).
+			Return(&model.Account{Id: toId, UserId: userId, Currency: "USD"}, nil)
+		mockRateService.On("Convert", mock.Anything, amount, "IDR", "USD", mock.Anything).Return(money.Amount(31), nil)
+		mockRepo.On("CreateTransfer", mock.Anything, mock.Anything).Return(nil)
+
+		response, err := sv.Transfer(context.Background(), &model.Transfer{
+			UserId:        userId,
+			FromAccountId: fromId,
+			ToAccountId:   toId,
+			Amount:        &amount,
+			Note:          " top up ",
+		})
+		require.NoError(t, err)
+		require.Equal(t, money.Amount(31), *response.ToAmount)
+		require.Equal(t, "top up", response.Note)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should reject a transfer to the same account", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewAccountService(&ServiceConfig{PgRepo: mockRepo})
+
+		_, err := sv.Transfer(context.Background(), &model.Transfer{
+			UserId:        userId,
+			FromAccountId: fromId,
+			ToAccountId:   fromId,
+			Amount:        &amount,
+		})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.InvalidTransferMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "CreateTransfer")
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Should reject an account of another user", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewAccountService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindByIdAndUserId", mock.Anything, fromId.String(), userId.String()).
+			Return(nil, errors.New("record not found"))
+
+		_, err := sv.Transfer(context.Background(), &model.Transfer{
+			UserId:        userId,
+			FromAccountId: fromId,
+			ToAccountId:   toId,
+			Amount:        &amount,
+		})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.UnknownAccountMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "CreateTransfer")
+	})
+}
+
+func TestAccountService_Delete(t *testing.T) {
+	userId := uuid.New()
+	accountId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Should return conflict when the account has transfers", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewAccountService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindByIdAndUserId", mock.Anything, accountId.String(), userId.String()).
+			Return(&model.Account{Id: accountId, UserId: userId}, nil)
+		mockRepo.On("CountTransfersByAccountId", mock.Anything, accountId.String()).Return(int64(2), nil)
+
+		err := sv.Delete(context.Background(), accountId.String(), userId.String())
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusConflict, er.Status)
+		require.Equal(t, httpErrors.AccountInUseMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "Remove")
+	})
+}
+
+func TestAccountService_GetBalances(t *testing.T) {
+	userId := uuid.New()
+	day := time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Balances are summed in the base currency at the end of the day", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		mockRateService := new(currencyMock.ExchangeRateServiceMock)
+		sv := NewAccountService(&ServiceConfig{
+			PgRepo:              mockRepo,
+			UserPgRepo:          mockUserRepo,
+			ExchangeRateService: mockRateService,
+			Logger:              logger.NewLogrusLogger(cfg),
+		})
+
+		asOf := time.Date(2024, time.March, 31, 23, 59, 59, 0, time.UTC).Unix()
+		mockRepo.On("FindBalances", mock.Anything, userId.String(), "", asOf).Return([]model.AccountBalance{
+			{Name: "bank", Currency: "IDR", OpeningBalance: 1000000, Incomes: 500000, Expenses: 200000, TransfersOut: 100000},
+			{Name: "cash", Currency: "USD", TransfersIn: 10},
+			{Name: "travel", Currency: "EUR", OpeningBalance: 5000},
+		}, nil)
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId, BaseCurrency: "IDR"}, nil)
+		mockRateService.On("Convert", mock.Anything, money.Amount(1200000), "IDR", "IDR", day).Return(money.Amount(1200000), nil)
+		mockRateService.On("Convert", mock.Anything, money.Amount(10), "USD", "IDR", day).Return(money.Amount(160000), nil)
+		mockRateService.On("Convert", mock.Anything, money.Amount(5000), "EUR", "IDR", day).Return(money.Amount(0), currencyModel.ErrExchangeRateNotFound)
+
+		balances, err := sv.GetBalances(context.Background(), userId.String(), "2024-03-31")
+		require.NoError(t, err)
+		require.Equal(t, "2024-03-31", balances.AsOf)
+		require.Len(t, balances.Accounts, 3)
+		require.Equal(t, money.Amount(1200000), balances.Accounts[0].Balance)
+		require.Equal(t, money.Amount(1360000), balances.Total)
+		require.Equal(t, []string{"EUR"}, balances.MissingRates)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return bad request for an invalid day", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewAccountService(&ServiceConfig{PgRepo: mockRepo})
+
+		_, err := sv.GetBalances(context.Background(), userId.String(), "31-03-2024")
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		mockRepo.AssertNotCalled(t, "FindBalances")
+	})
+}
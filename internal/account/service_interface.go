@@ -0,0 +1,31 @@
+package account
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/account/model"
+)
+
+// AccountService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type AccountService interface {
+	Create(ctx context.Context, request *model.Account) (*model.Account, error)
+
+	Update(ctx context.Context, request *model.Account) (*model.Account, error)
+
+	Delete(ctx context.Context, id, userId string) error
+
+	GetById(ctx context.Context, id, userId string) (*model.Account, error)
+
+	GetAll(ctx context.Context, userId string) ([]model.Account, error)
+
+	Transfer(ctx context.Context, request *model.Transfer) (*model.Transfer, error)
+
+	GetTransfers(ctx context.Context, userId string, page, limit int) ([]model.Transfer, int64, error)
+
+	DeleteTransfer(ctx context.Context, id, userId string) error
+
+	// GetBalances returns the balance of every account at the end of the day asOf (2006-01-02), today when empty
+	GetBalances(ctx context.Context, userId, asOf string) (*model.Balances, error)
+
+	GetBalance(ctx context.Context, id, userId, asOf string) (*model.AccountBalance, error)
+}
@@ -0,0 +1,13 @@
+package alert
+
+import "github.com/gin-gonic/gin"
+
+// AlertController defines methods the routes expects
+// any controllers it interacts with to implement
+type AlertController interface {
+	GetAllAlert() gin.HandlerFunc
+
+	MarkAlertRead() gin.HandlerFunc
+
+	MarkAllAlertRead() gin.HandlerFunc
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/mrizkisaputra/expenses-api/internal/alert"
+	"github.com/mrizkisaputra/expenses-api/internal/alert/model"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/sirupsen/logrus"
+	"math"
+	"net/http"
+)
+
+type ControllerConfig struct {
+	AlertService alert.AlertService
+	Logger       *logrus.Logger
+}
+
+// alertController acts as a struct for injecting an implementation of AlertController interface
+// for use in controller methods
+type alertController struct {
+	alertService alert.AlertService
+	logger       *logrus.Logger
+}
+
+// NewAlertController is a factory function
+// initializing a alertController with its service layer dependencies
+func NewAlertController(config *ControllerConfig) alert.AlertController {
+	return &alertController{
+		alertService: config.AlertService,
+		logger:       config.Logger,
+	}
+}
+
+// GetAllAlert is the alerts feed, the most recent first, only the unread alerts with unread=true
+func (ac alertController) GetAllAlert() gin.HandlerFunc {
+	type GetAllAlertRequest struct {
+		Page   int  `form:"page" validate:"omitempty,numeric,min=1"`
+		Limit  int  `form:"limit" validate:"omitempty,numeric,min=10"`
+		Unread bool `form:"unread"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(GetAllAlertRequest)
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if request.Page == 0 {
+			request.Page = 1
+		}
+		if request.Limit == 0 {
+			request.Limit = 10
+		}
+
+		alerts, total, err := ac.alertService.GetAll(ctx, auth.Id.String(), request.Unread, request.Page, request.Limit)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       alerts,
+			Page:       request.Page,
+			Limit:      request.Limit,
+			TotalItems: total,
+			TotalPages: int64(math.Ceil(float64(total) / float64(request.Limit))),
+		})
+	}
+}
+
+func (ac alertController) MarkAlertRead() gin.HandlerFunc {
+	type MarkAlertReadRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &MarkAlertReadRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := ac.alertService.MarkRead(ctx, request.Id, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (ac alertController) MarkAllAlertRead() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		count, err := ac.alertService.MarkAllRead(ctx, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, ac.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    &model.MarkAllReadResult{Marked: count},
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/alert"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+)
+
+func MapAlertRoutes(alertGroup *gin.RouterGroup, controller alert.AlertController, mw *middleware.MiddlewareManager) {
+	alertGroup.Use(mw.AuthJwtMiddleware())
+	alertGroup.GET("/", controller.GetAllAlert())
+	alertGroup.POST("/read", mw.IdempotencyMiddleware(), controller.MarkAllAlertRead())
+	alertGroup.POST("/:id/read", mw.IdempotencyMiddleware(), controller.MarkAlertRead())
+}
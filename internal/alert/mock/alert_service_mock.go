@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/alert/model"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// AlertServiceMock is a mock type for alert.AlertService
+type AlertServiceMock struct {
+	mock.Mock
+}
+
+func (m *AlertServiceMock) Evaluate(ctx context.Context, expense *expenseModel.Expense) ([]model.Alert, error) {
+	args := m.Called(ctx, expense)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Alert), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *AlertServiceMock) GetAll(ctx context.Context, userId string, unreadOnly bool, page, limit int) ([]model.Alert, int64, error) {
+	args := m.Called(ctx, userId, unreadOnly, page, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Alert), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *AlertServiceMock) MarkRead(ctx context.Context, id, userId string) (*model.Alert, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Alert), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *AlertServiceMock) MarkAllRead(ctx context.Context, userId string) (int64, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).(int64), args.Error(1)
+}
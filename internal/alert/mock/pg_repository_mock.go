@@ -0,0 +1,69 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/alert/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPostgresRepository is a mock type for alert.PostgresRepository
+type MockPostgresRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostgresRepository) Create(ctx context.Context, alert *model.Alert) error {
+	args := m.Called(ctx, alert)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Alert, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Alert), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindAllByUserId(ctx context.Context, userId string, unreadOnly bool, page, limit int) ([]model.Alert, int64, error) {
+	args := m.Called(ctx, userId, unreadOnly, page, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Alert), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockPostgresRepository) Update(ctx context.Context, alert *model.Alert) error {
+	args := m.Called(ctx, alert)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) MarkAllRead(ctx context.Context, userId string, readAt int64) (int64, error) {
+	args := m.Called(ctx, userId, readAt)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPostgresRepository) ExistsSince(ctx context.Context, userId, categoryId, kind string, since int64) (bool, error) {
+	args := m.Called(ctx, userId, categoryId, kind, since)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindAmountStats(ctx context.Context, userId, categoryId, currency string, from int64, excludeId string) (*model.AmountStats, error) {
+	args := m.Called(ctx, userId, categoryId, currency, from, excludeId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.AmountStats), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindWeeklyTotals(ctx context.Context, userId, categoryId, currency string, until int64, weeks int) ([]model.WeeklyTotal, error) {
+	args := m.Called(ctx, userId, categoryId, currency, until, weeks)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.WeeklyTotal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) CountByDescription(ctx context.Context, userId, description, excludeId string) (int64, error) {
+	args := m.Called(ctx, userId, description, excludeId)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,197 @@
+package model
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"math"
+)
+
+// kinds of alert
+const (
+	KindCategorySpike = "category_spike"
+	KindLargeExpense  = "large_expense"
+	KindNewMerchant   = "new_merchant"
+)
+
+// Titles are the titles of the notifications of the alerts by kind
+var Titles = map[string]string{
+	KindCategorySpike: "Spending spike",
+	KindLargeExpense:  "Unusually large expense",
+	KindNewMerchant:   "Large charge from a new merchant",
+}
+
+const (
+	// BaselineDays is how far back, in days, the expenses the baseline is computed from go
+	BaselineDays = 90
+
+	// BaselineWeeks is the number of weeks before the current one the weekly average of a category is computed from
+	BaselineWeeks = 12
+
+	// maxMessageLength is the size of the column keeping the message of an alert
+	maxMessageLength = 255
+
+	// minBaselineCount is the number of past expenses (or active weeks) a baseline needs to be trusted
+	minBaselineCount = 5
+
+	// spikeRatio is how many times its weekly average a category must spend this week to spike
+	spikeRatio = 3
+
+	// spikeDeviations is how many standard deviations above its weekly average a category must spend to spike
+	spikeDeviations = 2
+
+	// outlierScore is the modified z-score above which an expense is abnormally large (Iglewicz and Hoaglin)
+	outlierScore = 3.5
+
+	// outlierRatio is how many times the median an expense must also be to be abnormally large,
+	// so that a category of very regular amounts does not raise alerts for small differences
+	outlierRatio = 2
+)
+
+// Alert mapping table 'alerts'.
+// raised when a new expense looks unusual, Amount is what was spent (the expense, or the week of
+// the category for a spike) and Baseline what is usual, Score tells how far apart they are
+type Alert struct {
+	Id         uuid.UUID    `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	UserId     uuid.UUID    `json:"user_id" gorm:"column:id_user"`
+	ExpenseId  uuid.UUID    `json:"expense_id" gorm:"column:id_expense"`
+	CategoryId *uuid.UUID   `json:"category_id" gorm:"column:id_category"`
+	Kind       string       `json:"kind" gorm:"column:kind"`
+	Currency   string       `json:"currency" gorm:"column:currency"`
+	Amount     money.Amount `json:"amount" gorm:"column:amount"`
+	Baseline   money.Amount `json:"baseline" gorm:"column:baseline"`
+	Score      float64      `json:"score" gorm:"column:score"`
+	Message    string       `json:"message" gorm:"column:message"`
+	ReadAt     *int64       `json:"read_at" gorm:"column:read_at"`
+	CreatedAt  int64        `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt  int64        `json:"updated_at" gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (alert *Alert) TableName() string {
+	return "alerts"
+}
+
+// MarkAllReadResult is the number of alerts marked as read at once
+type MarkAllReadResult struct {
+	Marked int64 `json:"marked"`
+}
+
+// AmountStats are the statistics of the amounts of past expenses: their median, their median absolute
+// deviation (MAD) and their 90th percentile
+type AmountStats struct {
+	Count  int64   `gorm:"column:count"`
+	Median float64 `gorm:"column:median"`
+	MAD    float64 `gorm:"column:mad"`
+	P90    float64 `gorm:"column:p90"`
+}
+
+// WeeklyTotal is the sum of the expenses of a week, Week 0 is the week ending with the evaluated expense,
+// week 1 the one before and so on
+type WeeklyTotal struct {
+	Week   int          `gorm:"column:week"`
+	Amount money.Amount `gorm:"column:amount"`
+}
+
+// SplitWeeks returns the total of the current week and the totals of the weeks before it, weeks
+// without expense included as 0
+func SplitWeeks(totals []WeeklyTotal, weeks int) (money.Amount, []money.Amount) {
+	var current money.Amount
+	previous := make([]money.Amount, weeks)
+	for _, total := range totals {
+		switch {
+		case total.Week == 0:
+			current = total.Amount
+		case total.Week <= weeks:
+			previous[total.Week-1] = total.Amount
+		}
+	}
+	return current, previous
+}
+
+// LargeExpense reports whether the amount is abnormally large against the past amounts of its category,
+// the score is the modified z-score 0.6745 * (amount - median) / MAD. when most past amounts are the
+// same (MAD is 0) the amount is large from outlierRatio times the median
+func LargeExpense(amount money.Amount, stats *AmountStats) (float64, bool) {
+	if stats.Count < minBaselineCount || stats.Median <= 0 || float64(amount) < outlierRatio*stats.Median {
+		return 0, false
+	}
+	if stats.MAD == 0 {
+		return round(float64(amount) / stats.Median), true
+	}
+	score := 0.6745 * (float64(amount) - stats.Median) / stats.MAD
+	return round(score), score > outlierScore
+}
+
+// CategorySpike reports whether the spending of the current week is a spike against the weeks before,
+// weeks holds the totals of the BaselineWeeks previous weeks, a week without expense is 0.
+// the score is the ratio of the current week to the weekly average
+func CategorySpike(current money.Amount, weeks []money.Amount) (float64, float64, bool) {
+	active := 0
+	var sum float64
+	for _, total := range weeks {
+		if total > 0 {
+			active++
+		}
+		sum += float64(total)
+	}
+	if active < minBaselineCount || len(weeks) == 0 {
+		return 0, 0, false
+	}
+
+	mean := sum / float64(len(weeks))
+	var variance float64
+	for _, total := range weeks {
+		variance += (float64(total) - mean) * (float64(total) - mean)
+	}
+	deviation := math.Sqrt(variance / float64(len(weeks)))
+
+	ratio := float64(current) / mean
+	return round(ratio), mean, ratio >= spikeRatio && float64(current) > mean+spikeDeviations*deviation
+}
+
+// NewMerchant reports whether the first charge of a merchant is big, that is above the 90th percentile
+// of the past expenses of the user in the currency, the score is the ratio of the amount to that percentile
+func NewMerchant(amount money.Amount, seen bool, stats *AmountStats) (float64, bool) {
+	if seen || stats.Count < minBaselineCount || stats.P90 <= 0 {
+		return 0, false
+	}
+	return round(float64(amount) / stats.P90), float64(amount) > stats.P90
+}
+
+// NewCategorySpikeAlert is the alert of a category spending ratio times its weekly average this week
+func NewCategorySpikeAlert(week money.Amount, average float64, ratio float64) *Alert {
+	return &Alert{
+		Kind:     KindCategorySpike,
+		Amount:   week,
+		Baseline: money.Amount(math.Round(average)),
+		Score:    ratio,
+		Message:  fmt.Sprintf("spending of the category this week is %.1fx its weekly average", ratio),
+	}
+}
+
+// NewLargeExpenseAlert is the alert of an expense far above the usual amount of its category
+func NewLargeExpenseAlert(description string, amount money.Amount, stats *AmountStats, score float64) *Alert {
+	return &Alert{
+		Kind:     KindLargeExpense,
+		Amount:   amount,
+		Baseline: money.Amount(math.Round(stats.Median)),
+		Score:    score,
+		Message:  utils.Truncate(fmt.Sprintf("'%s' is %.1fx the usual amount of its category", description, float64(amount)/stats.Median), maxMessageLength),
+	}
+}
+
+// NewMerchantAlert is the alert of a big first charge of a merchant
+func NewMerchantAlert(description string, amount money.Amount, stats *AmountStats, score float64) *Alert {
+	return &Alert{
+		Kind:     KindNewMerchant,
+		Amount:   amount,
+		Baseline: money.Amount(math.Round(stats.P90)),
+		Score:    score,
+		Message:  utils.Truncate(fmt.Sprintf("first charge of '%s' is larger than 90%% of your expenses", description), maxMessageLength),
+	}
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
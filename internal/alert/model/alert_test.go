@@ -0,0 +1,62 @@
+package model
+
+import (
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLargeExpense(t *testing.T) {
+	stats := &AmountStats{Count: 20, Median: 50000, MAD: 10000, P90: 90000}
+
+	score, ok := LargeExpense(500000, stats)
+	require.True(t, ok)
+	require.Equal(t, 30.35, score)
+
+	// below twice the median, or not enough history
+	_, ok = LargeExpense(90000, stats)
+	require.False(t, ok)
+	_, ok = LargeExpense(500000, &AmountStats{Count: 3, Median: 50000, MAD: 10000})
+	require.False(t, ok)
+
+	// every past amount is the same
+	score, ok = LargeExpense(150000, &AmountStats{Count: 10, Median: 50000})
+	require.True(t, ok)
+	require.Equal(t, float64(3), score)
+}
+
+func TestCategorySpike(t *testing.T) {
+	weeks := []money.Amount{100000, 120000, 0, 90000, 110000, 100000, 0, 80000, 100000, 0, 0, 0}
+
+	ratio, average, ok := CategorySpike(400000, weeks)
+	require.True(t, ok)
+	require.InDelta(t, 58333.33, average, 0.01)
+	require.Equal(t, 6.86, ratio)
+
+	_, _, ok = CategorySpike(150000, weeks)
+	require.False(t, ok)
+
+	// a category with too few active weeks has no baseline
+	_, _, ok = CategorySpike(400000, []money.Amount{100000, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+	require.False(t, ok)
+}
+
+func TestNewMerchant(t *testing.T) {
+	stats := &AmountStats{Count: 20, Median: 50000, MAD: 10000, P90: 200000}
+
+	score, ok := NewMerchant(500000, false, stats)
+	require.True(t, ok)
+	require.Equal(t, 2.5, score)
+
+	_, ok = NewMerchant(500000, true, stats)
+	require.False(t, ok)
+	_, ok = NewMerchant(150000, false, stats)
+	require.False(t, ok)
+}
+
+func TestSplitWeeks(t *testing.T) {
+	current, previous := SplitWeeks([]WeeklyTotal{{Week: 0, Amount: 300}, {Week: 2, Amount: 200}, {Week: 13, Amount: 100}}, 12)
+	require.Equal(t, money.Amount(300), current)
+	require.Len(t, previous, 12)
+	require.Equal(t, []money.Amount{0, 200}, previous[:2])
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/alert"
+	"github.com/mrizkisaputra/expenses-api/internal/alert/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// secondsPerWeek is the length of the weeks the expenses of a category are summed per
+const secondsPerWeek = 7 * 24 * 60 * 60
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewAlertPgRepository(db *gorm.DB) alert.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (p *postgresRepository) Create(ctx context.Context, alert *model.Alert) error {
+	/**
+	SQL: INSERT INTO "alerts" ("id_user","id_expense","id_category","kind","currency","amount","baseline","score",
+	"message","read_at","created_at","updated_at") VALUES (?,?,?,?,?,?,?,?,?,?,?,?) ON CONFLICT DO NOTHING RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Clauses(clause.OnConflict{DoNothing: true}).Create(alert).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Create")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Alert, error) {
+	/**
+	SQL: SELECT * FROM "alerts" WHERE id = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	alert := new(model.Alert)
+	if err := DB.Where("id = ? AND id_user = ?", id, userId).Take(alert).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByIdAndUserId")
+	}
+	return alert, nil
+}
+
+func (p *postgresRepository) FindAllByUserId(ctx context.Context, userId string, unreadOnly bool, page, limit int) ([]model.Alert, int64, error) {
+	DB := p.db.WithContext(ctx)
+	query := func() *gorm.DB {
+		query := DB.Model(&model.Alert{}).Where("id_user = ?", userId)
+		if unreadOnly {
+			query = query.Where("read_at IS NULL")
+		}
+		return query
+	}
+
+	/**
+	SQL: SELECT * FROM "alerts" WHERE id_user = ? [AND read_at IS NULL] ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?
+	*/
+	var alerts []model.Alert
+	if err := query().
+		Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&alerts).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindAllByUserId.Find")
+	}
+
+	/**
+	SQL: SELECT count(*) FROM "alerts" WHERE id_user = ? [AND read_at IS NULL]
+	*/
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindAllByUserId.Count")
+	}
+
+	return alerts, total, nil
+}
+
+func (p *postgresRepository) Update(ctx context.Context, alert *model.Alert) error {
+	/**
+	SQL: UPDATE "alerts" SET ...,"read_at"=?,"updated_at"=? WHERE (id = ? AND id_user = ?) AND "id" = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", alert.Id, alert.UserId).Updates(alert).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Update")
+	}
+	return nil
+}
+
+func (p *postgresRepository) MarkAllRead(ctx context.Context, userId string, readAt int64) (int64, error) {
+	/**
+	SQL: UPDATE "alerts" SET "read_at"=?,"updated_at"=? WHERE id_user = ? AND read_at IS NULL
+	*/
+	DB := p.db.WithContext(ctx)
+	result := DB.Model(&model.Alert{}).
+		Where("id_user = ? AND read_at IS NULL", userId).
+		Updates(map[string]interface{}{"read_at": readAt, "updated_at": readAt})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "postgresRepository.MarkAllRead")
+	}
+	return result.RowsAffected, nil
+}
+
+func (p *postgresRepository) ExistsSince(ctx context.Context, userId, categoryId, kind string, since int64) (bool, error) {
+	/**
+	SQL: SELECT count(*) FROM "alerts" WHERE id_user = ? AND id_category = ? AND kind = ? AND created_at >= ?
+	*/
+	DB := p.db.WithContext(ctx)
+	var count int64
+	if err := DB.Model(&model.Alert{}).
+		Where("id_user = ? AND id_category = ? AND kind = ? AND created_at >= ?", userId, categoryId, kind, since).
+		Count(&count).Error; err != nil {
+		return false, errors.Wrap(err, "postgresRepository.ExistsSince")
+	}
+	return count > 0, nil
+}
+
+func (p *postgresRepository) FindAmountStats(
+	ctx context.Context,
+	userId, categoryId, currency string,
+	from int64,
+	excludeId string,
+) (*model.AmountStats, error) {
+	/**
+	SQL: the median, 90th percentile and median absolute deviation of the amounts of the past expenses
+	*/
+	DB := p.db.WithContext(ctx)
+	stats := new(model.AmountStats)
+	err := DB.Raw(`
+		WITH amounts AS (
+			SELECT amount FROM expenses
+			WHERE id_user = @user AND currency = @currency AND created_at >= @from AND id <> @exclude
+			AND deleted_at IS NULL AND (@category = '' OR id_category::TEXT = @category)
+		), stats AS (
+			SELECT COUNT(*) AS count,
+			COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY amount), 0) AS median,
+			COALESCE(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY amount), 0) AS p90
+			FROM amounts
+		)
+		SELECT stats.count, stats.median, stats.p90,
+		COALESCE((SELECT PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY ABS(amounts.amount - stats.median)) FROM amounts), 0) AS mad
+		FROM stats`,
+		map[string]interface{}{"user": userId, "category": categoryId, "currency": currency, "from": from, "exclude": excludeId},
+	).Scan(stats).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindAmountStats")
+	}
+	return stats, nil
+}
+
+func (p *postgresRepository) FindWeeklyTotals(
+	ctx context.Context,
+	userId, categoryId, currency string,
+	until int64,
+	weeks int,
+) ([]model.WeeklyTotal, error) {
+	/**
+	SQL: SELECT (? - created_at) / 604800 AS week, SUM(amount) AS amount FROM "expenses"
+	WHERE id_user = ? AND id_category = ? AND currency = ? AND created_at <= ? AND created_at > ?
+	AND "expenses"."deleted_at" IS NULL GROUP BY week ORDER BY week
+	*/
+	DB := p.db.WithContext(ctx)
+	var totals []model.WeeklyTotal
+	if err := DB.Table("expenses").
+		Select("(? - created_at) / ? AS week, SUM(amount) AS amount", until, secondsPerWeek).
+		Where("id_user = ? AND id_category = ? AND currency = ?", userId, categoryId, currency).
+		Where("created_at <= ? AND created_at > ?", until, until-int64(weeks+1)*secondsPerWeek).
+		Where("deleted_at IS NULL").
+		Group("week").
+		Order("week").
+		Scan(&totals).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindWeeklyTotals")
+	}
+	return totals, nil
+}
+
+func (p *postgresRepository) CountByDescription(ctx context.Context, userId, description, excludeId string) (int64, error) {
+	/**
+	SQL: SELECT count(*) FROM "expenses" WHERE id_user = ? AND description = ? AND id <> ? AND "expenses"."deleted_at" IS NULL
+	*/
+	DB := p.db.WithContext(ctx)
+	var count int64
+	if err := DB.Table("expenses").
+		Where("id_user = ? AND description = ? AND id <> ?", userId, description, excludeId).
+		Where("deleted_at IS NULL").
+		Count(&count).Error; err != nil {
+		return 0, errors.Wrap(err, "postgresRepository.CountByDescription")
+	}
+	return count, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/alert"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+)
+
+var (
+	repository alert.PostgresRepository
+	mock       sqlmock.Sqlmock
+)
+
+// Test Before and After
+func TestMain(m *testing.M) {
+	// initialize mock sql db
+	mockDb, mocking, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("Failed to open mock sql db, got error %v", err)
+	}
+	defer mockDb.Close()
+	mock = mocking
+
+	// initialize db connection
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDb}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database, got error %v", err)
+	}
+
+	// initialize repository
+	repository = NewAlertPgRepository(db)
+
+	os.Exit(m.Run())
+}
+
+func TestAlertPostgresRepository_FindWeeklyTotals(t *testing.T) {
+	userId, categoryId := uuid.New(), uuid.New()
+	until := int64(1767225600)
+
+	// define expect sql
+	expectedSQL := regexp.QuoteMeta(`SELECT ($1 - created_at) / $2 AS week, SUM(amount) AS amount FROM "expenses" WHERE (id_user = $3 AND id_category = $4 AND currency = $5) AND (created_at <= $6 AND created_at > $7) AND deleted_at IS NULL GROUP BY "week" ORDER BY week`)
+	rows := sqlmock.NewRows([]string{"week", "amount"}).AddRow(0, 400000).AddRow(3, 100000)
+	mock.ExpectQuery(expectedSQL).
+		WithArgs(until, secondsPerWeek, userId.String(), categoryId.String(), "IDR", until, until-13*secondsPerWeek).
+		WillReturnRows(rows)
+
+	totals, err := repository.FindWeeklyTotals(context.Background(), userId.String(), categoryId.String(), "IDR", until, 12)
+	require.NoError(t, err)
+	require.Len(t, totals, 2)
+	require.Equal(t, 3, totals[1].Week)
+	require.Equal(t, money.Amount(100000), totals[1].Amount)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertPostgresRepository_MarkAllRead(t *testing.T) {
+	userId := uuid.New()
+	readAt := int64(1767225600000)
+
+	// define expect sql
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "alerts" SET "read_at"=$1,"updated_at"=$2 WHERE id_user = $3 AND read_at IS NULL`)).
+		WithArgs(readAt, readAt, userId.String()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	count, err := repository.MarkAllRead(context.Background(), userId.String(), readAt)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
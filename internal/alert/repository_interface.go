@@ -0,0 +1,37 @@
+package alert
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/alert/model"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	// Create inserts the alert unless the expense already raised an alert of its kind
+	Create(ctx context.Context, alert *model.Alert) error
+
+	FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Alert, error)
+
+	// FindAllByUserId finds a page of the alerts of the user, the most recent first
+	FindAllByUserId(ctx context.Context, userId string, unreadOnly bool, page, limit int) ([]model.Alert, int64, error)
+
+	Update(ctx context.Context, alert *model.Alert) error
+
+	// MarkAllRead marks every unread alert of the user as read at readAt (unix milliseconds), it returns their number
+	MarkAllRead(ctx context.Context, userId string, readAt int64) (int64, error)
+
+	// ExistsSince reports whether the category raised an alert of the kind since (unix milliseconds)
+	ExistsSince(ctx context.Context, userId, categoryId, kind string, since int64) (bool, error)
+
+	// FindAmountStats computes the statistics of the amounts of the expenses of the user in the currency made
+	// from the unix time from, of the category unless categoryId is empty. the expense excludeId is left out
+	FindAmountStats(ctx context.Context, userId, categoryId, currency string, from int64, excludeId string) (*model.AmountStats, error)
+
+	// FindWeeklyTotals sums the expenses of the category in the currency per week back from the unix time until,
+	// for the current week (0) and the given number of weeks before it, weeks without expense are left out
+	FindWeeklyTotals(ctx context.Context, userId, categoryId, currency string, until int64, weeks int) ([]model.WeeklyTotal, error)
+
+	// CountByDescription counts the expenses of the user with the description, the expense excludeId left out
+	CountByDescription(ctx context.Context, userId, description, excludeId string) (int64, error)
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/alert"
+	"github.com/mrizkisaputra/expenses-api/internal/alert/model"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	notificationModel "github.com/mrizkisaputra/expenses-api/internal/notification/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type ServiceConfig struct {
+	PgRepo    alert.PostgresRepository
+	Publisher notification.Publisher
+	Config    *config.Config
+	Logger    *logrus.Logger
+}
+
+// alertService acts as a struct for injecting an implementation of AlertService interface
+// for use in service methods.
+type alertService struct {
+	pgRepo    alert.PostgresRepository
+	publisher notification.Publisher
+	logger    *logrus.Logger
+}
+
+// NewAlertService is a factory function for
+// initializing a alertService with its repository layer dependencies
+func NewAlertService(config *ServiceConfig) alert.AlertService {
+	return &alertService{
+		pgRepo:    config.PgRepo,
+		publisher: config.Publisher,
+		logger:    config.Logger,
+	}
+}
+
+// Evaluate checks the new expense against a baseline computed from the expenses of the last BaselineDays days:
+// the median and MAD of the amounts of its category for an abnormally large expense, the 90th percentile
+// of the amounts of the user for a big first charge of a merchant, and the mean and standard deviation of the
+// weekly totals of its category for a spike. a category spikes once a week at most
+func (a *alertService) Evaluate(ctx context.Context, expense *expenseModel.Expense) ([]model.Alert, error) {
+	if expense.Amount == nil {
+		return nil, nil
+	}
+
+	userId, categoryId, id := expense.UserId.String(), expense.CategoryId.String(), expense.Id.String()
+	from := expense.CreatedAt - model.BaselineDays*24*60*60
+	var raised []*model.Alert
+
+	categoryStats, err := a.pgRepo.FindAmountStats(ctx, userId, categoryId, expense.Currency, from, id)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	if score, ok := model.LargeExpense(*expense.Amount, categoryStats); ok {
+		raised = append(raised, model.NewLargeExpenseAlert(expense.Description, *expense.Amount, categoryStats, score))
+	}
+
+	if expense.Description != "" {
+		seen, err := a.pgRepo.CountByDescription(ctx, userId, expense.Description, id)
+		if err != nil {
+			return nil, httpErrors.NewInternalServerError(err)
+		}
+		userStats, err := a.pgRepo.FindAmountStats(ctx, userId, "", expense.Currency, from, id)
+		if err != nil {
+			return nil, httpErrors.NewInternalServerError(err)
+		}
+		if score, ok := model.NewMerchant(*expense.Amount, seen > 0, userStats); ok {
+			raised = append(raised, model.NewMerchantAlert(expense.Description, *expense.Amount, userStats, score))
+		}
+	}
+
+	totals, err := a.pgRepo.FindWeeklyTotals(ctx, userId, categoryId, expense.Currency, expense.CreatedAt, model.BaselineWeeks)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	current, previous := model.SplitWeeks(totals, model.BaselineWeeks)
+	if ratio, average, ok := model.CategorySpike(current, previous); ok {
+		since := time.Now().AddDate(0, 0, -7).UnixMilli()
+		exists, err := a.pgRepo.ExistsSince(ctx, userId, categoryId, model.KindCategorySpike, since)
+		if err != nil {
+			return nil, httpErrors.NewInternalServerError(err)
+		}
+		if !exists {
+			raised = append(raised, model.NewCategorySpikeAlert(current, average, ratio))
+		}
+	}
+
+	category := expense.CategoryId
+	alerts := make([]model.Alert, 0, len(raised))
+	for _, found := range raised {
+		found.UserId = expense.UserId
+		found.ExpenseId = expense.Id
+		found.CategoryId = &category
+		found.Currency = expense.Currency
+		if err := a.pgRepo.Create(ctx, found); err != nil {
+			return nil, httpErrors.NewInternalServerError(err)
+		}
+		alerts = append(alerts, *found)
+		a.notify(ctx, found)
+	}
+	return alerts, nil
+}
+
+// notify publishes a new alert as a notification, an alert the expense already raised (not created
+// again, without id) is not published twice. a failure only loses the notification
+func (a *alertService) notify(ctx context.Context, raised *model.Alert) {
+	if a.publisher == nil || raised.Id == uuid.Nil {
+		return
+	}
+	err := a.publisher.Publish(ctx, &notificationModel.Notification{
+		UserId: raised.UserId,
+		Kind:   notificationModel.KindSpendingAlert,
+		Title:  model.Titles[raised.Kind],
+		Body:   raised.Message,
+		Data: notificationModel.Data{
+			"alert_id":   raised.Id.String(),
+			"alert_kind": raised.Kind,
+			"expense_id": raised.ExpenseId.String(),
+		},
+	})
+	if err != nil {
+		a.logger.WithError(err).WithField("alertId", raised.Id).Warn("alertService.notify.Publish")
+	}
+}
+
+func (a *alertService) GetAll(ctx context.Context, userId string, unreadOnly bool, page, limit int) ([]model.Alert, int64, error) {
+	alerts, total, err := a.pgRepo.FindAllByUserId(ctx, userId, unreadOnly, page, limit)
+	if err != nil {
+		return nil, 0, httpErrors.NewInternalServerError(err)
+	}
+	return alerts, total, nil
+}
+
+func (a *alertService) MarkRead(ctx context.Context, id, userId string) (*model.Alert, error) {
+	found, err := a.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	if found.ReadAt != nil {
+		return found, nil
+	}
+
+	readAt := time.Now().UnixMilli()
+	found.ReadAt = &readAt
+	if err := a.pgRepo.Update(ctx, found); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return found, nil
+}
+
+func (a *alertService) MarkAllRead(ctx context.Context, userId string) (int64, error) {
+	count, err := a.pgRepo.MarkAllRead(ctx, userId, time.Now().UnixMilli())
+	if err != nil {
+		return 0, httpErrors.NewInternalServerError(err)
+	}
+	return count, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/alert/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/alert/model"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	notificationMock "github.com/mrizkisaputra/expenses-api/internal/notification/mock"
+	notificationModel "github.com/mrizkisaputra/expenses-api/internal/notification/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestAlertService_Evaluate(t *testing.T) {
+	userId, categoryId := uuid.New(), uuid.New()
+	amount := money.Amount(500000)
+	expense := &expenseModel.Expense{
+		Id:          uuid.New(),
+		UserId:      userId,
+		Description: "new laptop store",
+		Amount:      &amount,
+		Currency:    "IDR",
+		CategoryId:  categoryId,
+		CreatedAt:   1767225600,
+	}
+	from := expense.CreatedAt - model.BaselineDays*24*60*60
+	weeks := []model.WeeklyTotal{
+		{Week: 0, Amount: 600000}, {Week: 1, Amount: 100000}, {Week: 2, Amount: 120000},
+		{Week: 3, Amount: 90000}, {Week: 5, Amount: 110000}, {Week: 6, Amount: 100000},
+	}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] An unusual expense raises every kind of alert", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewAlertService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindAmountStats", mock.Anything, userId.String(), categoryId.String(), "IDR", from, expense.Id.String()).
+			Return(&model.AmountStats{Count: 20, Median: 50000, MAD: 10000, P90: 90000}, nil)
+		mockRepo.On("FindAmountStats", mock.Anything, userId.String(), "", "IDR", from, expense.Id.String()).
+			Return(&model.AmountStats{Count: 50, Median: 40000, MAD: 20000, P90: 200000}, nil)
+		mockRepo.On("CountByDescription", mock.Anything, userId.String(), "new laptop store", expense.Id.String()).Return(int64(0), nil)
+		mockRepo.On("FindWeeklyTotals", mock.Anything, userId.String(), categoryId.String(), "IDR", expense.CreatedAt, model.BaselineWeeks).
+			Return(weeks, nil)
+		mockRepo.On("ExistsSince", mock.Anything, userId.String(), categoryId.String(), model.KindCategorySpike, mock.Anything).Return(false, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		alerts, err := sv.Evaluate(context.Background(), expense)
+		require.NoError(t, err)
+		require.Len(t, alerts, 3)
+		require.Equal(t, model.KindLargeExpense, alerts[0].Kind)
+		require.Equal(t, money.Amount(50000), alerts[0].Baseline)
+		require.Equal(t, model.KindNewMerchant, alerts[1].Kind)
+		require.Equal(t, model.KindCategorySpike, alerts[2].Kind)
+		require.Equal(t, money.Amount(600000), alerts[2].Amount)
+		require.Equal(t, expense.Id, alerts[2].ExpenseId)
+		require.Equal(t, categoryId, *alerts[2].CategoryId)
+		mockRepo.AssertNumberOfCalls(t, "Create", 3)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] A known merchant and a category that already spiked this week raise no alert", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewAlertService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindAmountStats", mock.Anything, userId.String(), categoryId.String(), "IDR", from, expense.Id.String()).
+			Return(&model.AmountStats{Count: 20, Median: 400000, MAD: 100000, P90: 600000}, nil)
+		mockRepo.On("FindAmountStats", mock.Anything, userId.String(), "", "IDR", from, expense.Id.String()).
+			Return(&model.AmountStats{Count: 50, Median: 40000, MAD: 20000, P90: 200000}, nil)
+		mockRepo.On("CountByDescription", mock.Anything, userId.String(), "new laptop store", expense.Id.String()).Return(int64(2), nil)
+		mockRepo.On("FindWeeklyTotals", mock.Anything, userId.String(), categoryId.String(), "IDR", expense.CreatedAt, model.BaselineWeeks).
+			Return(weeks, nil)
+		mockRepo.On("ExistsSince", mock.Anything, userId.String(), categoryId.String(), model.KindCategorySpike, mock.Anything).Return(true, nil)
+
+		alerts, err := sv.Evaluate(context.Background(), expense)
+		require.NoError(t, err)
+		require.Empty(t, alerts)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] A new alert is published as a notification", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockPublisher := new(notificationMock.PublisherMock)
+		sv := NewAlertService(&ServiceConfig{PgRepo: mockRepo, Publisher: mockPublisher})
+
+		mockRepo.On("FindAmountStats", mock.Anything, userId.String(), categoryId.String(), "IDR", from, expense.Id.String()).
+			Return(&model.AmountStats{Count: 20, Median: 50000, MAD: 10000, P90: 90000}, nil)
+		mockRepo.On("FindAmountStats", mock.Anything, userId.String(), "", "IDR", from, expense.Id.String()).
+			Return(&model.AmountStats{Count: 50, Median: 40000, MAD: 20000, P90: 600000}, nil)
+		mockRepo.On("CountByDescription", mock.Anything, userId.String(), "new laptop store", expense.Id.String()).Return(int64(2), nil)
+		mockRepo.On("FindWeeklyTotals", mock.Anything, userId.String(), categoryId.String(), "IDR", expense.CreatedAt, model.BaselineWeeks).
+			Return([]model.WeeklyTotal{}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*model.Alert).Id = uuid.New()
+		})
+		mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(notification *notificationModel.Notification) bool {
+			return notification.Kind == notificationModel.KindSpendingAlert &&
+				notification.UserId == userId &&
+				notification.Title == model.Titles[model.KindLargeExpense] &&
+				notification.Data["expense_id"] == expense.Id.String()
+		})).Return(nil)
+
+		alerts, err := sv.Evaluate(context.Background(), expense)
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		mockPublisher.AssertNumberOfCalls(t, "Publish", 1)
+	})
+}
+
+func TestAlertService_MarkRead(t *testing.T) {
+	userId, id := uuid.New(), uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] An unread alert is marked as read", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewAlertService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindByIdAndUserId", mock.Anything, id.String(), userId.String()).Return(&model.Alert{Id: id, UserId: userId}, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		response, err := sv.MarkRead(context.Background(), id.String(), userId.String())
+		require.NoError(t, err)
+		require.NotNil(t, response.ReadAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return not found for an unknown alert", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewAlertService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindByIdAndUserId", mock.Anything, id.String(), userId.String()).Return(nil, errors.New("record not found"))
+
+		_, err := sv.MarkRead(context.Background(), id.String(), userId.String())
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusNotFound, er.Status)
+	})
+}
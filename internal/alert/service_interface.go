@@ -0,0 +1,21 @@
+package alert
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/alert/model"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+)
+
+// AlertService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type AlertService interface {
+	// Evaluate compares a new expense to the spending history of the user and raises the alerts it deserves
+	Evaluate(ctx context.Context, expense *expenseModel.Expense) ([]model.Alert, error)
+
+	GetAll(ctx context.Context, userId string, unreadOnly bool, page, limit int) ([]model.Alert, int64, error)
+
+	MarkRead(ctx context.Context, id, userId string) (*model.Alert, error)
+
+	// MarkAllRead marks every unread alert of the user as read, it returns their number
+	MarkAllRead(ctx context.Context, userId string) (int64, error)
+}
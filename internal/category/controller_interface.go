@@ -0,0 +1,17 @@
+package category
+
+import "github.com/gin-gonic/gin"
+
+// CategoryController defines methods the routes expects
+// any controllers it interacts with to implement
+type CategoryController interface {
+	CreateCategory() gin.HandlerFunc
+
+	UpdateCategory() gin.HandlerFunc
+
+	GetCategoryById() gin.HandlerFunc
+
+	GetAllCategory() gin.HandlerFunc
+
+	MergeCategory() gin.HandlerFunc
+}
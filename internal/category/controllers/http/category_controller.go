@@ -0,0 +1,206 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/category"
+	"github.com/mrizkisaputra/expenses-api/internal/category/model"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type ControllerConfig struct {
+	CategoryService category.CategoryService
+	Logger          *logrus.Logger
+}
+
+// categoryController acts as a struct for injecting an implementation of CategoryController interface
+// for use in controller methods
+type categoryController struct {
+	categoryService category.CategoryService
+	logger          *logrus.Logger
+}
+
+// NewCategoryController is a factory function
+// initializing a categoryController with its service layer dependencies
+func NewCategoryController(config *ControllerConfig) category.CategoryController {
+	return &categoryController{
+		categoryService: config.CategoryService,
+		logger:          config.Logger,
+	}
+}
+
+func (cc categoryController) CreateCategory() gin.HandlerFunc {
+	type CreateCategoryRequest struct {
+		Name     string `json:"name" validate:"required,max=100"`
+		Color    string `json:"color" validate:"omitempty,len=7,hexcolor"`
+		Icon     string `json:"icon" validate:"omitempty,max=50"`
+		ParentId string `json:"parent_id" validate:"omitempty,uuid"`
+		Kind     string `json:"kind" validate:"omitempty,oneof=expense income"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(CreateCategoryRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, cc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Category{
+			UserId:   auth.Id,
+			ParentId: utils.ParseOptionalId(request.ParentId),
+			Name:     request.Name,
+			Color:    request.Color,
+			Icon:     request.Icon,
+			Kind:     request.Kind,
+		}
+		response, err := cc.categoryService.Create(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, cc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, &expenseModel.ApiResponse{
+			Status:  http.StatusCreated,
+			Message: "Created",
+			Data:    response,
+		})
+	}
+}
+
+func (cc categoryController) UpdateCategory() gin.HandlerFunc {
+	type UpdateCategoryRequest struct {
+		Id       string `json:"-" validate:"required,uuid"`
+		Name     string `json:"name" validate:"omitempty,max=100"`
+		Color    string `json:"color" validate:"omitempty,len=7,hexcolor"`
+		Icon     string `json:"icon" validate:"omitempty,max=50"`
+		ParentId string `json:"parent_id" validate:"omitempty,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(UpdateCategoryRequest)
+		request.Id = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, cc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Category{
+			Id:       uuid.MustParse(request.Id),
+			UserId:   auth.Id,
+			ParentId: utils.ParseOptionalId(request.ParentId),
+			Name:     request.Name,
+			Color:    request.Color,
+			Icon:     request.Icon,
+		}
+		response, err := cc.categoryService.Update(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, cc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, &expenseModel.ApiResponse{
+			Status:  http.StatusAccepted,
+			Message: "Accepted",
+			Data:    response,
+		})
+	}
+}
+
+func (cc categoryController) GetCategoryById() gin.HandlerFunc {
+	type GetCategoryRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &GetCategoryRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, cc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := cc.categoryService.GetById(ctx, request.Id, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, cc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (cc categoryController) GetAllCategory() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		categories, err := cc.categoryService.GetAll(ctx, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, cc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       categories,
+			TotalItems: int64(len(categories)),
+		})
+	}
+}
+
+func (cc categoryController) MergeCategory() gin.HandlerFunc {
+	type MergeCategoryRequest struct {
+		Id       string `json:"-" validate:"required,uuid"`
+		TargetId string `json:"target_id" validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(MergeCategoryRequest)
+		request.Id = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, cc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := cc.categoryService.Merge(ctx, auth.Id.String(), request.Id, request.TargetId)
+		if err != nil {
+			utils.LogErrorResponse(ctx, cc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
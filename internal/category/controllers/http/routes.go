@@ -0,0 +1,16 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/category"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+)
+
+func MapCategoryRoutes(categoryGroup *gin.RouterGroup, controller category.CategoryController, mw *middleware.MiddlewareManager) {
+	categoryGroup.Use(mw.AuthJwtMiddleware())
+	categoryGroup.POST("/", mw.IdempotencyMiddleware(), controller.CreateCategory())
+	categoryGroup.GET("/", controller.GetAllCategory())
+	categoryGroup.GET("/:id", controller.GetCategoryById())
+	categoryGroup.PATCH("/:id", mw.IdempotencyMiddleware(), controller.UpdateCategory())
+	categoryGroup.POST("/:id/merge", mw.IdempotencyMiddleware(), controller.MergeCategory())
+}
@@ -0,0 +1,61 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/category/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPostgresRepository is a mock type for category.PostgresRepository
+type MockPostgresRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostgresRepository) Create(ctx context.Context, category *model.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) CreateMany(ctx context.Context, categories []model.Category) error {
+	args := m.Called(ctx, categories)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Category, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Category), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Category, error) {
+	args := m.Called(ctx, name, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Category), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindAllByUserId(ctx context.Context, userId string) ([]model.Category, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Category), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) CountByUserId(ctx context.Context, userId string) (int64, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPostgresRepository) Update(ctx context.Context, category *model.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) Merge(ctx context.Context, source, target *model.Category) (int64, error) {
+	args := m.Called(ctx, source, target)
+	return args.Get(0).(int64), args.Error(1)
+}
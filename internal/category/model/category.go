@@ -0,0 +1,144 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"strings"
+)
+
+const (
+	DefaultColor = "#9E9E9E"
+	DefaultIcon  = "tag"
+)
+
+// kinds of category, expenses may only use expense categories and incomes income categories
+const (
+	KindExpense = "expense"
+	KindIncome  = "income"
+)
+
+// Category mapping table 'categories'.
+// categories are owned by a user and may be nested under a parent category
+type Category struct {
+	Id        uuid.UUID  `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	UserId    uuid.UUID  `json:"user_id" gorm:"column:id_user"`
+	ParentId  *uuid.UUID `json:"parent_id" gorm:"column:id_parent"`
+	Name      string     `json:"name" gorm:"column:name"`
+	Color     string     `json:"color" gorm:"column:color"`
+	Icon      string     `json:"icon" gorm:"column:icon"`
+	Kind      string     `json:"kind" gorm:"column:kind;<-:create"`                                  // allow read and create
+	CreatedAt int64      `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt int64      `json:"updated_at" gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+	ChangeXid int64      `json:"-" gorm:"column:change_xid;->"` // transaction of the last write in the sync change feed
+	ChangeSeq int64      `json:"-" gorm:"column:change_seq;->"` // position of the last write in the sync change feed
+}
+
+func (category *Category) TableName() string {
+	return "categories"
+}
+
+func (category *Category) PrepareCreate() {
+	category.Name = NormalizeName(category.Name)
+	category.Color = strings.ToUpper(strings.TrimSpace(category.Color))
+	category.Icon = strings.TrimSpace(category.Icon)
+	if category.Color == "" {
+		category.Color = DefaultColor
+	}
+	if category.Icon == "" {
+		category.Icon = DefaultIcon
+	}
+	if category.Kind == "" {
+		category.Kind = KindExpense
+	}
+}
+
+func (category *Category) PrepareUpdate(oldCategory *Category) {
+	if category.Name != "" {
+		oldCategory.Name = NormalizeName(category.Name)
+	}
+
+	if category.Color != "" {
+		oldCategory.Color = strings.ToUpper(strings.TrimSpace(category.Color))
+	}
+
+	if category.Icon != "" {
+		oldCategory.Icon = strings.TrimSpace(category.Icon)
+	}
+
+	if category.ParentId != nil {
+		oldCategory.ParentId = category.ParentId
+	}
+}
+
+// NormalizeName lowercases the name and collapses whitespaces,
+// so "Food", " food" and "FOOD " are the same category
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// MergeResult is the response of merging a category into another
+type MergeResult struct {
+	Target          *Category `json:"target"`
+	MergedId        uuid.UUID `json:"merged_id"`
+	ExpensesUpdated int64     `json:"expenses_updated"`
+	IncomesUpdated  int64     `json:"incomes_updated"`
+}
+
+// defaultCategory describes a seeded category and its sub categories
+type defaultCategory struct {
+	name     string
+	color    string
+	icon     string
+	kind     string
+	children []string
+}
+
+var defaults = []defaultCategory{
+	{name: "food", color: "#FF7043", icon: "utensils", children: []string{"groceries", "restaurants"}},
+	{name: "transport", color: "#42A5F5", icon: "car", children: []string{"fuel", "public transport"}},
+	{name: "housing", color: "#8D6E63", icon: "home", children: []string{"rent", "maintenance"}},
+	{name: "utilities", color: "#FFCA28", icon: "bolt", children: []string{"electricity", "water", "internet"}},
+	{name: "health", color: "#EF5350", icon: "heart"},
+	{name: "entertainment", color: "#AB47BC", icon: "film"},
+	{name: "shopping", color: "#EC407A", icon: "shopping-bag"},
+	{name: "travel", color: "#26A69A", icon: "plane"},
+	{name: "education", color: "#5C6BC0", icon: "book"},
+	{name: "other", color: DefaultColor, icon: DefaultIcon},
+	{name: "salary", color: "#66BB6A", icon: "briefcase", kind: KindIncome},
+	{name: "refunds", color: "#29B6F6", icon: "rotate-ccw", kind: KindIncome},
+	{name: "side income", color: "#FFA726", icon: "coins", kind: KindIncome},
+	{name: "other income", color: DefaultColor, icon: DefaultIcon, kind: KindIncome},
+}
+
+// DefaultCategories returns the categories seeded for a user without any category,
+// sub categories inherit the color, icon and kind of their parent
+func DefaultCategories(userId uuid.UUID) []Category {
+	var categories []Category
+	for _, d := range defaults {
+		kind := d.kind
+		if kind == "" {
+			kind = KindExpense
+		}
+
+		parentId := uuid.New()
+		categories = append(categories, Category{
+			Id:     parentId,
+			UserId: userId,
+			Name:   d.name,
+			Color:  d.color,
+			Icon:   d.icon,
+			Kind:   kind,
+		})
+		for _, child := range d.children {
+			categories = append(categories, Category{
+				Id:       uuid.New(),
+				UserId:   userId,
+				ParentId: &parentId,
+				Name:     child,
+				Color:    d.color,
+				Icon:     d.icon,
+				Kind:     kind,
+			})
+		}
+	}
+	return categories
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/category"
+	"github.com/mrizkisaputra/expenses-api/internal/category/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryPgRepository(db *gorm.DB) category.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (p *postgresRepository) Create(ctx context.Context, category *model.Category) error {
+	/**
+	SQL: INSERT INTO "categories" ("id_user","id_parent","name","color","icon","created_at","updated_at")
+	VALUES (?,?,?,?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(category).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Create")
+	}
+	return nil
+}
+
+func (p *postgresRepository) CreateMany(ctx context.Context, categories []model.Category) error {
+	/**
+	SQL: INSERT INTO "categories" ("id_user","id_parent","name","color","icon","created_at","updated_at","id")
+	VALUES (?,?,?,?,?,?,?,?),...
+	*/
+	DB := p.db.WithContext(ctx)
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&categories).Error
+	})
+	if err != nil {
+		return errors.Wrap(err, "postgresRepository.CreateMany")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Category, error) {
+	/**
+	SQL: SELECT * FROM "categories" WHERE id = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	category := new(model.Category)
+	if err := DB.Where("id = ? AND id_user = ?", id, userId).Take(category).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByIdAndUserId")
+	}
+	return category, nil
+}
+
+func (p *postgresRepository) FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Category, error) {
+	/**
+	SQL: SELECT * FROM "categories" WHERE name = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	category := new(model.Category)
+	if err := DB.Where("name = ? AND id_user = ?", name, userId).Take(category).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByNameAndUserId")
+	}
+	return category, nil
+}
+
+func (p *postgresRepository) FindAllByUserId(ctx context.Context, userId string) ([]model.Category, error) {
+	/**
+	SQL: SELECT * FROM "categories" WHERE id_user = ? ORDER BY name
+	*/
+	DB := p.db.WithContext(ctx)
+	var categories []model.Category
+	if err := DB.Where("id_user = ?", userId).Order("name").Find(&categories).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindAllByUserId")
+	}
+	return categories, nil
+}
+
+func (p *postgresRepository) CountByUserId(ctx context.Context, userId string) (int64, error) {
+	/**
+	SQL: SELECT count(*) FROM "categories" WHERE id_user = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	var total int64
+	if err := DB.Model(&model.Category{}).Where("id_user = ?", userId).Count(&total).Error; err != nil {
+		return 0, errors.Wrap(err, "postgresRepository.CountByUserId")
+	}
+	return total, nil
+}
+
+func (p *postgresRepository) Update(ctx context.Context, category *model.Category) error {
+	/**
+	SQL: UPDATE "categories" SET "id_user"=?,"id_parent"=?,"name"=?,"color"=?,"icon"=?,"updated_at"=?
+	WHERE (id = ? AND id_user = ?) AND "id" = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Select("*").Omit("id", "created_at").
+		Where("id = ? AND id_user = ?", category.Id, category.UserId).
+		Updates(category).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Update")
+	}
+	return nil
+}
+
+// Merge moves every expense and sub category of source to target then deletes source,
+// in a single transaction. it returns the number of expenses that were re-pointed
+func (p *postgresRepository) Merge(ctx context.Context, source, target *model.Category) (int64, error) {
+	var updated int64
+	DB := p.db.WithContext(ctx)
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		/**
+		SQL: UPDATE "categories" SET "id_parent"=?,"updated_at"=? WHERE id = ? AND id_user = ?
+		*/
+		if err := tx.Model(&model.Category{}).
+			Where("id = ? AND id_user = ?", target.Id, target.UserId).
+			Updates(map[string]interface{}{"id_parent": target.ParentId, "updated_at": time.Now().UnixMilli()}).Error; err != nil {
+			return errors.Wrap(err, "Target")
+		}
+
+		/**
+		SQL: UPDATE "categories" SET "id_parent"=?,"updated_at"=? WHERE id_parent = ? AND id <> ?
+		*/
+		if err := tx.Model(&model.Category{}).
+			Where("id_parent = ? AND id <> ?", source.Id, target.Id).
+			Updates(map[string]interface{}{"id_parent": target.Id, "updated_at": time.Now().UnixMilli()}).Error; err != nil {
+			return errors.Wrap(err, "Children")
+		}
+
+		/**
+		SQL: UPDATE expenses SET id_category = ?, updated_at = ? WHERE id_category = ?
+		or incomes for an income category, both kinds of category are never merged together.
+		soft deleted transactions are re-pointed too so they stay consistent if restored
+		*/
+		table := "expenses"
+		if source.Kind == model.KindIncome {
+			table = "incomes"
+		}
+		result := tx.Exec("UPDATE "+table+" SET id_category = ?, updated_at = ? WHERE id_category = ?",
+			target.Id, time.Now().Unix(), source.Id)
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "Transactions")
+		}
+		updated = result.RowsAffected
+
+		/**
+		SQL: DELETE FROM "categories" WHERE id = ? AND id_user = ?
+		*/
+		if err := tx.Where("id = ? AND id_user = ?", source.Id, source.UserId).
+			Delete(&model.Category{}).Error; err != nil {
+			return errors.Wrap(err, "Delete")
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "postgresRepository.Merge")
+	}
+	return updated, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/category"
+	"github.com/mrizkisaputra/expenses-api/internal/category/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	repository category.PostgresRepository
+	mock       sqlmock.Sqlmock
+)
+
+// Test Before and After
+func TestMain(m *testing.M) {
+	// initialize mock sql db
+	mockDb, mocking, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("Failed to open mock sql db, got error %v", err)
+	}
+	defer mockDb.Close()
+	mock = mocking
+
+	// initialize db connection
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDb}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database, got error %v", err)
+	}
+
+	// initialize repository
+	repository = NewCategoryPgRepository(db)
+
+	os.Exit(m.Run())
+}
+
+func TestCategoryPostgresRepository_FindAllByUserId(t *testing.T) {
+	userId := uuid.New()
+	parentId := uuid.New()
+
+	// define expect sql
+	expectedSQL := regexp.QuoteMeta(`SELECT * FROM "categories" WHERE id_user = $1 ORDER BY name`)
+	rows := sqlmock.NewRows([]string{"id", "id_user", "id_parent", "name", "color", "icon", "created_at", "updated_at"}).
+		AddRow(parentId, userId, nil, "food", "#FF7043", "utensils", time.Now().UnixMilli(), time.Now().UnixMilli()).
+		AddRow(uuid.New(), userId, parentId, "groceries", "#FF7043", "utensils", time.Now().UnixMilli(), time.Now().UnixMilli())
+	mock.ExpectQuery(expectedSQL).WithArgs(userId.String()).WillReturnRows(rows)
+
+	categories, err := repository.FindAllByUserId(context.Background(), userId.String())
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	require.Nil(t, categories[0].ParentId)
+	require.Equal(t, parentId, *categories[1].ParentId)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
+
+func TestCategoryPostgresRepository_Merge(t *testing.T) {
+	userId := uuid.New()
+	source := &model.Category{Id: uuid.New(), UserId: userId, Name: "foods"}
+	target := &model.Category{Id: uuid.New(), UserId: userId, Name: "food"}
+
+	// define expect sql
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "id_parent"=$1,"updated_at"=$2 WHERE id = $3 AND id_user = $4`)).
+		WithArgs(nil, sqlmock.AnyArg(), target.Id, userId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "id_parent"=$1,"updated_at"=$2 WHERE id_parent = $3 AND id <> $4`)).
+		WithArgs(target.Id, sqlmock.AnyArg(), source.Id, target.Id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE expenses SET id_category = $1, updated_at = $2 WHERE id_category = $3`)).
+		WithArgs(target.Id, sqlmock.AnyArg(), source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "categories" WHERE id = $1 AND id_user = $2`)).
+		WithArgs(source.Id, userId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	updated, err := repository.Merge(context.Background(), source, target)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), updated)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
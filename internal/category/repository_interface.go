@@ -0,0 +1,28 @@
+package category
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/category/model"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	Create(ctx context.Context, category *model.Category) error
+
+	CreateMany(ctx context.Context, categories []model.Category) error
+
+	FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Category, error)
+
+	FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Category, error)
+
+	FindAllByUserId(ctx context.Context, userId string) ([]model.Category, error)
+
+	CountByUserId(ctx context.Context, userId string) (int64, error)
+
+	Update(ctx context.Context, category *model.Category) error
+
+	// Merge moves the transactions and sub categories of source to target and deletes source,
+	// it returns the number of expenses (or incomes for an income category) moved
+	Merge(ctx context.Context, source, target *model.Category) (int64, error)
+}
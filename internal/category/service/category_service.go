@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/category"
+	"github.com/mrizkisaputra/expenses-api/internal/category/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type ServiceConfig struct {
+	PgRepo category.PostgresRepository
+	Config *config.Config
+	Logger *logrus.Logger
+}
+
+// categoryService acts as a struct for injecting an implementation of CategoryService interface
+// for use in service methods.
+type categoryService struct {
+	pgRepo category.PostgresRepository
+	logger *logrus.Logger
+}
+
+// NewCategoryService is a factory function for
+// initializing a categoryService with its repository layer dependencies
+func NewCategoryService(config *ServiceConfig) category.CategoryService {
+	return &categoryService{
+		pgRepo: config.PgRepo,
+		logger: config.Logger,
+	}
+}
+
+func (c *categoryService) Create(ctx context.Context, request *model.Category) (*model.Category, error) {
+	request.PrepareCreate()
+
+	if err := c.ensureUniqueName(ctx, request); err != nil {
+		return nil, err
+	}
+	if err := c.validateParent(ctx, request); err != nil {
+		return nil, err
+	}
+
+	if err := c.pgRepo.Create(ctx, request); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return request, nil
+}
+
+func (c *categoryService) Update(ctx context.Context, request *model.Category) (*model.Category, error) {
+	categories, err := c.pgRepo.FindByIdAndUserId(ctx, request.Id.String(), request.UserId.String())
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+
+	request.PrepareUpdate(categories)
+
+	if err := c.ensureUniqueName(ctx, categories); err != nil {
+		return nil, err
+	}
+	if err := c.validateParent(ctx, categories); err != nil {
+		return nil, err
+	}
+
+	if err := c.pgRepo.Update(ctx, categories); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return categories, nil
+}
+
+func (c *categoryService) GetById(ctx context.Context, id, userId string) (*model.Category, error) {
+	categories, err := c.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	return categories, nil
+}
+
+// GetAll returns every category of the user, a user without any category
+// gets the default categories seeded first
+func (c *categoryService) GetAll(ctx context.Context, userId string) ([]model.Category, error) {
+	if err := c.seedDefaults(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	categories, err := c.pgRepo.FindAllByUserId(ctx, userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return categories, nil
+}
+
+// Merge merges the source category into the target: transactions and sub categories of
+// source are moved to target and source is deleted, both must be of the same kind
+func (c *categoryService) Merge(ctx context.Context, userId, sourceId, targetId string) (*model.MergeResult, error) {
+	if sourceId == targetId {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidCategoryMergeMsg, nil)
+	}
+
+	source, err := c.pgRepo.FindByIdAndUserId(ctx, sourceId, userId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	target, err := c.pgRepo.FindByIdAndUserId(ctx, targetId, userId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	if source.Kind != target.Kind {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.CategoryKindMismatchMsg, nil)
+	}
+
+	// a target nested under source takes the place of source in the tree,
+	// otherwise re-parenting the children of source onto it would create a cycle
+	descendant, err := c.isDescendant(ctx, target, source.Id)
+	if err != nil {
+		return nil, err
+	}
+	if descendant {
+		target.ParentId = source.ParentId
+	}
+
+	updated, err := c.pgRepo.Merge(ctx, source, target)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	result := &model.MergeResult{Target: target, MergedId: source.Id}
+	if source.Kind == model.KindIncome {
+		result.IncomesUpdated = updated
+	} else {
+		result.ExpensesUpdated = updated
+	}
+	return result, nil
+}
+
+func (c *categoryService) seedDefaults(ctx context.Context, userId string) error {
+	total, err := c.pgRepo.CountByUserId(ctx, userId)
+	if err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	if total > 0 {
+		return nil
+	}
+
+	parsedId, err := uuid.Parse(userId)
+	if err != nil {
+		return httpErrors.NewInternalServerError(errors.Wrap(err, "categoryService.seedDefaults.uuid.Parse"))
+	}
+	if err := c.pgRepo.CreateMany(ctx, model.DefaultCategories(parsedId)); err != nil {
+		// a concurrent request may have seeded the defaults in the meantime
+		if total, countErr := c.pgRepo.CountByUserId(ctx, userId); countErr == nil && total > 0 {
+			return nil
+		}
+		return httpErrors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+func (c *categoryService) ensureUniqueName(ctx context.Context, request *model.Category) error {
+	found, err := c.pgRepo.FindByNameAndUserId(ctx, request.Name, request.UserId.String())
+	if err == nil && found != nil && found.Id != request.Id {
+		return httpErrors.NewError(http.StatusConflict, httpErrors.CategoryAlreadyExistsMsg, nil)
+	}
+	return nil
+}
+
+// validateParent ensures the parent belongs to the same user, is of the same kind and
+// is not the category itself or one of its descendants
+func (c *categoryService) validateParent(ctx context.Context, request *model.Category) error {
+	if request.ParentId == nil {
+		return nil
+	}
+
+	parent, err := c.pgRepo.FindByIdAndUserId(ctx, request.ParentId.String(), request.UserId.String())
+	if err != nil {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidCategoryParentMsg, err)
+	}
+	if parent.Kind != request.Kind {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.CategoryKindMismatchMsg, nil)
+	}
+	if request.Id == uuid.Nil {
+		return nil
+	}
+
+	cycle, err := c.isDescendant(ctx, parent, request.Id)
+	if err != nil {
+		return err
+	}
+	if cycle || parent.Id == request.Id {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidCategoryParentMsg, nil)
+	}
+	return nil
+}
+
+// isDescendant walks up the parents of category and reports whether ancestorId is one of them
+func (c *categoryService) isDescendant(ctx context.Context, category *model.Category, ancestorId uuid.UUID) (bool, error) {
+	visited := map[uuid.UUID]bool{category.Id: true}
+	for current := category; current.ParentId != nil; {
+		if *current.ParentId == ancestorId {
+			return true, nil
+		}
+		if visited[*current.ParentId] {
+			return false, nil
+		}
+		visited[*current.ParentId] = true
+
+		parent, err := c.pgRepo.FindByIdAndUserId(ctx, current.ParentId.String(), current.UserId.String())
+		if err != nil {
+			return false, httpErrors.NewInternalServerError(err)
+		}
+		current = parent
+	}
+	return false, nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/category/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/category/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+)
+
+func TestCategoryService_Create(t *testing.T) {
+	userId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Name is normalized and defaults are applied", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewCategoryService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindByNameAndUserId", mock.Anything, "eating out", userId.String()).Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		response, err := sv.Create(context.Background(), &model.Category{UserId: userId, Name: "  Eating   Out "})
+		require.NoError(t, err)
+		require.Equal(t, "eating out", response.Name)
+		require.Equal(t, model.DefaultColor, response.Color)
+		require.Equal(t, model.DefaultIcon, response.Icon)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return conflict when the name already exists", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewCategoryService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindByNameAndUserId", mock.Anything, "food", userId.String()).
+			Return(&model.Category{Id: uuid.New(), UserId: userId, Name: "food"}, nil)
+
+		_, err := sv.Create(context.Background(), &model.Category{UserId: userId, Name: "Food "})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusConflict, er.Status)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestCategoryService_Update(t *testing.T) {
+	userId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Should reject a descendant as parent", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewCategoryService(&ServiceConfig{PgRepo: mockRepo})
+
+		parent := &model.Category{Id: uuid.New(), UserId: userId, Name: "food"}
+		child := &model.Category{Id: uuid.New(), UserId: userId, ParentId: &parent.Id, Name: "groceries"}
+		mockRepo.On("FindByIdAndUserId", mock.Anything, parent.Id.String(), userId.String()).Return(parent, nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, child.Id.String(), userId.String()).Return(child, nil)
+		mockRepo.On("FindByNameAndUserId", mock.Anything, "food", userId.String()).Return(parent, nil)
+
+		_, err := sv.Update(context.Background(), &model.Category{Id: parent.Id, UserId: userId, ParentId: &child.Id})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		mockRepo.AssertNotCalled(t, "Update")
+	})
+}
+
+func TestCategoryService_GetAll(t *testing.T) {
+	userId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Defaults are seeded for a user without categories", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewCategoryService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("CountByUserId", mock.Anything, userId.String()).Return(int64(0), nil)
+		mockRepo.On("CreateMany", mock.Anything, mock.MatchedBy(func(categories []model.Category) bool {
+			for _, c := range categories {
+				if c.UserId != userId {
+					return false
+				}
+			}
+			return len(categories) > 0
+		})).Return(nil)
+		mockRepo.On("FindAllByUserId", mock.Anything, userId.String()).Return([]model.Category{}, nil)
+
+		_, err := sv.GetAll(context.Background(), userId.String())
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Existing categories are not seeded again", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewCategoryService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("CountByUserId", mock.Anything, userId.String()).Return(int64(4), nil)
+		mockRepo.On("FindAllByUserId", mock.Anything, userId.String()).Return([]model.Category{}, nil)
+
+		_, err := sv.GetAll(context.Background(), userId.String())
+		require.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "CreateMany")
+	})
+}
+
+func TestCategoryService_Merge(t *testing.T) {
+	userId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] A target nested under the source takes its place", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewCategoryService(&ServiceConfig{PgRepo: mockRepo})
+
+		root := uuid.New()
+		source := &model.Category{Id: uuid.New(), UserId: userId, ParentId: &root, Name: "food"}
+		target := &model.Category{Id: uuid.New(), UserId: userId, ParentId: &source.Id, Name: "groceries"}
+		mockRepo.On("FindByIdAndUserId", mock.Anything, source.Id.String(), userId.String()).Return(source, nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, target.Id.String(), userId.String()).Return(target, nil)
+		mockRepo.On("Merge", mock.Anything, source, mock.MatchedBy(func(c *model.Category) bool {
+			return c.Id == target.Id && *c.ParentId == root
+		})).Return(int64(5), nil)
+
+		result, err := sv.Merge(context.Background(), userId.String(), source.Id.String(), target.Id.String())
+		require.NoError(t, err)
+		require.Equal(t, int64(5), result.ExpensesUpdated)
+		require.Equal(t, source.Id, result.MergedId)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should reject merging a category into itself", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewCategoryService(&ServiceConfig{PgRepo: mockRepo})
+
+		id := uuid.New().String()
+		_, err := sv.Merge(context.Background(), userId.String(), id, id)
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		mockRepo.AssertNotCalled(t, "Merge")
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Should reject merging an income category into an expense category", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewCategoryService(&ServiceConfig{PgRepo: mockRepo})
+
+		source := &model.Category{Id: uuid.New(), UserId: userId, Name: "salary", Kind: model.KindIncome}
+		target := &model.Category{Id: uuid.New(), UserId: userId, Name: "other", Kind: model.KindExpense}
+		mockRepo.On("FindByIdAndUserId", mock.Anything, source.Id.String(), userId.String()).Return(source, nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, target.Id.String(), userId.String()).Return(target, nil)
+
+		_, err := sv.Merge(context.Background(), userId.String(), source.Id.String(), target.Id.String())
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.CategoryKindMismatchMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "Merge")
+	})
+
+	// scenario test case #4
+	t.Run("[Test Case #4] Incomes moved by an income merge are reported as incomes", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewCategoryService(&ServiceConfig{PgRepo: mockRepo})
+
+		source := &model.Category{Id: uuid.New(), UserId: userId, Name: "bonus", Kind: model.KindIncome}
+		target := &model.Category{Id: uuid.New(), UserId: userId, Name: "salary", Kind: model.KindIncome}
+		mockRepo.On("FindByIdAndUserId", mock.Anything, source.Id.String(), userId.String()).Return(source, nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, target.Id.String(), userId.String()).Return(target, nil)
+		mockRepo.On("Merge", mock.Anything, source, target).Return(int64(2), nil)
+
+		result, err := sv.Merge(context.Background(), userId.String(), source.Id.String(), target.Id.String())
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.IncomesUpdated)
+		require.Equal(t, int64(0), result.ExpensesUpdated)
+	})
+}
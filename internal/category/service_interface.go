@@ -0,0 +1,20 @@
+package category
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/category/model"
+)
+
+// CategoryService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type CategoryService interface {
+	Create(ctx context.Context, request *model.Category) (*model.Category, error)
+
+	Update(ctx context.Context, request *model.Category) (*model.Category, error)
+
+	GetById(ctx context.Context, id, userId string) (*model.Category, error)
+
+	GetAll(ctx context.Context, userId string) ([]model.Category, error)
+
+	Merge(ctx context.Context, userId, sourceId, targetId string) (*model.MergeResult, error)
+}
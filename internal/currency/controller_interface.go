@@ -0,0 +1,11 @@
+package currency
+
+import "github.com/gin-gonic/gin"
+
+// ExchangeRateController defines methods the routes expects
+// any controllers it interacts with to implement
+type ExchangeRateController interface {
+	ImportExchangeRates() gin.HandlerFunc
+
+	GetAllExchangeRate() gin.HandlerFunc
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/mrizkisaputra/expenses-api/internal/currency"
+	"github.com/mrizkisaputra/expenses-api/internal/currency/model"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"math"
+	"net/http"
+	"time"
+)
+
+type ControllerConfig struct {
+	ExchangeRateService currency.ExchangeRateService
+	Logger              *logrus.Logger
+}
+
+// exchangeRateController acts as a struct for injecting an implementation of ExchangeRateController interface
+// for use in controller methods
+type exchangeRateController struct {
+	exchangeRateService currency.ExchangeRateService
+	logger              *logrus.Logger
+}
+
+// NewExchangeRateController is a factory function
+// initializing a exchangeRateController with its service layer dependencies
+func NewExchangeRateController(config *ControllerConfig) currency.ExchangeRateController {
+	return &exchangeRateController{
+		exchangeRateService: config.ExchangeRateService,
+		logger:              config.Logger,
+	}
+}
+
+func (rc exchangeRateController) ImportExchangeRates() gin.HandlerFunc {
+	type ExchangeRateRequest struct {
+		Base  string     `json:"base" validate:"required,iso4217"`
+		Quote string     `json:"quote" validate:"required,iso4217"`
+		Rate  money.Rate `json:"rate"`
+		Date  string     `json:"date" validate:"required,datetime=2006-01-02"`
+	}
+	type ImportExchangeRatesRequest struct {
+		Rates []ExchangeRateRequest `json:"rates" validate:"required,min=1,max=1000,dive"`
+	}
+
+	return func(ctx *gin.Context) {
+		request := new(ImportExchangeRatesRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		rates := make([]model.ExchangeRate, len(request.Rates))
+		for i, r := range request.Rates {
+			date, _ := time.Parse("2006-01-02", r.Date) // already validated
+			rates[i] = model.ExchangeRate{
+				BaseCurrency:  r.Base,
+				QuoteCurrency: r.Quote,
+				Rate:          r.Rate,
+				RateDate:      date,
+			}
+		}
+
+		total, err := rc.exchangeRateService.Import(ctx, rates)
+		if err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, &expenseModel.ApiResponse{
+			Status:     http.StatusCreated,
+			Message:    "Created",
+			Data:       rates,
+			TotalItems: int64(total),
+		})
+	}
+}
+
+func (rc exchangeRateController) GetAllExchangeRate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		request := new(model.ExchangeRateQueryParam)
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if request.Page == 0 {
+			request.Page = 1
+		}
+		if request.Limit == 0 {
+			request.Limit = 10
+		}
+
+		rates, total, err := rc.exchangeRateService.GetAll(ctx, request)
+		if err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       rates,
+			Page:       request.Page,
+			Limit:      request.Limit,
+			TotalItems: total,
+			TotalPages: int64(math.Ceil(float64(total) / float64(request.Limit))),
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/currency"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+)
+
+// MapExchangeRateRoutes is a function routes, importing rates is reserved for admins
+func MapExchangeRateRoutes(rateGroup *gin.RouterGroup, controller currency.ExchangeRateController, mw *middleware.MiddlewareManager) {
+	rateGroup.Use(mw.AuthJwtMiddleware())
+	rateGroup.GET("/", controller.GetAllExchangeRate())
+	rateGroup.POST("/", mw.AdminMiddleware(), controller.ImportExchangeRates())
+}
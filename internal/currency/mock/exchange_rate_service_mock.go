@@ -0,0 +1,37 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/currency/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+// ExchangeRateServiceMock is a mock type for currency.ExchangeRateService
+type ExchangeRateServiceMock struct {
+	mock.Mock
+}
+
+func (m *ExchangeRateServiceMock) Import(ctx context.Context, rates []model.ExchangeRate) (int, error) {
+	args := m.Called(ctx, rates)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ExchangeRateServiceMock) LoadFile(ctx context.Context, path string) (int, error) {
+	args := m.Called(ctx, path)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ExchangeRateServiceMock) Convert(ctx context.Context, amount money.Amount, from, to string, on time.Time) (money.Amount, error) {
+	args := m.Called(ctx, amount, from, to, on)
+	return args.Get(0).(money.Amount), args.Error(1)
+}
+
+func (m *ExchangeRateServiceMock) GetAll(ctx context.Context, request *model.ExchangeRateQueryParam) ([]model.ExchangeRate, int64, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.ExchangeRate), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
@@ -0,0 +1,34 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/currency/model"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+// MockPostgresRepository is a mock type for currency.PostgresRepository
+type MockPostgresRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostgresRepository) UpsertMany(ctx context.Context, rates []model.ExchangeRate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindLatest(ctx context.Context, from, to string, on time.Time) (*model.ExchangeRate, error) {
+	args := m.Called(ctx, from, to, on)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.ExchangeRate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindAll(ctx context.Context, base, quote string, offset, limit int) ([]model.ExchangeRate, int64, error) {
+	args := m.Called(ctx, base, quote, offset, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.ExchangeRate), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
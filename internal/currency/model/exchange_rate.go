@@ -0,0 +1,64 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var ErrExchangeRateNotFound = errors.New("no exchange rate known for the currency pair")
+
+// ExchangeRate mapping table 'exchange_rates'.
+// 1 unit of BaseCurrency is worth Rate units of QuoteCurrency on RateDate
+type ExchangeRate struct {
+	Id            uuid.UUID  `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	BaseCurrency  string     `json:"base" gorm:"column:base_currency"`
+	QuoteCurrency string     `json:"quote" gorm:"column:quote_currency"`
+	Rate          money.Rate `json:"rate" gorm:"column:rate"`
+	RateDate      time.Time  `json:"date" gorm:"column:rate_date;type:date"`
+	CreatedAt     int64      `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt     int64      `json:"updated_at" gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (rate *ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
+
+func (rate *ExchangeRate) PrepareCreate() {
+	rate.BaseCurrency = strings.ToUpper(strings.TrimSpace(rate.BaseCurrency))
+	rate.QuoteCurrency = strings.ToUpper(strings.TrimSpace(rate.QuoteCurrency))
+	rate.RateDate = Day(rate.RateDate)
+}
+
+// ConversionFactor returns the exact number to multiply an amount in from by to get
+// the amount in to, the rate may be stored in either direction
+func (rate *ExchangeRate) ConversionFactor(from, to string) (*big.Rat, bool) {
+	if rate.Rate.IsZero() {
+		return nil, false
+	}
+	factor := rate.Rate.Rat()
+
+	switch {
+	case rate.BaseCurrency == from && rate.QuoteCurrency == to:
+		return factor, true
+	case rate.BaseCurrency == to && rate.QuoteCurrency == from:
+		return factor.Inv(factor), true
+	default:
+		return nil, false
+	}
+}
+
+// Day truncates t to midnight UTC, exchange rates are daily
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+type ExchangeRateQueryParam struct {
+	Page  int    `form:"page" validate:"omitempty,numeric,min=1"`
+	Limit int    `form:"limit" validate:"omitempty,numeric,min=10"`
+	Base  string `form:"base" validate:"omitempty,iso4217"`
+	Quote string `form:"quote" validate:"omitempty,iso4217"`
+}
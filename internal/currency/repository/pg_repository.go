@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/currency"
+	"github.com/mrizkisaputra/expenses-api/internal/currency/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRatePgRepository(db *gorm.DB) currency.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (p *postgresRepository) UpsertMany(ctx context.Context, rates []model.ExchangeRate) error {
+	/**
+	SQL: INSERT INTO "exchange_rates" ("base_currency","quote_currency","rate","rate_date","created_at","updated_at")
+	VALUES (?,?,?,?,?,?),... ON CONFLICT ("base_currency","quote_currency","rate_date")
+	DO UPDATE SET "rate"="excluded"."rate","updated_at"="excluded"."updated_at" RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	err := DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}, {Name: "rate_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(&rates).Error
+	if err != nil {
+		return errors.Wrap(err, "postgresRepository.UpsertMany")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindLatest(ctx context.Context, from, to string, on time.Time) (*model.ExchangeRate, error) {
+	/**
+	SQL: SELECT * FROM "exchange_rates"
+	WHERE ((base_currency = ? AND quote_currency = ?) OR (base_currency = ? AND quote_currency = ?)) AND rate_date <= ?
+	ORDER BY rate_date DESC LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	rate := new(model.ExchangeRate)
+	if err := DB.Where("((base_currency = ? AND quote_currency = ?) OR (base_currency = ? AND quote_currency = ?)) AND rate_date <= ?",
+		from, to, to, from, on).
+		Order("rate_date DESC").
+		Take(rate).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindLatest")
+	}
+	return rate, nil
+}
+
+func (p *postgresRepository) FindAll(ctx context.Context, base, quote string, offset, limit int) ([]model.ExchangeRate, int64, error) {
+	DB := p.db.WithContext(ctx).Model(&model.ExchangeRate{})
+	if base != "" {
+		DB = DB.Where("base_currency = ?", base)
+	}
+	if quote != "" {
+		DB = DB.Where("quote_currency = ?", quote)
+	}
+
+	/**
+	SQL: SELECT count(*) FROM "exchange_rates" WHERE base_currency = ? AND quote_currency = ?
+	*/
+	var total int64
+	if err := DB.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindAll.Count")
+	}
+
+	/**
+	SQL: SELECT * FROM "exchange_rates" WHERE base_currency = ? AND quote_currency = ? ORDER BY rate_date DESC LIMIT ? OFFSET ?
+	*/
+	var rates []model.ExchangeRate
+	if err := DB.Order("rate_date DESC").
+		Order("base_currency").
+		Order("quote_currency").
+		Offset((offset - 1) * limit).
+		Limit(limit).
+		Find(&rates).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindAll.Find")
+	}
+
+	return rates, total, nil
+}
@@ -0,0 +1,17 @@
+package currency
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/currency/model"
+	"time"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	UpsertMany(ctx context.Context, rates []model.ExchangeRate) error
+
+	FindLatest(ctx context.Context, from, to string, on time.Time) (*model.ExchangeRate, error)
+
+	FindAll(ctx context.Context, base, quote string, offset, limit int) ([]model.ExchangeRate, int64, error)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/currency"
+	"github.com/mrizkisaputra/expenses-api/internal/currency/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type ServiceConfig struct {
+	PgRepo currency.PostgresRepository
+	Config *config.Config
+	Logger *logrus.Logger
+}
+
+// exchangeRateService acts as a struct for injecting an implementation of ExchangeRateService interface
+// for use in service methods.
+type exchangeRateService struct {
+	pgRepo currency.PostgresRepository
+}
+
+// NewExchangeRateService is a factory function for
+// initializing a exchangeRateService with its repository layer dependencies
+func NewExchangeRateService(config *ServiceConfig) currency.ExchangeRateService {
+	return &exchangeRateService{
+		pgRepo: config.PgRepo,
+	}
+}
+
+// rateFileRecord is the shape of one exchange rate in a json rates file
+type rateFileRecord struct {
+	Base  string     `json:"base"`
+	Quote string     `json:"quote"`
+	Rate  money.Rate `json:"rate"`
+	Date  string     `json:"date"`
+}
+
+func (e *exchangeRateService) Import(ctx context.Context, rates []model.ExchangeRate) (int, error) {
+	if len(rates) == 0 {
+		return 0, nil
+	}
+
+	for i := range rates {
+		rates[i].PrepareCreate()
+		if err := validateRate(&rates[i]); err != nil {
+			return 0, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidExchangeRateMsg, err)
+		}
+	}
+
+	if err := e.pgRepo.UpsertMany(ctx, rates); err != nil {
+		return 0, httpErrors.NewInternalServerError(err)
+	}
+	return len(rates), nil
+}
+
+// LoadFile imports the exchange rates of a local file, the format is taken from
+// the extension: '.csv' (base,quote,rate,date with an optional header) or '.json'
+func (e *exchangeRateService) LoadFile(ctx context.Context, path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, errors.Wrap(err, "exchangeRateService.LoadFile.Open")
+	}
+	defer file.Close()
+
+	var records []rateFileRecord
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		records, err = readCSV(file)
+	case ".json":
+		err = json.NewDecoder(file).Decode(&records)
+	default:
+		err = errors.Errorf("unsupported exchange rates file '%s'", path)
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "exchangeRateService.LoadFile.Decode")
+	}
+
+	rates := make([]model.ExchangeRate, len(records))
+	for i, record := range records {
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(record.Date))
+		if err != nil {
+			return 0, errors.Wrapf(err, "exchangeRateService.LoadFile: invalid date on record %d", i+1)
+		}
+		rates[i] = model.ExchangeRate{
+			BaseCurrency:  record.Base,
+			QuoteCurrency: record.Quote,
+			Rate:          record.Rate,
+			RateDate:      date,
+		}
+	}
+
+	return e.Import(ctx, rates)
+}
+
+// Convert converts amount from one currency to another with the most recent rate known
+// on the given day, the result is rounded half away from zero to the minor unit of the currency
+func (e *exchangeRateService) Convert(ctx context.Context, amount money.Amount, from, to string, on time.Time) (money.Amount, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	rate, err := e.pgRepo.FindLatest(ctx, from, to, model.Day(on))
+	if err != nil {
+		return 0, errors.Wrap(model.ErrExchangeRateNotFound, err.Error())
+	}
+
+	factor, ok := rate.ConversionFactor(from, to)
+	if !ok {
+		return 0, model.ErrExchangeRateNotFound
+	}
+	return amount.Mul(factor).Round(to), nil
+}
+
+func (e *exchangeRateService) GetAll(ctx context.Context, request *model.ExchangeRateQueryParam) ([]model.ExchangeRate, int64, error) {
+	rates, total, err := e.pgRepo.FindAll(
+		ctx,
+		strings.ToUpper(request.Base),
+		strings.ToUpper(request.Quote),
+		request.Page,
+		request.Limit,
+	)
+	if err != nil {
+		return nil, 0, httpErrors.NewInternalServerError(err)
+	}
+	return rates, total, nil
+}
+
+func validateRate(rate *model.ExchangeRate) error {
+	if err := validator.Validate.Var(rate.BaseCurrency, "required,iso4217"); err != nil {
+		return errors.Wrap(err, "base")
+	}
+	if err := validator.Validate.Var(rate.QuoteCurrency, "required,iso4217"); err != nil {
+		return errors.Wrap(err, "quote")
+	}
+	if rate.BaseCurrency == rate.QuoteCurrency {
+		return errors.New("base and quote currency must differ")
+	}
+	if rate.Rate.IsZero() {
+		return errors.New("rate must be greater than zero")
+	}
+	if rate.RateDate.IsZero() {
+		return errors.New("date is required")
+	}
+	return nil
+}
+
+func readCSV(reader io.Reader) ([]rateFileRecord, error) {
+	rows, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var records []rateFileRecord
+	for i, row := range rows {
+		if len(row) != 4 {
+			return nil, errors.Errorf("line %d: expected 4 columns (base,quote,rate,date)", i+1)
+		}
+		if i == 0 && strings.EqualFold(strings.TrimSpace(row[0]), "base") {
+			continue // header
+		}
+		rate, err := money.ParseRate(row[2])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", i+1)
+		}
+		records = append(records, rateFileRecord{
+			Base:  row[0],
+			Quote: row[1],
+			Rate:  rate,
+			Date:  row[3],
+		})
+	}
+	return records, nil
+}
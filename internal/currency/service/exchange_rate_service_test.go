@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/currency/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/currency/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExchangeRateService_Convert(t *testing.T) {
+	on := time.Date(2026, 3, 14, 18, 30, 0, 0, time.UTC)
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	usdIdr := &model.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: money.MustParseRate("16000"), RateDate: day}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Same currency is returned unchanged", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExchangeRateService(&ServiceConfig{PgRepo: mockRepo})
+
+		converted, err := sv.Convert(context.Background(), money.Amount(1250), "USD", "USD", on)
+		require.NoError(t, err)
+		require.Equal(t, money.Amount(1250), converted)
+		mockRepo.AssertNotCalled(t, "FindLatest")
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Rate stored in the same direction", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExchangeRateService(&ServiceConfig{PgRepo: mockRepo})
+		mockRepo.On("FindLatest", mock.Anything, "USD", "IDR", day).Return(usdIdr, nil)
+
+		converted, err := sv.Convert(context.Background(), money.Amount(250), "USD", "IDR", on)
+		require.NoError(t, err)
+		require.Equal(t, money.Amount(4000000), converted)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Rate stored in the inverse direction", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExchangeRateService(&ServiceConfig{PgRepo: mockRepo})
+		mockRepo.On("FindLatest", mock.Anything, "IDR", "USD", day).Return(usdIdr, nil)
+
+		converted, err := sv.Convert(context.Background(), money.Amount(5000000), "IDR", "USD", on)
+		require.NoError(t, err)
+		require.Equal(t, money.Amount(313), converted)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #4
+	t.Run("[Test Case #4] Unknown pair returns ErrExchangeRateNotFound", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExchangeRateService(&ServiceConfig{PgRepo: mockRepo})
+		mockRepo.On("FindLatest", mock.Anything, "EUR", "IDR", day).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := sv.Convert(context.Background(), money.Amount(1000), "EUR", "IDR", on)
+		require.ErrorIs(t, err, model.ErrExchangeRateNotFound)
+	})
+
+	// scenario test case #5
+	t.Run("[Test Case #5] The result is rounded to the minor unit of the target currency", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExchangeRateService(&ServiceConfig{PgRepo: mockRepo})
+		usdJpy := &model.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "JPY", Rate: money.MustParseRate("150.5"), RateDate: day}
+		mockRepo.On("FindLatest", mock.Anything, "USD", "JPY", day).Return(usdJpy, nil)
+
+		// 12.34 USD is 1857.17 JPY, a yen has no decimals
+		converted, err := sv.Convert(context.Background(), money.Amount(1234), "USD", "JPY", on)
+		require.NoError(t, err)
+		require.Equal(t, money.Amount(185700), converted)
+	})
+}
+
+func TestExchangeRateService_LoadFile(t *testing.T) {
+	// scenario test case #1
+	t.Run("[Test Case #1] Successfully load a csv file with header", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExchangeRateService(&ServiceConfig{PgRepo: mockRepo})
+
+		path := filepath.Join(t.TempDir(), "rates.csv")
+		content := "base,quote,rate,date\nusd,IDR,16000,2026-03-14\nEUR,IDR,17500.5,2026-03-14\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		mockRepo.On("UpsertMany", mock.Anything, mock.MatchedBy(func(rates []model.ExchangeRate) bool {
+			return len(rates) == 2 && rates[0].BaseCurrency == "USD" && rates[1].Rate.String() == "17500.5"
+		})).Return(nil)
+
+		total, err := sv.LoadFile(context.Background(), path)
+		require.NoError(t, err)
+		require.Equal(t, 2, total)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Successfully load a json file", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExchangeRateService(&ServiceConfig{PgRepo: mockRepo})
+
+		path := filepath.Join(t.TempDir(), "rates.json")
+		content := `[{"base":"USD","quote":"EUR","rate":0.92,"date":"2026-03-14"}]`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		mockRepo.On("UpsertMany", mock.Anything, mock.Anything).Return(nil)
+
+		total, err := sv.LoadFile(context.Background(), path)
+		require.NoError(t, err)
+		require.Equal(t, 1, total)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Should return bad request for an invalid currency", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExchangeRateService(&ServiceConfig{PgRepo: mockRepo})
+
+		path := filepath.Join(t.TempDir(), "rates.csv")
+		require.NoError(t, os.WriteFile(path, []byte("USD,XYZ,1.5,2026-03-14\n"), 0o600))
+
+		_, err := sv.LoadFile(context.Background(), path)
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		mockRepo.AssertNotCalled(t, "UpsertMany")
+	})
+}
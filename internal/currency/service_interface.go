@@ -0,0 +1,20 @@
+package currency
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/currency/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"time"
+)
+
+// ExchangeRateService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type ExchangeRateService interface {
+	Import(ctx context.Context, rates []model.ExchangeRate) (int, error)
+
+	LoadFile(ctx context.Context, path string) (int, error)
+
+	Convert(ctx context.Context, amount money.Amount, from, to string, on time.Time) (money.Amount, error)
+
+	GetAll(ctx context.Context, request *model.ExchangeRateQueryParam) ([]model.ExchangeRate, int64, error)
+}
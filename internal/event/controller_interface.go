@@ -0,0 +1,9 @@
+package event
+
+import "github.com/gin-gonic/gin"
+
+// EventController defines methods the routes expects
+// any controllers it interacts with to implement
+type EventController interface {
+	StreamEvents() gin.HandlerFunc
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/event"
+	"github.com/mrizkisaputra/expenses-api/internal/event/model"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/pkg/contextutils"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// defaultHeartbeat is the time between two heartbeats without configured heartbeat
+	defaultHeartbeat = 15 * time.Second
+
+	// retry is the milliseconds a client waits before reconnecting a lost stream
+	retry = 3000
+)
+
+type ControllerConfig struct {
+	EventService event.EventService
+	Config       *config.Config
+	Logger       *logrus.Logger
+}
+
+// eventController acts as a struct for injecting an implementation of EventController interface
+// for use in controller methods
+type eventController struct {
+	eventService event.EventService
+	heartbeat    time.Duration
+	logger       *logrus.Logger
+}
+
+// NewEventController is a factory function
+// initializing a eventController with its service layer dependencies
+func NewEventController(config *ControllerConfig) event.EventController {
+	heartbeat := defaultHeartbeat
+	if config.Config != nil && config.Config.Events.Heartbeat > 0 {
+		heartbeat = config.Config.Events.Heartbeat * time.Second
+	}
+	return &eventController{
+		eventService: config.EventService,
+		heartbeat:    heartbeat,
+		logger:       config.Logger,
+	}
+}
+
+// StreamEvents streams the events of the user as server-sent events until the client leaves. a client
+// reconnecting with the header Last-Event-ID (or the query last_event_id) first gets the events it missed.
+// a comment is sent every heartbeat so proxies keep the connection open
+func (ec eventController) StreamEvents() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		lastEventId := ctx.GetHeader("Last-Event-ID")
+		if lastEventId == "" {
+			lastEventId = ctx.Query("last_event_id")
+		}
+
+		// the stream ends when the client leaves or the server shuts down
+		streamCtx, cancel := contextutils.UntilShutdown(ctx.Request.Context())
+		defer cancel()
+
+		events, err := ec.eventService.Stream(streamCtx, auth.Id.String(), lastEventId)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		// the stream lasts longer than the write timeout of the server
+		if err := http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{}); err != nil {
+			ec.logger.WithError(err).Warn("eventController.StreamEvents.SetWriteDeadline")
+		}
+
+		ctx.Header("Content-Type", "text/event-stream")
+		ctx.Header("Cache-Control", "no-cache")
+		ctx.Header("Connection", "keep-alive")
+		ctx.Header("X-Accel-Buffering", "no")
+		ctx.Status(http.StatusOK)
+		fmt.Fprintf(ctx.Writer, "retry: %d\n\n", retry)
+		ctx.Writer.Flush()
+
+		heartbeat := time.NewTicker(ec.heartbeat)
+		defer heartbeat.Stop()
+
+		ctx.Stream(func(w io.Writer) bool {
+			select {
+			case sent, ok := <-events:
+				if !ok {
+					return false
+				}
+				writeEvent(w, sent)
+				return true
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+				return true
+			}
+		})
+	}
+}
+
+// writeEvent writes an event in the text/event-stream format, the data is JSON on a single line
+func writeEvent(w io.Writer, sent model.Event) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", sent.Id, sent.Type, sent.Data)
+}
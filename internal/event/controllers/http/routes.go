@@ -0,0 +1,12 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/event"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+)
+
+func MapEventRoutes(eventGroup *gin.RouterGroup, controller event.EventController, mw *middleware.MiddlewareManager) {
+	eventGroup.Use(mw.AuthJwtMiddleware())
+	eventGroup.GET("/", controller.StreamEvents())
+}
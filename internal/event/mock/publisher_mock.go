@@ -0,0 +1,17 @@
+package mock
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// PublisherMock is a mock type for event.Publisher
+type PublisherMock struct {
+	mock.Mock
+}
+
+func (m *PublisherMock) Publish(ctx context.Context, userId uuid.UUID, eventType string, data interface{}) error {
+	args := m.Called(ctx, userId, eventType, data)
+	return args.Error(0)
+}
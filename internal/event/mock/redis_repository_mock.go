@@ -0,0 +1,33 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/event/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRedisRepository is a mock type for event.RedisRepository
+type MockRedisRepository struct {
+	mock.Mock
+}
+
+func (m *MockRedisRepository) Publish(ctx context.Context, event *model.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockRedisRepository) FindAfter(ctx context.Context, userId, afterId string, limit int64) ([]model.Event, error) {
+	args := m.Called(ctx, userId, afterId, limit)
+	if events, ok := args.Get(0).([]model.Event); ok {
+		return events, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRedisRepository) Subscribe(ctx context.Context, userId string) (<-chan model.Event, error) {
+	args := m.Called(ctx, userId)
+	if events, ok := args.Get(0).(chan model.Event); ok {
+		return events, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
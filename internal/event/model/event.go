@@ -0,0 +1,62 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"strconv"
+	"strings"
+)
+
+// types of event pushed to the connected clients
+const (
+	TypeExpenseCreated      = "expense.created"
+	TypeExpenseUpdated      = "expense.updated"
+	TypeExpenseDeleted      = "expense.deleted"
+	TypeNotificationCreated = "notification.created"
+)
+
+// Event is a change pushed to every connected client of a user. Id is given when the event is
+// kept in the history of the user, ids grow with time so a client resumes after the last id it saw
+type Event struct {
+	Id     string          `json:"id"`
+	UserId uuid.UUID       `json:"-"`
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Deleted is the data of an event about a deleted resource
+type Deleted struct {
+	Id uuid.UUID `json:"id"`
+}
+
+// ValidId reports whether id is an event id, "<unix milliseconds>-<sequence>"
+func ValidId(id string) bool {
+	_, _, ok := parseId(id)
+	return ok
+}
+
+// IsAfter reports whether the event id a was published after the event id b
+func IsAfter(a, b string) bool {
+	aTime, aSequence, aOk := parseId(a)
+	bTime, bSequence, bOk := parseId(b)
+	if !aOk || !bOk {
+		return true
+	}
+	return aTime > bTime || (aTime == bTime && aSequence > bSequence)
+}
+
+func parseId(id string) (uint64, uint64, bool) {
+	milliseconds, sequence, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+	time, err := strconv.ParseUint(milliseconds, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	counter, err := strconv.ParseUint(sequence, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return time, counter, true
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/mrizkisaputra/expenses-api/internal/event"
+	"github.com/mrizkisaputra/expenses-api/internal/event/model"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+const (
+	// defaultHistory is the number of past events kept per user without configured history
+	defaultHistory = 1000
+
+	// historyTTL is how long the history of a user without new event is kept
+	historyTTL = 24 * time.Hour
+)
+
+// eventRedisRepository keeps the history of the events of a user in a redis stream, the stream ids
+// are the event ids, and fans them out through a pub/sub channel per user
+type eventRedisRepository struct {
+	redisClient *redis.Client
+	history     int64
+}
+
+// NewEventRedisRepository is a factory for initializing Event Redis Repository,
+// history is the number of past events kept per user
+func NewEventRedisRepository(redisClient *redis.Client, history int64) event.RedisRepository {
+	if history <= 0 {
+		history = defaultHistory
+	}
+	return &eventRedisRepository{redisClient: redisClient, history: history}
+}
+
+func (r *eventRedisRepository) Publish(ctx context.Context, event *model.Event) error {
+	stream := historyKey(event.UserId.String())
+	id, err := r.redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: r.history,
+		Approx: true,
+		Values: map[string]interface{}{"type": event.Type, "data": string(event.Data)},
+	}).Result()
+	if err != nil {
+		return errors.Wrap(err, "EventRedisRepository.Publish.redisClient.XAdd")
+	}
+	event.Id = id
+
+	if err := r.redisClient.Expire(ctx, stream, historyTTL).Err(); err != nil {
+		return errors.Wrap(err, "EventRedisRepository.Publish.redisClient.Expire")
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "EventRedisRepository.Publish.json.Marshal")
+	}
+	if err := r.redisClient.Publish(ctx, channelKey(event.UserId.String()), payload).Err(); err != nil {
+		return errors.Wrap(err, "EventRedisRepository.Publish.redisClient.Publish")
+	}
+	return nil
+}
+
+func (r *eventRedisRepository) FindAfter(ctx context.Context, userId, afterId string, limit int64) ([]model.Event, error) {
+	// the range starts at afterId itself, it is skipped below
+	messages, err := r.redisClient.XRangeN(ctx, historyKey(userId), afterId, "+", limit+1).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "EventRedisRepository.FindAfter.redisClient.XRangeN")
+	}
+
+	events := make([]model.Event, 0, len(messages))
+	for _, message := range messages {
+		if message.ID == afterId {
+			continue
+		}
+		eventType, _ := message.Values["type"].(string)
+		data, _ := message.Values["data"].(string)
+		events = append(events, model.Event{Id: message.ID, Type: eventType, Data: json.RawMessage(data)})
+	}
+	if int64(len(events)) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (r *eventRedisRepository) Subscribe(ctx context.Context, userId string) (<-chan model.Event, error) {
+	pubsub := r.redisClient.Subscribe(ctx, channelKey(userId))
+	// wait for the subscription so no event published from now on is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, errors.Wrap(err, "EventRedisRepository.Subscribe.pubsub.Receive")
+	}
+
+	events := make(chan model.Event)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				var received model.Event
+				if err := json.Unmarshal([]byte(message.Payload), &received); err != nil {
+					continue
+				}
+				select {
+				case events <- received:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+func historyKey(userId string) string {
+	return "events:history:" + userId
+}
+
+func channelKey(userId string) string {
+	return "events:" + userId
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/event"
+	"github.com/mrizkisaputra/expenses-api/internal/event/model"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"log"
+	"testing"
+	"time"
+)
+
+func SetupRedis() event.RedisRepository {
+	server, err := miniredis.Run()
+	if err != nil {
+		log.Fatalf("could not connet to redis server, got error %v", err)
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: server.Addr(),
+	})
+
+	return NewEventRedisRepository(redisClient, 10)
+}
+
+func TestEventRedisRepository_FindAfter(t *testing.T) {
+	repo := SetupRedis()
+	userId := uuid.New()
+
+	var ids []string
+	for _, eventType := range []string{model.TypeExpenseCreated, model.TypeExpenseUpdated, model.TypeExpenseDeleted} {
+		published := &model.Event{UserId: userId, Type: eventType, Data: json.RawMessage(`{"id":"1"}`)}
+		require.NoError(t, repo.Publish(context.Background(), published))
+		require.True(t, model.ValidId(published.Id))
+		ids = append(ids, published.Id)
+	}
+
+	events, err := repo.FindAfter(context.Background(), userId.String(), ids[0], 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, ids[1], events[0].Id)
+	require.Equal(t, model.TypeExpenseUpdated, events[0].Type)
+	require.JSONEq(t, `{"id":"1"}`, string(events[0].Data))
+	require.Equal(t, ids[2], events[1].Id)
+
+	events, err = repo.FindAfter(context.Background(), userId.String(), ids[0], 1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	events, err = repo.FindAfter(context.Background(), uuid.NewString(), ids[0], 10)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestEventRedisRepository_Subscribe(t *testing.T) {
+	repo := SetupRedis()
+	userId := uuid.New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := repo.Subscribe(ctx, userId.String())
+	require.NoError(t, err)
+
+	published := &model.Event{UserId: userId, Type: model.TypeExpenseCreated, Data: json.RawMessage(`{}`)}
+	require.NoError(t, repo.Publish(context.Background(), published))
+
+	select {
+	case received := <-events:
+		require.Equal(t, published.Id, received.Id)
+		require.Equal(t, model.TypeExpenseCreated, received.Type)
+	case <-time.After(time.Second * 2):
+		t.Fatal("event not received")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		require.False(t, ok)
+	case <-time.After(time.Second * 2):
+		t.Fatal("channel not closed")
+	}
+}
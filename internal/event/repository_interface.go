@@ -0,0 +1,20 @@
+package event
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/event/model"
+)
+
+// RedisRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type RedisRepository interface {
+	// Publish appends the event to the history of its user, which gives the event its id,
+	// and publishes it to the API instances streaming to the user
+	Publish(ctx context.Context, event *model.Event) error
+
+	// FindAfter reads at most limit events of the history of the user published after the event afterId, oldest first
+	FindAfter(ctx context.Context, userId, afterId string, limit int64) ([]model.Event, error)
+
+	// Subscribe listens to the events published to the user, the channel is closed once ctx is done
+	Subscribe(ctx context.Context, userId string) (<-chan model.Event, error)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/event"
+	"github.com/mrizkisaputra/expenses-api/internal/event/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultReplay is the number of missed events sent at most when a stream resumes
+const defaultReplay = 1000
+
+type ServiceConfig struct {
+	RedisRepo event.RedisRepository
+	Config    *config.Config
+	Logger    *logrus.Logger
+}
+
+// eventService acts as a struct for injecting an implementation of EventService interface
+// for use in service methods.
+type eventService struct {
+	redisRepo event.RedisRepository
+	replay    int64
+	logger    *logrus.Logger
+}
+
+// NewEventService is a factory function for
+// initializing a eventService with its repository layer dependencies
+func NewEventService(config *ServiceConfig) event.EventService {
+	replay := int64(defaultReplay)
+	if config.Config != nil && config.Config.Events.History > 0 {
+		replay = config.Config.Events.History
+	}
+	return &eventService{
+		redisRepo: config.RedisRepo,
+		replay:    replay,
+		logger:    config.Logger,
+	}
+}
+
+func (e *eventService) Publish(ctx context.Context, userId uuid.UUID, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return httpErrors.NewInternalServerError(errors.Wrap(err, "eventService.Publish.json.Marshal"))
+	}
+
+	request := &model.Event{UserId: userId, Type: eventType, Data: payload}
+	if err := e.redisRepo.Publish(ctx, request); err != nil {
+		return httpErrors.NewInternalServerError(errors.Wrap(err, "eventService.Publish.Publish"))
+	}
+	return nil
+}
+
+// Stream subscribes before reading the missed events so none is lost in between, the events published
+// meanwhile come twice, live events not after the last sent one are dropped
+func (e *eventService) Stream(ctx context.Context, userId, lastEventId string) (<-chan model.Event, error) {
+	live, err := e.redisRepo.Subscribe(ctx, userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "eventService.Stream.Subscribe"))
+	}
+
+	var missed []model.Event
+	if model.ValidId(lastEventId) {
+		missed, err = e.redisRepo.FindAfter(ctx, userId, lastEventId, e.replay)
+		if err != nil {
+			// the stream goes on with the new events only
+			e.logger.WithError(err).WithField("userId", userId).Warn("eventService.Stream.FindAfter")
+			missed = nil
+		}
+	}
+
+	events := make(chan model.Event)
+	go func() {
+		defer close(events)
+
+		last := lastEventId
+		send := func(sent model.Event) bool {
+			select {
+			case events <- sent:
+				last = sent.Id
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for _, missedEvent := range missed {
+			if !send(missedEvent) {
+				return
+			}
+		}
+		for liveEvent := range live {
+			if model.ValidId(last) && !model.IsAfter(liveEvent.Id, last) {
+				continue
+			}
+			if !send(liveEvent) {
+				return
+			}
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/event/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/event/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+var cfg = &config.Config{
+	Logger: config.LoggerConfig{Level: "panic"},
+}
+
+func TestEventService_Publish(t *testing.T) {
+	userId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] The data is encoded in JSON", func(t *testing.T) {
+		redisRepo := new(mockObject.MockRedisRepository)
+		sv := NewEventService(&ServiceConfig{RedisRepo: redisRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		redisRepo.On("Publish", mock.Anything, mock.MatchedBy(func(published *model.Event) bool {
+			return published.UserId == userId &&
+				published.Type == model.TypeExpenseDeleted &&
+				string(published.Data) == `{"id":"1"}`
+		})).Return(nil).Once()
+
+		err := sv.Publish(context.Background(), userId, model.TypeExpenseDeleted, map[string]string{"id": "1"})
+		require.NoError(t, err)
+		redisRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Redis failing", func(t *testing.T) {
+		redisRepo := new(mockObject.MockRedisRepository)
+		sv := NewEventService(&ServiceConfig{RedisRepo: redisRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		redisRepo.On("Publish", mock.Anything, mock.Anything).Return(errors.New("connection refused")).Once()
+
+		err := sv.Publish(context.Background(), userId, model.TypeExpenseCreated, map[string]string{})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusInternalServerError, er.Status)
+	})
+}
+
+func TestEventService_Stream(t *testing.T) {
+	userId := uuid.NewString()
+	data := json.RawMessage(`{}`)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Missed events come first, live events already sent are dropped", func(t *testing.T) {
+		redisRepo := new(mockObject.MockRedisRepository)
+		sv := NewEventService(&ServiceConfig{RedisRepo: redisRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		live := make(chan model.Event, 3)
+		live <- model.Event{Id: "100-1", Type: model.TypeExpenseCreated, Data: data}
+		live <- model.Event{Id: "101-0", Type: model.TypeExpenseDeleted, Data: data}
+		close(live)
+		redisRepo.On("Subscribe", mock.Anything, userId).Return(live, nil).Once()
+		redisRepo.On("FindAfter", mock.Anything, userId, "100-0", int64(defaultReplay)).Return([]model.Event{
+			{Id: "100-1", Type: model.TypeExpenseCreated, Data: data},
+		}, nil).Once()
+
+		events, err := sv.Stream(context.Background(), userId, "100-0")
+		require.NoError(t, err)
+
+		var ids []string
+		for received := range events {
+			ids = append(ids, received.Id)
+		}
+		require.Equal(t, []string{"100-1", "101-0"}, ids)
+		redisRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Without last event id only live events are sent", func(t *testing.T) {
+		redisRepo := new(mockObject.MockRedisRepository)
+		sv := NewEventService(&ServiceConfig{RedisRepo: redisRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		live := make(chan model.Event, 1)
+		live <- model.Event{Id: "100-0", Type: model.TypeNotificationCreated, Data: data}
+		close(live)
+		redisRepo.On("Subscribe", mock.Anything, userId).Return(live, nil).Once()
+
+		events, err := sv.Stream(context.Background(), userId, "")
+		require.NoError(t, err)
+
+		received, ok := <-events
+		require.True(t, ok)
+		require.Equal(t, model.TypeNotificationCreated, received.Type)
+		_, ok = <-events
+		require.False(t, ok)
+		redisRepo.AssertNotCalled(t, "FindAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Subscription failing", func(t *testing.T) {
+		redisRepo := new(mockObject.MockRedisRepository)
+		sv := NewEventService(&ServiceConfig{RedisRepo: redisRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		redisRepo.On("Subscribe", mock.Anything, userId).Return(nil, errors.New("connection refused")).Once()
+
+		events, err := sv.Stream(context.Background(), userId, "")
+		require.Nil(t, events)
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusInternalServerError, er.Status)
+	})
+}
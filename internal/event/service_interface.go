@@ -0,0 +1,23 @@
+package event
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/event/model"
+)
+
+// Publisher is what the other modules push events to the connected clients of a user with
+type Publisher interface {
+	// Publish pushes an event of the type with data encoded in JSON
+	Publish(ctx context.Context, userId uuid.UUID, eventType string, data interface{}) error
+}
+
+// EventService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type EventService interface {
+	Publisher
+
+	// Stream returns the events of the user published after lastEventId, when given, followed by the
+	// new events as they are published. the channel is closed once ctx is done
+	Stream(ctx context.Context, userId, lastEventId string) (<-chan model.Event, error)
+}
@@ -11,5 +11,19 @@ type ExpenseController interface {
 
 	UpdateExpense() gin.HandlerFunc
 
+	RestoreExpense() gin.HandlerFunc
+
+	GetExpenseHistory() gin.HandlerFunc
+
 	GetAllExpense() gin.HandlerFunc
+
+	GetExpenseReport() gin.HandlerFunc
+
+	GetExpenseForecast() gin.HandlerFunc
+
+	BatchExpenses() gin.HandlerFunc
+
+	GetDuplicates() gin.HandlerFunc
+
+	MergeDuplicates() gin.HandlerFunc
 }
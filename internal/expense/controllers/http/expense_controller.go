@@ -1,15 +1,19 @@
 package http
 
 import (
+	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
 	"github.com/mrizkisaputra/expenses-api/internal/expense"
 	"github.com/mrizkisaputra/expenses-api/internal/expense/model"
 	"github.com/mrizkisaputra/expenses-api/internal/middleware"
 	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
 	"github.com/mrizkisaputra/expenses-api/pkg/utils"
 	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"math"
 	"net/http"
@@ -17,6 +21,7 @@ import (
 
 type ControllerConfig struct {
 	ExpenseService expense.ExpenseService
+	Config         *config.Config
 	Logger         *logrus.Logger
 }
 
@@ -24,6 +29,7 @@ type ControllerConfig struct {
 // for use in controller methods
 type expenseController struct {
 	expenseService expense.ExpenseService
+	cfg            *config.Config
 	logger         *logrus.Logger
 }
 
@@ -32,40 +38,79 @@ type expenseController struct {
 func NewExpenseController(config *ControllerConfig) expense.ExpenseController {
 	return &expenseController{
 		expenseService: config.ExpenseService,
+		cfg:            config.Config,
 		logger:         config.Logger,
 	}
 }
 
-func (ec expenseController) CreateNewExpense() gin.HandlerFunc {
-	type CreateExpenseRequest struct {
-		Description string   `json:"description" validate:"required,max=200"`
-		Amount      *float64 `json:"amount" validate:"required,gt=0"`
-		Category    string   `json:"category" validate:"required,max=100"`
+// createExpenseRequest is the body of a new expense, alone or in a batch
+type createExpenseRequest struct {
+	Description string        `json:"description" validate:"required,max=200"`
+	Amount      *money.Amount `json:"amount" validate:"required,gt=0"`
+	Currency    string        `json:"currency" validate:"omitempty,iso4217"`
+	CategoryId  string        `json:"category_id" validate:"required,uuid"`
+	AccountId   string        `json:"account_id" validate:"omitempty,uuid"`
+	Tags        []string      `json:"tags" validate:"omitempty,max=20,dive,required,max=50"`
+}
+
+func (request *createExpenseRequest) entity(userId uuid.UUID) *model.Expense {
+	return &model.Expense{
+		UserId:      userId,
+		Description: request.Description,
+		Amount:      request.Amount,
+		Currency:    request.Currency,
+		CategoryId:  uuid.MustParse(request.CategoryId),
+		AccountId:   utils.ParseOptionalId(request.AccountId),
+		Tags:        request.Tags,
+	}
+}
+
+// updateExpenseRequest is the body of an expense update, alone or in a batch
+type updateExpenseRequest struct {
+	Id          string        `json:"-" validate:"required,uuid"`
+	Description string        `json:"description" validate:"omitempty,max=200"`
+	Amount      *money.Amount `json:"amount" validate:"omitempty,gt=0"`
+	Currency    string        `json:"currency" validate:"omitempty,iso4217"`
+	CategoryId  string        `json:"category_id" validate:"omitempty,uuid"`
+	AccountId   string        `json:"account_id" validate:"omitempty,uuid"`
+	Tags        []string      `json:"tags" validate:"omitempty,max=20,dive,required,max=50"`
+}
+
+func (request *updateExpenseRequest) entity(userId uuid.UUID) *model.Expense {
+	entity := &model.Expense{
+		Id:          uuid.MustParse(request.Id),
+		UserId:      userId,
+		Description: request.Description,
+		Amount:      request.Amount,
+		Currency:    request.Currency,
+		Tags:        request.Tags,
+	}
+	if request.CategoryId != "" {
+		entity.CategoryId = uuid.MustParse(request.CategoryId)
 	}
+	entity.AccountId = utils.ParseOptionalId(request.AccountId)
+	return entity
+}
 
+func (ec expenseController) CreateNewExpense() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		auth := middleware.GetAuth(ctx)
 
-		request := new(CreateExpenseRequest)
+		request := new(createExpenseRequest)
 		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
 			utils.LogErrorResponse(ctx, ec.logger, err)
 			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
 			return
 		}
 
-		entity := &model.Expense{
-			UserId:      auth.Id,
-			Description: request.Description,
-			Amount:      request.Amount,
-			Category:    request.Category,
-		}
-		response, err := ec.expenseService.Insert(ctx, entity)
+		response, err := ec.expenseService.Insert(ctx, request.entity(auth.Id))
 		if err != nil {
 			utils.LogErrorResponse(ctx, ec.logger, err)
 			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
 			return
 		}
 
+		ctx.Header("ETag", utils.ETag(response.Version))
 		ctx.JSON(http.StatusCreated, &model.ApiResponse{
 			Status:  http.StatusCreated,
 			Message: "Created",
@@ -101,6 +146,7 @@ func (ec expenseController) GetExpenseById() gin.HandlerFunc {
 			return
 		}
 
+		ctx.Header("ETag", utils.ETag(response.Version))
 		ctx.JSON(http.StatusOK, &model.ApiResponse{
 			Status:  http.StatusOK,
 			Message: "OK",
@@ -126,9 +172,17 @@ func (ec expenseController) DeleteExpense() gin.HandlerFunc {
 			return
 		}
 
+		version, err := ec.ifMatch(ctx)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
 		entity := &model.Expense{
-			Id:     uuid.MustParse(request.Id),
-			UserId: auth.Id,
+			Id:      uuid.MustParse(request.Id),
+			UserId:  auth.Id,
+			Version: version,
 		}
 		if err := ec.expenseService.Delete(ctx, entity); err != nil {
 			utils.LogErrorResponse(ctx, ec.logger, err)
@@ -144,17 +198,10 @@ func (ec expenseController) DeleteExpense() gin.HandlerFunc {
 }
 
 func (ec expenseController) UpdateExpense() gin.HandlerFunc {
-	type UpdateExpenseRequest struct {
-		Id          string   `json:"-" validate:"required,uuid"`
-		Description string   `json:"description" validate:"omitempty,max=200"`
-		Amount      *float64 `json:"amount" validate:"omitempty,gt=0"`
-		Category    string   `json:"category" validate:"omitempty,max=100"`
-	}
-
 	return func(ctx *gin.Context) {
 		auth := middleware.GetAuth(ctx)
 
-		request := new(UpdateExpenseRequest)
+		request := new(updateExpenseRequest)
 		request.Id = ctx.Param("id")
 		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
 			utils.LogErrorResponse(ctx, ec.logger, err)
@@ -162,13 +209,15 @@ func (ec expenseController) UpdateExpense() gin.HandlerFunc {
 			return
 		}
 
-		entity := &model.Expense{
-			Id:          uuid.MustParse(request.Id),
-			UserId:      auth.Id,
-			Description: request.Description,
-			Amount:      request.Amount,
-			Category:    request.Category,
+		version, err := ec.ifMatch(ctx)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
 		}
+
+		entity := request.entity(auth.Id)
+		entity.Version = version
 		response, err := ec.expenseService.Update(ctx, entity)
 		if err != nil {
 			utils.LogErrorResponse(ctx, ec.logger, err)
@@ -176,6 +225,8 @@ func (ec expenseController) UpdateExpense() gin.HandlerFunc {
 			return
 		}
 
+		ctx.Header("ETag", utils.ETag(response.Version))
+
 		ctx.JSON(http.StatusAccepted, &model.ApiResponse{
 			Status:  http.StatusAccepted,
 			Message: "Accepted",
@@ -184,6 +235,75 @@ func (ec expenseController) UpdateExpense() gin.HandlerFunc {
 	}
 }
 
+func (ec expenseController) RestoreExpense() gin.HandlerFunc {
+	type RestoreExpenseRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+		request := &RestoreExpenseRequest{
+			Id: ctx.Param("id"),
+		}
+
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Expense{
+			Id:     uuid.MustParse(request.Id),
+			UserId: auth.Id,
+		}
+		response, err := ec.expenseService.Restore(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.Header("ETag", utils.ETag(response.Version))
+		ctx.JSON(http.StatusOK, &model.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (ec expenseController) GetExpenseHistory() gin.HandlerFunc {
+	type GetExpenseHistoryRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+		request := &GetExpenseHistoryRequest{
+			Id: ctx.Param("id"),
+		}
+
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := ec.expenseService.GetHistory(ctx, request.Id, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &model.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
 func (ec expenseController) GetAllExpense() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		auth := middleware.GetAuth(ctx)
@@ -202,21 +322,242 @@ func (ec expenseController) GetAllExpense() gin.HandlerFunc {
 			request.Limit = 10
 		}
 
-		expenses, total, err := ec.expenseService.GetAll(ctx, auth.Id.String(), request)
+		page, err := ec.expenseService.GetAll(ctx, auth.Id.String(), request)
 		if err != nil {
 			utils.LogErrorResponse(ctx, ec.logger, err)
 			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
 			return
 		}
 
-		ctx.JSON(http.StatusOK, &model.ApiResponse{
+		response := &model.ApiResponse{
 			Status:     http.StatusOK,
 			Message:    "OK",
-			Data:       expenses,
-			Page:       request.Page,
+			Data:       page.Expenses,
 			Limit:      request.Limit,
-			TotalItems: total,
-			TotalPages: int64(math.Ceil(float64(total) / float64(request.Limit))),
+			NextCursor: page.NextCursor,
+		}
+		// totals are only known in page mode, a cursor page is not counted
+		if request.Cursor == "" {
+			response.Page = request.Page
+			response.TotalItems = page.TotalItems
+			response.TotalPages = int64(math.Ceil(float64(page.TotalItems) / float64(request.Limit)))
+		}
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+func (ec expenseController) GetExpenseReport() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(model.SearchExpenseRequestQueryParam)
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		report, err := ec.expenseService.GetReport(ctx, auth.Id.String(), request)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &model.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    report,
+		})
+	}
+}
+
+// GetExpenseForecast projects the spending at the end of the current month, overall and per category
+func (ec expenseController) GetExpenseForecast() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		forecast, err := ec.expenseService.GetForecast(ctx, auth.Id)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &model.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    forecast,
+		})
+	}
+}
+
+// GetDuplicates lists the groups of expenses that look like duplicates, it takes the filters of the listing
+func (ec expenseController) GetDuplicates() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(model.SearchExpenseRequestQueryParam)
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		groups, err := ec.expenseService.GetDuplicates(ctx, auth.Id.String(), request)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &model.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       groups,
+			TotalItems: int64(len(groups)),
+		})
+	}
+}
+
+// MergeDuplicates keeps one expense and deletes its duplicates
+func (ec expenseController) MergeDuplicates() gin.HandlerFunc {
+	type MergeDuplicatesRequest struct {
+		KeepId       string   `json:"keep_id" validate:"required,uuid"`
+		DuplicateIds []string `json:"duplicate_ids" validate:"required,min=1,max=50,unique,dive,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(MergeDuplicatesRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.MergeRequest{
+			UserId: auth.Id,
+			KeepId: uuid.MustParse(request.KeepId),
+		}
+		for _, id := range request.DuplicateIds {
+			entity.DuplicateIds = append(entity.DuplicateIds, uuid.MustParse(id))
+		}
+		response, err := ec.expenseService.Merge(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.Header("ETag", utils.ETag(response.Version))
+		ctx.JSON(http.StatusOK, &model.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
 		})
 	}
 }
+
+func (ec expenseController) BatchExpenses() gin.HandlerFunc {
+	type BatchOperationRequest struct {
+		Op      string          `json:"op" validate:"required,oneof=create update delete"`
+		Id      string          `json:"id" validate:"required_unless=Op create,omitempty,uuid"`
+		Version int64           `json:"version" validate:"omitempty,gt=0"` // same as If-Match, for update and delete
+		Data    json.RawMessage `json:"data"`
+	}
+	type BatchRequest struct {
+		Atomic     bool                    `form:"atomic" json:"atomic"`
+		Operations []BatchOperationRequest `json:"operations" validate:"required,min=1,max=100,dive"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(BatchRequest)
+		if err := ctx.ShouldBindQuery(request); err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, httpErrors.NewBadRequestError(err))
+			ctx.JSON(httpErrors.ErrorResponse(ctx, httpErrors.NewBadRequestError(err)))
+			return
+		}
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		// the data of every operation is validated on its own so an invalid
+		// operation is reported in its result instead of rejecting the batch
+		operations := make([]model.BatchOperation, len(request.Operations))
+		for i, operation := range request.Operations {
+			operations[i] = model.BatchOperation{Op: operation.Op}
+			switch operation.Op {
+			case model.BatchCreate:
+				data := new(createExpenseRequest)
+				if operations[i].Err = readBatchData(ctx, operation.Data, data); operations[i].Err == nil {
+					operations[i].Expense = data.entity(auth.Id)
+				}
+			case model.BatchUpdate:
+				data := &updateExpenseRequest{Id: operation.Id}
+				if operations[i].Err = readBatchData(ctx, operation.Data, data); operations[i].Err == nil {
+					operations[i].Expense = data.entity(auth.Id)
+					operations[i].Expense.Version = operation.Version
+				}
+			case model.BatchDelete:
+				operations[i].Expense = &model.Expense{Id: uuid.MustParse(operation.Id), UserId: auth.Id, Version: operation.Version}
+			}
+		}
+
+		response, err := ec.expenseService.Batch(ctx, operations, request.Atomic)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ec.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+		for i := range response.Results {
+			if response.Results[i].Err != nil {
+				info := httpErrors.ParseErrors(ctx, response.Results[i].Err).ErrorInfo
+				response.Results[i].Error = &info
+			}
+		}
+
+		// a rolled back atomic batch did not change anything
+		status, message := http.StatusOK, "OK"
+		if !response.Applied {
+			status, message = http.StatusUnprocessableEntity, "Unprocessable Entity"
+		}
+		ctx.JSON(status, &model.ApiResponse{
+			Status:  status,
+			Message: message,
+			Data:    response,
+		})
+	}
+}
+
+// ifMatch reads the version expected by the If-Match header, 0 when any version is accepted
+func (ec expenseController) ifMatch(ctx *gin.Context) (int64, error) {
+	header := ctx.GetHeader("If-Match")
+	if header == "" && ec.cfg.Concurrency.RequireIfMatch {
+		return 0, httpErrors.NewError(http.StatusPreconditionRequired, httpErrors.IfMatchRequiredMsg, nil)
+	}
+	version, ok := utils.ParseIfMatch(header)
+	if !ok {
+		return 0, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidIfMatchMsg, errors.Errorf("If-Match: %s", header))
+	}
+	return version, nil
+}
+
+// readBatchData decodes and validates the data of one batch operation
+func readBatchData(ctx *gin.Context, raw json.RawMessage, data interface{}) error {
+	if len(raw) == 0 {
+		raw = json.RawMessage("{}")
+	}
+	if err := json.Unmarshal(raw, data); err != nil {
+		return httpErrors.NewBadRequestError(errors.Wrap(err, "readBatchData.Unmarshal"))
+	}
+	if err := validator.ValidateStruct(ctx, data); err != nil {
+		return errors.Wrap(err, "readBatchData.ValidateStruct")
+	}
+	return nil
+}
@@ -8,9 +8,16 @@ import (
 
 func MapExpenseRoutes(expenseGroup *gin.RouterGroup, controller ExpenseController, mw *middleware.MiddlewareManager) {
 	expenseGroup.Use(mw.AuthJwtMiddleware())
-	expenseGroup.POST("/create", controller.CreateNewExpense())
+	expenseGroup.POST("/create", mw.IdempotencyMiddleware(), controller.CreateNewExpense())
+	expenseGroup.POST("/batch", mw.IdempotencyMiddleware(), controller.BatchExpenses())
+	expenseGroup.GET("/report", controller.GetExpenseReport())
+	expenseGroup.GET("/forecast", controller.GetExpenseForecast())
+	expenseGroup.GET("/duplicates", controller.GetDuplicates())
+	expenseGroup.POST("/duplicates/merge", mw.IdempotencyMiddleware(), controller.MergeDuplicates())
 	expenseGroup.GET("/:id", controller.GetExpenseById())
-	expenseGroup.DELETE("/:id", controller.DeleteExpense())
-	expenseGroup.PATCH("/:id", controller.UpdateExpense())
+	expenseGroup.GET("/:id/history", controller.GetExpenseHistory())
+	expenseGroup.POST("/:id/restore", mw.IdempotencyMiddleware(), controller.RestoreExpense())
+	expenseGroup.DELETE("/:id", mw.IdempotencyMiddleware(), controller.DeleteExpense())
+	expenseGroup.PATCH("/:id", mw.IdempotencyMiddleware(), controller.UpdateExpense())
 	expenseGroup.GET("/", controller.GetAllExpense())
 }
@@ -0,0 +1,106 @@
+package mock
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// ExpenseServiceMock is a mock type for expense.ExpenseService
+type ExpenseServiceMock struct {
+	mock.Mock
+}
+
+func (m *ExpenseServiceMock) Insert(ctx context.Context, request *model.Expense) (*model.Expense, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Expense), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ExpenseServiceMock) Delete(ctx context.Context, request *model.Expense) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *ExpenseServiceMock) Update(ctx context.Context, request *model.Expense) (*model.Expense, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Expense), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ExpenseServiceMock) Restore(ctx context.Context, request *model.Expense) (*model.Expense, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Expense), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ExpenseServiceMock) GetHistory(ctx context.Context, id, userId string) ([]model.ExpenseHistory, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.ExpenseHistory), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ExpenseServiceMock) GetById(ctx context.Context, id, userId string) (*model.Expense, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Expense), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ExpenseServiceMock) GetAll(ctx context.Context, userId string, request *model.SearchExpenseRequestQueryParam) (*model.ExpensePage, error) {
+	args := m.Called(ctx, userId, request)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.ExpensePage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ExpenseServiceMock) GetReport(ctx context.Context, userId string, request *model.SearchExpenseRequestQueryParam) (*model.ExpenseReport, error) {
+	args := m.Called(ctx, userId, request)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.ExpenseReport), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ExpenseServiceMock) Batch(ctx context.Context, operations []model.BatchOperation, atomic bool) (*model.BatchResponse, error) {
+	args := m.Called(ctx, operations, atomic)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.BatchResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ExpenseServiceMock) GetForecast(ctx context.Context, userId uuid.UUID) (*model.Forecast, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Forecast), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ExpenseServiceMock) GetDuplicates(ctx context.Context, userId string, request *model.SearchExpenseRequestQueryParam) ([]model.DuplicateGroup, error) {
+	args := m.Called(ctx, userId, request)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.DuplicateGroup), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ExpenseServiceMock) Merge(ctx context.Context, request *model.MergeRequest) (*model.Expense, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Expense), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
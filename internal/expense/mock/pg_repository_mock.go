@@ -2,40 +2,125 @@ package mock
 
 import (
 	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/expense"
 	"github.com/mrizkisaputra/expenses-api/internal/expense/model"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
+// MockPostgresRepository is a mock type for expense.PostgresRepository
 type MockPostgresRepository struct {
 	mock.Mock
 }
 
-func (m MockPostgresRepository) Create(ctx context.Context, expense *model.Expense) error {
-	//TODO implement me
-	panic("implement me")
+func (m *MockPostgresRepository) Create(ctx context.Context, expense *model.Expense) error {
+	args := m.Called(ctx, expense)
+	return args.Error(0)
 }
 
-func (m MockPostgresRepository) FindByIdAndUserId(ctx context.Context, expense *model.Expense, id, userId string) error {
-	//TODO implement me
-	panic("implement me")
+func (m *MockPostgresRepository) FindByIdAndUserId(ctx context.Context, expense *model.Expense, id, userId string) error {
+	args := m.Called(ctx, expense, id, userId)
+	if found, ok := args.Get(0).(*model.Expense); ok && found != nil {
+		*expense = *found
+	}
+	return args.Error(1)
 }
 
-func (m MockPostgresRepository) Remove(ctx context.Context, expense *model.Expense) error {
-	//TODO implement me
-	panic("implement me")
+func (m *MockPostgresRepository) Remove(ctx context.Context, expense *model.Expense) error {
+	args := m.Called(ctx, expense)
+	return args.Error(0)
 }
 
-func (m MockPostgresRepository) Update(ctx context.Context, expense *model.Expense) error {
-	//TODO implement me
-	panic("implement me")
+func (m *MockPostgresRepository) Update(ctx context.Context, expense *model.Expense) error {
+	args := m.Called(ctx, expense)
+	return args.Error(0)
 }
 
-func (m MockPostgresRepository) FindAll(ctx context.Context, userId string, offset, limit int) ([]model.Expense, int64, error) {
-	//TODO implement me
-	panic("implement me")
+func (m *MockPostgresRepository) Restore(ctx context.Context, expense *model.Expense) error {
+	args := m.Called(ctx, expense)
+	return args.Error(0)
 }
 
-func (m MockPostgresRepository) FindAllByDateRange(ctx context.Context, userId string, startDate, endDate int64, offset, limit int) ([]model.Expense, int64, error) {
-	//TODO implement me
-	panic("implement me")
+func (m *MockPostgresRepository) CreateHistory(ctx context.Context, history *model.ExpenseHistory) error {
+	args := m.Called(ctx, history)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindHistory(ctx context.Context, expenseId, userId string) ([]model.ExpenseHistory, error) {
+	args := m.Called(ctx, expenseId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.ExpenseHistory), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindAll(ctx context.Context, userId string, filter *model.ExpenseFilter, sort *model.ExpenseSort, offset, limit int) ([]model.Expense, int64, error) {
+	args := m.Called(ctx, userId, filter, sort, offset, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Expense), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockPostgresRepository) FindAllAfter(ctx context.Context, userId string, filter *model.ExpenseFilter, sort *model.ExpenseSort, after *model.ExpenseCursor, limit int) ([]model.Expense, error) {
+	args := m.Called(ctx, userId, filter, sort, after, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Expense), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) Summarize(ctx context.Context, userId string, filter *model.ExpenseFilter) (*model.ExpenseReport, error) {
+	args := m.Called(ctx, userId, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.ExpenseReport), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) SumByDay(ctx context.Context, userId string, filter *model.ExpenseFilter) ([]model.DailyTotal, error) {
+	args := m.Called(ctx, userId, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.DailyTotal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) SumByCategoryAndDay(ctx context.Context, userId string, filter *model.ExpenseFilter) ([]model.CategoryDailyTotal, error) {
+	args := m.Called(ctx, userId, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.CategoryDailyTotal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindDuplicateCandidates(ctx context.Context, userId string, filter *model.ExpenseFilter, window int64, limit int) ([]model.Expense, error) {
+	args := m.Called(ctx, userId, filter, window, limit)
+	if expenses, ok := args.Get(0).([]model.Expense); ok {
+		return expenses, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindRecurring(ctx context.Context, userId string, filter *model.ExpenseFilter, months int) ([]model.RecurringExpense, error) {
+	args := m.Called(ctx, userId, filter, months)
+	if recurring, ok := args.Get(0).([]model.RecurringExpense); ok {
+		return recurring, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// Transaction records the call and runs fn without transaction, the error of the
+// expectation (if any) is returned instead of running fn
+func (m *MockPostgresRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	args := m.Called(ctx, fn)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(nil)
+}
+
+// WithTx returns the mock itself so expectations hold inside transactions
+func (m *MockPostgresRepository) WithTx(tx *gorm.DB) expense.PostgresRepository {
+	return m
 }
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchOperation is one create, update or delete of a batch, Expense carries the id
+// for update and delete. an operation rejected before it runs (invalid data) carries Err
+type BatchOperation struct {
+	Op      string
+	Expense *Expense
+	Err     error
+}
+
+// BatchResult is the outcome of one operation, Status mirrors the status code
+// the single endpoint would have answered
+type BatchResult struct {
+	Index  int                   `json:"index"`
+	Op     string                `json:"op"`
+	Status int                   `json:"status"`
+	Id     *uuid.UUID            `json:"id,omitempty"`
+	Data   *Expense              `json:"data,omitempty"`
+	Error  *httpErrors.ErrorInfo `json:"error,omitempty"`
+	Err    error                 `json:"-"`
+}
+
+// Fail records the error of the operation and its status
+func (result *BatchResult) Fail(err error) {
+	result.Err = err
+	result.Data = nil
+	result.Status = http.StatusInternalServerError
+
+	var er *httpErrors.Error
+	if errors.As(err, &er) {
+		result.Status = er.Status
+	}
+}
+
+// BatchResponse holds the results in the order of the operations, an atomic
+// batch is either applied entirely or not at all (Applied is false)
+type BatchResponse struct {
+	Atomic    bool          `json:"atomic"`
+	Applied   bool          `json:"applied"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []BatchResult `json:"results"`
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid expense cursor")
+
+// ExpenseCursor is the position of the last expense of a page in keyset pagination,
+// the next page starts right after the sort key (Value, Id) of that expense
+type ExpenseCursor struct {
+	Sort  ExpenseSort
+	Value interface{}
+	Id    uuid.UUID
+}
+
+// cursorPayload is the encoded form of a cursor, clients only see it as an opaque string
+type cursorPayload struct {
+	Field      string          `json:"f"`
+	Descending bool            `json:"d,omitempty"`
+	Value      json.RawMessage `json:"v"`
+	Id         uuid.UUID       `json:"i"`
+}
+
+// NewExpenseCursor returns the cursor positioned on the expense for the sort
+func NewExpenseCursor(sort ExpenseSort, last *Expense) *ExpenseCursor {
+	cursor := &ExpenseCursor{Sort: sort, Id: last.Id}
+	switch sort.Field {
+	case "created_at":
+		cursor.Value = last.CreatedAt
+	case "updated_at":
+		cursor.Value = last.UpdatedAt
+	case "amount":
+		if last.Amount != nil {
+			cursor.Value = last.Amount.Minor()
+		}
+	case "description":
+		cursor.Value = last.Description
+	case "currency":
+		cursor.Value = last.Currency
+	case "relevance":
+		if last.SearchRank != nil {
+			cursor.Value = *last.SearchRank
+		}
+	}
+	return cursor
+}
+
+// Encode returns the opaque (url safe) form of the cursor
+func (cursor *ExpenseCursor) Encode() string {
+	value, _ := json.Marshal(cursor.Value)
+	payload, _ := json.Marshal(&cursorPayload{
+		Field:      cursor.Sort.Field,
+		Descending: cursor.Sort.Descending,
+		Value:      value,
+		Id:         cursor.Id,
+	})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeExpenseCursor reads a cursor returned by Encode, the sort value is decoded
+// with the type of the sort field so it compares exactly to the column
+func DecodeExpenseCursor(raw string) (*ExpenseCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+
+	payload := new(cursorPayload)
+	if err := json.Unmarshal(data, payload); err != nil {
+		return nil, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+
+	cursor := &ExpenseCursor{
+		Sort: ExpenseSort{Field: payload.Field, Descending: payload.Descending},
+		Id:   payload.Id,
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload.Value))
+	switch payload.Field {
+	case "created_at", "updated_at", "amount":
+		var value int64
+		err = decoder.Decode(&value)
+		cursor.Value = value
+	case "description", "currency":
+		var value string
+		err = decoder.Decode(&value)
+		cursor.Value = value
+	case "relevance":
+		var value float64
+		err = decoder.Decode(&value)
+		cursor.Value = value
+	default:
+		err = errors.Errorf("unknown sort field '%s'", payload.Field)
+	}
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+	return cursor, nil
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestExpenseCursor_EncodeDecode(t *testing.T) {
+	amount := money.Amount(9223372036854775807)
+	rank := 0.6079270839691162
+	last := &Expense{
+		Id:          uuid.New(),
+		Description: "parking ticket",
+		Amount:      &amount,
+		Currency:    "IDR",
+		CreatedAt:   1767225600,
+		UpdatedAt:   1767229200,
+		SearchRank:  &rank,
+	}
+
+	for _, sort := range []ExpenseSort{
+		DefaultExpenseSort,
+		{Field: "updated_at"},
+		{Field: "amount", Descending: true},
+		{Field: "description"},
+		{Field: "currency", Descending: true},
+		{Field: "relevance", Descending: true},
+	} {
+		cursor := NewExpenseCursor(sort, last)
+		decoded, err := DecodeExpenseCursor(cursor.Encode())
+		require.NoError(t, err, sort.Field)
+		require.Equal(t, cursor, decoded, sort.Field)
+	}
+}
+
+func TestExpenseCursor_DecodeInvalid(t *testing.T) {
+	for _, raw := range []string{"", "not a cursor", "eyJmIjoiaWQiLCJ2IjoxfQ", "eyJmIjoiYW1vdW50IiwidiI6ImFiYyJ9"} {
+		_, err := DecodeExpenseCursor(raw)
+		require.ErrorIs(t, err, ErrInvalidCursor, raw)
+	}
+}
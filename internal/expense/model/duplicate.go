@@ -0,0 +1,173 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DuplicateWindow is how far apart, in seconds, two expenses can be made and still be duplicates
+const DuplicateWindow int64 = 2 * 24 * 60 * 60
+
+// duplicateSimilarity is the lowest similarity of two normalized descriptions of duplicates
+const duplicateSimilarity = 0.8
+
+// DuplicateCandidate is an expense that looks like a duplicate of another one
+type DuplicateCandidate struct {
+	Id          uuid.UUID     `json:"id"`
+	Description string        `json:"description"`
+	Amount      *money.Amount `json:"amount"`
+	Currency    string        `json:"currency"`
+	CreatedAt   int64         `json:"created_at"`
+	Similarity  float64       `json:"similarity"`
+}
+
+// DuplicateGroup is a set of expenses that look like duplicates of each other, oldest first
+type DuplicateGroup struct {
+	Amount   *money.Amount `json:"amount"`
+	Currency string        `json:"currency"`
+	Expenses []Expense     `json:"expenses"`
+}
+
+// MergeRequest keeps the expense KeepId and deletes its duplicates DuplicateIds
+type MergeRequest struct {
+	UserId       uuid.UUID
+	KeepId       uuid.UUID
+	DuplicateIds []uuid.UUID
+}
+
+// NormalizeDescription lowercases the description and keeps its letters and digits,
+// the other characters separate words. "GRAB*FOOD  jkt" becomes "grab food jkt"
+func NormalizeDescription(description string) string {
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// DescriptionSimilarity is the similarity of two descriptions from 0 to 1: 1 when they are
+// the same once normalized or one contains the other, else 1 minus their edit distance over the longest
+func DescriptionSimilarity(a, b string) float64 {
+	a, b = NormalizeDescription(a), NormalizeDescription(b)
+	if a == b {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0
+	}
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := math.Max(float64(len(ra)), float64(len(rb)))
+	return math.Round((1-float64(levenshtein(ra, rb))/longest)*100) / 100
+}
+
+// levenshtein is the number of single character edits turning a into b
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// IsDuplicate reports whether two different expenses have the same amount and currency, were made
+// within DuplicateWindow of each other and have similar descriptions, it returns their similarity
+func IsDuplicate(a, b *Expense) (float64, bool) {
+	if a.Id == b.Id || a.Amount == nil || b.Amount == nil || *a.Amount != *b.Amount || a.Currency != b.Currency {
+		return 0, false
+	}
+
+	gap := a.CreatedAt - b.CreatedAt
+	if gap < 0 {
+		gap = -gap
+	}
+	if gap > DuplicateWindow {
+		return 0, false
+	}
+
+	similarity := DescriptionSimilarity(a.Description, b.Description)
+	return similarity, similarity >= duplicateSimilarity
+}
+
+// NewDuplicateCandidate describes the expense as a duplicate with the given similarity
+func NewDuplicateCandidate(expense *Expense, similarity float64) DuplicateCandidate {
+	return DuplicateCandidate{
+		Id:          expense.Id,
+		Description: expense.Description,
+		Amount:      expense.Amount,
+		Currency:    expense.Currency,
+		CreatedAt:   expense.CreatedAt,
+		Similarity:  similarity,
+	}
+}
+
+// GroupDuplicates groups the expenses that are duplicates of each other, directly or through another
+// expense of the group. expenses without duplicate are left out, groups are ordered by their oldest expense
+func GroupDuplicates(expenses []Expense) []DuplicateGroup {
+	sorted := append([]Expense{}, expenses...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt < sorted[j].CreatedAt
+	})
+
+	// union-find over the pairs of duplicates
+	parent := make([]int, len(sorted))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range sorted {
+		for j := i + 1; j < len(sorted) && sorted[j].CreatedAt-sorted[i].CreatedAt <= DuplicateWindow; j++ {
+			if _, ok := IsDuplicate(&sorted[i], &sorted[j]); ok {
+				parent[root(j)] = root(i)
+			}
+		}
+	}
+
+	members := make(map[int][]Expense)
+	var roots []int
+	for i := range sorted {
+		r := root(i)
+		if _, ok := members[r]; !ok {
+			roots = append(roots, r)
+		}
+		members[r] = append(members[r], sorted[i])
+	}
+
+	groups := []DuplicateGroup{}
+	for _, r := range roots {
+		if len(members[r]) < 2 {
+			continue
+		}
+		groups = append(groups, DuplicateGroup{
+			Amount:   members[r][0].Amount,
+			Currency: members[r][0].Currency,
+			Expenses: members[r],
+		})
+	}
+	return groups
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDescriptionSimilarity(t *testing.T) {
+	require.Equal(t, "grab food jkt", NormalizeDescription("  GRAB*FOOD  jkt "))
+	require.Equal(t, float64(1), DescriptionSimilarity("GRAB*FOOD", "grab food"))
+	require.Equal(t, float64(1), DescriptionSimilarity("Grab Food", "grab food jakarta"))
+	require.GreaterOrEqual(t, DescriptionSimilarity("starbucks coffee", "starbuck coffe"), duplicateSimilarity)
+	require.Less(t, DescriptionSimilarity("coffee", "electricity"), duplicateSimilarity)
+	require.Equal(t, float64(0), DescriptionSimilarity("", "coffee"))
+}
+
+func TestIsDuplicate(t *testing.T) {
+	amount, other := money.Amount(2500000), money.Amount(2600000)
+	expense := &Expense{Id: uuid.New(), Description: "coffee shop", Amount: &amount, Currency: "IDR", CreatedAt: 1767225600}
+
+	similar := &Expense{Id: uuid.New(), Description: "COFFEE-SHOP", Amount: &amount, Currency: "IDR", CreatedAt: 1767225600 + 3600}
+	similarity, ok := IsDuplicate(expense, similar)
+	require.True(t, ok)
+	require.Equal(t, float64(1), similarity)
+
+	// another amount, another currency, too far apart or the expense itself
+	_, ok = IsDuplicate(expense, &Expense{Id: uuid.New(), Description: "coffee shop", Amount: &other, Currency: "IDR", CreatedAt: 1767225600})
+	require.False(t, ok)
+	_, ok = IsDuplicate(expense, &Expense{Id: uuid.New(), Description: "coffee shop", Amount: &amount, Currency: "USD", CreatedAt: 1767225600})
+	require.False(t, ok)
+	_, ok = IsDuplicate(expense, &Expense{Id: uuid.New(), Description: "coffee shop", Amount: &amount, Currency: "IDR", CreatedAt: 1767225600 + DuplicateWindow + 1})
+	require.False(t, ok)
+	_, ok = IsDuplicate(expense, expense)
+	require.False(t, ok)
+}
+
+func TestGroupDuplicates(t *testing.T) {
+	amount, other := money.Amount(2500000), money.Amount(900000)
+	expenses := []Expense{
+		{Id: uuid.New(), Description: "coffee", Amount: &amount, Currency: "IDR", CreatedAt: 1767225600 + 2*DuplicateWindow},
+		{Id: uuid.New(), Description: "coffee", Amount: &amount, Currency: "IDR", CreatedAt: 1767225600 + DuplicateWindow},
+		{Id: uuid.New(), Description: "coffee", Amount: &amount, Currency: "IDR", CreatedAt: 1767225600},
+		{Id: uuid.New(), Description: "taxi", Amount: &other, Currency: "IDR", CreatedAt: 1767225600},
+		{Id: uuid.New(), Description: "taxi ride", Amount: &other, Currency: "IDR", CreatedAt: 1767225700},
+		{Id: uuid.New(), Description: "parking", Amount: &other, Currency: "IDR", CreatedAt: 1767225800},
+	}
+
+	groups := GroupDuplicates(expenses)
+	require.Len(t, groups, 2)
+
+	// the first and last coffee are too far apart but both are duplicates of the one in between
+	require.Len(t, groups[0].Expenses, 3)
+	require.Equal(t, expenses[2].Id, groups[0].Expenses[0].Id)
+	require.Equal(t, amount, *groups[0].Amount)
+
+	require.Len(t, groups[1].Expenses, 2)
+	require.Equal(t, expenses[3].Id, groups[1].Expenses[0].Id)
+}
@@ -2,20 +2,50 @@ package model
 
 import (
 	"github.com/google/uuid"
+	tagModel "github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
 	"gorm.io/gorm"
 	"strings"
+	"time"
 )
 
+// ErrVersionConflict is returned when the expense was changed since the version the caller read
+var ErrVersionConflict = errors.New("expense version conflict")
+
 // Expense mapping table 'expenses'
 type Expense struct {
 	Id          uuid.UUID      `gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
 	UserId      uuid.UUID      `gorm:"column:id_user"`
 	Description string         `gorm:"column:description"`
-	Amount      *float64       `gorm:"column:amount"`
-	Category    string         `gorm:"column:category"`
+	Amount      *money.Amount  `gorm:"column:amount"`
+	Currency    string         `gorm:"column:currency"`
+	CategoryId  uuid.UUID      `gorm:"column:id_category"`
+	AccountId   *uuid.UUID     `gorm:"column:id_account"`                          // account the expense was paid from, nil when unknown
 	CreatedAt   int64          `gorm:"column:created_at;autoCreateTime;<-:create"` // allow read and create
 	UpdatedAt   int64          `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
 	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at"`
+
+	// bumped on every update, sent as ETag and expected back in If-Match, 0 on a request means no precondition
+	Version int64 `gorm:"column:version"`
+
+	// position of the last write in the sync change feed, set by the database
+	ChangeXid int64 `json:"-" gorm:"column:change_xid;->"`
+	ChangeSeq int64 `json:"-" gorm:"column:change_seq;->"`
+
+	// names of the tags attached to the expense, nil on update leaves the tags unchanged
+	Tags []string `gorm:"-"`
+
+	// amount converted to the user's base currency, only filled on listing
+	BaseAmount   *money.Amount `gorm:"-"`
+	BaseCurrency string        `gorm:"-"`
+
+	// relevance of the expense and its description with the matched words highlighted, only filled on search
+	SearchRank *float64 `json:",omitempty" gorm:"column:search_rank;->"`
+	Snippet    *string  `json:",omitempty" gorm:"column:snippet;->"`
+
+	// expenses that look like duplicates of a new expense, only filled on create
+	PossibleDuplicates []DuplicateCandidate `json:",omitempty" gorm:"-"`
 	//User        model.User     `gorm:"foreignKey:user_id;references:id"`
 }
 
@@ -24,13 +54,26 @@ func (expense *Expense) TableName() string {
 }
 
 func (expense *Expense) PrepareCreate() {
+	expense.Version = 1
 	expense.Description = strings.ToLower(strings.TrimSpace(expense.Description))
-	expense.Category = strings.ToLower(strings.TrimSpace(expense.Category))
+	expense.Currency = strings.ToUpper(strings.TrimSpace(expense.Currency))
+	if expense.Tags != nil {
+		expense.Tags = tagModel.NormalizeNames(expense.Tags)
+	}
+}
+
+// Date is the day the expense was made on, used to pick the exchange rate
+func (expense *Expense) Date() time.Time {
+	return time.Unix(expense.CreatedAt, 0)
 }
 
 func (expense *Expense) PrepareUpdate(oldExpense *Expense) {
-	if expense.Category != "" {
-		oldExpense.Category = expense.Category
+	if expense.CategoryId != uuid.Nil {
+		oldExpense.CategoryId = expense.CategoryId
+	}
+
+	if expense.AccountId != nil {
+		oldExpense.AccountId = expense.AccountId
 	}
 
 	if expense.Description != "" {
@@ -40,4 +83,8 @@ func (expense *Expense) PrepareUpdate(oldExpense *Expense) {
 	if expense.Amount != nil {
 		oldExpense.Amount = expense.Amount
 	}
+
+	if expense.Currency != "" {
+		oldExpense.Currency = strings.ToUpper(strings.TrimSpace(expense.Currency))
+	}
 }
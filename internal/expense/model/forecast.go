@@ -0,0 +1,254 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"math"
+	"sort"
+	"time"
+)
+
+// ForecastHistoryMonths is the number of full months before the current one the daily pattern is learned from,
+// an expense made in every one of them with the same description is recurring
+const ForecastHistoryMonths = 3
+
+// ForecastConfidence is the probability of the month-end total to fall within the band of a forecast
+const ForecastConfidence = 0.95
+
+// forecastZ is the z-score of ForecastConfidence for a normal distribution
+const forecastZ = 1.96
+
+// RecurringExpense is an expense made once a month with the same description, category and currency.
+// Amount is its average amount, Day its usual day of the month and LastAt when it was last made
+type RecurringExpense struct {
+	Description string       `gorm:"column:description"`
+	CategoryId  uuid.UUID    `gorm:"column:id_category"`
+	Currency    string       `gorm:"column:currency"`
+	Amount      money.Amount `gorm:"column:amount"`
+	Day         int          `gorm:"column:day"`
+	LastAt      int64        `gorm:"column:last_at"`
+}
+
+// UpcomingExpense is a recurring expense not made yet this month, expected on Date
+type UpcomingExpense struct {
+	Description string       `json:"description"`
+	CategoryId  uuid.UUID    `json:"category_id"`
+	Currency    string       `json:"currency"`
+	Amount      money.Amount `json:"amount"`
+	BaseAmount  money.Amount `json:"base_amount"`
+	Date        string       `json:"date"`
+}
+
+// CategoryForecast is the month-end projection of one category, in the base currency
+type CategoryForecast struct {
+	CategoryId uuid.UUID    `json:"category_id"`
+	Name       string       `json:"name"`
+	Spent      money.Amount `json:"spent"`
+	Upcoming   money.Amount `json:"upcoming"`
+	Projected  money.Amount `json:"projected"`
+	Low        money.Amount `json:"low"`
+	High       money.Amount `json:"high"`
+
+	history float64
+}
+
+// Forecast projects the total spent at the end of the current month (UTC) in the base currency: what was spent
+// so far, plus the recurring expenses still expected and the usual spending of every remaining day of the week.
+// Low and High bound the month-end total with the probability Confidence. amounts without exchange rate to the
+// base currency are left out, their currencies are listed in MissingRates
+type Forecast struct {
+	Currency         string             `json:"currency"`
+	Month            string             `json:"month"`
+	DaysElapsed      int                `json:"days_elapsed"`
+	DaysRemaining    int                `json:"days_remaining"`
+	Spent            money.Amount       `json:"spent"`
+	Upcoming         money.Amount       `json:"upcoming"`
+	Projected        money.Amount       `json:"projected"`
+	Low              money.Amount       `json:"low"`
+	High             money.Amount       `json:"high"`
+	Confidence       float64            `json:"confidence"`
+	UpcomingExpenses []UpcomingExpense  `json:"upcoming_expenses"`
+	Categories       []CategoryForecast `json:"categories"`
+	MissingRates     []string           `json:"missing_rates,omitempty"`
+
+	today        time.Time
+	monthStart   time.Time
+	historyStart time.Time
+	history      []float64
+	categories   map[uuid.UUID]int
+	missing      map[string]bool
+}
+
+// NewForecast creates the empty forecast of the month of now, the history covers
+// the ForecastHistoryMonths full months before it
+func NewForecast(currency string, now time.Time) *Forecast {
+	year, month, day := now.UTC().Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	historyStart := monthStart.AddDate(0, -ForecastHistoryMonths, 0)
+	daysInMonth := monthStart.AddDate(0, 1, -1).Day()
+
+	return &Forecast{
+		Currency:         currency,
+		Month:            monthStart.Format("2006-01"),
+		DaysElapsed:      day,
+		DaysRemaining:    daysInMonth - day,
+		Confidence:       ForecastConfidence,
+		UpcomingExpenses: []UpcomingExpense{},
+		Categories:       []CategoryForecast{},
+		today:            today,
+		monthStart:       monthStart,
+		historyStart:     historyStart,
+		history:          make([]float64, int(monthStart.Sub(historyStart).Hours()/24)),
+		categories:       map[uuid.UUID]int{},
+		missing:          map[string]bool{},
+	}
+}
+
+// HistoryRange returns the first and last instants (unix seconds) of the months the pattern is learned from
+func (forecast *Forecast) HistoryRange() (int64, int64) {
+	return forecast.historyStart.Unix(), forecast.monthStart.Unix() - 1
+}
+
+// MonthRange returns the first and last instants (unix seconds) of the month up to the end of today
+func (forecast *Forecast) MonthRange() (int64, int64) {
+	return forecast.monthStart.Unix(), forecast.today.AddDate(0, 0, 1).Unix() - 1
+}
+
+// AddDay adds an amount spent on day, to the history before the month and to the spending
+// so far during it, days after today are ignored
+func (forecast *Forecast) AddDay(day time.Time, amount money.Amount) {
+	switch {
+	case day.Before(forecast.historyStart) || day.After(forecast.today):
+	case day.Before(forecast.monthStart):
+		forecast.history[int(day.Sub(forecast.historyStart).Hours()/24)] += float64(amount)
+	default:
+		forecast.Spent += amount
+	}
+}
+
+// AddCategory adds to a category what it spent so far this month and during the history
+func (forecast *Forecast) AddCategory(categoryId uuid.UUID, name string, spent, history money.Amount) {
+	category := forecast.category(categoryId, name)
+	category.Spent += spent
+	category.history += float64(history)
+}
+
+// AddRecurring takes a recurring expense out of the daily pattern, it is made on a day of the month and not on
+// a day of the week. when it was not made yet this month it is expected on its usual day, or today once passed
+func (forecast *Forecast) AddRecurring(recurring RecurringExpense, baseAmount money.Amount) {
+	for start := forecast.historyStart; start.Before(forecast.monthStart); start = start.AddDate(0, 1, 0) {
+		i := int(recurringDate(start, recurring.Day).Sub(forecast.historyStart).Hours() / 24)
+		forecast.history[i] = math.Max(0, forecast.history[i]-float64(baseAmount))
+	}
+	category := forecast.category(recurring.CategoryId, "")
+	category.history = math.Max(0, category.history-float64(baseAmount)*ForecastHistoryMonths)
+
+	if recurring.LastAt >= forecast.monthStart.Unix() {
+		return
+	}
+	date := recurringDate(forecast.monthStart, recurring.Day)
+	if date.Before(forecast.today) {
+		date = forecast.today
+	}
+	forecast.UpcomingExpenses = append(forecast.UpcomingExpenses, UpcomingExpense{
+		Description: recurring.Description,
+		CategoryId:  recurring.CategoryId,
+		Currency:    recurring.Currency,
+		Amount:      recurring.Amount,
+		BaseAmount:  baseAmount,
+		Date:        date.Format("2006-01-02"),
+	})
+	forecast.Upcoming += baseAmount
+	category.Upcoming += baseAmount
+}
+
+// AddMissingRate lists a currency without exchange rate to the base currency
+func (forecast *Forecast) AddMissingRate(currency string) {
+	if !forecast.missing[currency] {
+		forecast.missing[currency] = true
+		forecast.MissingRates = append(forecast.MissingRates, currency)
+	}
+}
+
+// Finish projects the month-end totals. every remaining day is expected to spend the mean of the past days of
+// its weekday, with their variance, the days being independent the variances add up. a category takes its share
+// of the past spending out of recurring expenses, its band is that share of the band of the total
+func (forecast *Forecast) Finish() {
+	var means, variances [7]float64
+	var counts [7]int
+	for i, amount := range forecast.history {
+		weekday := forecast.historyStart.AddDate(0, 0, i).Weekday()
+		means[weekday] += amount
+		counts[weekday]++
+	}
+	for weekday := range means {
+		if counts[weekday] > 0 {
+			means[weekday] /= float64(counts[weekday])
+		}
+	}
+	for i, amount := range forecast.history {
+		weekday := forecast.historyStart.AddDate(0, 0, i).Weekday()
+		variances[weekday] += (amount - means[weekday]) * (amount - means[weekday]) / float64(counts[weekday])
+	}
+
+	var expected, variance float64
+	for i := 1; i <= forecast.DaysRemaining; i++ {
+		weekday := forecast.today.AddDate(0, 0, i).Weekday()
+		expected += means[weekday]
+		variance += variances[weekday]
+	}
+	margin := forecastZ * math.Sqrt(variance)
+
+	forecast.Projected, forecast.Low, forecast.High = project(forecast.Spent+forecast.Upcoming, expected, margin)
+
+	var history float64
+	for _, category := range forecast.Categories {
+		history += category.history
+	}
+	for i := range forecast.Categories {
+		category := &forecast.Categories[i]
+		share := 0.0
+		if history > 0 {
+			share = category.history / history
+		}
+		category.Projected, category.Low, category.High = project(category.Spent+category.Upcoming, expected*share, margin*share)
+	}
+	sort.SliceStable(forecast.Categories, func(i, j int) bool {
+		if forecast.Categories[i].Projected != forecast.Categories[j].Projected {
+			return forecast.Categories[i].Projected > forecast.Categories[j].Projected
+		}
+		return forecast.Categories[i].Name < forecast.Categories[j].Name
+	})
+	sort.SliceStable(forecast.UpcomingExpenses, func(i, j int) bool {
+		return forecast.UpcomingExpenses[i].Date < forecast.UpcomingExpenses[j].Date
+	})
+}
+
+// project is the month-end total of what is certain plus the expected spending, the band never goes below the certain part
+func project(certain money.Amount, expected, margin float64) (projected, low, high money.Amount) {
+	projected = certain + money.Amount(math.Round(expected))
+	low = certain + money.Amount(math.Round(math.Max(0, expected-margin)))
+	high = certain + money.Amount(math.Round(expected+margin))
+	return projected, low, high
+}
+
+// category returns the forecast of a category, created on first use
+func (forecast *Forecast) category(categoryId uuid.UUID, name string) *CategoryForecast {
+	i, ok := forecast.categories[categoryId]
+	if !ok {
+		i = len(forecast.Categories)
+		forecast.categories[categoryId] = i
+		forecast.Categories = append(forecast.Categories, CategoryForecast{CategoryId: categoryId})
+	}
+	if name != "" {
+		forecast.Categories[i].Name = name
+	}
+	return &forecast.Categories[i]
+}
+
+// recurringDate is the day of the month starting on monthStart, cut to the last day of shorter months
+func recurringDate(monthStart time.Time, day int) time.Time {
+	last := monthStart.AddDate(0, 1, -1).Day()
+	return monthStart.AddDate(0, 0, min(max(day, 1), last)-1)
+}
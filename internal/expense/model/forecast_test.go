@@ -0,0 +1,91 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestForecast(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 15, 0, 0, 0, time.UTC)
+	historyStart := time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC)
+	rentCategory, foodCategory := uuid.New(), uuid.New()
+	rent := RecurringExpense{
+		Description: "rent",
+		CategoryId:  rentCategory,
+		Currency:    "IDR",
+		Amount:      money.Amount(100000),
+		Day:         1,
+		LastAt:      time.Date(2024, time.February, 1, 9, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Recurring expenses are expected and the remaining days follow the history", func(t *testing.T) {
+		forecast := NewForecast("IDR", now)
+		require.Equal(t, "2024-03", forecast.Month)
+		require.Equal(t, 10, forecast.DaysElapsed)
+		require.Equal(t, 21, forecast.DaysRemaining)
+
+		from, to := forecast.HistoryRange()
+		require.Equal(t, historyStart.Unix(), from)
+		require.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC).Unix()-1, to)
+
+		for day := historyStart; day.Month() != time.March; day = day.AddDate(0, 0, 1) {
+			forecast.AddDay(day, money.Amount(10000))
+			if day.Day() == 1 {
+				forecast.AddDay(day, money.Amount(100000))
+			}
+		}
+		forecast.AddDay(time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), money.Amount(50000))
+		forecast.AddDay(time.Date(2024, time.March, 20, 0, 0, 0, 0, time.UTC), money.Amount(999999))
+		forecast.AddCategory(rentCategory, "housing", money.Amount(50000), money.Amount(600000))
+		forecast.AddCategory(foodCategory, "food", 0, money.Amount(100000))
+		forecast.AddRecurring(rent, money.Amount(100000))
+		forecast.Finish()
+
+		require.Equal(t, money.Amount(50000), forecast.Spent)
+		require.Equal(t, money.Amount(100000), forecast.Upcoming)
+		require.Len(t, forecast.UpcomingExpenses, 1)
+		require.Equal(t, "2024-03-10", forecast.UpcomingExpenses[0].Date)
+		require.Equal(t, money.Amount(360000), forecast.Projected)
+		require.Equal(t, forecast.Projected, forecast.Low)
+		require.Equal(t, forecast.Projected, forecast.High)
+
+		require.Len(t, forecast.Categories, 2)
+		require.Equal(t, "housing", forecast.Categories[0].Name)
+		require.Equal(t, money.Amount(307500), forecast.Categories[0].Projected)
+		require.Equal(t, "food", forecast.Categories[1].Name)
+		require.Equal(t, money.Amount(52500), forecast.Categories[1].Projected)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Irregular days widen the band, never below what is already spent", func(t *testing.T) {
+		forecast := NewForecast("IDR", now)
+		for day, i := historyStart, 0; day.Month() != time.March; day, i = day.AddDate(0, 0, 1), i+1 {
+			if i%2 == 0 {
+				forecast.AddDay(day, money.Amount(20000))
+			}
+		}
+		forecast.AddDay(time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), money.Amount(80000))
+		forecast.Finish()
+
+		require.Equal(t, money.Amount(80000), forecast.Spent)
+		require.Less(t, forecast.Low, forecast.Projected)
+		require.Greater(t, forecast.High, forecast.Projected)
+		require.GreaterOrEqual(t, forecast.Low, forecast.Spent)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Without history the month ends with what is spent", func(t *testing.T) {
+		forecast := NewForecast("IDR", now)
+		forecast.AddDay(time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC), money.Amount(70000))
+		forecast.Finish()
+
+		require.Equal(t, money.Amount(70000), forecast.Projected)
+		require.Equal(t, money.Amount(70000), forecast.Low)
+		require.Equal(t, money.Amount(70000), forecast.High)
+		require.Empty(t, forecast.UpcomingExpenses)
+	})
+}
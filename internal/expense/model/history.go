@@ -0,0 +1,117 @@
+package model
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+)
+
+const (
+	HistoryCreate  = "create"
+	HistoryUpdate  = "update"
+	HistoryDelete  = "delete"
+	HistoryRestore = "restore"
+)
+
+// ExpenseHistory mapping table 'expense_history', one immutable row for every change of an expense.
+// ActorId is nil for a change not made by a user request, Version is the version after the change
+type ExpenseHistory struct {
+	Id        uuid.UUID      `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	ExpenseId uuid.UUID      `json:"expense_id" gorm:"column:id_expense"`
+	UserId    uuid.UUID      `json:"-" gorm:"column:id_user"`
+	ActorId   *uuid.UUID     `json:"actor_id" gorm:"column:id_actor"`
+	Action    string         `json:"action" gorm:"column:action"`
+	RequestId string         `json:"request_id" gorm:"column:request_id"`
+	Version   int64          `json:"version" gorm:"column:version"`
+	Changes   HistoryChanges `json:"changes" gorm:"column:changes;type:jsonb"`
+	CreatedAt int64          `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+}
+
+func (history *ExpenseHistory) TableName() string {
+	return "expense_history"
+}
+
+// FieldChange is the value of a field before and after a change, null when the expense did not exist
+type FieldChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// HistoryChanges are the changed fields of an expense by their json name
+type HistoryChanges map[string]FieldChange
+
+func (changes HistoryChanges) Value() (driver.Value, error) {
+	return json.Marshal(changes)
+}
+
+func (changes *HistoryChanges) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return errors.Errorf("HistoryChanges.Scan: unsupported type %T", src)
+	}
+	return json.Unmarshal(data, changes)
+}
+
+// expenseSnapshot is the part of an expense the history keeps track of
+type expenseSnapshot struct {
+	Description string        `json:"description"`
+	Amount      *money.Amount `json:"amount"`
+	Currency    string        `json:"currency"`
+	CategoryId  uuid.UUID     `json:"category_id"`
+	AccountId   *uuid.UUID    `json:"account_id"`
+	Tags        []string      `json:"tags"`
+}
+
+func snapshot(expense *Expense) map[string]json.RawMessage {
+	fields := make(map[string]json.RawMessage)
+	if expense == nil {
+		return fields
+	}
+	tags := expense.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	data, _ := json.Marshal(&expenseSnapshot{
+		Description: expense.Description,
+		Amount:      expense.Amount,
+		Currency:    expense.Currency,
+		CategoryId:  expense.CategoryId,
+		AccountId:   expense.AccountId,
+		Tags:        tags,
+	})
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+// DiffExpense returns the fields that differ between two states of an expense,
+// before is nil for a created expense and after is nil for a deleted one
+func DiffExpense(before, after *Expense) HistoryChanges {
+	null := json.RawMessage("null")
+	old, current := snapshot(before), snapshot(after)
+
+	changes := make(HistoryChanges)
+	for _, state := range []map[string]json.RawMessage{old, current} {
+		for field := range state {
+			oldValue, ok := old[field]
+			if !ok {
+				oldValue = null
+			}
+			newValue, ok := current[field]
+			if !ok {
+				newValue = null
+			}
+			if !bytes.Equal(oldValue, newValue) {
+				changes[field] = FieldChange{Before: oldValue, After: newValue}
+			}
+		}
+	}
+	return changes
+}
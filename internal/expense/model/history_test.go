@@ -0,0 +1,30 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDiffExpense(t *testing.T) {
+	categoryId := uuid.New()
+	before, after := money.Amount(10000), money.Amount(12500)
+	old := &Expense{Description: "dinner", Amount: &before, Currency: "IDR", CategoryId: categoryId, Tags: []string{"family"}}
+	updated := &Expense{Description: "dinner", Amount: &after, Currency: "IDR", CategoryId: categoryId, Tags: []string{"family", "trip"}}
+
+	changes := DiffExpense(old, updated)
+	require.Len(t, changes, 2)
+	require.JSONEq(t, `"100.00"`, string(changes["amount"].Before))
+	require.JSONEq(t, `"125.00"`, string(changes["amount"].After))
+	require.JSONEq(t, `["family","trip"]`, string(changes["tags"].After))
+
+	// a created expense has every field changed from null
+	created := DiffExpense(nil, old)
+	require.Len(t, created, 5)
+	require.Equal(t, json.RawMessage("null"), created["description"].Before)
+
+	// nothing changed
+	require.Empty(t, DiffExpense(old, old))
+}
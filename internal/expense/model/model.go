@@ -1,5 +1,12 @@
 package model
 
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"sort"
+	"time"
+)
+
 type ApiResponse struct {
 	Status     int         `json:"status"`
 	Message    string      `json:"message"`
@@ -8,12 +15,156 @@ type ApiResponse struct {
 	Limit      int         `json:"limit,omitempty"`
 	TotalItems int64       `json:"total_items,omitempty"`
 	TotalPages int64       `json:"total_pages,omitempty"`
+	NextCursor string      `json:"next_cursor,omitempty"`
 }
 
 type SearchExpenseRequestQueryParam struct {
-	Page      int    `form:"page" validate:"omitempty,numeric,min=1"`
-	Limit     int    `form:"limit" validate:"omitempty,numeric,min=10"`
-	Filter    string `form:"filter" validate:"omitempty,oneof=last_week last_month last_3_month custom"`
-	StartDate string `form:"start_date" validate:"omitempty,datetime=2006-01-02"`
-	EndDate   string `form:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	Page        int    `form:"page" validate:"omitempty,numeric,min=1"`
+	Limit       int    `form:"limit" validate:"omitempty,numeric,min=10"`
+	Cursor      string `form:"cursor" validate:"omitempty,max=1000"`
+	Filter      string `form:"filter" validate:"omitempty,oneof=last_week last_month last_3_month custom"`
+	StartDate   string `form:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate     string `form:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	Tags        string `form:"tags" validate:"omitempty,max=500"`
+	TagsMode    string `form:"tags_mode" validate:"omitempty,oneof=any all"`
+	CategoryId  string `form:"category_id" validate:"omitempty,max=1000"`
+	MinAmount   string `form:"min_amount" validate:"omitempty,numeric"`
+	MaxAmount   string `form:"max_amount" validate:"omitempty,numeric"`
+	Description string `form:"description" validate:"omitempty,max=200"`
+	CreatedFrom string `form:"created_from" validate:"omitempty,datetime=2006-01-02"`
+	CreatedTo   string `form:"created_to" validate:"omitempty,datetime=2006-01-02"`
+	UpdatedFrom string `form:"updated_from" validate:"omitempty,datetime=2006-01-02"`
+	UpdatedTo   string `form:"updated_to" validate:"omitempty,datetime=2006-01-02"`
+	Q           string `form:"q" validate:"omitempty,max=200"`
+	Sort        string `form:"sort" validate:"omitempty,oneof=created_at updated_at amount description currency relevance"`
+	Order       string `form:"order" validate:"omitempty,oneof=asc desc"`
+}
+
+// ExpenseFilter narrows down the expenses read by the repository, every field left
+// to its zero value does not filter. With Tags an expense matches if it has any
+// of the tags (or all of them with MatchAllTags), the time bounds are inclusive unix seconds.
+// Search is a full-text search on the description and the category name
+type ExpenseFilter struct {
+	Search       string
+	Tags         []string
+	MatchAllTags bool
+	CategoryIds  []uuid.UUID
+	MinAmount    *money.Amount
+	MaxAmount    *money.Amount
+	Description  string
+	CreatedFrom  int64
+	CreatedTo    int64
+	UpdatedFrom  int64
+	UpdatedTo    int64
+}
+
+// ExpenseSort orders a listing on one of the whitelisted fields, ties are broken by the expense id.
+// 'relevance' orders on the search rank and only applies with a search
+type ExpenseSort struct {
+	Field      string
+	Descending bool
+}
+
+// DefaultExpenseSort lists the most recent expenses first
+var DefaultExpenseSort = ExpenseSort{Field: "created_at", Descending: true}
+
+// ExpensePage is one page of an expense listing, TotalItems is only counted in page mode
+// (without cursor) and NextCursor is empty on the last page
+type ExpensePage struct {
+	Expenses   []Expense
+	TotalItems int64
+	NextCursor string
+}
+
+// ExpenseReport summarizes the expenses matching a query. Totals and Categories are per currency,
+// Total and BaseCategories are in the base currency of the user (Currency) at the rate of the day
+// of the expenses. amounts without rate to the base currency are left out of them, their currencies
+// are listed in MissingRates
+type ExpenseReport struct {
+	TotalExpenses  int64               `json:"total_expenses"`
+	Totals         []CurrencyTotal     `json:"totals"`
+	Categories     []CategoryTotal     `json:"categories"`
+	Tags           []TagUsage          `json:"tags"`
+	Currency       string              `json:"currency"`
+	Total          money.Amount        `json:"total"`
+	BaseCategories []BaseCategoryTotal `json:"base_categories"`
+	MissingRates   []string            `json:"missing_rates,omitempty"`
+}
+
+// BaseCategoryTotal is the sum of the expenses of one category in the base currency of the user
+type BaseCategoryTotal struct {
+	CategoryId uuid.UUID    `json:"category_id"`
+	Name       string       `json:"name"`
+	Amount     money.Amount `json:"amount"`
+}
+
+// AddConverted adds an amount of a category converted to the base currency
+func (report *ExpenseReport) AddConverted(categoryId uuid.UUID, name string, amount money.Amount) {
+	report.Total = report.Total.Add(amount)
+	for i := range report.BaseCategories {
+		if report.BaseCategories[i].CategoryId == categoryId {
+			report.BaseCategories[i].Amount = report.BaseCategories[i].Amount.Add(amount)
+			return
+		}
+	}
+	report.BaseCategories = append(report.BaseCategories, BaseCategoryTotal{CategoryId: categoryId, Name: name, Amount: amount})
+}
+
+// AddMissingRate lists a currency without rate to the base currency, once
+func (report *ExpenseReport) AddMissingRate(currency string) {
+	for _, missing := range report.MissingRates {
+		if missing == currency {
+			return
+		}
+	}
+	report.MissingRates = append(report.MissingRates, currency)
+}
+
+// SortBaseCategories orders the categories in the base currency like Categories, the biggest first
+func (report *ExpenseReport) SortBaseCategories() {
+	sort.SliceStable(report.BaseCategories, func(i, j int) bool {
+		if report.BaseCategories[i].Amount != report.BaseCategories[j].Amount {
+			return report.BaseCategories[i].Amount > report.BaseCategories[j].Amount
+		}
+		return report.BaseCategories[i].Name < report.BaseCategories[j].Name
+	})
+}
+
+// CurrencyTotal is the sum of the expenses made in one currency
+type CurrencyTotal struct {
+	Currency string       `json:"currency" gorm:"column:currency"`
+	Count    int64        `json:"count" gorm:"column:count"`
+	Amount   money.Amount `json:"amount" gorm:"column:amount"`
+}
+
+// DailyTotal is the sum of the transactions made on one day (UTC) in one currency
+type DailyTotal struct {
+	Day      time.Time    `json:"day" gorm:"column:day"`
+	Currency string       `json:"currency" gorm:"column:currency"`
+	Amount   money.Amount `json:"amount" gorm:"column:amount"`
+}
+
+// CategoryDailyTotal is the sum of the expenses of one category made on one day (UTC) in one currency
+type CategoryDailyTotal struct {
+	CategoryId uuid.UUID    `gorm:"column:id_category"`
+	Name       string       `gorm:"column:name"`
+	Day        time.Time    `gorm:"column:day"`
+	Currency   string       `gorm:"column:currency"`
+	Amount     money.Amount `gorm:"column:amount"`
+}
+
+// CategoryTotal is the sum of the expenses of one category made in one currency
+type CategoryTotal struct {
+	CategoryId uuid.UUID    `json:"category_id" gorm:"column:id_category"`
+	Name       string       `json:"name" gorm:"column:name"`
+	Currency   string       `json:"currency" gorm:"column:currency"`
+	Count      int64        `json:"count" gorm:"column:count"`
+	Amount     money.Amount `json:"amount" gorm:"column:amount"`
+}
+
+// TagUsage is the number of expenses a tag is attached to
+type TagUsage struct {
+	TagId      uuid.UUID `json:"tag_id" gorm:"column:id_tag"`
+	Name       string    `json:"name" gorm:"column:name"`
+	UsageCount int64     `json:"usage_count" gorm:"column:usage_count"`
 }
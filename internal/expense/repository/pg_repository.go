@@ -6,6 +6,7 @@ import (
 	"github.com/mrizkisaputra/expenses-api/internal/expense/model"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"time"
 )
 
 type postgresRepository struct {
@@ -18,10 +19,24 @@ func NewExpensePgRepository(db *gorm.DB) expense.PostgresRepository {
 	}
 }
 
+func (p *postgresRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	/**
+	SQL: BEGIN; ...; COMMIT (or ROLLBACK when fn fails)
+	*/
+	DB := p.db.WithContext(ctx)
+	return DB.Transaction(fn)
+}
+
+func (p *postgresRepository) WithTx(tx *gorm.DB) expense.PostgresRepository {
+	return &postgresRepository{
+		db: tx,
+	}
+}
+
 func (p *postgresRepository) Create(ctx context.Context, expense *model.Expense) error {
 	/**
-	SQL: INSERT INTO "expenses" ("id_user","description","amount","category","created_at","updated_at","deleted_at")
-	VALUES (?,?,?,?,?,?,NULL) RETURNING "id"
+	SQL: INSERT INTO "expenses" ("id_user","description","amount","currency","id_category","created_at","updated_at","deleted_at","version")
+	VALUES (?,?,?,?,?,?,?,NULL,1) RETURNING "id"
 	*/
 	DB := p.db.WithContext(ctx)
 	if err := DB.Create(expense).Error; err != nil {
@@ -43,83 +58,314 @@ func (p *postgresRepository) FindByIdAndUserId(ctx context.Context, expense *mod
 
 func (p *postgresRepository) Remove(ctx context.Context, expense *model.Expense) error {
 	/**
-	SQL: UPDATE "expenses" SET "deleted_at"='2024-11-21 21:25:19.08' WHERE "expenses"."id" = '?' AND "expenses"."deleted_at" IS NULL
+	SQL: UPDATE "expenses" SET "deleted_at"='2024-11-21 21:25:19.08' WHERE version = ? AND "expenses"."id" = '?' AND "expenses"."deleted_at" IS NULL
 	*/
 	DB := p.db.WithContext(ctx)
-	if err := DB.Delete(expense).Error; err != nil {
+	result := DB.Where("version = ?", expense.Version).Delete(expense)
+	if err := result.Error; err != nil {
 		return errors.Wrap(err, "postgresRepository.Remove")
 	}
+	if result.RowsAffected == 0 {
+		return errors.Wrap(model.ErrVersionConflict, "postgresRepository.Remove")
+	}
 	return nil
 }
 
 func (p *postgresRepository) Update(ctx context.Context, expense *model.Expense) error {
 	/**
 	SQL: UPDATE "expenses"
-	SET "id_user"='?',"description"='?',"amount"=?,"category"='?',"updated_at"=? WHERE (id = '?' AND id_user = '?') AND "expenses"."deleted_at" IS NULL AND "id" = '?'
+	SET "id_user"='?',"description"='?',"amount"=?,"currency"='?',"id_category"='?',"updated_at"=?,"version"=?
+	WHERE (id = '?' AND id_user = '?' AND version = ?) AND "expenses"."deleted_at" IS NULL AND "id" = '?'
 	*/
 	DB := p.db.WithContext(ctx)
-	if err := DB.Where("id = ? AND id_user = ?", expense.Id, expense.UserId).Updates(expense).Error; err != nil {
+
+	// the update only applies to the version that was read, a concurrent update already bumped it
+	version := expense.Version
+	expense.Version = version + 1
+	result := DB.Where("id = ? AND id_user = ? AND version = ?", expense.Id, expense.UserId, version).Updates(expense)
+	if err := result.Error; err != nil {
+		expense.Version = version
 		return errors.Wrap(err, "postgresRepository.Update")
 	}
+	if result.RowsAffected == 0 {
+		expense.Version = version
+		return errors.Wrap(model.ErrVersionConflict, "postgresRepository.Update")
+	}
+	return nil
+}
+
+func (p *postgresRepository) Restore(ctx context.Context, expense *model.Expense) error {
+	/**
+	SQL: UPDATE "expenses" SET "deleted_at"=NULL,"updated_at"=?,"version"=version + 1
+	WHERE id = '?' AND id_user = '?' AND deleted_at IS NOT NULL
+	*/
+	DB := p.db.WithContext(ctx)
+	result := DB.Unscoped().Model(&model.Expense{}).
+		Where("id = ? AND id_user = ? AND deleted_at IS NOT NULL", expense.Id, expense.UserId).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"updated_at": time.Now().Unix(),
+			"version":    gorm.Expr("version + 1"),
+		})
+	if err := result.Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Restore")
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrap(gorm.ErrRecordNotFound, "postgresRepository.Restore")
+	}
+	return nil
+}
+
+func (p *postgresRepository) CreateHistory(ctx context.Context, history *model.ExpenseHistory) error {
+	/**
+	SQL: INSERT INTO "expense_history" ("id_expense","id_user","id_actor","action","request_id","version","changes","created_at")
+	VALUES (?,?,?,?,?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(history).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.CreateHistory")
+	}
 	return nil
 }
 
-func (p *postgresRepository) FindAll(ctx context.Context, userId string, offset, limit int) ([]model.Expense, int64, error) {
+func (p *postgresRepository) FindHistory(ctx context.Context, expenseId, userId string) ([]model.ExpenseHistory, error) {
+	/**
+	SQL: SELECT * FROM "expense_history" WHERE id_expense = ? AND id_user = ? ORDER BY created_at, version
+	*/
+	DB := p.db.WithContext(ctx)
+	var history []model.ExpenseHistory
+	err := DB.Where("id_expense = ? AND id_user = ?", expenseId, userId).
+		Order("created_at, version").
+		Find(&history).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindHistory")
+	}
+	return history, nil
+}
+
+func (p *postgresRepository) FindAll(
+	ctx context.Context,
+	userId string,
+	filter *model.ExpenseFilter,
+	sort *model.ExpenseSort,
+	offset, limit int,
+) ([]model.Expense, int64, error) {
 	DB := p.db.WithContext(ctx)
 
 	/**
-	SQL: SELECT * FROM "expenses" WHERE id_user = ? AND "expenses"."deleted_at" IS NULL LIMIT 10
+	SQL: SELECT * FROM "expenses" WHERE expenses.id_user = ? AND ... AND "expenses"."deleted_at" IS NULL
+	ORDER BY expenses.created_at DESC, expenses.id DESC LIMIT 10
+	with a search: SELECT expenses.*, ts_rank(...) + word_similarity(...) AS search_rank, ts_headline(...) AS snippet
+	FROM "expenses" WHERE ... AND (expenses.search_vector @@ websearch_to_tsquery('simple', ?) OR ? <% expenses.description
+	OR expenses.id_category IN (...)) ORDER BY search_rank DESC, expenses.id DESC
 	*/
 	var expenses []model.Expense
-	if err := DB.Offset((offset-1)*limit).
-		Limit(limit).
-		Where("id_user = ?", userId).
+	if err := newExpenseQuery(DB, userId).
+		Filter(filter).
+		Ranked().
+		Sort(sort).
+		Page(offset, limit).
+		DB().
 		Find(&expenses).Error; err != nil {
 		return nil, 0, errors.Wrap(err, "postgresRepository.FindAll.Find")
 	}
 
 	/**
-	SQL: SELECT count(*) FROM "expenses" WHERE "expenses"."deleted_at" IS NULL
+	SQL: SELECT count(*) FROM "expenses" WHERE expenses.id_user = ? AND ... AND "expenses"."deleted_at" IS NULL
 	*/
 	var count int64
-	if err := DB.Model(&model.Expense{}).Count(&count).Error; err != nil {
+	if err := newExpenseQuery(DB, userId).
+		Filter(filter).
+		DB().
+		Count(&count).Error; err != nil {
 		return nil, 0, errors.Wrap(err, "postgresRepository.FindAll.Count")
 	}
 
 	return expenses, count, nil
 }
 
-func (p *postgresRepository) FindAllByDateRange(
+// FindAllAfter reads the next limit expenses after the cursor (keyset pagination), the expenses
+// are not counted so the cost of a page does not grow with the number of expenses of the user
+func (p *postgresRepository) FindAllAfter(
 	ctx context.Context,
 	userId string,
-	startDate, endDate int64,
-	offset, limit int,
-) ([]model.Expense, int64, error) {
+	filter *model.ExpenseFilter,
+	sort *model.ExpenseSort,
+	after *model.ExpenseCursor,
+	limit int,
+) ([]model.Expense, error) {
 	DB := p.db.WithContext(ctx)
 
 	/**
-	SQL:
+	SQL: SELECT * FROM "expenses" WHERE expenses.id_user = ? AND ... AND (expenses.created_at, expenses.id) < (?, ?)
+	AND "expenses"."deleted_at" IS NULL ORDER BY expenses.created_at DESC, expenses.id DESC LIMIT 10
 	*/
 	var expenses []model.Expense
-	if err := DB.Where("id_user = ? AND created_at BETWEEN ? AND ?", userId, startDate, endDate).
-		Order("created_at DESC").
-		Offset((offset - 1) * limit).
+	if err := newExpenseQuery(DB, userId).
+		Filter(filter).
+		After(after).
+		Ranked().
+		Sort(sort).
+		DB().
 		Limit(limit).
 		Find(&expenses).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindAllAfter")
+	}
+	return expenses, nil
+}
 
-		return nil, 0, errors.Wrap(err, "postgresRepository.FindAllByDateRange")
+// Summarize computes the totals per currency, per category and the tag usage
+// of the expenses of the user matching filter
+func (p *postgresRepository) Summarize(ctx context.Context, userId string, filter *model.ExpenseFilter) (*model.ExpenseReport, error) {
+	DB := p.db.WithContext(ctx)
+
+	// matching builds a new query on the matching expenses every time it is called
+	// so the statements of the sub queries do not leak into each other
+	matching := func() *gorm.DB {
+		return newExpenseQuery(DB, userId).Filter(filter).DB()
 	}
 
+	report := new(model.ExpenseReport)
+
 	/**
-	SQL:
+	SQL: SELECT currency, COUNT(*) AS count, SUM(amount) AS amount FROM "expenses"
+	WHERE expenses.id_user = ? AND ... AND "expenses"."deleted_at" IS NULL GROUP BY "currency" ORDER BY currency
 	*/
-	var total int64
-	if err := DB.Model(&model.Expense{}).
-		Where("id_user = ? AND created_at BETWEEN ? AND ?", userId, startDate, endDate).
-		Count(&total).Error; err != nil {
+	if err := matching().
+		Select("currency, COUNT(*) AS count, SUM(amount) AS amount").
+		Group("currency").
+		Order("currency").
+		Scan(&report.Totals).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.Summarize.Totals")
+	}
+	for _, total := range report.Totals {
+		report.TotalExpenses += total.Count
+	}
 
-		return nil, 0, errors.Wrap(err, "postgresRepository.FindAllByDateRange.Count")
+	/**
+	SQL: SELECT expenses.id_category, categories.name, expenses.currency, COUNT(*) AS count, SUM(expenses.amount) AS amount
+	FROM "expenses" JOIN categories ON categories.id = expenses.id_category
+	WHERE expenses.id_user = ? AND ... AND "expenses"."deleted_at" IS NULL
+	GROUP BY expenses.id_category, categories.name, expenses.currency ORDER BY amount DESC, categories.name
+	*/
+	if err := matching().
+		Select("expenses.id_category, categories.name, expenses.currency, COUNT(*) AS count, SUM(expenses.amount) AS amount").
+		Joins("JOIN categories ON categories.id = expenses.id_category").
+		Group("expenses.id_category, categories.name, expenses.currency").
+		Order("amount DESC, categories.name").
+		Scan(&report.Categories).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.Summarize.Categories")
+	}
+
+	/**
+	SQL: SELECT tags.id AS id_tag, tags.name, COUNT(*) AS usage_count FROM expense_tags
+	JOIN tags ON tags.id = expense_tags.id_tag
+	WHERE expense_tags.id_expense IN (SELECT "id" FROM "expenses" WHERE ...)
+	GROUP BY tags.id, tags.name ORDER BY usage_count DESC, tags.name
+	*/
+	if err := DB.Table("expense_tags").
+		Select("tags.id AS id_tag, tags.name, COUNT(*) AS usage_count").
+		Joins("JOIN tags ON tags.id = expense_tags.id_tag").
+		Where("expense_tags.id_expense IN (?)", matching().Select("expenses.id")).
+		Group("tags.id, tags.name").
+		Order("usage_count DESC, tags.name").
+		Scan(&report.Tags).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.Summarize.Tags")
 	}
 
-	return expenses, total, nil
+	return report, nil
+}
+
+func (p *postgresRepository) SumByDay(ctx context.Context, userId string, filter *model.ExpenseFilter) ([]model.DailyTotal, error) {
+	/**
+	SQL: SELECT (TO_TIMESTAMP(expenses.created_at) AT TIME ZONE 'UTC')::DATE AS day, expenses.currency, SUM(expenses.amount) AS amount
+	FROM "expenses" WHERE expenses.id_user = ? AND ... AND "expenses"."deleted_at" IS NULL
+	GROUP BY day, expenses.currency ORDER BY day, expenses.currency
+	*/
+	DB := p.db.WithContext(ctx)
+	var totals []model.DailyTotal
+	if err := newExpenseQuery(DB, userId).Filter(filter).DB().
+		Select("(TO_TIMESTAMP(expenses.created_at) AT TIME ZONE 'UTC')::DATE AS day, expenses.currency, SUM(expenses.amount) AS amount").
+		Group("day, expenses.currency").
+		Order("day, expenses.currency").
+		Scan(&totals).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.SumByDay")
+	}
+	return totals, nil
+}
+
+func (p *postgresRepository) SumByCategoryAndDay(ctx context.Context, userId string, filter *model.ExpenseFilter) ([]model.CategoryDailyTotal, error) {
+	/**
+	SQL: SELECT expenses.id_category, categories.name, (TO_TIMESTAMP(expenses.created_at) AT TIME ZONE 'UTC')::DATE AS day,
+	expenses.currency, SUM(expenses.amount) AS amount FROM "expenses" JOIN categories ON categories.id = expenses.id_category
+	WHERE expenses.id_user = ? AND ... AND "expenses"."deleted_at" IS NULL
+	GROUP BY expenses.id_category, categories.name, day, expenses.currency ORDER BY day, expenses.currency
+	*/
+	DB := p.db.WithContext(ctx)
+	var totals []model.CategoryDailyTotal
+	if err := newExpenseQuery(DB, userId).Filter(filter).DB().
+		Select("expenses.id_category, categories.name, (TO_TIMESTAMP(expenses.created_at) AT TIME ZONE 'UTC')::DATE AS day, expenses.currency, SUM(expenses.amount) AS amount").
+		Joins("JOIN categories ON categories.id = expenses.id_category").
+		Group("expenses.id_category, categories.name, day, expenses.currency").
+		Order("day, expenses.currency").
+		Scan(&totals).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.SumByCategoryAndDay")
+	}
+	return totals, nil
+}
+
+func (p *postgresRepository) FindDuplicateCandidates(
+	ctx context.Context,
+	userId string,
+	filter *model.ExpenseFilter,
+	window int64,
+	limit int,
+) ([]model.Expense, error) {
+	/**
+	SQL: SELECT * FROM "expenses" WHERE expenses.id_user = ? AND ... AND EXISTS (SELECT 1 FROM expenses AS other
+	WHERE other.id_user = expenses.id_user AND other.id <> expenses.id AND other.amount = expenses.amount
+	AND other.currency = expenses.currency AND ABS(other.created_at - expenses.created_at) <= ? AND other.deleted_at IS NULL)
+	AND "expenses"."deleted_at" IS NULL ORDER BY expenses.currency, expenses.amount, expenses.created_at, expenses.id LIMIT 1000
+	*/
+	DB := p.db.WithContext(ctx)
+	var expenses []model.Expense
+	if err := newExpenseQuery(DB, userId).Filter(filter).DB().
+		Where("EXISTS (SELECT 1 FROM expenses AS other WHERE other.id_user = expenses.id_user AND other.id <> expenses.id "+
+			"AND other.amount = expenses.amount AND other.currency = expenses.currency "+
+			"AND ABS(other.created_at - expenses.created_at) <= ? AND other.deleted_at IS NULL)", window).
+		Order("expenses.currency, expenses.amount, expenses.created_at, expenses.id").
+		Limit(limit).
+		Find(&expenses).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindDuplicateCandidates")
+	}
+	return expenses, nil
+}
+
+// FindRecurring reads the expenses made once a month, in at least months different months, with the same
+// description, category and currency. the day of the month they are made on is the median of their days
+func (p *postgresRepository) FindRecurring(ctx context.Context, userId string, filter *model.ExpenseFilter, months int) ([]model.RecurringExpense, error) {
+	/**
+	SQL: SELECT expenses.description, expenses.id_category, expenses.currency, ROUND(AVG(expenses.amount))::BIGINT AS amount,
+	PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(DAY FROM TO_TIMESTAMP(expenses.created_at) AT TIME ZONE 'UTC'))::INT AS day,
+	MAX(expenses.created_at) AS last_at FROM "expenses" WHERE expenses.id_user = ? AND ... AND expenses.description <> ''
+	AND "expenses"."deleted_at" IS NULL GROUP BY expenses.description, expenses.id_category, expenses.currency
+	HAVING COUNT(DISTINCT DATE_TRUNC('month', ...)) >= ? AND COUNT(*) = COUNT(DISTINCT DATE_TRUNC('month', ...))
+	ORDER BY day, expenses.description
+	*/
+	const month = "DATE_TRUNC('month', TO_TIMESTAMP(expenses.created_at) AT TIME ZONE 'UTC')"
+
+	DB := p.db.WithContext(ctx)
+	var recurring []model.RecurringExpense
+	if err := newExpenseQuery(DB, userId).Filter(filter).DB().
+		Select("expenses.description, expenses.id_category, expenses.currency, ROUND(AVG(expenses.amount))::BIGINT AS amount, "+
+			"PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(DAY FROM TO_TIMESTAMP(expenses.created_at) AT TIME ZONE 'UTC'))::INT AS day, "+
+			"MAX(expenses.created_at) AS last_at").
+		Where("expenses.description <> ''").
+		Group("expenses.description, expenses.id_category, expenses.currency").
+		Having("COUNT(DISTINCT "+month+") >= ? AND COUNT(*) = COUNT(DISTINCT "+month+")", months).
+		Order("day, expenses.description").
+		Scan(&recurring).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindRecurring")
+	}
+	return recurring, nil
 }
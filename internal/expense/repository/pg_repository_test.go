@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/mrizkisaputra/expenses-api/internal/expense"
 	"github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -55,22 +56,24 @@ func TestMain(m *testing.M) {
 
 func TestExpensePostgresRepository_Create(t *testing.T) {
 	userId := uuid.New()
-	amount := 100.00
+	amount := money.Amount(10000)
 	e := &model.Expense{
 		UserId:      userId,
 		Description: "dinner with family",
 		Amount:      &amount,
-		Category:    "Dinner",
+		Currency:    "IDR",
+		CategoryId:  uuid.New(),
 		CreatedAt:   time.Now().UnixMilli(),
 		UpdatedAt:   time.Now().UnixMilli(),
+		Version:     1,
 	}
 
 	// define expect sql
-	expectedSQLCreateExpense := regexp.QuoteMeta(`INSERT INTO "expenses" ("id_user","description","amount","category","created_at","updated_at","deleted_at")
-	VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)
+	expectedSQLCreateExpense := regexp.QuoteMeta(`INSERT INTO "expenses" ("id_user","description","amount","currency","id_category","id_account","created_at","updated_at","deleted_at","version")
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`)
 	mock.ExpectBegin()
 	mock.ExpectQuery(expectedSQLCreateExpense).
-		WithArgs(e.UserId, e.Description, e.Amount, e.Category, e.CreatedAt, e.UpdatedAt, e.DeletedAt).
+		WithArgs(e.UserId, e.Description, e.Amount, e.Currency, e.CategoryId, e.AccountId, e.CreatedAt, e.UpdatedAt, e.DeletedAt, e.Version).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
 	mock.ExpectCommit()
 
@@ -90,8 +93,8 @@ func TestExpensePostgresRepository_FindByIdAndUserId(t *testing.T) {
 
 	// define expect sql
 	expectedSQLFindByIdAnUserId := regexp.QuoteMeta(`SELECT * FROM "expenses" WHERE (id = $1 AND id_user = $2) AND "expenses"."deleted_at" IS NULL LIMIT $3`)
-	rows := sqlmock.NewRows([]string{"id", "id_user", "description", "amount", "category", "created_at", "updated_at", "deleted_at"}).
-		AddRow(id, userId, "dinner with family", 100.00, "dinner", time.Now().UnixMilli(), time.Now().UnixMilli(), nil)
+	rows := sqlmock.NewRows([]string{"id", "id_user", "description", "amount", "id_category", "created_at", "updated_at", "deleted_at"}).
+		AddRow(id, userId, "dinner with family", 10000, uuid.New(), time.Now().UnixMilli(), time.Now().UnixMilli(), nil)
 	mock.ExpectQuery(expectedSQLFindByIdAnUserId).WithArgs(id, userId, 1).
 		WillReturnRows(rows)
 
@@ -112,19 +115,20 @@ func TestExpensePostgresRepository_FindByIdAndUserId(t *testing.T) {
 func TestExpensePostgresRepository_Remove(t *testing.T) {
 	userId := uuid.New()
 	id := uuid.New()
-	amount := 20.00
+	amount := money.Amount(2000)
 	expenses := &model.Expense{
 		Id:          id,
 		UserId:      userId,
 		Description: "Breakfast with family",
 		Amount:      &amount,
-		Category:    "Breakfast",
+		CategoryId:  uuid.New(),
+		Version:     3,
 	}
 
 	// define expect sql
-	expectedSQLRemove := regexp.QuoteMeta(`UPDATE "expenses" SET "deleted_at"=$1 WHERE "expenses"."id" = $2 AND "expenses"."deleted_at" IS NULL`)
+	expectedSQLRemove := regexp.QuoteMeta(`UPDATE "expenses" SET "deleted_at"=$1 WHERE version = $2 AND "expenses"."id" = $3 AND "expenses"."deleted_at" IS NULL`)
 	mock.ExpectBegin()
-	mock.ExpectExec(expectedSQLRemove).WithArgs(sqlmock.AnyArg(), id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(expectedSQLRemove).WithArgs(sqlmock.AnyArg(), int64(3), id).WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
 	err := repository.Remove(context.Background(), expenses)
@@ -138,13 +142,319 @@ func TestExpensePostgresRepository_Remove(t *testing.T) {
 }
 
 func TestExpensePostgresRepository_Update(t *testing.T) {
+	userId := uuid.New()
+	id := uuid.New()
+	categoryId := uuid.New()
+	amount := money.Amount(5000)
+	newExpense := func() *model.Expense {
+		return &model.Expense{
+			Id:          id,
+			UserId:      userId,
+			Description: "lunch",
+			Amount:      &amount,
+			Currency:    "IDR",
+			CategoryId:  categoryId,
+			Version:     2,
+		}
+	}
+
+	// define expect sql
+	expectedSQLUpdate := regexp.QuoteMeta(`UPDATE "expenses" SET "id_user"=$1,"description"=$2,"amount"=$3,"currency"=$4,"id_category"=$5,"updated_at"=$6,"version"=$7 WHERE (id = $8 AND id_user = $9 AND version = $10) AND "expenses"."deleted_at" IS NULL AND "id" = $11`)
+
+	t.Run("[Test Case #1] the stored version is updated and bumped", func(t *testing.T) {
+		expenses := newExpense()
+		mock.ExpectBegin()
+		mock.ExpectExec(expectedSQLUpdate).
+			WithArgs(userId, "lunch", &amount, "IDR", categoryId, sqlmock.AnyArg(), int64(3), id, userId, int64(2), id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repository.Update(context.Background(), expenses)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), expenses.Version)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("[Test Case #2] a concurrent update already bumped the version", func(t *testing.T) {
+		expenses := newExpense()
+		mock.ExpectBegin()
+		mock.ExpectExec(expectedSQLUpdate).
+			WithArgs(userId, "lunch", &amount, "IDR", categoryId, sqlmock.AnyArg(), int64(3), id, userId, int64(2), id).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
 
+		err := repository.Update(context.Background(), expenses)
+		require.ErrorIs(t, err, model.ErrVersionConflict)
+		require.Equal(t, int64(2), expenses.Version)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestExpensePostgresRepository_FindAll(t *testing.T) {
+	userId := uuid.New()
+	filter := &model.ExpenseFilter{Tags: []string{"business", "reimbursable"}, MatchAllTags: true}
+
+	// define expect sql
+	expectedSQLFind := regexp.QuoteMeta(`SELECT * FROM "expenses" WHERE expenses.id_user = $1 AND expenses.id IN (SELECT expense_tags.id_expense FROM "expense_tags" JOIN tags ON tags.id = expense_tags.id_tag WHERE tags.name IN ($2,$3) GROUP BY "expense_tags"."id_expense" HAVING COUNT(DISTINCT tags.id) = $4) AND "expenses"."deleted_at" IS NULL ORDER BY expenses.created_at DESC,expenses.id DESC LIMIT $5`)
+	rows := sqlmock.NewRows([]string{"id", "id_user", "description", "amount", "currency", "id_category", "created_at", "updated_at", "deleted_at"}).
+		AddRow(uuid.New(), userId, "flight", 150000000, "IDR", uuid.New(), time.Now().Unix(), time.Now().Unix(), nil)
+	mock.ExpectQuery(expectedSQLFind).WithArgs(userId.String(), "business", "reimbursable", 2, 10).WillReturnRows(rows)
+
+	expectedSQLCount := regexp.QuoteMeta(`SELECT count(*) FROM "expenses" WHERE expenses.id_user = $1 AND expenses.id IN (SELECT expense_tags.id_expense FROM "expense_tags" JOIN tags ON tags.id = expense_tags.id_tag WHERE tags.name IN ($2,$3) GROUP BY "expense_tags"."id_expense" HAVING COUNT(DISTINCT tags.id) = $4) AND "expenses"."deleted_at" IS NULL`)
+	mock.ExpectQuery(expectedSQLCount).WithArgs(userId.String(), "business", "reimbursable", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	expenses, total, err := repository.FindAll(context.Background(), userId.String(), filter, nil, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, expenses, 1)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, money.Amount(150000000), *expenses[0].Amount)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
+
+func TestExpensePostgresRepository_FindAll_FilterAndSort(t *testing.T) {
+	userId := uuid.New()
+	categoryId := uuid.New()
+	min, max := money.Amount(1000), money.Amount(50000)
+	filter := &model.ExpenseFilter{
+		CategoryIds: []uuid.UUID{categoryId},
+		MinAmount:   &min,
+		MaxAmount:   &max,
+		Description: "50%_off",
+		CreatedFrom: 1767225600,
+		UpdatedTo:   1769903999,
+	}
+	sort := &model.ExpenseSort{Field: "amount"}
+
+	// define expect sql
+	conditions := `expenses.id_user = $1 AND expenses.id_category IN ($2) AND expenses.amount >= $3 AND expenses.amount <= $4 AND expenses.description ILIKE $5 AND expenses.created_at >= $6 AND expenses.updated_at <= $7 AND "expenses"."deleted_at" IS NULL`
+	expectedSQLFind := regexp.QuoteMeta(`SELECT * FROM "expenses" WHERE ` + conditions + ` ORDER BY expenses.amount ASC,expenses.id ASC LIMIT $8 OFFSET $9`)
+	mock.ExpectQuery(expectedSQLFind).
+		WithArgs(userId.String(), categoryId, min, max, `%50\%\_off%`, int64(1767225600), int64(1769903999), 10, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	expectedSQLCount := regexp.QuoteMeta(`SELECT count(*) FROM "expenses" WHERE ` + conditions)
+	mock.ExpectQuery(expectedSQLCount).
+		WithArgs(userId.String(), categoryId, min, max, `%50\%\_off%`, int64(1767225600), int64(1769903999)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	expenses, total, err := repository.FindAll(context.Background(), userId.String(), filter, sort, 2, 10)
+	require.NoError(t, err)
+	require.Empty(t, expenses)
+	require.Equal(t, int64(0), total)
 
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
 }
 
-func TestExpensePostgresRepository_FindAllByDateRange(t *testing.T) {
+func TestExpensePostgresRepository_FindAll_Search(t *testing.T) {
+	userId := uuid.New()
+	filter := &model.ExpenseFilter{Search: "parking bandung"}
+	sort := &model.ExpenseSort{Field: "relevance", Descending: true}
+
+	// define expect sql
+	conditions := `expenses.id_user = $4 AND (expenses.search_vector @@ websearch_to_tsquery('simple', $5) OR $6 <% expenses.description OR expenses.id_category IN (SELECT categories.id FROM "categories" WHERE $7 <% categories.name)) AND "expenses"."deleted_at" IS NULL`
+	expectedSQLFind := regexp.QuoteMeta(`SELECT expenses.*, (ts_rank(expenses.search_vector, websearch_to_tsquery('simple', $1)) + word_similarity($2, expenses.description))::float8 AS search_rank, ts_headline('simple', expenses.description, websearch_to_tsquery('simple', $3), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS snippet FROM "expenses" WHERE ` + conditions + ` ORDER BY search_rank DESC,expenses.id DESC LIMIT $8`)
+	rows := sqlmock.NewRows([]string{"id", "id_user", "description", "amount", "currency", "id_category", "created_at", "updated_at", "deleted_at", "search_rank", "snippet"}).
+		AddRow(uuid.New(), userId, "parking ticket in bandung", 1000000, "IDR", uuid.New(), time.Now().Unix(), time.Now().Unix(), nil, 0.91, "<mark>parking</mark> ticket in <mark>bandung</mark>")
+	mock.ExpectQuery(expectedSQLFind).
+		WithArgs("parking bandung", "parking bandung", "parking bandung", userId.String(), "parking bandung", "parking bandung", "parking bandung", 10).
+		WillReturnRows(rows)
+
+	expectedSQLCount := regexp.QuoteMeta(`SELECT count(*) FROM "expenses" WHERE expenses.id_user = $1 AND (expenses.search_vector @@ websearch_to_tsquery('simple', $2) OR $3 <% expenses.description OR expenses.id_category IN (SELECT categories.id FROM "categories" WHERE $4 <% categories.name)) AND "expenses"."deleted_at" IS NULL`)
+	mock.ExpectQuery(expectedSQLCount).
+		WithArgs(userId.String(), "parking bandung", "parking bandung", "parking bandung").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	expenses, total, err := repository.FindAll(context.Background(), userId.String(), filter, sort, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, 0.91, *expenses[0].SearchRank)
+	require.Equal(t, "<mark>parking</mark> ticket in <mark>bandung</mark>", *expenses[0].Snippet)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
+
+func TestExpensePostgresRepository_FindAllAfter(t *testing.T) {
+	userId := uuid.New()
+	after := &model.ExpenseCursor{Sort: model.DefaultExpenseSort, Value: int64(1767225600), Id: uuid.New()}
+
+	// define expect sql
+	expectedSQL := regexp.QuoteMeta(`SELECT * FROM "expenses" WHERE expenses.id_user = $1 AND (expenses.created_at, expenses.id) < ($2, $3) AND "expenses"."deleted_at" IS NULL ORDER BY expenses.created_at DESC,expenses.id DESC LIMIT $4`)
+	rows := sqlmock.NewRows([]string{"id", "id_user", "description", "amount", "currency", "id_category", "created_at", "updated_at", "deleted_at"}).
+		AddRow(uuid.New(), userId, "lunch", 5000000, "IDR", uuid.New(), 1767225500, 1767225500, nil)
+	mock.ExpectQuery(expectedSQL).WithArgs(userId.String(), int64(1767225600), after.Id, 11).WillReturnRows(rows)
+
+	expenses, err := repository.FindAllAfter(context.Background(), userId.String(), nil, &model.DefaultExpenseSort, after, 11)
+	require.NoError(t, err)
+	require.Len(t, expenses, 1)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
+
+func TestExpensePostgresRepository_Restore(t *testing.T) {
+	userId := uuid.New()
+	id := uuid.New()
+
+	// define expect sql
+	expectedSQLRestore := regexp.QuoteMeta(`UPDATE "expenses" SET "deleted_at"=$1,"updated_at"=$2,"version"=version + 1 WHERE id = $3 AND id_user = $4 AND deleted_at IS NOT NULL`)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] A deleted expense is restored", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(expectedSQLRestore).WithArgs(nil, sqlmock.AnyArg(), id, userId).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
 
+		err := repository.Restore(context.Background(), &model.Expense{Id: id, UserId: userId})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return not found when the expense is not deleted", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(expectedSQLRestore).WithArgs(nil, sqlmock.AnyArg(), id, userId).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repository.Restore(context.Background(), &model.Expense{Id: id, UserId: userId})
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExpensePostgresRepository_FindHistory(t *testing.T) {
+	userId := uuid.New()
+	id := uuid.New()
+
+	// define expect sql
+	expectedSQL := regexp.QuoteMeta(`SELECT * FROM "expense_history" WHERE id_expense = $1 AND id_user = $2 ORDER BY created_at, version`)
+	rows := sqlmock.NewRows([]string{"id", "id_expense", "id_user", "id_actor", "action", "request_id", "version", "changes", "created_at"}).
+		AddRow(uuid.New(), id, userId, userId, "update", "f3b1", 2, []byte(`{"amount":{"before":"100.00","after":"125.00"}}`), time.Now().UnixMilli())
+	mock.ExpectQuery(expectedSQL).WithArgs(id.String(), userId.String()).WillReturnRows(rows)
+
+	history, err := repository.FindHistory(context.Background(), id.String(), userId.String())
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, model.HistoryUpdate, history[0].Action)
+	require.JSONEq(t, `"125.00"`, string(history[0].Changes["amount"].After))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExpensePostgresRepository_SumByDay(t *testing.T) {
+	userId := uuid.New()
+	day := time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC)
+	filter := &model.ExpenseFilter{CreatedFrom: 1767225600, CreatedTo: 1769903999}
+
+	// define expect sql
+	expectedSQL := regexp.QuoteMeta(`SELECT (TO_TIMESTAMP(expenses.created_at) AT TIME ZONE 'UTC')::DATE AS day, expenses.currency, SUM(expenses.amount) AS amount FROM "expenses" WHERE expenses.id_user = $1 AND expenses.created_at >= $2 AND expenses.created_at <= $3 AND "expenses"."deleted_at" IS NULL GROUP BY day, expenses.currency ORDER BY day, expenses.currency`)
+	rows := sqlmock.NewRows([]string{"day", "currency", "amount"}).AddRow(day, "IDR", 4500000)
+	mock.ExpectQuery(expectedSQL).
+		WithArgs(userId.String(), int64(1767225600), int64(1769903999)).
+		WillReturnRows(rows)
+
+	totals, err := repository.SumByDay(context.Background(), userId.String(), filter)
+	require.NoError(t, err)
+	require.Len(t, totals, 1)
+	require.True(t, day.Equal(totals[0].Day))
+	require.Equal(t, money.Amount(4500000), totals[0].Amount)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
+
+func TestExpensePostgresRepository_SumByCategoryAndDay(t *testing.T) {
+	userId := uuid.New()
+	categoryId := uuid.New()
+	day := time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC)
+	filter := &model.ExpenseFilter{CreatedFrom: 1767225600, CreatedTo: 1769903999}
+
+	// define expect sql
+	expectedSQL := regexp.QuoteMeta(`SELECT expenses.id_category, categories.name, (TO_TIMESTAMP(expenses.created_at) AT TIME ZONE 'UTC')::DATE AS day, ` +
+		`expenses.currency, SUM(expenses.amount) AS amount FROM "expenses" JOIN categories ON categories.id = expenses.id_category ` +
+		`WHERE expenses.id_user = $1 AND expenses.created_at >= $2 AND expenses.created_at <= $3 AND "expenses"."deleted_at" IS NULL ` +
+		`GROUP BY expenses.id_category, categories.name, day, expenses.currency ORDER BY day, expenses.currency`)
+	rows := sqlmock.NewRows([]string{"id_category", "name", "day", "currency", "amount"}).AddRow(categoryId, "food", day, "USD", 1250)
+	mock.ExpectQuery(expectedSQL).
+		WithArgs(userId.String(), int64(1767225600), int64(1769903999)).
+		WillReturnRows(rows)
+
+	totals, err := repository.SumByCategoryAndDay(context.Background(), userId.String(), filter)
+	require.NoError(t, err)
+	require.Len(t, totals, 1)
+	require.Equal(t, categoryId, totals[0].CategoryId)
+	require.True(t, day.Equal(totals[0].Day))
+	require.Equal(t, money.Amount(1250), totals[0].Amount)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
+
+func TestExpensePostgresRepository_FindRecurring(t *testing.T) {
+	userId := uuid.New()
+	categoryId := uuid.New()
+	filter := &model.ExpenseFilter{CreatedFrom: 1759276800, CreatedTo: 1767225599}
+
+	// define expect sql
+	month := "DATE_TRUNC('month', TO_TIMESTAMP(expenses.created_at) AT TIME ZONE 'UTC')"
+	expectedSQL := regexp.QuoteMeta(`SELECT expenses.description, expenses.id_category, expenses.currency, ROUND(AVG(expenses.amount))::BIGINT AS amount, ` +
+		`PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(DAY FROM TO_TIMESTAMP(expenses.created_at) AT TIME ZONE 'UTC'))::INT AS day, ` +
+		`MAX(expenses.created_at) AS last_at FROM "expenses" WHERE expenses.id_user = $1 AND expenses.created_at >= $2 AND expenses.created_at <= $3 ` +
+		`AND expenses.description <> '' AND "expenses"."deleted_at" IS NULL GROUP BY expenses.description, expenses.id_category, expenses.currency ` +
+		`HAVING COUNT(DISTINCT ` + month + `) >= $4 AND COUNT(*) = COUNT(DISTINCT ` + month + `) ORDER BY day, expenses.description`)
+	rows := sqlmock.NewRows([]string{"description", "id_category", "currency", "amount", "day", "last_at"}).
+		AddRow("rent", categoryId, "IDR", 350000000, 1, 1764550800)
+	mock.ExpectQuery(expectedSQL).
+		WithArgs(userId.String(), int64(1759276800), int64(1767225599), 3).
+		WillReturnRows(rows)
+
+	recurring, err := repository.FindRecurring(context.Background(), userId.String(), filter, 3)
+	require.NoError(t, err)
+	require.Len(t, recurring, 1)
+	require.Equal(t, categoryId, recurring[0].CategoryId)
+	require.Equal(t, money.Amount(350000000), recurring[0].Amount)
+	require.Equal(t, 1, recurring[0].Day)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
+
+func TestExpensePostgresRepository_FindDuplicateCandidates(t *testing.T) {
+	userId := uuid.New()
+	filter := &model.ExpenseFilter{CreatedFrom: 1767225600}
+
+	// define expect sql
+	expectedSQL := regexp.QuoteMeta(`SELECT * FROM "expenses" WHERE expenses.id_user = $1 AND expenses.created_at >= $2 AND (EXISTS (SELECT 1 FROM expenses AS other WHERE other.id_user = expenses.id_user AND other.id <> expenses.id AND other.amount = expenses.amount AND other.currency = expenses.currency AND ABS(other.created_at - expenses.created_at) <= $3 AND other.deleted_at IS NULL)) AND "expenses"."deleted_at" IS NULL ORDER BY expenses.currency, expenses.amount, expenses.created_at, expenses.id LIMIT $4`)
+	rows := sqlmock.NewRows([]string{"id", "description", "amount", "currency", "created_at"}).
+		AddRow(uuid.New(), "coffee", 2500000, "IDR", 1767225600).
+		AddRow(uuid.New(), "coffee shop", 2500000, "IDR", 1767229200)
+	mock.ExpectQuery(expectedSQL).
+		WithArgs(userId.String(), int64(1767225600), model.DuplicateWindow, 1000).
+		WillReturnRows(rows)
+
+	expenses, err := repository.FindDuplicateCandidates(context.Background(), userId.String(), filter, model.DuplicateWindow, 1000)
+	require.NoError(t, err)
+	require.Len(t, expenses, 2)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
 }
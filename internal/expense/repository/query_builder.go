@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// sortColumns whitelists the fields an expense listing can be ordered by
+var sortColumns = map[string]string{
+	"created_at":  "expenses.created_at",
+	"updated_at":  "expenses.updated_at",
+	"amount":      "expenses.amount",
+	"description": "expenses.description",
+	"currency":    "expenses.currency",
+}
+
+// searchQuery parses the search text with the websearch syntax ("quoted phrase", or, -word)
+const searchQuery = "websearch_to_tsquery('simple', ?)"
+
+// searchRank is the relevance of a searched expense, the trigram word similarity is added
+// to the rank so partial words still score. it is a float8 so a cursor compares to it exactly
+const searchRank = "(ts_rank(expenses.search_vector, " + searchQuery + ") + word_similarity(?, expenses.description))::float8"
+
+// searchColumns are the rank and highlighted snippet of a searched expense
+const searchColumns = "expenses.*, " + searchRank + " AS search_rank, " +
+	"ts_headline('simple', expenses.description, " + searchQuery + ", 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS snippet"
+
+// likeEscaper escapes the wildcards of a LIKE pattern, '\' is the default escape character of postgres
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// expenseQuery composes the conditions shared by every expense listing and report,
+// each method narrows the query and returns it so the calls can be chained.
+// a query must not be reused once executed, build a new one instead
+type expenseQuery struct {
+	db     *gorm.DB
+	search string
+}
+
+// newExpenseQuery starts a query on the (non deleted) expenses of the user
+func newExpenseQuery(db *gorm.DB, userId string) *expenseQuery {
+	return &expenseQuery{db: db.Model(&model.Expense{}).Where("expenses.id_user = ?", userId)}
+}
+
+// Filter applies every condition of the filter
+func (q *expenseQuery) Filter(filter *model.ExpenseFilter) *expenseQuery {
+	if filter == nil {
+		return q
+	}
+	return q.Search(filter.Search).
+		Tags(filter.Tags, filter.MatchAllTags).
+		Categories(filter.CategoryIds).
+		AmountBetween(filter.MinAmount, filter.MaxAmount).
+		DescriptionContains(filter.Description).
+		Between("expenses.created_at", filter.CreatedFrom, filter.CreatedTo).
+		Between("expenses.updated_at", filter.UpdatedFrom, filter.UpdatedTo)
+}
+
+// Search keeps the expenses whose description or category name matches the text, with
+// the full-text index or, for partial and misspelled words, the trigram indexes
+func (q *expenseQuery) Search(text string) *expenseQuery {
+	if text == "" {
+		return q
+	}
+	q.search = text
+
+	categories := q.db.Session(&gorm.Session{NewDB: true}).
+		Table("categories").
+		Select("categories.id").
+		Where("? <% categories.name", text)
+	q.db = q.db.Where(
+		"expenses.search_vector @@ "+searchQuery+" OR ? <% expenses.description OR expenses.id_category IN (?)",
+		text, text, categories,
+	)
+	return q
+}
+
+// Ranked selects the search rank and snippet of every expense, it does nothing without a search
+func (q *expenseQuery) Ranked() *expenseQuery {
+	if q.search == "" {
+		return q
+	}
+	q.db = q.db.Select(searchColumns, q.search, q.search, q.search)
+	return q
+}
+
+// Tags keeps the expenses having at least one of the tags, or every one of them with matchAll
+func (q *expenseQuery) Tags(names []string, matchAll bool) *expenseQuery {
+	if len(names) == 0 {
+		return q
+	}
+
+	tagged := q.db.Session(&gorm.Session{NewDB: true}).
+		Table("expense_tags").
+		Select("expense_tags.id_expense").
+		Joins("JOIN tags ON tags.id = expense_tags.id_tag").
+		Where("tags.name IN ?", names)
+	if matchAll {
+		tagged = tagged.Group("expense_tags.id_expense").
+			Having("COUNT(DISTINCT tags.id) = ?", len(names))
+	}
+	q.db = q.db.Where("expenses.id IN (?)", tagged)
+	return q
+}
+
+// Categories keeps the expenses of one of the categories
+func (q *expenseQuery) Categories(ids []uuid.UUID) *expenseQuery {
+	if len(ids) == 0 {
+		return q
+	}
+	q.db = q.db.Where("expenses.id_category IN ?", ids)
+	return q
+}
+
+// AmountBetween keeps the expenses whose amount is within the inclusive bounds, a nil bound is open
+func (q *expenseQuery) AmountBetween(min, max *money.Amount) *expenseQuery {
+	if min != nil {
+		q.db = q.db.Where("expenses.amount >= ?", *min)
+	}
+	if max != nil {
+		q.db = q.db.Where("expenses.amount <= ?", *max)
+	}
+	return q
+}
+
+// DescriptionContains keeps the expenses whose description contains text, case insensitive
+func (q *expenseQuery) DescriptionContains(text string) *expenseQuery {
+	if text == "" {
+		return q
+	}
+	q.db = q.db.Where("expenses.description ILIKE ?", "%"+likeEscaper.Replace(text)+"%")
+	return q
+}
+
+// Between keeps the expenses whose timestamp column is within the inclusive bounds, a zero bound is open
+func (q *expenseQuery) Between(column string, from, to int64) *expenseQuery {
+	if from != 0 {
+		q.db = q.db.Where(column+" >= ?", from)
+	}
+	if to != 0 {
+		q.db = q.db.Where(column+" <= ?", to)
+	}
+	return q
+}
+
+// Sort orders the query on a whitelisted field, the expense id is the tiebreaker so pages
+// are stable. an unknown field, or relevance without search, falls back to the default sort
+func (q *expenseQuery) Sort(sort *model.ExpenseSort) *expenseQuery {
+	column, descending, _ := q.sortKey(sort)
+	if column == searchRank {
+		column = "search_rank" // the selected alias
+	}
+
+	direction := " ASC"
+	if descending {
+		direction = " DESC"
+	}
+	q.db = q.db.Order(column + direction).Order("expenses.id" + direction)
+	return q
+}
+
+// After keeps the expenses coming after the cursor in the order of its sort (keyset pagination)
+func (q *expenseQuery) After(cursor *model.ExpenseCursor) *expenseQuery {
+	if cursor == nil {
+		return q
+	}
+	column, descending, args := q.sortKey(&cursor.Sort)
+
+	operator := " > "
+	if descending {
+		operator = " < "
+	}
+	q.db = q.db.Where("("+column+", expenses.id)"+operator+"(?, ?)", append(args, cursor.Value, cursor.Id)...)
+	return q
+}
+
+// sortKey resolves the column (or expression with its arguments) and the direction of a sort
+func (q *expenseQuery) sortKey(sort *model.ExpenseSort) (string, bool, []interface{}) {
+	if sort == nil {
+		sort = &model.DefaultExpenseSort
+	}
+	if sort.Field == "relevance" && q.search != "" {
+		return searchRank, sort.Descending, []interface{}{q.search, q.search}
+	}
+	if column, ok := sortColumns[sort.Field]; ok {
+		return column, sort.Descending, nil
+	}
+	return sortColumns[model.DefaultExpenseSort.Field], model.DefaultExpenseSort.Descending, nil
+}
+
+// Page limits the query to one page, pages start at 1
+func (q *expenseQuery) Page(page, limit int) *expenseQuery {
+	q.db = q.db.Offset((page - 1) * limit).Limit(limit)
+	return q
+}
+
+// DB returns the composed statement
+func (q *expenseQuery) DB() *gorm.DB {
+	return q.db
+}
@@ -3,6 +3,7 @@ package expense
 import (
 	"context"
 	"github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"gorm.io/gorm"
 )
 
 // PostgresRepository defines methods the service layer expects.
@@ -16,7 +17,37 @@ type PostgresRepository interface {
 
 	Update(ctx context.Context, expense *model.Expense) error
 
-	FindAll(ctx context.Context, userId string, offset, limit int) ([]model.Expense, int64, error)
+	// Restore undeletes a deleted expense and bumps its version, gorm.ErrRecordNotFound when there is none
+	Restore(ctx context.Context, expense *model.Expense) error
 
-	FindAllByDateRange(ctx context.Context, userId string, startDate, endDate int64, offset, limit int) ([]model.Expense, int64, error)
+	CreateHistory(ctx context.Context, history *model.ExpenseHistory) error
+
+	FindHistory(ctx context.Context, expenseId, userId string) ([]model.ExpenseHistory, error)
+
+	FindAll(ctx context.Context, userId string, filter *model.ExpenseFilter, sort *model.ExpenseSort, offset, limit int) ([]model.Expense, int64, error)
+
+	FindAllAfter(ctx context.Context, userId string, filter *model.ExpenseFilter, sort *model.ExpenseSort, after *model.ExpenseCursor, limit int) ([]model.Expense, error)
+
+	Summarize(ctx context.Context, userId string, filter *model.ExpenseFilter) (*model.ExpenseReport, error)
+
+	// SumByDay sums the matching expenses per day (UTC) and currency, ordered by day
+	SumByDay(ctx context.Context, userId string, filter *model.ExpenseFilter) ([]model.DailyTotal, error)
+
+	// SumByCategoryAndDay sums the matching expenses per category, day (UTC) and currency, ordered by day
+	SumByCategoryAndDay(ctx context.Context, userId string, filter *model.ExpenseFilter) ([]model.CategoryDailyTotal, error)
+
+	// FindDuplicateCandidates reads the matching expenses having another expense of the same amount and currency
+	// made within window seconds, ordered by currency, amount and creation, at most limit of them
+	FindDuplicateCandidates(ctx context.Context, userId string, filter *model.ExpenseFilter, window int64, limit int) ([]model.Expense, error)
+
+	// FindRecurring reads the expenses of the same description, category and currency made once
+	// in each of at least months different months (UTC)
+	FindRecurring(ctx context.Context, userId string, filter *model.ExpenseFilter, months int) ([]model.RecurringExpense, error)
+
+	// Transaction runs fn in one database transaction, committed when fn returns nil and
+	// rolled back otherwise. repositories given tx with WithTx run their queries in it
+	Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+
+	// WithTx returns a repository running its queries in the transaction tx
+	WithTx(tx *gorm.DB) PostgresRepository
 }
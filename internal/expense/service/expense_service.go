@@ -2,75 +2,699 @@ package service
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/account"
+	"github.com/mrizkisaputra/expenses-api/internal/alert"
+	"github.com/mrizkisaputra/expenses-api/internal/category"
+	categoryModel "github.com/mrizkisaputra/expenses-api/internal/category/model"
+	"github.com/mrizkisaputra/expenses-api/internal/currency"
+	eventModel "github.com/mrizkisaputra/expenses-api/internal/event/model"
 	"github.com/mrizkisaputra/expenses-api/internal/expense"
 	"github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox"
+	outboxModel "github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+	"github.com/mrizkisaputra/expenses-api/internal/rule"
+	ruleModel "github.com/mrizkisaputra/expenses-api/internal/rule/model"
+	"github.com/mrizkisaputra/expenses-api/internal/tag"
+	tagModel "github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/mrizkisaputra/expenses-api/internal/user"
+	userModel "github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/contextutils"
 	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"strings"
 	"time"
 )
 
+const (
+	// maxDuplicateWarnings is the number of expenses around a new expense checked for duplicates
+	maxDuplicateWarnings = 20
+
+	// maxDuplicateCandidates is the number of expenses read at most to group the duplicates
+	maxDuplicateCandidates = 1000
+)
+
 type ServiceConfig struct {
-	PgRepo expense.PostgresRepository
-	Config *config.Config
-	Logger *logrus.Logger
+	PgRepo              expense.PostgresRepository
+	UserPgRepo          user.UserPostgresRepository
+	CategoryPgRepo      category.PostgresRepository
+	TagPgRepo           tag.PostgresRepository
+	AccountPgRepo       account.PostgresRepository
+	RulePgRepo          rule.PostgresRepository
+	ExchangeRateService currency.ExchangeRateService
+	AlertService        alert.AlertService
+	OutboxRepo          outbox.PostgresRepository
+	Config              *config.Config
+	Logger              *logrus.Logger
 }
 
 type expenseService struct {
-	pgRepo expense.PostgresRepository
+	pgRepo              expense.PostgresRepository
+	userPgRepo          user.UserPostgresRepository
+	categoryPgRepo      category.PostgresRepository
+	tagPgRepo           tag.PostgresRepository
+	accountPgRepo       account.PostgresRepository
+	rulePgRepo          rule.PostgresRepository
+	exchangeRateService currency.ExchangeRateService
+	alertService        alert.AlertService
+	outboxRepo          outbox.PostgresRepository
+	logger              *logrus.Logger
+
+	// inTransaction is set on the copy of the service running in a transaction
+	inTransaction bool
 }
 
 func NewExpenseService(config *ServiceConfig) expense.ExpenseService {
 	return &expenseService{
-		pgRepo: config.PgRepo,
+		pgRepo:              config.PgRepo,
+		userPgRepo:          config.UserPgRepo,
+		categoryPgRepo:      config.CategoryPgRepo,
+		tagPgRepo:           config.TagPgRepo,
+		accountPgRepo:       config.AccountPgRepo,
+		rulePgRepo:          config.RulePgRepo,
+		exchangeRateService: config.ExchangeRateService,
+		alertService:        config.AlertService,
+		outboxRepo:          config.OutboxRepo,
+		logger:              config.Logger,
 	}
 }
 
 func (e *expenseService) Insert(ctx context.Context, request *model.Expense) (*model.Expense, error) {
 	request.PrepareCreate()
-	if err := e.pgRepo.Create(ctx, request); err != nil {
-		return nil, httpErrors.NewInternalServerError(err)
+
+	if err := e.applyRules(ctx, request); err != nil {
+		return nil, err
 	}
+	if err := e.ensureCategory(ctx, request); err != nil {
+		return nil, err
+	}
+	if err := validateTags(request); err != nil {
+		return nil, err
+	}
+	if err := e.ensureAccount(ctx, request); err != nil {
+		return nil, err
+	}
+
+	// an expense without currency (nor account) is in the user's base currency
+	if request.Currency == "" {
+		baseCurrency, err := e.baseCurrency(ctx, request.UserId)
+		if err != nil {
+			return nil, err
+		}
+		request.Currency = baseCurrency
+	}
+	if err := validateAmount(request); err != nil {
+		return nil, err
+	}
+
+	err := e.inTx(ctx, func(txService *expenseService) error {
+		if err := txService.pgRepo.Create(ctx, request); err != nil {
+			return httpErrors.NewInternalServerError(err)
+		}
+
+		if len(request.Tags) > 0 {
+			if err := txService.setTags(ctx, request); err != nil {
+				return err
+			}
+		}
+		return txService.record(ctx, model.HistoryCreate, nil, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.warnDuplicates(ctx, request)
+	e.evaluateAlerts(ctx, request)
 	return request, nil
 }
 
 func (e *expenseService) Delete(ctx context.Context, request *model.Expense) error {
-	expenses := new(model.Expense)
-	if err := e.pgRepo.FindByIdAndUserId(ctx, expenses, request.Id.String(), request.UserId.String()); err != nil {
-		return httpErrors.NewNotFoundError(err)
-	}
+	return e.inTx(ctx, func(txService *expenseService) error {
+		expenses := new(model.Expense)
+		if err := txService.pgRepo.FindByIdAndUserId(ctx, expenses, request.Id.String(), request.UserId.String()); err != nil {
+			return httpErrors.NewNotFoundError(err)
+		}
+		if err := checkVersion(request, expenses); err != nil {
+			return err
+		}
+		before, err := txService.withTags(ctx, expenses)
+		if err != nil {
+			return err
+		}
 
-	if err := e.pgRepo.Remove(ctx, expenses); err != nil {
-		return httpErrors.NewInternalServerError(err)
+		if err := txService.pgRepo.Remove(ctx, expenses); err != nil {
+			return versionError(err)
+		}
+		return txService.record(ctx, model.HistoryDelete, before, nil)
+	})
+}
+
+func (e *expenseService) Restore(ctx context.Context, request *model.Expense) (*model.Expense, error) {
+	var restored *model.Expense
+	err := e.inTx(ctx, func(txService *expenseService) error {
+		if err := txService.pgRepo.Restore(ctx, request); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return httpErrors.NewNotFoundError(err)
+			}
+			return httpErrors.NewInternalServerError(err)
+		}
+
+		var err error
+		restored, err = txService.GetById(ctx, request.Id.String(), request.UserId.String())
+		if err != nil {
+			return err
+		}
+		return txService.record(ctx, model.HistoryRestore, nil, restored)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return restored, nil
 }
 
 func (e *expenseService) Update(ctx context.Context, request *model.Expense) (*model.Expense, error) {
-	expenses := new(model.Expense)
-	if err := e.pgRepo.FindByIdAndUserId(ctx, expenses, request.Id.String(), request.UserId.String()); err != nil {
-		return nil, httpErrors.NewNotFoundError(err)
-	}
+	var expenses *model.Expense
+	err := e.inTx(ctx, func(txService *expenseService) error {
+		expenses = new(model.Expense)
+		if err := txService.pgRepo.FindByIdAndUserId(ctx, expenses, request.Id.String(), request.UserId.String()); err != nil {
+			return httpErrors.NewNotFoundError(err)
+		}
+		if err := checkVersion(request, expenses); err != nil {
+			return err
+		}
+		before, err := txService.withTags(ctx, expenses)
+		if err != nil {
+			return err
+		}
+
+		if request.CategoryId != uuid.Nil {
+			if err := txService.ensureCategory(ctx, request); err != nil {
+				return err
+			}
+		}
+
+		if request.Tags != nil {
+			request.Tags = tagModel.NormalizeNames(request.Tags)
+			if err := validateTags(request); err != nil {
+				return err
+			}
+		}
 
-	request.PrepareUpdate(expenses)
+		request.PrepareUpdate(expenses)
 
-	if err := e.pgRepo.Update(ctx, expenses); err != nil {
-		return nil, httpErrors.NewInternalServerError(err)
+		if request.AccountId != nil || request.Currency != "" {
+			if err := txService.ensureAccount(ctx, expenses); err != nil {
+				return err
+			}
+		}
+		if request.Amount != nil || request.Currency != "" {
+			if err := validateAmount(expenses); err != nil {
+				return err
+			}
+		}
+
+		if err := txService.pgRepo.Update(ctx, expenses); err != nil {
+			return versionError(err)
+		}
+
+		// tags are only replaced when the request carries them, an empty list removes every tag
+		if request.Tags != nil {
+			expenses.Tags = request.Tags
+			if err := txService.setTags(ctx, expenses); err != nil {
+				return err
+			}
+		} else {
+			expenses.Tags = before.Tags
+		}
+		return txService.record(ctx, model.HistoryUpdate, before, expenses)
+	})
+	if err != nil {
+		return nil, err
 	}
 	return expenses, nil
 }
 
+func (e *expenseService) GetHistory(ctx context.Context, id, userId string) ([]model.ExpenseHistory, error) {
+	history, err := e.pgRepo.FindHistory(ctx, id, userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+
+	// an expense written before the history existed has none, it is only unknown when it does not exist
+	if len(history) == 0 {
+		if err := e.pgRepo.FindByIdAndUserId(ctx, new(model.Expense), id, userId); err != nil {
+			return nil, httpErrors.NewNotFoundError(err)
+		}
+	}
+	return history, nil
+}
+
 func (e *expenseService) GetById(ctx context.Context, id, userId string) (*model.Expense, error) {
 	expenses := new(model.Expense)
 	if err := e.pgRepo.FindByIdAndUserId(ctx, expenses, id, userId); err != nil {
 		return nil, httpErrors.NewNotFoundError(err)
 	}
-	return expenses, nil
+
+	found := []model.Expense{*expenses}
+	if err := e.attachTags(ctx, found); err != nil {
+		return nil, err
+	}
+	return &found[0], nil
+}
+
+// GetAll lists a page of the expenses matching the query, by page number or,
+// when the query carries a cursor, right after the cursor
+func (e *expenseService) GetAll(ctx context.Context, userId string, request *model.SearchExpenseRequestQueryParam) (*model.ExpensePage, error) {
+	filter, err := newExpenseFilter(request)
+	if err != nil {
+		return nil, err
+	}
+	sort := newExpenseSort(request)
+
+	page := new(model.ExpensePage)
+	if request.Cursor != "" {
+		after, err := model.DecodeExpenseCursor(request.Cursor)
+		if err != nil || after.Sort != *sort {
+			return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidCursorMsg, err)
+		}
+
+		// one more expense than the limit is read to know whether there is a next page
+		expenses, err := e.pgRepo.FindAllAfter(ctx, userId, filter, sort, after, request.Limit+1)
+		if err != nil {
+			return nil, httpErrors.NewInternalServerError(err)
+		}
+		if len(expenses) > request.Limit {
+			expenses = expenses[:request.Limit]
+			page.NextCursor = model.NewExpenseCursor(*sort, &expenses[len(expenses)-1]).Encode()
+		}
+		page.Expenses = expenses
+	} else {
+		expenses, total, err := e.pgRepo.FindAll(ctx, userId, filter, sort, request.Page, request.Limit)
+		if err != nil {
+			return nil, httpErrors.NewInternalServerError(err)
+		}
+		// the cursor of the last expense lets a client go on with keyset pagination
+		if len(expenses) > 0 && int64(request.Page*request.Limit) < total {
+			page.NextCursor = model.NewExpenseCursor(*sort, &expenses[len(expenses)-1]).Encode()
+		}
+		page.Expenses, page.TotalItems = expenses, total
+	}
+
+	if err := e.attachTags(ctx, page.Expenses); err != nil {
+		return nil, err
+	}
+	if err := e.convertToBaseCurrency(ctx, userId, page.Expenses); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// GetDuplicates groups the expenses matching the same query as GetAll that look like duplicates
+// of each other, the most recent groups first
+func (e *expenseService) GetDuplicates(ctx context.Context, userId string, request *model.SearchExpenseRequestQueryParam) ([]model.DuplicateGroup, error) {
+	filter, err := newExpenseFilter(request)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := e.pgRepo.FindDuplicateCandidates(ctx, userId, filter, model.DuplicateWindow, maxDuplicateCandidates)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	if err := e.attachTags(ctx, candidates); err != nil {
+		return nil, err
+	}
+
+	groups := model.GroupDuplicates(candidates)
+	for i, j := 0, len(groups)-1; i < j; i, j = i+1, j-1 {
+		groups[i], groups[j] = groups[j], groups[i]
+	}
+	return groups, nil
+}
+
+// Merge keeps the expense KeepId and deletes its duplicates in one transaction, the kept expense gets the
+// tags of the deleted ones. a duplicate must have the amount and currency of the kept expense
+func (e *expenseService) Merge(ctx context.Context, request *model.MergeRequest) (*model.Expense, error) {
+	userId := request.UserId.String()
+	var kept *model.Expense
+	err := e.inTx(ctx, func(txService *expenseService) error {
+
+		found, err := txService.GetById(ctx, request.KeepId.String(), userId)
+		if err != nil {
+			return err
+		}
+
+		tags := found.Tags
+		duplicates := make([]*model.Expense, 0, len(request.DuplicateIds))
+		for _, id := range request.DuplicateIds {
+			duplicate, err := txService.GetById(ctx, id.String(), userId)
+			if err != nil {
+				return err
+			}
+			if duplicate.Id == found.Id || *duplicate.Amount != *found.Amount || duplicate.Currency != found.Currency {
+				return httpErrors.NewError(http.StatusBadRequest, httpErrors.NotDuplicateMsg, nil)
+			}
+			tags = append(tags, duplicate.Tags...)
+			duplicates = append(duplicates, duplicate)
+		}
+
+		for _, duplicate := range duplicates {
+			if err := txService.Delete(ctx, &model.Expense{Id: duplicate.Id, UserId: request.UserId}); err != nil {
+				return err
+			}
+		}
+
+		kept = found
+		if tags = tagModel.NormalizeNames(tags); len(tags) > len(found.Tags) {
+			kept, err = txService.Update(ctx, &model.Expense{Id: found.Id, UserId: request.UserId, Tags: tags})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return kept, nil
+}
+
+// errBatchRolledBack aborts the transaction of an atomic batch once an operation failed
+var errBatchRolledBack = errors.New("expenseService.Batch: rolled back")
+
+// Batch applies the operations in order and reports the result of each of them. without atomic
+// every operation is applied on its own, with atomic they run in one transaction that is
+// rolled back on the first failure, the operations that did not fail are then reported as not applied
+func (e *expenseService) Batch(ctx context.Context, operations []model.BatchOperation, atomic bool) (*model.BatchResponse, error) {
+	response := &model.BatchResponse{Atomic: atomic, Applied: true}
+
+	if !atomic {
+		for i := range operations {
+			response.Results = append(response.Results, e.apply(ctx, i, &operations[i]))
+		}
+	} else {
+		err := e.pgRepo.Transaction(ctx, func(tx *gorm.DB) error {
+			txService := e.withTx(tx)
+			for i := range operations {
+				result := txService.apply(ctx, i, &operations[i])
+				response.Results = append(response.Results, result)
+				if result.Err != nil {
+					return errBatchRolledBack
+				}
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errBatchRolledBack) {
+			return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "expenseService.Batch.Transaction"))
+		}
+
+		if err == nil {
+			// the expenses created in the transaction are only evaluated once committed
+			for _, result := range response.Results {
+				if result.Op == model.BatchCreate {
+					e.evaluateAlerts(ctx, result.Data)
+				}
+			}
+		} else {
+			response.Applied = false
+			for i := len(response.Results); i < len(operations); i++ {
+				response.Results = append(response.Results, model.BatchResult{Index: i, Op: operations[i].Op})
+			}
+			for i := range response.Results {
+				if response.Results[i].Err == nil {
+					response.Results[i].Id = nil
+					response.Results[i].Fail(httpErrors.NewError(http.StatusFailedDependency, httpErrors.BatchRolledBackMsg, nil))
+				}
+			}
+		}
+	}
+
+	for _, result := range response.Results {
+		if result.Err != nil {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+	return response, nil
+}
+
+// apply runs one operation of a batch
+func (e *expenseService) apply(ctx context.Context, index int, operation *model.BatchOperation) model.BatchResult {
+	result := model.BatchResult{Index: index, Op: operation.Op}
+	if operation.Err != nil {
+		result.Fail(operation.Err)
+		return result
+	}
+
+	var err error
+	switch operation.Op {
+	case model.BatchCreate:
+		result.Data, err = e.Insert(ctx, operation.Expense)
+		result.Status = http.StatusCreated
+	case model.BatchUpdate:
+		result.Data, err = e.Update(ctx, operation.Expense)
+		result.Status = http.StatusAccepted
+	case model.BatchDelete:
+		err = e.Delete(ctx, operation.Expense)
+		result.Status = http.StatusNoContent
+	default:
+		err = httpErrors.NewBadRequestError(errors.Errorf("unknown batch operation '%s'", operation.Op))
+	}
+	if err != nil {
+		result.Fail(err)
+		return result
+	}
+
+	if result.Data != nil {
+		result.Id = &result.Data.Id
+	} else {
+		result.Id = &operation.Expense.Id
+	}
+	return result
+}
+
+// withTx returns a copy of the service whose repositories run in the transaction tx
+func (e *expenseService) withTx(tx *gorm.DB) *expenseService {
+	txService := *e
+	txService.pgRepo = e.pgRepo.WithTx(tx)
+	txService.alertService = nil
+	txService.inTransaction = true
+	if e.tagPgRepo != nil {
+		txService.tagPgRepo = e.tagPgRepo.WithTx(tx)
+	}
+	if e.outboxRepo != nil {
+		txService.outboxRepo = e.outboxRepo.WithTx(tx)
+	}
+	return &txService
+}
+
+// inTx runs fn in a transaction, or in the transaction the service already runs in, so a change
+// and the events written to the outbox are committed together. an *httpErrors.Error of fn is returned as is
+func (e *expenseService) inTx(ctx context.Context, fn func(txService *expenseService) error) error {
+	if e.inTransaction {
+		return fn(e)
+	}
+
+	err := e.pgRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		return fn(e.withTx(tx))
+	})
+	if err != nil {
+		var er *httpErrors.Error
+		if errors.As(err, &er) {
+			return err
+		}
+		return httpErrors.NewInternalServerError(errors.Wrap(err, "expenseService.inTx.Transaction"))
+	}
+	return nil
+}
+
+// GetReport summarizes the expenses matching the same query as GetAll, the daily totals of every category
+// are converted to the base currency at the rate of their day
+func (e *expenseService) GetReport(ctx context.Context, userId string, request *model.SearchExpenseRequestQueryParam) (*model.ExpenseReport, error) {
+	filter, err := newExpenseFilter(request)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "expenseService.GetReport.uuid.Parse"))
+	}
+	baseCurrency, err := e.baseCurrency(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := e.pgRepo.Summarize(ctx, userId, filter)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	totals, err := e.pgRepo.SumByCategoryAndDay(ctx, userId, filter)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+
+	report.Currency = baseCurrency
+	report.BaseCategories = []model.BaseCategoryTotal{}
+	for _, total := range totals {
+		converted, err := e.exchangeRateService.Convert(ctx, total.Amount, total.Currency, baseCurrency, total.Day)
+		if err != nil {
+			e.logger.WithError(err).WithField("userId", userId).Debug("expenseService.GetReport.Convert")
+			report.AddMissingRate(total.Currency)
+			continue
+		}
+		report.AddConverted(total.CategoryId, total.Name, converted)
+	}
+	report.SortBaseCategories()
+	return report, nil
+}
+
+// GetForecast projects the month-end spending from the daily totals of the month so far and of the
+// ForecastHistoryMonths months before, the category totals of both periods and the recurring expenses
+// of the history. amounts are converted to the base currency at the rate of their day, the category
+// totals and recurring expenses at today's rate
+func (e *expenseService) GetForecast(ctx context.Context, userId uuid.UUID) (*model.Forecast, error) {
+	baseCurrency, err := e.baseCurrency(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	forecast := model.NewForecast(baseCurrency, now)
+	historyFrom, historyTo := forecast.HistoryRange()
+	monthFrom, monthTo := forecast.MonthRange()
+
+	convert := func(amount money.Amount, currency string, on time.Time) (money.Amount, bool) {
+		converted, err := e.exchangeRateService.Convert(ctx, amount, currency, baseCurrency, on)
+		if err != nil {
+			e.logger.WithError(err).WithField("userId", userId).Debug("expenseService.GetForecast.Convert")
+			forecast.AddMissingRate(currency)
+			return 0, false
+		}
+		return converted, true
+	}
+
+	totals, err := e.pgRepo.SumByDay(ctx, userId.String(), &model.ExpenseFilter{CreatedFrom: historyFrom, CreatedTo: monthTo})
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	for _, total := range totals {
+		if converted, ok := convert(total.Amount, total.Currency, total.Day); ok {
+			forecast.AddDay(total.Day, converted)
+		}
+	}
+
+	month, err := e.pgRepo.Summarize(ctx, userId.String(), &model.ExpenseFilter{CreatedFrom: monthFrom, CreatedTo: monthTo})
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	history, err := e.pgRepo.Summarize(ctx, userId.String(), &model.ExpenseFilter{CreatedFrom: historyFrom, CreatedTo: historyTo})
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	for _, total := range month.Categories {
+		if converted, ok := convert(total.Amount, total.Currency, now); ok {
+			forecast.AddCategory(total.CategoryId, total.Name, converted, 0)
+		}
+	}
+	for _, total := range history.Categories {
+		if converted, ok := convert(total.Amount, total.Currency, now); ok {
+			forecast.AddCategory(total.CategoryId, total.Name, 0, converted)
+		}
+	}
+
+	recurring, err := e.pgRepo.FindRecurring(ctx, userId.String(), &model.ExpenseFilter{CreatedFrom: historyFrom, CreatedTo: monthTo}, model.ForecastHistoryMonths)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	for _, item := range recurring {
+		if converted, ok := convert(item.Amount, item.Currency, now); ok {
+			forecast.AddRecurring(item, converted)
+		}
+	}
+
+	forecast.Finish()
+	return forecast, nil
+}
+
+// newExpenseFilter reads the filters of the query, the date preset and the created
+// range are both applied so an expense must be within the two of them
+func newExpenseFilter(request *model.SearchExpenseRequestQueryParam) (*model.ExpenseFilter, error) {
+	filter := &model.ExpenseFilter{
+		Search:       strings.TrimSpace(request.Q),
+		MatchAllTags: request.TagsMode == "all",
+		Description:  strings.TrimSpace(request.Description),
+	}
+
+	if request.Tags != "" {
+		filter.Tags = tagModel.NormalizeNames(strings.Split(request.Tags, ","))
+	}
+
+	for _, raw := range strings.Split(request.CategoryId, ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidExpenseFilterMsg, errors.Wrap(err, "category_id"))
+		}
+		filter.CategoryIds = append(filter.CategoryIds, id)
+	}
+
+	var err error
+	if filter.MinAmount, err = parseAmount(request.MinAmount); err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidExpenseFilterMsg, errors.Wrap(err, "min_amount"))
+	}
+	if filter.MaxAmount, err = parseAmount(request.MaxAmount); err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidExpenseFilterMsg, errors.Wrap(err, "max_amount"))
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidExpenseFilterMsg, errors.New("min_amount is greater than max_amount"))
+	}
+
+	start, end, bounded, err := dateRange(request)
+	if err != nil {
+		return nil, err
+	}
+	if bounded {
+		filter.CreatedFrom, filter.CreatedTo = start.Unix(), end.Unix()
+	}
+
+	if filter.CreatedFrom, filter.CreatedTo, err = narrowRange(filter.CreatedFrom, filter.CreatedTo, request.CreatedFrom, request.CreatedTo); err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidExpenseFilterMsg, errors.Wrap(err, "created"))
+	}
+	if filter.UpdatedFrom, filter.UpdatedTo, err = narrowRange(0, 0, request.UpdatedFrom, request.UpdatedTo); err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidExpenseFilterMsg, errors.Wrap(err, "updated"))
+	}
+	return filter, nil
+}
+
+// newExpenseSort reads the sort of the query, fields are ascending unless order is 'desc'.
+// a search is sorted by relevance, most relevant first, unless another field is asked
+func newExpenseSort(request *model.SearchExpenseRequestQueryParam) *model.ExpenseSort {
+	searching := strings.TrimSpace(request.Q) != ""
+	if request.Sort == "" && searching {
+		return &model.ExpenseSort{Field: "relevance", Descending: request.Order != "asc"}
+	}
+	if request.Sort == "" && request.Order == "" || request.Sort == "relevance" && !searching {
+		return &model.DefaultExpenseSort
+	}
+
+	sort := &model.ExpenseSort{Field: request.Sort, Descending: request.Order == "desc"}
+	if sort.Field == "" {
+		sort.Field = model.DefaultExpenseSort.Field
+	}
+	return sort
 }
 
-func (e *expenseService) GetAll(ctx context.Context, userId string, request *model.SearchExpenseRequestQueryParam) ([]model.Expense, int64, error) {
-	var start, end time.Time
+// dateRange returns the range of the date filter of the query, bounded is false without filter
+func dateRange(request *model.SearchExpenseRequestQueryParam) (start, end time.Time, bounded bool, err error) {
 	var now = time.Now()
 
 	switch request.Filter {
@@ -96,37 +720,336 @@ func (e *expenseService) GetAll(ctx context.Context, userId string, request *mod
 		{
 			startDate, err := time.Parse("2006-01-02", request.StartDate)
 			if err != nil {
-				return nil, 0, httpErrors.NewInternalServerError(errors.Wrap(err, "expenseService.GetAll.time.Parse"))
+				return start, end, false, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidExpenseFilterMsg, errors.Wrap(err, "expenseService.dateRange.time.Parse"))
 			}
 
 			endDate, err := time.Parse("2006-01-02", request.EndDate)
 			if err != nil {
-				return nil, 0, httpErrors.NewInternalServerError(errors.Wrap(err, "expenseService.GetAll.time.Parse"))
+				return start, end, false, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidExpenseFilterMsg, errors.Wrap(err, "expenseService.dateRange.time.Parse"))
 			}
 
 			start = startDate
-			end = endDate
+			end = endOfDay(endDate)
 			break
 		}
 	default:
-		expenses, total, err := e.pgRepo.FindAll(ctx, userId, request.Page, request.Limit)
+		return start, end, false, nil
+	}
+	return start, end, true, nil
+}
+
+// narrowRange intersects the unix range [from, to] with the days from and to (2006-01-02)
+// of the query, the last day is included entirely. zero bounds and empty days are open
+func narrowRange(from, to int64, fromDay, toDay string) (int64, int64, error) {
+	if fromDay != "" {
+		day, err := time.Parse("2006-01-02", fromDay)
+		if err != nil {
+			return 0, 0, err
+		}
+		if day.Unix() > from {
+			from = day.Unix()
+		}
+	}
+	if toDay != "" {
+		day, err := time.Parse("2006-01-02", toDay)
 		if err != nil {
-			return nil, 0, httpErrors.NewInternalServerError(err)
+			return 0, 0, err
+		}
+		if last := endOfDay(day).Unix(); to == 0 || last < to {
+			to = last
 		}
-		return expenses, total, nil
 	}
+	if from != 0 && to != 0 && from > to {
+		return 0, 0, errors.New("range starts after it ends")
+	}
+	return from, to, nil
+}
 
-	expenses, total, err := e.pgRepo.FindAllByDateRange(
-		ctx,
-		userId,
-		start.UnixMilli(),
-		end.UnixMilli(),
-		request.Page,
-		request.Limit,
-	)
+func endOfDay(day time.Time) time.Time {
+	return day.AddDate(0, 0, 1).Add(-time.Second)
+}
+
+func parseAmount(raw string) (*money.Amount, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	amount, err := money.Parse(raw)
 	if err != nil {
-		return nil, 0, httpErrors.NewInternalServerError(err)
+		return nil, err
+	}
+	return &amount, nil
+}
+
+// withTags returns a copy of the expense with its tags, the state of the expense before a change
+func (e *expenseService) withTags(ctx context.Context, expense *model.Expense) (*model.Expense, error) {
+	loaded := []model.Expense{*expense}
+	if err := e.attachTags(ctx, loaded); err != nil {
+		return nil, err
+	}
+	return &loaded[0], nil
+}
+
+// record appends a change to the history of the expense, before is nil for a created
+// or restored expense and after is nil for a deleted one
+func (e *expenseService) record(ctx context.Context, action string, before, after *model.Expense) error {
+	expense := after
+	if expense == nil {
+		expense = before
 	}
 
-	return expenses, total, nil
+	history := &model.ExpenseHistory{
+		ExpenseId: expense.Id,
+		UserId:    expense.UserId,
+		ActorId:   contextutils.ActorIdFromContext(ctx),
+		Action:    action,
+		RequestId: contextutils.RequestIdFromContext(ctx),
+		Version:   expense.Version,
+		Changes:   model.DiffExpense(before, after),
+	}
+	if err := e.pgRepo.CreateHistory(ctx, history); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	return e.writeEvent(ctx, action, expense)
+}
+
+// writeEvent writes the domain event of a change to the outbox, in the transaction of the change
+func (e *expenseService) writeEvent(ctx context.Context, action string, expense *model.Expense) error {
+	if e.outboxRepo == nil {
+		return nil
+	}
+
+	var eventType string
+	var data interface{} = expense
+	switch action {
+	case model.HistoryCreate, model.HistoryRestore:
+		eventType = outboxModel.TypeExpenseCreated
+	case model.HistoryUpdate:
+		eventType = outboxModel.TypeExpenseUpdated
+	case model.HistoryDelete:
+		eventType = outboxModel.TypeExpenseDeleted
+		data = eventModel.Deleted{Id: expense.Id}
+	default:
+		return nil
+	}
+
+	event, err := outboxModel.NewEvent(outboxModel.AggregateExpense, expense.Id, expense.UserId, eventType, data)
+	if err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	if err := e.outboxRepo.Create(ctx, event); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+// checkVersion fails with 412 when the request expects another version than the stored one,
+// a request without version (no If-Match) always passes
+func checkVersion(request, stored *model.Expense) error {
+	if request.Version != 0 && request.Version != stored.Version {
+		return httpErrors.NewError(
+			http.StatusPreconditionFailed,
+			httpErrors.PreconditionFailedMsg,
+			errors.Errorf("expected version %d, current version %d", request.Version, stored.Version),
+		)
+	}
+	return nil
+}
+
+// versionError maps a write that lost the race against a concurrent update to 412
+func versionError(err error) error {
+	if errors.Is(err, model.ErrVersionConflict) {
+		return httpErrors.NewError(http.StatusPreconditionFailed, httpErrors.PreconditionFailedMsg, err)
+	}
+	return httpErrors.NewInternalServerError(err)
+}
+
+// setTags attaches the tags named in expense.Tags to the expense, missing tags are created
+func (e *expenseService) setTags(ctx context.Context, expense *model.Expense) error {
+	tags, err := e.tagPgRepo.FindOrCreateByNames(ctx, expense.UserId, expense.Tags)
+	if err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+
+	ids := make([]uuid.UUID, len(tags))
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		ids[i] = t.Id
+		names[i] = t.Name
+	}
+	if err := e.tagPgRepo.ReplaceForExpense(ctx, expense.Id, ids); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	expense.Tags = names
+	return nil
+}
+
+// attachTags fills the tag names of every expense
+func (e *expenseService) attachTags(ctx context.Context, expenses []model.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(expenses))
+	for i := range expenses {
+		ids[i] = expenses[i].Id
+	}
+	tagged, err := e.tagPgRepo.FindByExpenseIds(ctx, ids)
+	if err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+
+	names := make(map[uuid.UUID][]string, len(expenses))
+	for _, t := range tagged {
+		names[t.ExpenseId] = append(names[t.ExpenseId], t.Name)
+	}
+	for i := range expenses {
+		expenses[i].Tags = names[expenses[i].Id]
+		if expenses[i].Tags == nil {
+			expenses[i].Tags = []string{}
+		}
+	}
+	return nil
+}
+
+// validateTags ensures every tag name of the expense is a valid label
+func validateTags(expense *model.Expense) error {
+	for _, name := range expense.Tags {
+		if !tagModel.ValidName(name) {
+			return httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidTagMsg, nil)
+		}
+	}
+	return nil
+}
+
+// validateAmount rejects an amount with decimals its currency does not have, such as 0.50 IDR
+func validateAmount(expense *model.Expense) error {
+	if expense.Amount != nil && !expense.Amount.ValidFor(expense.Currency) {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidCurrencyAmountMsg, nil)
+	}
+	return nil
+}
+
+// convertToBaseCurrency fills BaseAmount of every expense with its amount converted to the
+// user's base currency at the rate of the expense date, expenses without known rate are left empty
+func (e *expenseService) convertToBaseCurrency(ctx context.Context, userId string, expenses []model.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+
+	baseCurrency, err := e.baseCurrency(ctx, expenses[0].UserId)
+	if err != nil {
+		return err
+	}
+
+	for i := range expenses {
+		expenses[i].BaseCurrency = baseCurrency
+		if expenses[i].Amount == nil {
+			continue
+		}
+
+		converted, err := e.exchangeRateService.Convert(
+			ctx,
+			*expenses[i].Amount,
+			expenses[i].Currency,
+			baseCurrency,
+			expenses[i].Date(),
+		)
+		if err != nil {
+			e.logger.WithError(err).WithField("userId", userId).Debug("expenseService.convertToBaseCurrency.Convert")
+			continue
+		}
+		expenses[i].BaseAmount = &converted
+	}
+	return nil
+}
+
+// warnDuplicates fills PossibleDuplicates of a new expense with the expenses that look like duplicates of it,
+// it is only a warning so the expense is created whatever happens
+func (e *expenseService) warnDuplicates(ctx context.Context, request *model.Expense) {
+	filter := &model.ExpenseFilter{
+		MinAmount:   request.Amount,
+		MaxAmount:   request.Amount,
+		CreatedFrom: request.CreatedAt - model.DuplicateWindow,
+		CreatedTo:   request.CreatedAt + model.DuplicateWindow,
+	}
+	expenses, err := e.pgRepo.FindAllAfter(ctx, request.UserId.String(), filter, &model.DefaultExpenseSort, nil, maxDuplicateWarnings)
+	if err != nil {
+		e.logger.WithError(err).WithField("expenseId", request.Id).Warn("expenseService.warnDuplicates.FindAllAfter")
+		return
+	}
+
+	for i := range expenses {
+		if similarity, ok := model.IsDuplicate(request, &expenses[i]); ok {
+			request.PossibleDuplicates = append(request.PossibleDuplicates, model.NewDuplicateCandidate(&expenses[i], similarity))
+		}
+	}
+}
+
+// evaluateAlerts raises the alerts a new expense deserves, it is not evaluated inside a transaction
+// since the alerts refer to an expense not committed yet. a failure only loses the alerts
+func (e *expenseService) evaluateAlerts(ctx context.Context, request *model.Expense) {
+	if e.alertService == nil {
+		return
+	}
+	if _, err := e.alertService.Evaluate(ctx, request); err != nil {
+		e.logger.WithError(err).WithField("expenseId", request.Id).Warn("expenseService.evaluateAlerts.Evaluate")
+	}
+}
+
+// applyRules applies the enabled rules of the user to a new expense, the category,
+// tags and description they set are then validated like the ones of the request
+func (e *expenseService) applyRules(ctx context.Context, request *model.Expense) error {
+	if e.rulePgRepo == nil {
+		return nil
+	}
+
+	rules, err := e.rulePgRepo.FindEnabledByUserId(ctx, request.UserId.String())
+	if err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	ruleModel.Apply(rules, request)
+	return nil
+}
+
+// ensureCategory ensures the category of the expense exists, belongs to the user and is not an income category
+func (e *expenseService) ensureCategory(ctx context.Context, request *model.Expense) error {
+	found, err := e.categoryPgRepo.FindByIdAndUserId(ctx, request.CategoryId.String(), request.UserId.String())
+	if err != nil {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.UnknownCategoryMsg, err)
+	}
+	if found.Kind == categoryModel.KindIncome {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.CategoryKindMismatchMsg, nil)
+	}
+	return nil
+}
+
+// ensureAccount ensures the account of the expense, if any, belongs to the user and is in the
+// currency of the expense, an expense without currency takes the currency of its account
+func (e *expenseService) ensureAccount(ctx context.Context, request *model.Expense) error {
+	if request.AccountId == nil {
+		return nil
+	}
+
+	found, err := e.accountPgRepo.FindByIdAndUserId(ctx, request.AccountId.String(), request.UserId.String())
+	if err != nil {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.UnknownAccountMsg, err)
+	}
+	if request.Currency == "" {
+		request.Currency = found.Currency
+	}
+	if request.Currency != found.Currency {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.AccountCurrencyMismatchMsg, nil)
+	}
+	return nil
+}
+
+func (e *expenseService) baseCurrency(ctx context.Context, userId uuid.UUID) (string, error) {
+	found, err := e.userPgRepo.FindById(ctx, &userModel.User{Id: userId})
+	if err != nil {
+		return "", httpErrors.NewNotFoundError(errors.Wrap(err, "expenseService.baseCurrency.FindById"))
+	}
+	if found.BaseCurrency == "" {
+		return userModel.DefaultBaseCurrency, nil
+	}
+	return found.BaseCurrency, nil
 }
@@ -1 +1,866 @@
 package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	alertMock "github.com/mrizkisaputra/expenses-api/internal/alert/mock"
+	categoryMock "github.com/mrizkisaputra/expenses-api/internal/category/mock"
+	categoryModel "github.com/mrizkisaputra/expenses-api/internal/category/model"
+	currencyMock "github.com/mrizkisaputra/expenses-api/internal/currency/mock"
+	currencyModel "github.com/mrizkisaputra/expenses-api/internal/currency/model"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/expense/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	outboxMock "github.com/mrizkisaputra/expenses-api/internal/outbox/mock"
+	outboxModel "github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+	ruleMock "github.com/mrizkisaputra/expenses-api/internal/rule/mock"
+	ruleModel "github.com/mrizkisaputra/expenses-api/internal/rule/model"
+	tagMock "github.com/mrizkisaputra/expenses-api/internal/tag/mock"
+	tagModel "github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	userMock "github.com/mrizkisaputra/expenses-api/internal/user/mock"
+	userModel "github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/contextutils"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var cfg = &config.Config{
+	Logger: config.LoggerConfig{Level: "panic"},
+}
+
+func TestExpenseService_Insert(t *testing.T) {
+	userId := uuid.New()
+	categoryId := uuid.New()
+	amount := money.Amount(1500)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Expense without currency uses the user's base currency", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:         mockRepo,
+			UserPgRepo:     mockUserRepo,
+			CategoryPgRepo: mockCategoryRepo,
+			Logger:         logger.NewLogrusLogger(cfg),
+		})
+
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, categoryId.String(), userId.String()).
+			Return(&categoryModel.Category{Id: categoryId, UserId: userId}, nil)
+
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId, BaseCurrency: "EUR"}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("CreateHistory", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("FindAllAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Expense{}, nil)
+
+		response, err := sv.Insert(context.Background(), &model.Expense{
+			UserId:      userId,
+			Description: "Coffee",
+			Amount:      &amount,
+			CategoryId:  categoryId,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "EUR", response.Currency)
+		require.Equal(t, "coffee", response.Description)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Explicit currency is kept", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:         mockRepo,
+			UserPgRepo:     mockUserRepo,
+			CategoryPgRepo: mockCategoryRepo,
+			Logger:         logger.NewLogrusLogger(cfg),
+		})
+
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, categoryId.String(), userId.String()).
+			Return(&categoryModel.Category{Id: categoryId, UserId: userId}, nil)
+
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("CreateHistory", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("FindAllAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Expense{}, nil)
+
+		response, err := sv.Insert(context.Background(), &model.Expense{
+			UserId:      userId,
+			Description: "Hotel",
+			Amount:      &amount,
+			Currency:    "usd",
+			CategoryId:  categoryId,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "USD", response.Currency)
+		mockUserRepo.AssertNotCalled(t, "FindById")
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Tags are created and attached to the expense", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:         mockRepo,
+			CategoryPgRepo: mockCategoryRepo,
+			TagPgRepo:      mockTagRepo,
+			Logger:         logger.NewLogrusLogger(cfg),
+		})
+
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		tagId := uuid.New()
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, categoryId.String(), userId.String()).
+			Return(&categoryModel.Category{Id: categoryId, UserId: userId}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("CreateHistory", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("FindAllAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Expense{}, nil)
+		mockTagRepo.On("FindOrCreateByNames", mock.Anything, userId, []string{"trip-bali-2026"}).
+			Return([]tagModel.Tag{{Id: tagId, UserId: userId, Name: "trip-bali-2026"}}, nil)
+		mockTagRepo.On("ReplaceForExpense", mock.Anything, mock.Anything, []uuid.UUID{tagId}).Return(nil)
+
+		response, err := sv.Insert(context.Background(), &model.Expense{
+			UserId:      userId,
+			Description: "Villa",
+			Amount:      &amount,
+			Currency:    "IDR",
+			CategoryId:  categoryId,
+			Tags:        []string{"Trip-Bali-2026", "trip-bali-2026 "},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"trip-bali-2026"}, response.Tags)
+		mockTagRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #4
+	t.Run("[Test Case #4] Should return bad request when the category is not owned by the user", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, CategoryPgRepo: mockCategoryRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := sv.Insert(context.Background(), &model.Expense{
+			UserId:      userId,
+			Description: "Taxi",
+			Amount:      &amount,
+			Currency:    "IDR",
+			CategoryId:  uuid.New(),
+		})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+
+	// scenario test case #5
+	t.Run("[Test Case #5] Matching rules set the category, tags and description", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		mockRuleRepo := new(ruleMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:         mockRepo,
+			CategoryPgRepo: mockCategoryRepo,
+			TagPgRepo:      mockTagRepo,
+			RulePgRepo:     mockRuleRepo,
+			Logger:         logger.NewLogrusLogger(cfg),
+		})
+
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		transportId := uuid.New()
+		tagId := uuid.New()
+		mockRuleRepo.On("FindEnabledByUserId", mock.Anything, userId.String()).Return([]ruleModel.Rule{
+			{Id: uuid.New(), DescriptionRegex: `^grab\b`, CategoryId: &transportId, AddTags: ruleModel.Tags{"ride"}, RenameDescription: "grab ride"},
+			{Id: uuid.New(), DescriptionContains: "food", AddTags: ruleModel.Tags{"food"}},
+		}, nil)
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, transportId.String(), userId.String()).
+			Return(&categoryModel.Category{Id: transportId, UserId: userId}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("CreateHistory", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("FindAllAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Expense{}, nil)
+		mockTagRepo.On("FindOrCreateByNames", mock.Anything, userId, []string{"ride"}).
+			Return([]tagModel.Tag{{Id: tagId, UserId: userId, Name: "ride"}}, nil)
+		mockTagRepo.On("ReplaceForExpense", mock.Anything, mock.Anything, []uuid.UUID{tagId}).Return(nil)
+
+		response, err := sv.Insert(context.Background(), &model.Expense{
+			UserId:      userId,
+			Description: "GRAB 8412-JKT",
+			Amount:      &amount,
+			Currency:    "IDR",
+			CategoryId:  categoryId,
+		})
+		require.NoError(t, err)
+		require.Equal(t, transportId, response.CategoryId)
+		require.Equal(t, "grab ride", response.Description)
+		require.Equal(t, []string{"ride"}, response.Tags)
+		mockCategoryRepo.AssertNotCalled(t, "FindByIdAndUserId", mock.Anything, categoryId.String(), userId.String())
+	})
+
+	// scenario test case #6
+	t.Run("[Test Case #6] Expenses looking like duplicates are returned as warnings", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:         mockRepo,
+			CategoryPgRepo: mockCategoryRepo,
+			Logger:         logger.NewLogrusLogger(cfg),
+		})
+
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, categoryId.String(), userId.String()).
+			Return(&categoryModel.Category{Id: categoryId, UserId: userId}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).(*model.Expense).CreatedAt = 1767225600
+		}).Return(nil)
+		mockRepo.On("CreateHistory", mock.Anything, mock.Anything).Return(nil)
+
+		duplicateId := uuid.New()
+		mockRepo.On("FindAllAfter", mock.Anything, userId.String(), mock.MatchedBy(func(filter *model.ExpenseFilter) bool {
+			return *filter.MinAmount == amount && *filter.MaxAmount == amount &&
+				filter.CreatedFrom == 1767225600-model.DuplicateWindow && filter.CreatedTo == 1767225600+model.DuplicateWindow
+		}), mock.Anything, mock.Anything, mock.Anything).Return([]model.Expense{
+			{Id: duplicateId, Description: "coffee-shop", Amount: &amount, Currency: "IDR", CreatedAt: 1767222000},
+			{Id: uuid.New(), Description: "parking", Amount: &amount, Currency: "IDR", CreatedAt: 1767222000},
+		}, nil)
+
+		response, err := sv.Insert(context.Background(), &model.Expense{
+			UserId:      userId,
+			Description: "Coffee Shop",
+			Amount:      &amount,
+			Currency:    "IDR",
+			CategoryId:  categoryId,
+		})
+		require.NoError(t, err)
+		require.Len(t, response.PossibleDuplicates, 1)
+		require.Equal(t, duplicateId, response.PossibleDuplicates[0].Id)
+	})
+
+	// scenario test case #7
+	t.Run("[Test Case #7] The new expense is evaluated for alerts, a failure does not fail the insert", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		mockAlertService := new(alertMock.AlertServiceMock)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:         mockRepo,
+			CategoryPgRepo: mockCategoryRepo,
+			AlertService:   mockAlertService,
+			Logger:         logger.NewLogrusLogger(cfg),
+		})
+
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, categoryId.String(), userId.String()).
+			Return(&categoryModel.Category{Id: categoryId, UserId: userId}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("CreateHistory", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("FindAllAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Expense{}, nil)
+		mockAlertService.On("Evaluate", mock.Anything, mock.MatchedBy(func(expense *model.Expense) bool {
+			return expense.Description == "laptop"
+		})).Return(nil, errors.New("connection refused"))
+
+		response, err := sv.Insert(context.Background(), &model.Expense{
+			UserId:      userId,
+			Description: "Laptop",
+			Amount:      &amount,
+			Currency:    "IDR",
+			CategoryId:  categoryId,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "laptop", response.Description)
+		mockAlertService.AssertExpectations(t)
+	})
+
+	// scenario test case #8
+	t.Run("[Test Case #8] Should return bad request when the amount has decimals its currency does not have", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:         mockRepo,
+			CategoryPgRepo: mockCategoryRepo,
+			Logger:         logger.NewLogrusLogger(cfg),
+		})
+
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, categoryId.String(), userId.String()).
+			Return(&categoryModel.Category{Id: categoryId, UserId: userId}, nil)
+
+		cents := money.Amount(1550)
+		_, err := sv.Insert(context.Background(), &model.Expense{
+			UserId:      userId,
+			Description: "Parking",
+			Amount:      &cents,
+			Currency:    "IDR",
+			CategoryId:  categoryId,
+		})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.InvalidCurrencyAmountMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestExpenseService_Merge(t *testing.T) {
+	userId := uuid.New()
+	keepId, duplicateId := uuid.New(), uuid.New()
+	amount := money.Amount(2500000)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Duplicates are deleted and their tags added to the kept expense", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		mockOutboxRepo := new(outboxMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:     mockRepo,
+			TagPgRepo:  mockTagRepo,
+			OutboxRepo: mockOutboxRepo,
+			Logger:     logger.NewLogrusLogger(cfg),
+		})
+
+		kept := &model.Expense{Id: keepId, UserId: userId, Description: "coffee", Amount: &amount, Currency: "IDR", Version: 1}
+		duplicate := &model.Expense{Id: duplicateId, UserId: userId, Description: "coffee", Amount: &amount, Currency: "IDR", Version: 1}
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, keepId.String(), userId.String()).Return(kept, nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, duplicateId.String(), userId.String()).Return(duplicate, nil)
+		mockTagRepo.On("FindByExpenseIds", mock.Anything, []uuid.UUID{keepId}).
+			Return([]tagModel.TaggedExpense{{ExpenseId: keepId, Name: "cafe"}}, nil)
+		mockTagRepo.On("FindByExpenseIds", mock.Anything, []uuid.UUID{duplicateId}).
+			Return([]tagModel.TaggedExpense{{ExpenseId: duplicateId, Name: "work"}}, nil)
+		mockRepo.On("Remove", mock.Anything, mock.MatchedBy(func(expense *model.Expense) bool { return expense.Id == duplicateId })).Return(nil)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(expense *model.Expense) bool { return expense.Id == keepId })).Return(nil)
+		mockRepo.On("CreateHistory", mock.Anything, mock.Anything).Return(nil)
+
+		tagIds := []uuid.UUID{uuid.New(), uuid.New()}
+		mockTagRepo.On("FindOrCreateByNames", mock.Anything, userId, []string{"cafe", "work"}).Return([]tagModel.Tag{
+			{Id: tagIds[0], UserId: userId, Name: "cafe"},
+			{Id: tagIds[1], UserId: userId, Name: "work"},
+		}, nil)
+		mockTagRepo.On("ReplaceForExpense", mock.Anything, keepId, tagIds).Return(nil)
+		// the events are written to the outbox in the transaction of the merge
+		mockOutboxRepo.On("Create", mock.Anything, mock.MatchedBy(func(event *outboxModel.Event) bool {
+			return event.Type == outboxModel.TypeExpenseDeleted && event.AggregateId == duplicateId && event.UserId == userId
+		})).Return(nil).Once()
+		mockOutboxRepo.On("Create", mock.Anything, mock.MatchedBy(func(event *outboxModel.Event) bool {
+			return event.Type == outboxModel.TypeExpenseUpdated && event.AggregateId == keepId
+		})).Return(nil).Once()
+
+		response, err := sv.Merge(context.Background(), &model.MergeRequest{UserId: userId, KeepId: keepId, DuplicateIds: []uuid.UUID{duplicateId}})
+		require.NoError(t, err)
+		require.Equal(t, keepId, response.Id)
+		require.Equal(t, []string{"cafe", "work"}, response.Tags)
+		mockRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return bad request when the amounts differ", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, TagPgRepo: mockTagRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		other := money.Amount(100)
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, keepId.String(), userId.String()).
+			Return(&model.Expense{Id: keepId, UserId: userId, Amount: &amount, Currency: "IDR"}, nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, duplicateId.String(), userId.String()).
+			Return(&model.Expense{Id: duplicateId, UserId: userId, Amount: &other, Currency: "IDR"}, nil)
+		mockTagRepo.On("FindByExpenseIds", mock.Anything, mock.Anything).Return([]tagModel.TaggedExpense{}, nil)
+
+		_, err := sv.Merge(context.Background(), &model.MergeRequest{UserId: userId, KeepId: keepId, DuplicateIds: []uuid.UUID{duplicateId}})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.NotDuplicateMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "Remove")
+	})
+}
+
+func TestExpenseService_GetAll(t *testing.T) {
+	userId := uuid.New()
+	usd, idr := money.Amount(1000), money.Amount(5000000)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Listed amounts are converted to the base currency", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		mockRateService := new(currencyMock.ExchangeRateServiceMock)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:              mockRepo,
+			UserPgRepo:          mockUserRepo,
+			TagPgRepo:           mockTagRepo,
+			ExchangeRateService: mockRateService,
+			Logger:              logger.NewLogrusLogger(cfg),
+		})
+
+		mockTagRepo.On("FindByExpenseIds", mock.Anything, mock.Anything).Return([]tagModel.TaggedExpense{}, nil)
+		mockRepo.On("FindAll", mock.Anything, userId.String(), mock.Anything, &model.DefaultExpenseSort, 1, 10).Return([]model.Expense{
+			{UserId: userId, Amount: &usd, Currency: "USD"},
+			{UserId: userId, Amount: &idr, Currency: "IDR"},
+			{UserId: userId, Amount: &usd, Currency: "EUR"},
+		}, int64(3), nil)
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId, BaseCurrency: "IDR"}, nil)
+		mockRateService.On("Convert", mock.Anything, usd, "USD", "IDR", mock.Anything).Return(money.Amount(16000000), nil)
+		mockRateService.On("Convert", mock.Anything, idr, "IDR", "IDR", mock.Anything).Return(idr, nil)
+		mockRateService.On("Convert", mock.Anything, usd, "EUR", "IDR", mock.Anything).Return(money.Amount(0), currencyModel.ErrExchangeRateNotFound)
+
+		page, err := sv.GetAll(context.Background(), userId.String(), &model.SearchExpenseRequestQueryParam{Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Equal(t, int64(3), page.TotalItems)
+		require.Empty(t, page.NextCursor)
+		expenses := page.Expenses
+		require.Equal(t, money.Amount(16000000), *expenses[0].BaseAmount)
+		require.Equal(t, idr, *expenses[1].BaseAmount)
+		require.Nil(t, expenses[2].BaseAmount)
+		for _, e := range expenses {
+			require.Equal(t, "IDR", e.BaseCurrency)
+		}
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Tags filter is normalized and tags are attached", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		mockRateService := new(currencyMock.ExchangeRateServiceMock)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:              mockRepo,
+			UserPgRepo:          mockUserRepo,
+			TagPgRepo:           mockTagRepo,
+			ExchangeRateService: mockRateService,
+			Logger:              logger.NewLogrusLogger(cfg),
+		})
+
+		expenseId := uuid.New()
+		filter := &model.ExpenseFilter{Tags: []string{"business", "reimbursable"}, MatchAllTags: true}
+		mockRepo.On("FindAll", mock.Anything, userId.String(), filter, &model.DefaultExpenseSort, 1, 10).
+			Return([]model.Expense{{Id: expenseId, UserId: userId, Amount: &idr, Currency: "IDR"}}, int64(1), nil)
+		mockTagRepo.On("FindByExpenseIds", mock.Anything, []uuid.UUID{expenseId}).Return([]tagModel.TaggedExpense{
+			{ExpenseId: expenseId, Id: uuid.New(), Name: "business"},
+			{ExpenseId: expenseId, Id: uuid.New(), Name: "reimbursable"},
+		}, nil)
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId, BaseCurrency: "IDR"}, nil)
+		mockRateService.On("Convert", mock.Anything, idr, "IDR", "IDR", mock.Anything).Return(idr, nil)
+
+		page, err := sv.GetAll(context.Background(), userId.String(), &model.SearchExpenseRequestQueryParam{
+			Page:     1,
+			Limit:    10,
+			Tags:     " Business,reimbursable,business",
+			TagsMode: "all",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"business", "reimbursable"}, page.Expenses[0].Tags)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Query filters and sort are passed to the repository", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		food, travel := uuid.New(), uuid.New()
+		min, max := money.Amount(1050), money.Amount(200000)
+		filter := &model.ExpenseFilter{
+			CategoryIds: []uuid.UUID{food, travel},
+			MinAmount:   &min,
+			MaxAmount:   &max,
+			Description: "coffee",
+			CreatedFrom: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+			CreatedTo:   time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC).Unix(),
+		}
+		sort := &model.ExpenseSort{Field: "amount", Descending: true}
+		mockRepo.On("FindAll", mock.Anything, userId.String(), filter, sort, 1, 10).Return([]model.Expense{}, int64(0), nil)
+
+		_, err := sv.GetAll(context.Background(), userId.String(), &model.SearchExpenseRequestQueryParam{
+			Page:        1,
+			Limit:       10,
+			CategoryId:  food.String() + "," + travel.String(),
+			MinAmount:   "10.50",
+			MaxAmount:   "2000",
+			Description: " coffee ",
+			Filter:      "custom",
+			StartDate:   "2025-12-01",
+			EndDate:     "2026-01-31",
+			CreatedFrom: "2026-01-01",
+			Sort:        "amount",
+			Order:       "desc",
+		})
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #4
+	t.Run("[Test Case #4] Should return bad request for an invalid filter", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		for _, request := range []*model.SearchExpenseRequestQueryParam{
+			{Page: 1, Limit: 10, CategoryId: "food"},
+			{Page: 1, Limit: 10, MinAmount: "20", MaxAmount: "10"},
+			{Page: 1, Limit: 10, CreatedFrom: "2026-02-01", CreatedTo: "2026-01-01"},
+			{Page: 1, Limit: 10, Filter: "custom"},
+		} {
+			_, err := sv.GetAll(context.Background(), userId.String(), request)
+			var er *httpErrors.Error
+			require.True(t, errors.As(err, &er))
+			require.Equal(t, http.StatusBadRequest, er.Status)
+		}
+		mockRepo.AssertNotCalled(t, "FindAll")
+	})
+
+	// scenario test case #5
+	t.Run("[Test Case #5] A search is sorted by relevance unless another sort is asked", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		filter := &model.ExpenseFilter{Search: "parking bandung"}
+		mockRepo.On("FindAll", mock.Anything, userId.String(), filter, &model.ExpenseSort{Field: "relevance", Descending: true}, 1, 10).
+			Return([]model.Expense{}, int64(0), nil)
+		mockRepo.On("FindAll", mock.Anything, userId.String(), filter, &model.ExpenseSort{Field: "amount"}, 1, 10).
+			Return([]model.Expense{}, int64(0), nil)
+
+		_, err := sv.GetAll(context.Background(), userId.String(), &model.SearchExpenseRequestQueryParam{Page: 1, Limit: 10, Q: " parking bandung "})
+		require.NoError(t, err)
+		_, err = sv.GetAll(context.Background(), userId.String(), &model.SearchExpenseRequestQueryParam{Page: 1, Limit: 10, Q: "parking bandung", Sort: "amount"})
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #6
+	t.Run("[Test Case #6] Cursor pages read one more expense to return the next cursor", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		mockRateService := new(currencyMock.ExchangeRateServiceMock)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:              mockRepo,
+			UserPgRepo:          mockUserRepo,
+			TagPgRepo:           mockTagRepo,
+			ExchangeRateService: mockRateService,
+			Logger:              logger.NewLogrusLogger(cfg),
+		})
+
+		after := &model.ExpenseCursor{Sort: model.DefaultExpenseSort, Value: int64(1767225600), Id: uuid.New()}
+		expenses := make([]model.Expense, 11)
+		for i := range expenses {
+			expenses[i] = model.Expense{Id: uuid.New(), UserId: userId, Amount: &idr, Currency: "IDR", CreatedAt: int64(1767225599 - i)}
+		}
+		mockRepo.On("FindAllAfter", mock.Anything, userId.String(), mock.Anything, &model.DefaultExpenseSort, after, 11).Return(expenses, nil)
+		mockTagRepo.On("FindByExpenseIds", mock.Anything, mock.Anything).Return([]tagModel.TaggedExpense{}, nil)
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId, BaseCurrency: "IDR"}, nil)
+		mockRateService.On("Convert", mock.Anything, idr, "IDR", "IDR", mock.Anything).Return(idr, nil)
+
+		page, err := sv.GetAll(context.Background(), userId.String(), &model.SearchExpenseRequestQueryParam{Limit: 10, Cursor: after.Encode()})
+		require.NoError(t, err)
+		require.Len(t, page.Expenses, 10)
+
+		next, err := model.DecodeExpenseCursor(page.NextCursor)
+		require.NoError(t, err)
+		require.Equal(t, expenses[9].Id, next.Id)
+		require.Equal(t, expenses[9].CreatedAt, next.Value)
+		mockRepo.AssertNotCalled(t, "FindAll")
+	})
+
+	// scenario test case #7
+	t.Run("[Test Case #7] Should return bad request for a cursor of another sort", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		cursor := &model.ExpenseCursor{Sort: model.ExpenseSort{Field: "amount"}, Value: int64(1000), Id: uuid.New()}
+		_, err := sv.GetAll(context.Background(), userId.String(), &model.SearchExpenseRequestQueryParam{Limit: 10, Cursor: cursor.Encode()})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		mockRepo.AssertNotCalled(t, "FindAllAfter")
+	})
+}
+
+func TestExpenseService_GetReport(t *testing.T) {
+	userId := uuid.New()
+	food, transport := uuid.New(), uuid.New()
+	monday := time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Daily totals are converted to the base currency at the rate of their day", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		mockRateService := new(currencyMock.ExchangeRateServiceMock)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:              mockRepo,
+			UserPgRepo:          mockUserRepo,
+			ExchangeRateService: mockRateService,
+			Logger:              logger.NewLogrusLogger(cfg),
+		})
+
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId, BaseCurrency: "IDR"}, nil)
+		mockRepo.On("Summarize", mock.Anything, userId.String(), mock.Anything).Return(&model.ExpenseReport{TotalExpenses: 4}, nil)
+		mockRepo.On("SumByCategoryAndDay", mock.Anything, userId.String(), mock.Anything).Return([]model.CategoryDailyTotal{
+			{CategoryId: food, Name: "food", Day: monday, Currency: "USD", Amount: 1000},
+			{CategoryId: food, Name: "food", Day: tuesday, Currency: "USD", Amount: 1000},
+			{CategoryId: transport, Name: "transport", Day: tuesday, Currency: "IDR", Amount: 5000000},
+			{CategoryId: transport, Name: "transport", Day: tuesday, Currency: "EUR", Amount: 2000},
+		}, nil)
+		mockRateService.On("Convert", mock.Anything, money.Amount(1000), "USD", "IDR", monday).Return(money.Amount(16000000), nil)
+		mockRateService.On("Convert", mock.Anything, money.Amount(1000), "USD", "IDR", tuesday).Return(money.Amount(16500000), nil)
+		mockRateService.On("Convert", mock.Anything, money.Amount(5000000), "IDR", "IDR", tuesday).Return(money.Amount(5000000), nil)
+		mockRateService.On("Convert", mock.Anything, money.Amount(2000), "EUR", "IDR", tuesday).Return(money.Amount(0), currencyModel.ErrExchangeRateNotFound)
+
+		report, err := sv.GetReport(context.Background(), userId.String(), &model.SearchExpenseRequestQueryParam{})
+		require.NoError(t, err)
+		require.Equal(t, int64(4), report.TotalExpenses)
+		require.Equal(t, "IDR", report.Currency)
+		require.Equal(t, money.Amount(37500000), report.Total)
+		require.Equal(t, []model.BaseCategoryTotal{
+			{CategoryId: food, Name: "food", Amount: 32500000},
+			{CategoryId: transport, Name: "transport", Amount: 5000000},
+		}, report.BaseCategories)
+		require.Equal(t, []string{"EUR"}, report.MissingRates)
+		mockRateService.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return internal server error when the daily totals fail", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, UserPgRepo: mockUserRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId}, nil)
+		mockRepo.On("Summarize", mock.Anything, userId.String(), mock.Anything).Return(&model.ExpenseReport{}, nil)
+		mockRepo.On("SumByCategoryAndDay", mock.Anything, userId.String(), mock.Anything).Return(nil, errors.New("connection refused"))
+
+		report, err := sv.GetReport(context.Background(), userId.String(), &model.SearchExpenseRequestQueryParam{})
+		require.Nil(t, report)
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusInternalServerError, er.Status)
+	})
+}
+
+func TestExpenseService_GetForecast(t *testing.T) {
+	userId := uuid.New()
+	categoryId := uuid.New()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	usd, eur := money.Amount(1000), money.Amount(2000)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Spending so far is converted to the base currency and missing rates are listed", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		mockRateService := new(currencyMock.ExchangeRateServiceMock)
+		sv := NewExpenseService(&ServiceConfig{
+			PgRepo:              mockRepo,
+			UserPgRepo:          mockUserRepo,
+			ExchangeRateService: mockRateService,
+			Logger:              logger.NewLogrusLogger(cfg),
+		})
+
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId, BaseCurrency: "IDR"}, nil)
+		mockRepo.On("SumByDay", mock.Anything, userId.String(), mock.Anything).Return([]model.DailyTotal{
+			{Day: today, Currency: "USD", Amount: usd},
+			{Day: today, Currency: "EUR", Amount: eur},
+		}, nil)
+		mockRepo.On("Summarize", mock.Anything, userId.String(), mock.Anything).Return(&model.ExpenseReport{
+			Categories: []model.CategoryTotal{{CategoryId: categoryId, Name: "food", Currency: "USD", Count: 1, Amount: usd}},
+		}, nil).Once()
+		mockRepo.On("Summarize", mock.Anything, userId.String(), mock.Anything).Return(&model.ExpenseReport{}, nil).Once()
+		mockRepo.On("FindRecurring", mock.Anything, userId.String(), mock.Anything, model.ForecastHistoryMonths).Return([]model.RecurringExpense{}, nil)
+		mockRateService.On("Convert", mock.Anything, usd, "USD", "IDR", mock.Anything).Return(money.Amount(16000000), nil)
+		mockRateService.On("Convert", mock.Anything, eur, "EUR", "IDR", mock.Anything).Return(money.Amount(0), currencyModel.ErrExchangeRateNotFound)
+
+		forecast, err := sv.GetForecast(context.Background(), userId)
+		require.NoError(t, err)
+		require.Equal(t, "IDR", forecast.Currency)
+		require.Equal(t, money.Amount(16000000), forecast.Spent)
+		require.Equal(t, money.Amount(16000000), forecast.Projected)
+		require.Equal(t, []string{"EUR"}, forecast.MissingRates)
+		require.Len(t, forecast.Categories, 1)
+		require.Equal(t, money.Amount(16000000), forecast.Categories[0].Spent)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return internal server error when the daily totals fail", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, UserPgRepo: mockUserRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId}, nil)
+		mockRepo.On("SumByDay", mock.Anything, userId.String(), mock.Anything).Return(nil, errors.New("connection refused"))
+
+		forecast, err := sv.GetForecast(context.Background(), userId)
+		require.Nil(t, forecast)
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusInternalServerError, er.Status)
+	})
+}
+
+func TestExpenseService_Batch(t *testing.T) {
+	userId := uuid.New()
+	categoryId := uuid.New()
+	amount := money.Amount(2500)
+
+	newOperations := func(missing uuid.UUID) []model.BatchOperation {
+		return []model.BatchOperation{
+			{Op: model.BatchCreate, Expense: &model.Expense{UserId: userId, Description: "Parking", Amount: &amount, Currency: "IDR", CategoryId: categoryId}},
+			{Op: model.BatchDelete, Expense: &model.Expense{Id: missing, UserId: userId}},
+			{Op: model.BatchCreate, Err: httpErrors.NewBadRequestError(nil)},
+		}
+	}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Without atomic every operation is applied on its own", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, CategoryPgRepo: mockCategoryRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		missing := uuid.New()
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, categoryId.String(), userId.String()).
+			Return(&categoryModel.Category{Id: categoryId, UserId: userId}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("CreateHistory", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("FindAllAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Expense{}, nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, missing.String(), userId.String()).Return(nil, gorm.ErrRecordNotFound)
+
+		response, err := sv.Batch(context.Background(), newOperations(missing), false)
+		require.NoError(t, err)
+		require.True(t, response.Applied)
+		require.Equal(t, 1, response.Succeeded)
+		require.Equal(t, 2, response.Failed)
+		require.Equal(t, http.StatusCreated, response.Results[0].Status)
+		require.Equal(t, http.StatusNotFound, response.Results[1].Status)
+		require.Equal(t, http.StatusBadRequest, response.Results[2].Status)
+		// every operation runs in its own transaction, the invalid one runs in none
+		mockRepo.AssertNumberOfCalls(t, "Transaction", 2)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] An atomic batch is rolled back on the first failure", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, CategoryPgRepo: mockCategoryRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		missing := uuid.New()
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, categoryId.String(), userId.String()).
+			Return(&categoryModel.Category{Id: categoryId, UserId: userId}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("CreateHistory", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("FindAllAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Expense{}, nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, missing.String(), userId.String()).Return(nil, gorm.ErrRecordNotFound)
+
+		response, err := sv.Batch(context.Background(), newOperations(missing), true)
+		require.NoError(t, err)
+		require.False(t, response.Applied)
+		require.Equal(t, 0, response.Succeeded)
+		require.Equal(t, 3, response.Failed)
+		require.Equal(t, http.StatusFailedDependency, response.Results[0].Status)
+		require.Nil(t, response.Results[0].Data)
+		require.Equal(t, http.StatusNotFound, response.Results[1].Status)
+		require.Equal(t, http.StatusFailedDependency, response.Results[2].Status)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestExpenseService_Update_Version(t *testing.T) {
+	userId := uuid.New()
+	id := uuid.New()
+	amount := money.Amount(3000)
+	stored := func() *model.Expense {
+		return &model.Expense{Id: id, UserId: userId, Description: "taxi", Amount: &amount, Currency: "IDR", Version: 4}
+	}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Should return precondition failed for a stale If-Match", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, id.String(), userId.String()).Return(stored(), nil)
+
+		_, err := sv.Update(context.Background(), &model.Expense{Id: id, UserId: userId, Description: "bus", Version: 3})
+		var httpErr *httpErrors.Error
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, http.StatusPreconditionFailed, httpErr.Status)
+		mockRepo.AssertNotCalled(t, "Update")
+
+		err = sv.Delete(context.Background(), &model.Expense{Id: id, UserId: userId, Version: 3})
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, http.StatusPreconditionFailed, httpErr.Status)
+		mockRepo.AssertNotCalled(t, "Remove")
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return precondition failed when a concurrent update wins", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, TagPgRepo: mockTagRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockTagRepo.On("FindByExpenseIds", mock.Anything, []uuid.UUID{id}).Return([]tagModel.TaggedExpense{}, nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, id.String(), userId.String()).Return(stored(), nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(errors.Wrap(model.ErrVersionConflict, "Update"))
+
+		_, err := sv.Update(context.Background(), &model.Expense{Id: id, UserId: userId, Description: "bus"})
+		var httpErr *httpErrors.Error
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, http.StatusPreconditionFailed, httpErr.Status)
+	})
+}
+
+func TestExpenseService_History(t *testing.T) {
+	userId := uuid.New()
+	id := uuid.New()
+	amount := money.Amount(3000)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] A delete is recorded with the actor and the request id", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, TagPgRepo: mockTagRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		stored := &model.Expense{Id: id, UserId: userId, Description: "taxi", Amount: &amount, Currency: "IDR", Version: 2}
+		mockRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, id.String(), userId.String()).Return(stored, nil)
+		mockTagRepo.On("FindByExpenseIds", mock.Anything, []uuid.UUID{id}).
+			Return([]tagModel.TaggedExpense{{ExpenseId: id, Name: "business"}}, nil)
+		mockRepo.On("Remove", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("CreateHistory", mock.Anything, mock.MatchedBy(func(history *model.ExpenseHistory) bool {
+			return history.Action == model.HistoryDelete &&
+				history.ActorId != nil && *history.ActorId == userId &&
+				history.RequestId == "req-1" &&
+				string(history.Changes["tags"].Before) == `["business"]` &&
+				string(history.Changes["tags"].After) == "null"
+		})).Return(nil)
+
+		ctx := context.WithValue(context.WithValue(context.Background(), contextutils.KeyActorID, userId), contextutils.KeyRequestID, "req-1")
+		err := sv.Delete(ctx, &model.Expense{Id: id, UserId: userId})
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return not found for the history of an unknown expense", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewExpenseService(&ServiceConfig{PgRepo: mockRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("FindHistory", mock.Anything, id.String(), userId.String()).Return([]model.ExpenseHistory{}, nil)
+		mockRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, id.String(), userId.String()).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := sv.GetHistory(context.Background(), id.String(), userId.String())
+		var httpErr *httpErrors.Error
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, http.StatusNotFound, httpErr.Status)
+	})
+}
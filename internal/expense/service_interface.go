@@ -2,6 +2,7 @@ package expense
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/mrizkisaputra/expenses-api/internal/expense/model"
 )
 
@@ -14,7 +15,22 @@ type ExpenseService interface {
 
 	Update(ctx context.Context, request *model.Expense) (*model.Expense, error)
 
+	Restore(ctx context.Context, request *model.Expense) (*model.Expense, error)
+
+	GetHistory(ctx context.Context, id, userId string) ([]model.ExpenseHistory, error)
+
 	GetById(ctx context.Context, id, userId string) (*model.Expense, error)
 
-	GetAll(ctx context.Context, userId string, request *model.SearchExpenseRequestQueryParam) ([]model.Expense, int64, error)
+	GetAll(ctx context.Context, userId string, request *model.SearchExpenseRequestQueryParam) (*model.ExpensePage, error)
+
+	GetReport(ctx context.Context, userId string, request *model.SearchExpenseRequestQueryParam) (*model.ExpenseReport, error)
+
+	// GetForecast projects the spending of the user at the end of the current month
+	GetForecast(ctx context.Context, userId uuid.UUID) (*model.Forecast, error)
+
+	GetDuplicates(ctx context.Context, userId string, request *model.SearchExpenseRequestQueryParam) ([]model.DuplicateGroup, error)
+
+	Merge(ctx context.Context, request *model.MergeRequest) (*model.Expense, error)
+
+	Batch(ctx context.Context, operations []model.BatchOperation, atomic bool) (*model.BatchResponse, error)
 }
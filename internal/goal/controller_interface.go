@@ -0,0 +1,25 @@
+package goal
+
+import "github.com/gin-gonic/gin"
+
+// GoalController defines methods the routes expects
+// any controllers it interacts with to implement
+type GoalController interface {
+	CreateGoal() gin.HandlerFunc
+
+	UpdateGoal() gin.HandlerFunc
+
+	DeleteGoal() gin.HandlerFunc
+
+	GetGoalById() gin.HandlerFunc
+
+	GetAllGoal() gin.HandlerFunc
+
+	GetGoalProgress() gin.HandlerFunc
+
+	CreateContribution() gin.HandlerFunc
+
+	GetAllContribution() gin.HandlerFunc
+
+	DeleteContribution() gin.HandlerFunc
+}
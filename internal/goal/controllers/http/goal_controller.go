@@ -0,0 +1,359 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/goal"
+	"github.com/mrizkisaputra/expenses-api/internal/goal/model"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/sirupsen/logrus"
+	"math"
+	"net/http"
+	"time"
+)
+
+type ControllerConfig struct {
+	GoalService goal.GoalService
+	Logger      *logrus.Logger
+}
+
+// goalController acts as a struct for injecting an implementation of GoalController interface
+// for use in controller methods
+type goalController struct {
+	goalService goal.GoalService
+	logger      *logrus.Logger
+}
+
+// NewGoalController is a factory function
+// initializing a goalController with its service layer dependencies
+func NewGoalController(config *ControllerConfig) goal.GoalController {
+	return &goalController{
+		goalService: config.GoalService,
+		logger:      config.Logger,
+	}
+}
+
+func (gc goalController) CreateGoal() gin.HandlerFunc {
+	type CreateGoalRequest struct {
+		Name         string        `json:"name" validate:"required,max=100"`
+		TargetAmount *money.Amount `json:"target_amount" validate:"required,gt=0"`
+		Currency     string        `json:"currency" validate:"omitempty,iso4217"`
+		TargetDate   string        `json:"target_date" validate:"required,datetime=2006-01-02"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(CreateGoalRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		// the format is already validated
+		targetDate, _ := time.Parse("2006-01-02", request.TargetDate)
+		entity := &model.Goal{
+			UserId:       auth.Id,
+			Name:         request.Name,
+			TargetAmount: request.TargetAmount,
+			Currency:     request.Currency,
+			TargetDate:   targetDate,
+		}
+		response, err := gc.goalService.Create(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, &expenseModel.ApiResponse{
+			Status:  http.StatusCreated,
+			Message: "Created",
+			Data:    response,
+		})
+	}
+}
+
+func (gc goalController) UpdateGoal() gin.HandlerFunc {
+	type UpdateGoalRequest struct {
+		Id           string        `json:"-" validate:"required,uuid"`
+		Name         string        `json:"name" validate:"omitempty,max=100"`
+		TargetAmount *money.Amount `json:"target_amount" validate:"omitempty,gt=0"`
+		TargetDate   string        `json:"target_date" validate:"omitempty,datetime=2006-01-02"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(UpdateGoalRequest)
+		request.Id = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Goal{
+			Id:           uuid.MustParse(request.Id),
+			UserId:       auth.Id,
+			Name:         request.Name,
+			TargetAmount: request.TargetAmount,
+		}
+		if request.TargetDate != "" {
+			entity.TargetDate, _ = time.Parse("2006-01-02", request.TargetDate)
+		}
+		response, err := gc.goalService.Update(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, &expenseModel.ApiResponse{
+			Status:  http.StatusAccepted,
+			Message: "Accepted",
+			Data:    response,
+		})
+	}
+}
+
+func (gc goalController) DeleteGoal() gin.HandlerFunc {
+	type DeleteGoalRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &DeleteGoalRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if err := gc.goalService.Delete(ctx, request.Id, auth.Id.String()); err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusNoContent, &expenseModel.ApiResponse{
+			Status:  http.StatusNoContent,
+			Message: "No Content",
+		})
+	}
+}
+
+func (gc goalController) GetGoalById() gin.HandlerFunc {
+	type GetGoalRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &GetGoalRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := gc.goalService.GetById(ctx, request.Id, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (gc goalController) GetAllGoal() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		progresses, err := gc.goalService.GetAll(ctx, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       progresses,
+			TotalItems: int64(len(progresses)),
+		})
+	}
+}
+
+func (gc goalController) GetGoalProgress() gin.HandlerFunc {
+	type GetGoalProgressRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &GetGoalProgressRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := gc.goalService.GetProgress(ctx, request.Id, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (gc goalController) CreateContribution() gin.HandlerFunc {
+	type CreateContributionRequest struct {
+		GoalId     string        `json:"-" validate:"required,uuid"`
+		Amount     *money.Amount `json:"amount" validate:"required_without=TransferId,omitempty,gt=0"`
+		TransferId string        `json:"transfer_id" validate:"omitempty,uuid"`
+		Note       string        `json:"note" validate:"omitempty,max=200"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(CreateContributionRequest)
+		request.GoalId = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Contribution{
+			GoalId: uuid.MustParse(request.GoalId),
+			UserId: auth.Id,
+			Amount: request.Amount,
+			Note:   request.Note,
+		}
+		if request.TransferId != "" {
+			transferId := uuid.MustParse(request.TransferId)
+			entity.TransferId = &transferId
+		}
+		response, err := gc.goalService.Contribute(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, &expenseModel.ApiResponse{
+			Status:  http.StatusCreated,
+			Message: "Created",
+			Data:    response,
+		})
+	}
+}
+
+func (gc goalController) GetAllContribution() gin.HandlerFunc {
+	type GetAllContributionRequest struct {
+		GoalId string `form:"-" validate:"required,uuid"`
+		Page   int    `form:"page" validate:"omitempty,numeric,min=1"`
+		Limit  int    `form:"limit" validate:"omitempty,numeric,min=10"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(GetAllContributionRequest)
+		request.GoalId = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if request.Page == 0 {
+			request.Page = 1
+		}
+		if request.Limit == 0 {
+			request.Limit = 10
+		}
+
+		contributions, total, err := gc.goalService.GetContributions(ctx, request.GoalId, auth.Id.String(), request.Page, request.Limit)
+		if err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       contributions,
+			Page:       request.Page,
+			Limit:      request.Limit,
+			TotalItems: total,
+			TotalPages: int64(math.Ceil(float64(total) / float64(request.Limit))),
+		})
+	}
+}
+
+func (gc goalController) DeleteContribution() gin.HandlerFunc {
+	type DeleteContributionRequest struct {
+		GoalId string `validate:"required,uuid"`
+		Id     string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &DeleteContributionRequest{
+			GoalId: ctx.Param("id"),
+			Id:     ctx.Param("contributionId"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if err := gc.goalService.DeleteContribution(ctx, request.Id, request.GoalId, auth.Id.String()); err != nil {
+			utils.LogErrorResponse(ctx, gc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusNoContent, &expenseModel.ApiResponse{
+			Status:  http.StatusNoContent,
+			Message: "No Content",
+		})
+	}
+}
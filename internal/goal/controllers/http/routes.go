@@ -0,0 +1,20 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/goal"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+)
+
+func MapGoalRoutes(goalGroup *gin.RouterGroup, controller goal.GoalController, mw *middleware.MiddlewareManager) {
+	goalGroup.Use(mw.AuthJwtMiddleware())
+	goalGroup.POST("/", mw.IdempotencyMiddleware(), controller.CreateGoal())
+	goalGroup.GET("/", controller.GetAllGoal())
+	goalGroup.GET("/:id", controller.GetGoalById())
+	goalGroup.GET("/:id/progress", controller.GetGoalProgress())
+	goalGroup.PATCH("/:id", mw.IdempotencyMiddleware(), controller.UpdateGoal())
+	goalGroup.DELETE("/:id", mw.IdempotencyMiddleware(), controller.DeleteGoal())
+	goalGroup.POST("/:id/contributions", mw.IdempotencyMiddleware(), controller.CreateContribution())
+	goalGroup.GET("/:id/contributions", controller.GetAllContribution())
+	goalGroup.DELETE("/:id/contributions/:contributionId", mw.IdempotencyMiddleware(), controller.DeleteContribution())
+}
@@ -0,0 +1,85 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/goal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPostgresRepository is a mock type for goal.PostgresRepository
+type MockPostgresRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostgresRepository) Create(ctx context.Context, goal *model.Goal) error {
+	args := m.Called(ctx, goal)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Goal, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Goal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Goal, error) {
+	args := m.Called(ctx, name, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Goal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindAllByUserId(ctx context.Context, userId string) ([]model.Goal, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Goal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) Update(ctx context.Context, goal *model.Goal) error {
+	args := m.Called(ctx, goal)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) Remove(ctx context.Context, goal *model.Goal) error {
+	args := m.Called(ctx, goal)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) CreateContribution(ctx context.Context, contribution *model.Contribution) error {
+	args := m.Called(ctx, contribution)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindContributionByIdAndGoalId(ctx context.Context, id, goalId string) (*model.Contribution, error) {
+	args := m.Called(ctx, id, goalId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Contribution), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindContributionByTransferId(ctx context.Context, transferId string) (*model.Contribution, error) {
+	args := m.Called(ctx, transferId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Contribution), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindContributionsByGoalId(ctx context.Context, goalId string, page, limit int) ([]model.Contribution, int64, error) {
+	args := m.Called(ctx, goalId, page, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Contribution), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockPostgresRepository) RemoveContribution(ctx context.Context, contribution *model.Contribution) error {
+	args := m.Called(ctx, contribution)
+	return args.Error(0)
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"math"
+	"strings"
+	"time"
+)
+
+// status of the progress of a goal
+const (
+	StatusAchieved = "achieved"
+	StatusOnTrack  = "on_track"
+	StatusBehind   = "behind"
+	StatusOverdue  = "overdue"
+)
+
+// Goal mapping table 'goals'.
+// a target the user saves toward, like "Laptop Rp15jt by June". the currency is fixed once created,
+// Saved is the sum of its contributions, it is only read
+type Goal struct {
+	Id           uuid.UUID     `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	UserId       uuid.UUID     `json:"user_id" gorm:"column:id_user"`
+	Name         string        `json:"name" gorm:"column:name"`
+	TargetAmount *money.Amount `json:"target_amount" gorm:"column:target_amount"`
+	Currency     string        `json:"currency" gorm:"column:currency;<-:create"` // allow read and create
+	TargetDate   time.Time     `json:"target_date" gorm:"column:target_date;type:date"`
+	Saved        money.Amount  `json:"saved" gorm:"column:saved;->"`                                       // allow read
+	CreatedAt    int64         `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt    int64         `json:"updated_at" gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (goal *Goal) TableName() string {
+	return "goals"
+}
+
+func (goal *Goal) PrepareCreate() {
+	goal.Name = strings.Join(strings.Fields(goal.Name), " ")
+	goal.Currency = strings.ToUpper(strings.TrimSpace(goal.Currency))
+}
+
+func (goal *Goal) PrepareUpdate(oldGoal *Goal) {
+	if goal.Name != "" {
+		oldGoal.Name = strings.Join(strings.Fields(goal.Name), " ")
+	}
+
+	if goal.TargetAmount != nil {
+		oldGoal.TargetAmount = goal.TargetAmount
+	}
+
+	if !goal.TargetDate.IsZero() {
+		oldGoal.TargetDate = goal.TargetDate
+	}
+}
+
+// Contribution mapping table 'goal_contributions'.
+// money saved toward a goal in the currency of the goal, either entered manually or
+// linked to the transfer (to a savings account) the money was moved with
+type Contribution struct {
+	Id         uuid.UUID     `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	GoalId     uuid.UUID     `json:"goal_id" gorm:"column:id_goal"`
+	UserId     uuid.UUID     `json:"user_id" gorm:"column:id_user"`
+	TransferId *uuid.UUID    `json:"transfer_id" gorm:"column:id_transfer"`
+	Amount     *money.Amount `json:"amount" gorm:"column:amount"`
+	Note       string        `json:"note" gorm:"column:note"`
+	CreatedAt  int64         `json:"created_at" gorm:"column:created_at;autoCreateTime;<-:create"` // allow read and create
+	UpdatedAt  int64         `json:"updated_at" gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (contribution *Contribution) TableName() string {
+	return "goal_contributions"
+}
+
+// Progress is how far a goal is on a day: Remaining is still to save over MonthsLeft months
+// (the current month and the month of the target date included), that is RequiredMonthly a month.
+// the goal is on track while Saved is at least the share of the target of the time elapsed since it was created
+type Progress struct {
+	Goal            *Goal        `json:"goal"`
+	Remaining       money.Amount `json:"remaining"`
+	Percentage      float64      `json:"percentage"`
+	MonthsLeft      int          `json:"months_left"`
+	RequiredMonthly money.Amount `json:"required_monthly"`
+	Expected        money.Amount `json:"expected"`
+	Status          string       `json:"status"`
+}
+
+// NewProgress computes the progress of the goal on the day today (UTC)
+func NewProgress(goal *Goal, today time.Time) *Progress {
+	target := *goal.TargetAmount
+	progress := &Progress{
+		Goal:       goal,
+		Percentage: math.Round(float64(goal.Saved)/float64(target)*10000) / 100,
+		MonthsLeft: monthsLeft(today, goal.TargetDate),
+	}
+	if goal.Saved < target {
+		progress.Remaining = target - goal.Saved
+	}
+
+	// the amount expected by today when saving evenly from the creation to the target date
+	start := time.UnixMilli(goal.CreatedAt).UTC()
+	total := goal.TargetDate.Sub(start)
+	elapsed := today.Sub(start)
+	switch {
+	case total <= 0 || elapsed >= total:
+		progress.Expected = target
+	case elapsed > 0:
+		progress.Expected = money.Amount(math.Round(float64(target) * float64(elapsed) / float64(total)))
+	}
+
+	switch {
+	case progress.Remaining == 0:
+		progress.Status = StatusAchieved
+	case progress.MonthsLeft == 0:
+		progress.Status = StatusOverdue
+		progress.RequiredMonthly = progress.Remaining
+	default:
+		progress.RequiredMonthly = money.Amount(math.Ceil(float64(progress.Remaining) / float64(progress.MonthsLeft)))
+		if goal.Saved >= progress.Expected {
+			progress.Status = StatusOnTrack
+		} else {
+			progress.Status = StatusBehind
+		}
+	}
+	return progress
+}
+
+// monthsLeft counts the months from the month of today to the month of the target date both included,
+// 0 once the target date has passed
+func monthsLeft(today, targetDate time.Time) int {
+	year, month, day := today.UTC().Date()
+	today = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if targetDate.Before(today) {
+		return 0
+	}
+	return (targetDate.Year()-year)*12 + int(targetDate.Month()-month) + 1
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNewProgress(t *testing.T) {
+	target := money.Amount(1500000000)
+	created := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	goal := &Goal{
+		TargetAmount: &target,
+		TargetDate:   time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC),
+		CreatedAt:    created.UnixMilli(),
+	}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Remaining is spread over the months left including the current one", func(t *testing.T) {
+		goal.Saved = 300000000
+		progress := NewProgress(goal, time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC))
+		require.Equal(t, money.Amount(1200000000), progress.Remaining)
+		require.Equal(t, 4, progress.MonthsLeft)
+		require.Equal(t, money.Amount(300000000), progress.RequiredMonthly)
+		require.Equal(t, 20.0, progress.Percentage)
+		require.Equal(t, StatusBehind, progress.Status)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Goal saving ahead of the even pace is on track", func(t *testing.T) {
+		goal.Saved = 600000000
+		progress := NewProgress(goal, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC))
+		require.Equal(t, 5, progress.MonthsLeft)
+		require.Equal(t, StatusOnTrack, progress.Status)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Goal past its target date is overdue", func(t *testing.T) {
+		goal.Saved = 600000000
+		progress := NewProgress(goal, time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+		require.Equal(t, 0, progress.MonthsLeft)
+		require.Equal(t, money.Amount(900000000), progress.RequiredMonthly)
+		require.Equal(t, target, progress.Expected)
+		require.Equal(t, StatusOverdue, progress.Status)
+	})
+
+	// scenario test case #4
+	t.Run("[Test Case #4] Goal saved in full is achieved", func(t *testing.T) {
+		goal.Saved = 1600000000
+		progress := NewProgress(goal, time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+		require.Equal(t, money.Amount(0), progress.Remaining)
+		require.Equal(t, money.Amount(0), progress.RequiredMonthly)
+		require.Equal(t, StatusAchieved, progress.Status)
+	})
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/goal"
+	"github.com/mrizkisaputra/expenses-api/internal/goal/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// savedColumns selects a goal with the sum of its contributions
+const savedColumns = `goals.*,
+(SELECT COALESCE(SUM(goal_contributions.amount), 0) FROM goal_contributions
+ WHERE goal_contributions.id_goal = goals.id) AS saved`
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewGoalPgRepository(db *gorm.DB) goal.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (p *postgresRepository) Create(ctx context.Context, goal *model.Goal) error {
+	/**
+	SQL: INSERT INTO "goals" ("id_user","name","target_amount","currency","target_date","created_at","updated_at")
+	VALUES (?,?,?,?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(goal).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Create")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Goal, error) {
+	/**
+	SQL: SELECT goals.*, (SELECT COALESCE(SUM(goal_contributions.amount), 0) FROM goal_contributions WHERE ...) AS saved
+	FROM "goals" WHERE goals.id = ? AND goals.id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	goal := new(model.Goal)
+	if err := DB.Select(savedColumns).
+		Where("goals.id = ? AND goals.id_user = ?", id, userId).
+		Take(goal).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByIdAndUserId")
+	}
+	return goal, nil
+}
+
+func (p *postgresRepository) FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Goal, error) {
+	/**
+	SQL: SELECT * FROM "goals" WHERE name = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	goal := new(model.Goal)
+	if err := DB.Where("name = ? AND id_user = ?", name, userId).Take(goal).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByNameAndUserId")
+	}
+	return goal, nil
+}
+
+func (p *postgresRepository) FindAllByUserId(ctx context.Context, userId string) ([]model.Goal, error) {
+	/**
+	SQL: SELECT goals.*, (SELECT COALESCE(SUM(goal_contributions.amount), 0) FROM goal_contributions WHERE ...) AS saved
+	FROM "goals" WHERE goals.id_user = ? ORDER BY goals.target_date, goals.name
+	*/
+	DB := p.db.WithContext(ctx)
+	var goals []model.Goal
+	if err := DB.Select(savedColumns).
+		Where("goals.id_user = ?", userId).
+		Order("goals.target_date, goals.name").
+		Find(&goals).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindAllByUserId")
+	}
+	return goals, nil
+}
+
+func (p *postgresRepository) Update(ctx context.Context, goal *model.Goal) error {
+	/**
+	SQL: UPDATE "goals" SET "id_user"=?,"name"=?,"target_amount"=?,"target_date"=?,"updated_at"=?
+	WHERE (id = ? AND id_user = ?) AND "id" = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", goal.Id, goal.UserId).Updates(goal).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Update")
+	}
+	return nil
+}
+
+func (p *postgresRepository) Remove(ctx context.Context, goal *model.Goal) error {
+	/**
+	SQL: DELETE FROM "goals" WHERE id = ? AND id_user = ?
+	its contributions are deleted with it (ON DELETE CASCADE)
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", goal.Id, goal.UserId).Delete(&model.Goal{}).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Remove")
+	}
+	return nil
+}
+
+func (p *postgresRepository) CreateContribution(ctx context.Context, contribution *model.Contribution) error {
+	/**
+	SQL: INSERT INTO "goal_contributions" ("id_goal","id_user","id_transfer","amount","note","created_at","updated_at")
+	VALUES (?,?,?,?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(contribution).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.CreateContribution")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindContributionByIdAndGoalId(ctx context.Context, id, goalId string) (*model.Contribution, error) {
+	/**
+	SQL: SELECT * FROM "goal_contributions" WHERE id = ? AND id_goal = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	contribution := new(model.Contribution)
+	if err := DB.Where("id = ? AND id_goal = ?", id, goalId).Take(contribution).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindContributionByIdAndGoalId")
+	}
+	return contribution, nil
+}
+
+func (p *postgresRepository) FindContributionByTransferId(ctx context.Context, transferId string) (*model.Contribution, error) {
+	/**
+	SQL: SELECT * FROM "goal_contributions" WHERE id_transfer = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	contribution := new(model.Contribution)
+	if err := DB.Where("id_transfer = ?", transferId).Take(contribution).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindContributionByTransferId")
+	}
+	return contribution, nil
+}
+
+func (p *postgresRepository) FindContributionsByGoalId(ctx context.Context, goalId string, page, limit int) ([]model.Contribution, int64, error) {
+	DB := p.db.WithContext(ctx)
+
+	/**
+	SQL: SELECT * FROM "goal_contributions" WHERE id_goal = ? ORDER BY created_at DESC, id LIMIT ? OFFSET ?
+	*/
+	var contributions []model.Contribution
+	if err := DB.Where("id_goal = ?", goalId).
+		Order("created_at DESC, id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&contributions).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindContributionsByGoalId.Find")
+	}
+
+	/**
+	SQL: SELECT count(*) FROM "goal_contributions" WHERE id_goal = ?
+	*/
+	var total int64
+	if err := DB.Model(&model.Contribution{}).
+		Where("id_goal = ?", goalId).
+		Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindContributionsByGoalId.Count")
+	}
+
+	return contributions, total, nil
+}
+
+func (p *postgresRepository) RemoveContribution(ctx context.Context, contribution *model.Contribution) error {
+	/**
+	SQL: DELETE FROM "goal_contributions" WHERE id = ? AND id_goal = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_goal = ?", contribution.Id, contribution.GoalId).Delete(&model.Contribution{}).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.RemoveContribution")
+	}
+	return nil
+}
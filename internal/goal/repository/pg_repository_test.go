@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/goal"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	repository goal.PostgresRepository
+	mock       sqlmock.Sqlmock
+)
+
+// Test Before and After
+func TestMain(m *testing.M) {
+	// initialize mock sql db
+	mockDb, mocking, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("Failed to open mock sql db, got error %v", err)
+	}
+	defer mockDb.Close()
+	mock = mocking
+
+	// initialize db connection
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDb}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database, got error %v", err)
+	}
+
+	// initialize repository
+	repository = NewGoalPgRepository(db)
+
+	os.Exit(m.Run())
+}
+
+func TestGoalPostgresRepository_FindByIdAndUserId(t *testing.T) {
+	id := uuid.New()
+	userId := uuid.New()
+	targetDate := time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	// define expect sql
+	rows := sqlmock.NewRows([]string{"id", "id_user", "name", "target_amount", "currency", "target_date", "created_at", "updated_at", "saved"}).
+		AddRow(id, userId, "laptop", 1500000000, "IDR", targetDate, time.Now().UnixMilli(), time.Now().UnixMilli(), 300000000)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT goals.*,`)+`(?s).*`+
+		regexp.QuoteMeta(`AS saved FROM "goals" WHERE goals.id = $1 AND goals.id_user = $2 LIMIT $3`)).
+		WithArgs(id.String(), userId.String(), 1).
+		WillReturnRows(rows)
+
+	found, err := repository.FindByIdAndUserId(context.Background(), id.String(), userId.String())
+	require.NoError(t, err)
+	require.Equal(t, money.Amount(300000000), found.Saved)
+	require.Equal(t, targetDate, found.TargetDate)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
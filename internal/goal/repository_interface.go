@@ -0,0 +1,34 @@
+package goal
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/goal/model"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	Create(ctx context.Context, goal *model.Goal) error
+
+	// FindByIdAndUserId finds a goal with the sum of its contributions as Saved
+	FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Goal, error)
+
+	FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Goal, error)
+
+	// FindAllByUserId finds the goals of the user with the sum of their contributions as Saved
+	FindAllByUserId(ctx context.Context, userId string) ([]model.Goal, error)
+
+	Update(ctx context.Context, goal *model.Goal) error
+
+	Remove(ctx context.Context, goal *model.Goal) error
+
+	CreateContribution(ctx context.Context, contribution *model.Contribution) error
+
+	FindContributionByIdAndGoalId(ctx context.Context, id, goalId string) (*model.Contribution, error)
+
+	FindContributionByTransferId(ctx context.Context, transferId string) (*model.Contribution, error)
+
+	FindContributionsByGoalId(ctx context.Context, goalId string, page, limit int) ([]model.Contribution, int64, error)
+
+	RemoveContribution(ctx context.Context, contribution *model.Contribution) error
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/account"
+	"github.com/mrizkisaputra/expenses-api/internal/currency"
+	"github.com/mrizkisaputra/expenses-api/internal/goal"
+	"github.com/mrizkisaputra/expenses-api/internal/goal/model"
+	"github.com/mrizkisaputra/expenses-api/internal/user"
+	userModel "github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ServiceConfig struct {
+	PgRepo              goal.PostgresRepository
+	AccountPgRepo       account.PostgresRepository
+	UserPgRepo          user.UserPostgresRepository
+	ExchangeRateService currency.ExchangeRateService
+	Config              *config.Config
+	Logger              *logrus.Logger
+}
+
+// goalService acts as a struct for injecting an implementation of GoalService interface
+// for use in service methods.
+type goalService struct {
+	pgRepo              goal.PostgresRepository
+	accountPgRepo       account.PostgresRepository
+	userPgRepo          user.UserPostgresRepository
+	exchangeRateService currency.ExchangeRateService
+	logger              *logrus.Logger
+}
+
+// NewGoalService is a factory function for
+// initializing a goalService with its repository layer dependencies
+func NewGoalService(config *ServiceConfig) goal.GoalService {
+	return &goalService{
+		pgRepo:              config.PgRepo,
+		accountPgRepo:       config.AccountPgRepo,
+		userPgRepo:          config.UserPgRepo,
+		exchangeRateService: config.ExchangeRateService,
+		logger:              config.Logger,
+	}
+}
+
+func (g *goalService) Create(ctx context.Context, request *model.Goal) (*model.Goal, error) {
+	request.PrepareCreate()
+
+	if request.TargetDate.Before(today()) {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidGoalDateMsg, nil)
+	}
+	if err := g.ensureUniqueName(ctx, request); err != nil {
+		return nil, err
+	}
+
+	// a goal without currency is in the user's base currency
+	if request.Currency == "" {
+		baseCurrency, err := g.baseCurrency(ctx, request.UserId)
+		if err != nil {
+			return nil, err
+		}
+		request.Currency = baseCurrency
+	}
+
+	if err := g.pgRepo.Create(ctx, request); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return request, nil
+}
+
+func (g *goalService) Update(ctx context.Context, request *model.Goal) (*model.Goal, error) {
+	found, err := g.pgRepo.FindByIdAndUserId(ctx, request.Id.String(), request.UserId.String())
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+
+	if !request.TargetDate.IsZero() && request.TargetDate.Before(today()) {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidGoalDateMsg, nil)
+	}
+
+	request.PrepareUpdate(found)
+	if err := g.ensureUniqueName(ctx, found); err != nil {
+		return nil, err
+	}
+
+	if err := g.pgRepo.Update(ctx, found); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return found, nil
+}
+
+func (g *goalService) Delete(ctx context.Context, id, userId string) error {
+	found, err := g.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return httpErrors.NewNotFoundError(err)
+	}
+
+	if err := g.pgRepo.Remove(ctx, found); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+func (g *goalService) GetById(ctx context.Context, id, userId string) (*model.Goal, error) {
+	found, err := g.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	return found, nil
+}
+
+func (g *goalService) GetAll(ctx context.Context, userId string) ([]model.Progress, error) {
+	goals, err := g.pgRepo.FindAllByUserId(ctx, userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+
+	day := today()
+	progresses := make([]model.Progress, 0, len(goals))
+	for i := range goals {
+		progresses = append(progresses, *model.NewProgress(&goals[i], day))
+	}
+	return progresses, nil
+}
+
+func (g *goalService) GetProgress(ctx context.Context, id, userId string) (*model.Progress, error) {
+	found, err := g.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	return model.NewProgress(found, today()), nil
+}
+
+func (g *goalService) Contribute(ctx context.Context, request *model.Contribution) (*model.Contribution, error) {
+	found, err := g.pgRepo.FindByIdAndUserId(ctx, request.GoalId.String(), request.UserId.String())
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+
+	if request.TransferId != nil {
+		if err := g.linkTransfer(ctx, found, request); err != nil {
+			return nil, err
+		}
+	}
+	if request.Amount == nil || *request.Amount <= 0 {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidContributionMsg, nil)
+	}
+	request.Note = strings.TrimSpace(request.Note)
+
+	if err := g.pgRepo.CreateContribution(ctx, request); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return request, nil
+}
+
+func (g *goalService) GetContributions(ctx context.Context, goalId, userId string, page, limit int) ([]model.Contribution, int64, error) {
+	if _, err := g.pgRepo.FindByIdAndUserId(ctx, goalId, userId); err != nil {
+		return nil, 0, httpErrors.NewNotFoundError(err)
+	}
+
+	contributions, total, err := g.pgRepo.FindContributionsByGoalId(ctx, goalId, page, limit)
+	if err != nil {
+		return nil, 0, httpErrors.NewInternalServerError(err)
+	}
+	return contributions, total, nil
+}
+
+func (g *goalService) DeleteContribution(ctx context.Context, id, goalId, userId string) error {
+	if _, err := g.pgRepo.FindByIdAndUserId(ctx, goalId, userId); err != nil {
+		return httpErrors.NewNotFoundError(err)
+	}
+
+	found, err := g.pgRepo.FindContributionByIdAndGoalId(ctx, id, goalId)
+	if err != nil {
+		return httpErrors.NewNotFoundError(err)
+	}
+
+	if err := g.pgRepo.RemoveContribution(ctx, found); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+// linkTransfer checks the transfer of a contribution belongs to the user and is not a contribution yet,
+// without amount the contribution is what the transfer brought in converted to the currency of the goal
+// at the rate of the day of the transfer
+func (g *goalService) linkTransfer(ctx context.Context, found *model.Goal, request *model.Contribution) error {
+	transfer, err := g.accountPgRepo.FindTransferByIdAndUserId(ctx, request.TransferId.String(), request.UserId.String())
+	if err != nil {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.UnknownTransferMsg, err)
+	}
+
+	linked, err := g.pgRepo.FindContributionByTransferId(ctx, transfer.Id.String())
+	if err == nil && linked != nil {
+		return httpErrors.NewError(http.StatusConflict, httpErrors.TransferContributedMsg, nil)
+	}
+
+	if request.Amount != nil {
+		return nil
+	}
+
+	to, err := g.accountPgRepo.FindByIdAndUserId(ctx, transfer.ToAccountId.String(), request.UserId.String())
+	if err != nil {
+		return httpErrors.NewInternalServerError(errors.Wrap(err, "goalService.linkTransfer.FindByIdAndUserId"))
+	}
+	converted, err := g.exchangeRateService.Convert(ctx, *transfer.ToAmount, to.Currency, found.Currency, time.Unix(transfer.CreatedAt, 0).UTC())
+	if err != nil {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidContributionMsg, err)
+	}
+	request.Amount = &converted
+	return nil
+}
+
+// today is the current day (UTC) at midnight
+func today() time.Time {
+	year, month, day := time.Now().UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (g *goalService) ensureUniqueName(ctx context.Context, request *model.Goal) error {
+	found, err := g.pgRepo.FindByNameAndUserId(ctx, request.Name, request.UserId.String())
+	if err == nil && found != nil && found.Id != request.Id {
+		return httpErrors.NewError(http.StatusConflict, httpErrors.GoalAlreadyExistsMsg, nil)
+	}
+	return nil
+}
+
+func (g *goalService) baseCurrency(ctx context.Context, userId uuid.UUID) (string, error) {
+	found, err := g.userPgRepo.FindById(ctx, &userModel.User{Id: userId})
+	if err != nil {
+		return "", httpErrors.NewNotFoundError(errors.Wrap(err, "goalService.baseCurrency.FindById"))
+	}
+	if found.BaseCurrency == "" {
+		return userModel.DefaultBaseCurrency, nil
+	}
+	return found.BaseCurrency, nil
+}
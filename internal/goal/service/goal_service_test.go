@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	accountMock "github.com/mrizkisaputra/expenses-api/internal/account/mock"
+	accountModel "github.com/mrizkisaputra/expenses-api/internal/account/model"
+	currencyMock "github.com/mrizkisaputra/expenses-api/internal/currency/mock"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/goal/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/goal/model"
+	userMock "github.com/mrizkisaputra/expenses-api/internal/user/mock"
+	userModel "github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGoalService_Create(t *testing.T) {
+	userId := uuid.New()
+	target := money.Amount(1500000000)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Goal without currency uses the user's base currency", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		sv := NewGoalService(&ServiceConfig{PgRepo: mockRepo, UserPgRepo: mockUserRepo})
+
+		mockRepo.On("FindByNameAndUserId", mock.Anything, "laptop", userId.String()).Return(nil, errors.New("record not found"))
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId, BaseCurrency: "IDR"}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		response, err := sv.Create(context.Background(), &model.Goal{
+			UserId:       userId,
+			Name:         " laptop ",
+			TargetAmount: &target,
+			TargetDate:   today().AddDate(0, 6, 0),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "IDR", response.Currency)
+		require.Equal(t, "laptop", response.Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should reject a target date in the past", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewGoalService(&ServiceConfig{PgRepo: mockRepo})
+
+		_, err := sv.Create(context.Background(), &model.Goal{
+			UserId:       userId,
+			Name:         "laptop",
+			TargetAmount: &target,
+			TargetDate:   today().AddDate(0, 0, -1),
+		})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.InvalidGoalDateMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestGoalService_Contribute(t *testing.T) {
+	userId := uuid.New()
+	goalId := uuid.New()
+	transferId := uuid.New()
+	accountId := uuid.New()
+	transferredAt := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC)
+	toAmount := money.Amount(10000)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Contribution linked to a transfer defaults to the converted transfer amount", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockAccountRepo := new(accountMock.MockPostgresRepository)
+		mockRateService := new(currencyMock.ExchangeRateServiceMock)
+		sv := NewGoalService(&ServiceConfig{PgRepo: mockRepo, AccountPgRepo: mockAccountRepo, ExchangeRateService: mockRateService})
+
+		mockRepo.On("FindByIdAndUserId", mock.Anything, goalId.String(), userId.String()).
+			Return(&model.Goal{Id: goalId, UserId: userId, Currency: "IDR"}, nil)
+		mockAccountRepo.On("FindTransferByIdAndUserId", mock.Anything, transferId.String(), userId.String()).
+			Return(&accountModel.Transfer{Id: transferId, UserId: userId, ToAccountId: accountId, ToAmount: &toAmount, CreatedAt: transferredAt.Unix()}, nil)
+		mockRepo.On("FindContributionByTransferId", mock.Anything, transferId.String()).Return(nil, errors.New("record not found"))
+		mockAccountRepo.On("FindByIdAndUserId", mock.Anything, accountId.String(), userId.String()).
+			Return(&accountModel.Account{Id: accountId, UserId: userId, Currency: "USD"}, nil)
+		mockRateService.On("Convert", mock.Anything, toAmount, "USD", "IDR", transferredAt).Return(money.Amount(160000000), nil)
+		mockRepo.On("CreateContribution", mock.Anything, mock.Anything).Return(nil)
+
+		response, err := sv.Contribute(context.Background(), &model.Contribution{
+			GoalId:     goalId,
+			UserId:     userId,
+			TransferId: &transferId,
+		})
+		require.NoError(t, err)
+		require.Equal(t, money.Amount(160000000), *response.Amount)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return conflict when the transfer is already a contribution", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockAccountRepo := new(accountMock.MockPostgresRepository)
+		sv := NewGoalService(&ServiceConfig{PgRepo: mockRepo, AccountPgRepo: mockAccountRepo})
+
+		mockRepo.On("FindByIdAndUserId", mock.Anything, goalId.String(), userId.String()).
+			Return(&model.Goal{Id: goalId, UserId: userId, Currency: "IDR"}, nil)
+		mockAccountRepo.On("FindTransferByIdAndUserId", mock.Anything, transferId.String(), userId.String()).
+			Return(&accountModel.Transfer{Id: transferId, UserId: userId, ToAmount: &toAmount}, nil)
+		mockRepo.On("FindContributionByTransferId", mock.Anything, transferId.String()).
+			Return(&model.Contribution{Id: uuid.New(), TransferId: &transferId}, nil)
+
+		_, err := sv.Contribute(context.Background(), &model.Contribution{
+			GoalId:     goalId,
+			UserId:     userId,
+			TransferId: &transferId,
+		})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusConflict, er.Status)
+		require.Equal(t, httpErrors.TransferContributedMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "CreateContribution")
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Should reject a manual contribution without amount", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewGoalService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindByIdAndUserId", mock.Anything, goalId.String(), userId.String()).
+			Return(&model.Goal{Id: goalId, UserId: userId, Currency: "IDR"}, nil)
+
+		_, err := sv.Contribute(context.Background(), &model.Contribution{GoalId: goalId, UserId: userId})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.InvalidContributionMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "CreateContribution")
+	})
+}
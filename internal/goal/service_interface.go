@@ -0,0 +1,31 @@
+package goal
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/goal/model"
+)
+
+// GoalService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type GoalService interface {
+	Create(ctx context.Context, request *model.Goal) (*model.Goal, error)
+
+	Update(ctx context.Context, request *model.Goal) (*model.Goal, error)
+
+	Delete(ctx context.Context, id, userId string) error
+
+	GetById(ctx context.Context, id, userId string) (*model.Goal, error)
+
+	// GetAll returns the progress of every goal of the user today
+	GetAll(ctx context.Context, userId string) ([]model.Progress, error)
+
+	GetProgress(ctx context.Context, id, userId string) (*model.Progress, error)
+
+	// Contribute records money saved toward a goal, a contribution linked to a transfer
+	// defaults to the amount the transfer brought in converted to the currency of the goal
+	Contribute(ctx context.Context, request *model.Contribution) (*model.Contribution, error)
+
+	GetContributions(ctx context.Context, goalId, userId string, page, limit int) ([]model.Contribution, int64, error)
+
+	DeleteContribution(ctx context.Context, id, goalId, userId string) error
+}
@@ -0,0 +1,19 @@
+package income
+
+import "github.com/gin-gonic/gin"
+
+// IncomeController defines methods the routes expects
+// any controllers it interacts with to implement
+type IncomeController interface {
+	CreateIncome() gin.HandlerFunc
+
+	UpdateIncome() gin.HandlerFunc
+
+	DeleteIncome() gin.HandlerFunc
+
+	GetIncomeById() gin.HandlerFunc
+
+	GetAllIncome() gin.HandlerFunc
+
+	GetCashFlow() gin.HandlerFunc
+}
@@ -0,0 +1,258 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/income"
+	"github.com/mrizkisaputra/expenses-api/internal/income/model"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/sirupsen/logrus"
+	"math"
+	"net/http"
+)
+
+type ControllerConfig struct {
+	IncomeService income.IncomeService
+	Logger        *logrus.Logger
+}
+
+// incomeController acts as a struct for injecting an implementation of IncomeController interface
+// for use in controller methods
+type incomeController struct {
+	incomeService income.IncomeService
+	logger        *logrus.Logger
+}
+
+// NewIncomeController is a factory function
+// initializing a incomeController with its service layer dependencies
+func NewIncomeController(config *ControllerConfig) income.IncomeController {
+	return &incomeController{
+		incomeService: config.IncomeService,
+		logger:        config.Logger,
+	}
+}
+
+func (ic incomeController) CreateIncome() gin.HandlerFunc {
+	type CreateIncomeRequest struct {
+		Description string        `json:"description" validate:"required,max=200"`
+		Amount      *money.Amount `json:"amount" validate:"required,gt=0"`
+		Currency    string        `json:"currency" validate:"omitempty,iso4217"`
+		CategoryId  string        `json:"category_id" validate:"required,uuid"`
+		AccountId   string        `json:"account_id" validate:"omitempty,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(CreateIncomeRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, ic.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Income{
+			UserId:      auth.Id,
+			CategoryId:  uuid.MustParse(request.CategoryId),
+			AccountId:   utils.ParseOptionalId(request.AccountId),
+			Description: request.Description,
+			Amount:      request.Amount,
+			Currency:    request.Currency,
+		}
+		response, err := ic.incomeService.Insert(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ic.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, &expenseModel.ApiResponse{
+			Status:  http.StatusCreated,
+			Message: "Created",
+			Data:    response,
+		})
+	}
+}
+
+func (ic incomeController) UpdateIncome() gin.HandlerFunc {
+	type UpdateIncomeRequest struct {
+		Id          string        `json:"-" validate:"required,uuid"`
+		Description string        `json:"description" validate:"omitempty,max=200"`
+		Amount      *money.Amount `json:"amount" validate:"omitempty,gt=0"`
+		Currency    string        `json:"currency" validate:"omitempty,iso4217"`
+		CategoryId  string        `json:"category_id" validate:"omitempty,uuid"`
+		AccountId   string        `json:"account_id" validate:"omitempty,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(UpdateIncomeRequest)
+		request.Id = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, ic.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Income{
+			Id:          uuid.MustParse(request.Id),
+			UserId:      auth.Id,
+			AccountId:   utils.ParseOptionalId(request.AccountId),
+			Description: request.Description,
+			Amount:      request.Amount,
+			Currency:    request.Currency,
+		}
+		if request.CategoryId != "" {
+			entity.CategoryId = uuid.MustParse(request.CategoryId)
+		}
+		response, err := ic.incomeService.Update(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ic.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, &expenseModel.ApiResponse{
+			Status:  http.StatusAccepted,
+			Message: "Accepted",
+			Data:    response,
+		})
+	}
+}
+
+func (ic incomeController) DeleteIncome() gin.HandlerFunc {
+	type DeleteIncomeRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &DeleteIncomeRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, ic.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if err := ic.incomeService.Delete(ctx, request.Id, auth.Id.String()); err != nil {
+			utils.LogErrorResponse(ctx, ic.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusNoContent, &expenseModel.ApiResponse{
+			Status:  http.StatusNoContent,
+			Message: "No Content",
+		})
+	}
+}
+
+func (ic incomeController) GetIncomeById() gin.HandlerFunc {
+	type GetIncomeRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &GetIncomeRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, ic.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := ic.incomeService.GetById(ctx, request.Id, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, ic.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (ic incomeController) GetAllIncome() gin.HandlerFunc {
+	type GetAllIncomeRequest struct {
+		Page  int `form:"page" validate:"omitempty,numeric,min=1"`
+		Limit int `form:"limit" validate:"omitempty,numeric,min=10"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(GetAllIncomeRequest)
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, ic.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if request.Page == 0 {
+			request.Page = 1
+		}
+		if request.Limit == 0 {
+			request.Limit = 10
+		}
+
+		incomes, total, err := ic.incomeService.GetAll(ctx, auth.Id.String(), request.Page, request.Limit)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ic.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       incomes,
+			Page:       request.Page,
+			Limit:      request.Limit,
+			TotalItems: total,
+			TotalPages: int64(math.Ceil(float64(total) / float64(request.Limit))),
+		})
+	}
+}
+
+func (ic incomeController) GetCashFlow() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(model.CashFlowRequestQueryParam)
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, ic.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := ic.incomeService.GetCashFlow(ctx, auth.Id.String(), request)
+		if err != nil {
+			utils.LogErrorResponse(ctx, ic.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
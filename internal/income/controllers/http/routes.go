@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/income"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+)
+
+func MapIncomeRoutes(incomeGroup *gin.RouterGroup, controller income.IncomeController, mw *middleware.MiddlewareManager) {
+	incomeGroup.Use(mw.AuthJwtMiddleware())
+	incomeGroup.POST("/", mw.IdempotencyMiddleware(), controller.CreateIncome())
+	incomeGroup.GET("/", controller.GetAllIncome())
+	incomeGroup.GET("/:id", controller.GetIncomeById())
+	incomeGroup.PATCH("/:id", mw.IdempotencyMiddleware(), controller.UpdateIncome())
+	incomeGroup.DELETE("/:id", mw.IdempotencyMiddleware(), controller.DeleteIncome())
+}
+
+// MapCashFlowRoutes maps the cash flow of the incomes and expenses of the user
+func MapCashFlowRoutes(cashFlowGroup *gin.RouterGroup, controller income.IncomeController, mw *middleware.MiddlewareManager) {
+	cashFlowGroup.Use(mw.AuthJwtMiddleware())
+	cashFlowGroup.GET("", controller.GetCashFlow())
+}
@@ -0,0 +1,52 @@
+package mock
+
+import (
+	"context"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/income/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPostgresRepository is a mock type for income.PostgresRepository
+type MockPostgresRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostgresRepository) Create(ctx context.Context, income *model.Income) error {
+	args := m.Called(ctx, income)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Income, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Income), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindAllByUserId(ctx context.Context, userId string, page, limit int) ([]model.Income, int64, error) {
+	args := m.Called(ctx, userId, page, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Income), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockPostgresRepository) Update(ctx context.Context, income *model.Income) error {
+	args := m.Called(ctx, income)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) Remove(ctx context.Context, income *model.Income) error {
+	args := m.Called(ctx, income)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) SumByDay(ctx context.Context, userId string, from, to int64) ([]expenseModel.DailyTotal, error) {
+	args := m.Called(ctx, userId, from, to)
+	if args.Get(0) != nil {
+		return args.Get(0).([]expenseModel.DailyTotal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,176 @@
+package model
+
+import (
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"math"
+	"time"
+)
+
+// granularities of a cash flow, weeks start on monday
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+	PeriodYear  = "year"
+)
+
+// MaxCashFlowPeriods bounds the number of periods of one cash flow
+const MaxCashFlowPeriods = 400
+
+// defaultPeriods is the number of periods up to today covered without start date
+var defaultPeriods = map[string]int{
+	PeriodDay:   30,
+	PeriodWeek:  12,
+	PeriodMonth: 12,
+	PeriodYear:  5,
+}
+
+type CashFlowRequestQueryParam struct {
+	Period    string `form:"period" validate:"omitempty,oneof=day week month year"`
+	StartDate string `form:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `form:"end_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// CashFlowPeriod is the money received and spent during one period, in the base currency.
+// SavingsRate is the share of the income that was not spent, nil without income
+type CashFlowPeriod struct {
+	Start       string       `json:"start"`
+	End         string       `json:"end"`
+	Income      money.Amount `json:"income"`
+	Expenses    money.Amount `json:"expenses"`
+	Net         money.Amount `json:"net"`
+	SavingsRate *float64     `json:"savings_rate"`
+}
+
+// CashFlow is the income, expenses and net of every period between two days (inclusive, UTC).
+// amounts without exchange rate to the base currency are left out, their currencies are
+// listed in MissingRates
+type CashFlow struct {
+	Currency     string           `json:"currency"`
+	Period       string           `json:"period"`
+	Periods      []CashFlowPeriod `json:"periods"`
+	Total        CashFlowPeriod   `json:"total"`
+	MissingRates []string         `json:"missing_rates,omitempty"`
+
+	from time.Time
+}
+
+// NewCashFlow creates the empty periods of a cash flow from the day from to the day to,
+// the first and last periods are cut to the range. ok is false when there are too many periods
+func NewCashFlow(currency, period string, from, to time.Time) (cashFlow *CashFlow, ok bool) {
+	cashFlow = &CashFlow{
+		Currency: currency,
+		Period:   period,
+		Periods:  []CashFlowPeriod{},
+		Total:    CashFlowPeriod{Start: from.Format("2006-01-02"), End: to.Format("2006-01-02")},
+		from:     PeriodStart(from, period),
+	}
+	for start := cashFlow.from; !start.After(to); start = nextPeriod(start, period) {
+		if len(cashFlow.Periods) == MaxCashFlowPeriods {
+			return nil, false
+		}
+		first, last := start, nextPeriod(start, period).AddDate(0, 0, -1)
+		if first.Before(from) {
+			first = from
+		}
+		if last.After(to) {
+			last = to
+		}
+		cashFlow.Periods = append(cashFlow.Periods, CashFlowPeriod{
+			Start: first.Format("2006-01-02"),
+			End:   last.Format("2006-01-02"),
+		})
+	}
+	return cashFlow, true
+}
+
+// DefaultCashFlowStart is the first day covered by a cash flow ending on the day to without start date
+func DefaultCashFlowStart(period string, to time.Time) time.Time {
+	start := PeriodStart(to, period)
+	for i := 1; i < defaultPeriods[period]; i++ {
+		start = PeriodStart(start.AddDate(0, 0, -1), period)
+	}
+	return start
+}
+
+// AddIncome adds an amount received on day to its period, days out of the periods are ignored
+func (cashFlow *CashFlow) AddIncome(day time.Time, amount money.Amount) {
+	if i, ok := cashFlow.index(day); ok {
+		cashFlow.Periods[i].Income += amount
+	}
+}
+
+// AddExpenses adds an amount spent on day to its period, days out of the periods are ignored
+func (cashFlow *CashFlow) AddExpenses(day time.Time, amount money.Amount) {
+	if i, ok := cashFlow.index(day); ok {
+		cashFlow.Periods[i].Expenses += amount
+	}
+}
+
+// Finish computes the net and savings rate of every period and of the total
+func (cashFlow *CashFlow) Finish() {
+	for i := range cashFlow.Periods {
+		cashFlow.Total.Income += cashFlow.Periods[i].Income
+		cashFlow.Total.Expenses += cashFlow.Periods[i].Expenses
+		cashFlow.Periods[i].finish()
+	}
+	cashFlow.Total.finish()
+}
+
+func (cashFlowPeriod *CashFlowPeriod) finish() {
+	cashFlowPeriod.Net = cashFlowPeriod.Income - cashFlowPeriod.Expenses
+	cashFlowPeriod.SavingsRate = nil
+	if cashFlowPeriod.Income > 0 {
+		rate := math.Round(float64(cashFlowPeriod.Net)/float64(cashFlowPeriod.Income)*10000) / 10000
+		cashFlowPeriod.SavingsRate = &rate
+	}
+}
+
+// index returns the position of the period of day
+func (cashFlow *CashFlow) index(day time.Time) (int, bool) {
+	start := PeriodStart(day, cashFlow.Period)
+	var i int
+	switch cashFlow.Period {
+	case PeriodDay:
+		i = int(start.Sub(cashFlow.from).Hours() / 24)
+	case PeriodWeek:
+		i = int(start.Sub(cashFlow.from).Hours() / (24 * 7))
+	case PeriodMonth:
+		i = (start.Year()-cashFlow.from.Year())*12 + int(start.Month()-cashFlow.from.Month())
+	case PeriodYear:
+		i = start.Year() - cashFlow.from.Year()
+	}
+	if start.Before(cashFlow.from) || i >= len(cashFlow.Periods) {
+		return 0, false
+	}
+	return i, true
+}
+
+// PeriodStart is the first day (UTC) of the period containing day
+func PeriodStart(day time.Time, period string) time.Time {
+	year, month, date := day.UTC().Date()
+	switch period {
+	case PeriodWeek:
+		weekday := (int(day.UTC().Weekday()) + 6) % 7 // days since monday
+		return time.Date(year, month, date-weekday, 0, 0, 0, 0, time.UTC)
+	case PeriodMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	case PeriodYear:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(year, month, date, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func nextPeriod(start time.Time, period string) time.Time {
+	switch period {
+	case PeriodWeek:
+		return start.AddDate(0, 0, 7)
+	case PeriodMonth:
+		return start.AddDate(0, 1, 0)
+	case PeriodYear:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
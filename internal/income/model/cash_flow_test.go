@@ -0,0 +1,62 @@
+package model
+
+import (
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPeriodStart(t *testing.T) {
+	day := time.Date(2024, time.March, 14, 18, 30, 0, 0, time.UTC) // a thursday
+
+	require.Equal(t, time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC), PeriodStart(day, PeriodDay))
+	require.Equal(t, time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC), PeriodStart(day, PeriodWeek))
+	require.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), PeriodStart(day, PeriodMonth))
+	require.Equal(t, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), PeriodStart(day, PeriodYear))
+
+	sunday := time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC), PeriodStart(sunday, PeriodWeek))
+}
+
+func TestCashFlow(t *testing.T) {
+	from := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Periods are cut to the range and totals add up", func(t *testing.T) {
+		cashFlow, ok := NewCashFlow("IDR", PeriodMonth, from, to)
+		require.True(t, ok)
+		require.Len(t, cashFlow.Periods, 3)
+		require.Equal(t, "2024-01-15", cashFlow.Periods[0].Start)
+		require.Equal(t, "2024-01-31", cashFlow.Periods[0].End)
+		require.Equal(t, "2024-03-01", cashFlow.Periods[2].Start)
+		require.Equal(t, "2024-03-10", cashFlow.Periods[2].End)
+
+		cashFlow.AddIncome(time.Date(2024, time.January, 25, 0, 0, 0, 0, time.UTC), money.Amount(1000000))
+		cashFlow.AddExpenses(time.Date(2024, time.January, 28, 0, 0, 0, 0, time.UTC), money.Amount(250000))
+		cashFlow.AddExpenses(time.Date(2024, time.February, 2, 0, 0, 0, 0, time.UTC), money.Amount(100000))
+		cashFlow.AddIncome(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), money.Amount(1))
+		cashFlow.Finish()
+
+		require.Equal(t, money.Amount(750000), cashFlow.Periods[0].Net)
+		require.Equal(t, 0.75, *cashFlow.Periods[0].SavingsRate)
+		require.Equal(t, money.Amount(-100000), cashFlow.Periods[1].Net)
+		require.Nil(t, cashFlow.Periods[1].SavingsRate)
+		require.Equal(t, money.Amount(1000000), cashFlow.Total.Income)
+		require.Equal(t, money.Amount(350000), cashFlow.Total.Expenses)
+		require.Equal(t, 0.65, *cashFlow.Total.SavingsRate)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should refuse too many periods", func(t *testing.T) {
+		_, ok := NewCashFlow("IDR", PeriodDay, from.AddDate(-2, 0, 0), to)
+		require.False(t, ok)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Default start covers the default number of periods", func(t *testing.T) {
+		require.Equal(t, time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC), DefaultCashFlowStart(PeriodMonth, to))
+		require.Equal(t, time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC), DefaultCashFlowStart(PeriodDay, to))
+	})
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// Income mapping table 'incomes'.
+// money received by a user (salary, refunds, side income) in one of their income categories,
+// timestamps are unix seconds like the expenses they are compared with in the cash flow
+type Income struct {
+	Id          uuid.UUID      `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	UserId      uuid.UUID      `json:"user_id" gorm:"column:id_user"`
+	CategoryId  uuid.UUID      `json:"category_id" gorm:"column:id_category"`
+	AccountId   *uuid.UUID     `json:"account_id" gorm:"column:id_account"` // account the income was received on, nil when unknown
+	Description string         `json:"description" gorm:"column:description"`
+	Amount      *money.Amount  `json:"amount" gorm:"column:amount"`
+	Currency    string         `json:"currency" gorm:"column:currency"`
+	CreatedAt   int64          `json:"created_at" gorm:"column:created_at;autoCreateTime;<-:create"` // allow read and create
+	UpdatedAt   int64          `json:"updated_at" gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"column:deleted_at"`
+}
+
+func (income *Income) TableName() string {
+	return "incomes"
+}
+
+func (income *Income) PrepareCreate() {
+	income.Description = strings.ToLower(strings.TrimSpace(income.Description))
+	income.Currency = strings.ToUpper(strings.TrimSpace(income.Currency))
+}
+
+func (income *Income) PrepareUpdate(oldIncome *Income) {
+	if income.CategoryId != uuid.Nil {
+		oldIncome.CategoryId = income.CategoryId
+	}
+
+	if income.AccountId != nil {
+		oldIncome.AccountId = income.AccountId
+	}
+
+	if income.Description != "" {
+		oldIncome.Description = strings.ToLower(strings.TrimSpace(income.Description))
+	}
+
+	if income.Amount != nil {
+		oldIncome.Amount = income.Amount
+	}
+
+	if income.Currency != "" {
+		oldIncome.Currency = strings.ToUpper(strings.TrimSpace(income.Currency))
+	}
+}
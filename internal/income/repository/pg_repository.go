@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/income"
+	"github.com/mrizkisaputra/expenses-api/internal/income/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewIncomePgRepository(db *gorm.DB) income.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (p *postgresRepository) Create(ctx context.Context, income *model.Income) error {
+	/**
+	SQL: INSERT INTO "incomes" ("id_user","id_category","description","amount","currency","created_at","updated_at","deleted_at")
+	VALUES (?,?,?,?,?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(income).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Create")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Income, error) {
+	/**
+	SQL: SELECT * FROM "incomes" WHERE (id = ? AND id_user = ?) AND "incomes"."deleted_at" IS NULL LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	income := new(model.Income)
+	if err := DB.Where("id = ? AND id_user = ?", id, userId).Take(income).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByIdAndUserId")
+	}
+	return income, nil
+}
+
+func (p *postgresRepository) FindAllByUserId(ctx context.Context, userId string, page, limit int) ([]model.Income, int64, error) {
+	DB := p.db.WithContext(ctx)
+
+	/**
+	SQL: SELECT * FROM "incomes" WHERE id_user = ? AND "incomes"."deleted_at" IS NULL
+	ORDER BY created_at DESC, id LIMIT ? OFFSET ?
+	*/
+	var incomes []model.Income
+	if err := DB.Where("id_user = ?", userId).
+		Order("created_at DESC, id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&incomes).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindAllByUserId.Find")
+	}
+
+	/**
+	SQL: SELECT count(*) FROM "incomes" WHERE id_user = ? AND "incomes"."deleted_at" IS NULL
+	*/
+	var total int64
+	if err := DB.Model(&model.Income{}).
+		Where("id_user = ?", userId).
+		Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindAllByUserId.Count")
+	}
+
+	return incomes, total, nil
+}
+
+func (p *postgresRepository) Update(ctx context.Context, income *model.Income) error {
+	/**
+	SQL: UPDATE "incomes" SET "id_user"=?,"id_category"=?,"description"=?,"amount"=?,"currency"=?,"created_at"=?,"updated_at"=?
+	WHERE (id = ? AND id_user = ?) AND "incomes"."deleted_at" IS NULL AND "id" = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", income.Id, income.UserId).Updates(income).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Update")
+	}
+	return nil
+}
+
+func (p *postgresRepository) Remove(ctx context.Context, income *model.Income) error {
+	/**
+	SQL: UPDATE "incomes" SET "deleted_at"=? WHERE "incomes"."id" = ? AND "incomes"."deleted_at" IS NULL
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Delete(income).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Remove")
+	}
+	return nil
+}
+
+func (p *postgresRepository) SumByDay(ctx context.Context, userId string, from, to int64) ([]expenseModel.DailyTotal, error) {
+	/**
+	SQL: SELECT (TO_TIMESTAMP(incomes.created_at) AT TIME ZONE 'UTC')::DATE AS day, incomes.currency, SUM(incomes.amount) AS amount
+	FROM "incomes" WHERE (incomes.id_user = ? AND incomes.created_at BETWEEN ? AND ?) AND "incomes"."deleted_at" IS NULL
+	GROUP BY day, incomes.currency ORDER BY day, incomes.currency
+	*/
+	DB := p.db.WithContext(ctx)
+	var totals []expenseModel.DailyTotal
+	if err := DB.Model(&model.Income{}).
+		Select("(TO_TIMESTAMP(incomes.created_at) AT TIME ZONE 'UTC')::DATE AS day, incomes.currency, SUM(incomes.amount) AS amount").
+		Where("incomes.id_user = ? AND incomes.created_at BETWEEN ? AND ?", userId, from, to).
+		Group("day, incomes.currency").
+		Order("day, incomes.currency").
+		Scan(&totals).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.SumByDay")
+	}
+	return totals, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/income"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	repository income.PostgresRepository
+	mock       sqlmock.Sqlmock
+)
+
+// Test Before and After
+func TestMain(m *testing.M) {
+	// initialize mock sql db
+	mockDb, mocking, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("Failed to open mock sql db, got error %v", err)
+	}
+	defer mockDb.Close()
+	mock = mocking
+
+	// initialize db connection
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDb}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database, got error %v", err)
+	}
+
+	// initialize repository
+	repository = NewIncomePgRepository(db)
+
+	os.Exit(m.Run())
+}
+
+func TestIncomePostgresRepository_FindAllByUserId(t *testing.T) {
+	userId := uuid.New()
+
+	// define expect sql
+	rows := sqlmock.NewRows([]string{"id", "id_user", "id_category", "description", "amount", "currency", "created_at", "updated_at"}).
+		AddRow(uuid.New(), userId, uuid.New(), "salary march", 1500000000, "IDR", time.Now().Unix(), time.Now().Unix())
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "incomes" WHERE id_user = $1 AND "incomes"."deleted_at" IS NULL ORDER BY created_at DESC, id LIMIT $2 OFFSET $3`)).
+		WithArgs(userId.String(), 10, 10).
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "incomes" WHERE id_user = $1 AND "incomes"."deleted_at" IS NULL`)).
+		WithArgs(userId.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+	incomes, total, err := repository.FindAllByUserId(context.Background(), userId.String(), 2, 10)
+	require.NoError(t, err)
+	require.Len(t, incomes, 1)
+	require.Equal(t, int64(11), total)
+	require.Equal(t, money.Amount(1500000000), *incomes[0].Amount)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
+
+func TestIncomePostgresRepository_SumByDay(t *testing.T) {
+	userId := uuid.New()
+	day := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	// define expect sql
+	expectedSQL := regexp.QuoteMeta(`SELECT (TO_TIMESTAMP(incomes.created_at) AT TIME ZONE 'UTC')::DATE AS day, incomes.currency, SUM(incomes.amount) AS amount FROM "incomes" WHERE (incomes.id_user = $1 AND incomes.created_at BETWEEN $2 AND $3) AND "incomes"."deleted_at" IS NULL GROUP BY day, incomes.currency ORDER BY day, incomes.currency`)
+	rows := sqlmock.NewRows([]string{"day", "currency", "amount"}).
+		AddRow(day, "IDR", 1500000000).
+		AddRow(day, "USD", 20000)
+	mock.ExpectQuery(expectedSQL).WithArgs(userId.String(), int64(1), int64(2)).WillReturnRows(rows)
+
+	totals, err := repository.SumByDay(context.Background(), userId.String(), 1, 2)
+	require.NoError(t, err)
+	require.Len(t, totals, 2)
+	require.Equal(t, "USD", totals[1].Currency)
+	require.Equal(t, money.Amount(20000), totals[1].Amount)
+	require.True(t, day.Equal(totals[0].Day))
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package income
+
+import (
+	"context"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/income/model"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	Create(ctx context.Context, income *model.Income) error
+
+	FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Income, error)
+
+	FindAllByUserId(ctx context.Context, userId string, page, limit int) ([]model.Income, int64, error)
+
+	Update(ctx context.Context, income *model.Income) error
+
+	Remove(ctx context.Context, income *model.Income) error
+
+	// SumByDay sums the incomes created within the inclusive unix bounds per day (UTC) and currency, ordered by day
+	SumByDay(ctx context.Context, userId string, from, to int64) ([]expenseModel.DailyTotal, error)
+}
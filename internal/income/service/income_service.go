@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/account"
+	"github.com/mrizkisaputra/expenses-api/internal/category"
+	categoryModel "github.com/mrizkisaputra/expenses-api/internal/category/model"
+	"github.com/mrizkisaputra/expenses-api/internal/currency"
+	"github.com/mrizkisaputra/expenses-api/internal/expense"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/income"
+	"github.com/mrizkisaputra/expenses-api/internal/income/model"
+	"github.com/mrizkisaputra/expenses-api/internal/user"
+	userModel "github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+type ServiceConfig struct {
+	PgRepo              income.PostgresRepository
+	ExpensePgRepo       expense.PostgresRepository
+	UserPgRepo          user.UserPostgresRepository
+	CategoryPgRepo      category.PostgresRepository
+	AccountPgRepo       account.PostgresRepository
+	ExchangeRateService currency.ExchangeRateService
+	Config              *config.Config
+	Logger              *logrus.Logger
+}
+
+// incomeService acts as a struct for injecting an implementation of IncomeService interface
+// for use in service methods.
+type incomeService struct {
+	pgRepo              income.PostgresRepository
+	expensePgRepo       expense.PostgresRepository
+	userPgRepo          user.UserPostgresRepository
+	categoryPgRepo      category.PostgresRepository
+	accountPgRepo       account.PostgresRepository
+	exchangeRateService currency.ExchangeRateService
+	logger              *logrus.Logger
+}
+
+// NewIncomeService is a factory function for
+// initializing a incomeService with its repository layer dependencies
+func NewIncomeService(config *ServiceConfig) income.IncomeService {
+	return &incomeService{
+		pgRepo:              config.PgRepo,
+		expensePgRepo:       config.ExpensePgRepo,
+		userPgRepo:          config.UserPgRepo,
+		categoryPgRepo:      config.CategoryPgRepo,
+		accountPgRepo:       config.AccountPgRepo,
+		exchangeRateService: config.ExchangeRateService,
+		logger:              config.Logger,
+	}
+}
+
+func (i *incomeService) Insert(ctx context.Context, request *model.Income) (*model.Income, error) {
+	request.PrepareCreate()
+
+	if err := i.ensureCategory(ctx, request); err != nil {
+		return nil, err
+	}
+	if err := i.ensureAccount(ctx, request); err != nil {
+		return nil, err
+	}
+
+	// an income without currency (nor account) is in the user's base currency
+	if request.Currency == "" {
+		baseCurrency, err := i.baseCurrency(ctx, request.UserId)
+		if err != nil {
+			return nil, err
+		}
+		request.Currency = baseCurrency
+	}
+
+	if err := i.pgRepo.Create(ctx, request); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return request, nil
+}
+
+func (i *incomeService) Update(ctx context.Context, request *model.Income) (*model.Income, error) {
+	found, err := i.pgRepo.FindByIdAndUserId(ctx, request.Id.String(), request.UserId.String())
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+
+	if request.CategoryId != uuid.Nil && request.CategoryId != found.CategoryId {
+		if err := i.ensureCategory(ctx, request); err != nil {
+			return nil, err
+		}
+	}
+
+	request.PrepareUpdate(found)
+	if request.AccountId != nil || request.Currency != "" {
+		if err := i.ensureAccount(ctx, found); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := i.pgRepo.Update(ctx, found); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return found, nil
+}
+
+func (i *incomeService) Delete(ctx context.Context, id, userId string) error {
+	found, err := i.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return httpErrors.NewNotFoundError(err)
+	}
+
+	if err := i.pgRepo.Remove(ctx, found); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+func (i *incomeService) GetById(ctx context.Context, id, userId string) (*model.Income, error) {
+	found, err := i.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	return found, nil
+}
+
+func (i *incomeService) GetAll(ctx context.Context, userId string, page, limit int) ([]model.Income, int64, error) {
+	incomes, total, err := i.pgRepo.FindAllByUserId(ctx, userId, page, limit)
+	if err != nil {
+		return nil, 0, httpErrors.NewInternalServerError(err)
+	}
+	return incomes, total, nil
+}
+
+// GetCashFlow sums the incomes and expenses per day and currency, converts every daily total
+// to the base currency at the rate of its day and adds them up per period
+func (i *incomeService) GetCashFlow(ctx context.Context, userId string, request *model.CashFlowRequestQueryParam) (*model.CashFlow, error) {
+	period := request.Period
+	if period == "" {
+		period = model.PeriodMonth
+	}
+
+	from, to, err := cashFlowRange(period, request)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedId, err := uuid.Parse(userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "incomeService.GetCashFlow.uuid.Parse"))
+	}
+	baseCurrency, err := i.baseCurrency(ctx, parsedId)
+	if err != nil {
+		return nil, err
+	}
+
+	cashFlow, ok := model.NewCashFlow(baseCurrency, period, from, to)
+	if !ok {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidCashFlowMsg, errors.New("too many periods"))
+	}
+
+	last := to.AddDate(0, 0, 1).Add(-time.Second)
+	incomes, err := i.pgRepo.SumByDay(ctx, userId, from.Unix(), last.Unix())
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	expenses, err := i.expensePgRepo.SumByDay(ctx, userId, &expenseModel.ExpenseFilter{CreatedFrom: from.Unix(), CreatedTo: last.Unix()})
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+
+	missing := map[string]bool{}
+	convert := func(totals []expenseModel.DailyTotal, add func(day time.Time, amount money.Amount)) {
+		for _, total := range totals {
+			converted, err := i.exchangeRateService.Convert(ctx, total.Amount, total.Currency, baseCurrency, total.Day)
+			if err != nil {
+				i.logger.WithError(err).WithField("userId", userId).Debug("incomeService.GetCashFlow.Convert")
+				if !missing[total.Currency] {
+					missing[total.Currency] = true
+					cashFlow.MissingRates = append(cashFlow.MissingRates, total.Currency)
+				}
+				continue
+			}
+			add(total.Day, converted)
+		}
+	}
+	convert(incomes, cashFlow.AddIncome)
+	convert(expenses, cashFlow.AddExpenses)
+
+	cashFlow.Finish()
+	return cashFlow, nil
+}
+
+// cashFlowRange returns the first and last days of the cash flow, it ends today without end date
+// and covers the default number of periods without start date
+func cashFlowRange(period string, request *model.CashFlowRequestQueryParam) (from, to time.Time, err error) {
+	to = model.PeriodStart(time.Now(), model.PeriodDay)
+	if request.EndDate != "" {
+		if to, err = time.Parse("2006-01-02", request.EndDate); err != nil {
+			return from, to, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidCashFlowMsg, errors.Wrap(err, "incomeService.cashFlowRange.time.Parse"))
+		}
+	}
+
+	from = model.DefaultCashFlowStart(period, to)
+	if request.StartDate != "" {
+		if from, err = time.Parse("2006-01-02", request.StartDate); err != nil {
+			return from, to, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidCashFlowMsg, errors.Wrap(err, "incomeService.cashFlowRange.time.Parse"))
+		}
+	}
+
+	if from.After(to) {
+		return from, to, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidCashFlowMsg, errors.New("start_date is after end_date"))
+	}
+	return from, to, nil
+}
+
+// ensureCategory ensures the category of the income exists, belongs to the user and is an income category
+func (i *incomeService) ensureCategory(ctx context.Context, request *model.Income) error {
+	found, err := i.categoryPgRepo.FindByIdAndUserId(ctx, request.CategoryId.String(), request.UserId.String())
+	if err != nil {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.UnknownCategoryMsg, err)
+	}
+	if found.Kind != categoryModel.KindIncome {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.CategoryKindMismatchMsg, nil)
+	}
+	return nil
+}
+
+// ensureAccount ensures the account of the income, if any, belongs to the user and is in the
+// currency of the income, an income without currency takes the currency of its account
+func (i *incomeService) ensureAccount(ctx context.Context, request *model.Income) error {
+	if request.AccountId == nil {
+		return nil
+	}
+
+	found, err := i.accountPgRepo.FindByIdAndUserId(ctx, request.AccountId.String(), request.UserId.String())
+	if err != nil {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.UnknownAccountMsg, err)
+	}
+	if request.Currency == "" {
+		request.Currency = found.Currency
+	}
+	if request.Currency != found.Currency {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.AccountCurrencyMismatchMsg, nil)
+	}
+	return nil
+}
+
+func (i *incomeService) baseCurrency(ctx context.Context, userId uuid.UUID) (string, error) {
+	found, err := i.userPgRepo.FindById(ctx, &userModel.User{Id: userId})
+	if err != nil {
+		return "", httpErrors.NewNotFoundError(errors.Wrap(err, "incomeService.baseCurrency.FindById"))
+	}
+	if found.BaseCurrency == "" {
+		return userModel.DefaultBaseCurrency, nil
+	}
+	return found.BaseCurrency, nil
+}
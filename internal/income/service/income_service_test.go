@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	accountMock "github.com/mrizkisaputra/expenses-api/internal/account/mock"
+	accountModel "github.com/mrizkisaputra/expenses-api/internal/account/model"
+	categoryMock "github.com/mrizkisaputra/expenses-api/internal/category/mock"
+	categoryModel "github.com/mrizkisaputra/expenses-api/internal/category/model"
+	currencyMock "github.com/mrizkisaputra/expenses-api/internal/currency/mock"
+	currencyModel "github.com/mrizkisaputra/expenses-api/internal/currency/model"
+	expenseMock "github.com/mrizkisaputra/expenses-api/internal/expense/mock"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/income/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/income/model"
+	userMock "github.com/mrizkisaputra/expenses-api/internal/user/mock"
+	userModel "github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var cfg = &config.Config{
+	Logger: config.LoggerConfig{Level: "panic"},
+}
+
+func TestIncomeService_Insert(t *testing.T) {
+	userId := uuid.New()
+	categoryId := uuid.New()
+	amount := money.Amount(1500000000)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Income without currency uses the user's base currency", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		sv := NewIncomeService(&ServiceConfig{PgRepo: mockRepo, UserPgRepo: mockUserRepo, CategoryPgRepo: mockCategoryRepo})
+
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, categoryId.String(), userId.String()).
+			Return(&categoryModel.Category{Id: categoryId, UserId: userId, Kind: categoryModel.KindIncome}, nil)
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId, BaseCurrency: "EUR"}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		response, err := sv.Insert(context.Background(), &model.Income{
+			UserId:      userId,
+			CategoryId:  categoryId,
+			Description: " Salary March ",
+			Amount:      &amount,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "EUR", response.Currency)
+		require.Equal(t, "salary march", response.Description)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should reject an expense category", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		sv := NewIncomeService(&ServiceConfig{PgRepo: mockRepo, CategoryPgRepo: mockCategoryRepo})
+
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, categoryId.String(), userId.String()).
+			Return(&categoryModel.Category{Id: categoryId, UserId: userId, Kind: categoryModel.KindExpense}, nil)
+
+		_, err := sv.Insert(context.Background(), &model.Income{UserId: userId, CategoryId: categoryId, Amount: &amount, Currency: "IDR"})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.CategoryKindMismatchMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Income on an account takes the account currency and rejects another one", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockCategoryRepo := new(categoryMock.MockPostgresRepository)
+		mockAccountRepo := new(accountMock.MockPostgresRepository)
+		sv := NewIncomeService(&ServiceConfig{PgRepo: mockRepo, CategoryPgRepo: mockCategoryRepo, AccountPgRepo: mockAccountRepo})
+
+		accountId := uuid.New()
+		mockCategoryRepo.On("FindByIdAndUserId", mock.Anything, categoryId.String(), userId.String()).
+			Return(&categoryModel.Category{Id: categoryId, UserId: userId, Kind: categoryModel.KindIncome}, nil)
+		mockAccountRepo.On("FindByIdAndUserId", mock.Anything, accountId.String(), userId.String()).
+			Return(&accountModel.Account{Id: accountId, UserId: userId, Currency: "USD"}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		response, err := sv.Insert(context.Background(), &model.Income{UserId: userId, CategoryId: categoryId, AccountId: &accountId, Amount: &amount})
+		require.NoError(t, err)
+		require.Equal(t, "USD", response.Currency)
+
+		_, err = sv.Insert(context.Background(), &model.Income{UserId: userId, CategoryId: categoryId, AccountId: &accountId, Amount: &amount, Currency: "IDR"})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.AccountCurrencyMismatchMsg, er.Message)
+		mockRepo.AssertNumberOfCalls(t, "Create", 1)
+	})
+}
+
+func TestIncomeService_GetCashFlow(t *testing.T) {
+	userId := uuid.New()
+	january := time.Date(2024, time.January, 25, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2024, time.February, 3, 0, 0, 0, 0, time.UTC)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Incomes and expenses are converted and summed per period", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockExpenseRepo := new(expenseMock.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		mockRateService := new(currencyMock.ExchangeRateServiceMock)
+		sv := NewIncomeService(&ServiceConfig{
+			PgRepo:              mockRepo,
+			ExpensePgRepo:       mockExpenseRepo,
+			UserPgRepo:          mockUserRepo,
+			ExchangeRateService: mockRateService,
+			Logger:              logger.NewLogrusLogger(cfg),
+		})
+
+		from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, time.February, 29, 23, 59, 59, 0, time.UTC)
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId, BaseCurrency: "IDR"}, nil)
+		mockRepo.On("SumByDay", mock.Anything, userId.String(), from.Unix(), to.Unix()).Return([]expenseModel.DailyTotal{
+			{Day: january, Currency: "IDR", Amount: money.Amount(1000000)},
+			{Day: february, Currency: "USD", Amount: money.Amount(10000)},
+		}, nil)
+		mockExpenseRepo.On("SumByDay", mock.Anything, userId.String(), &expenseModel.ExpenseFilter{CreatedFrom: from.Unix(), CreatedTo: to.Unix()}).
+			Return([]expenseModel.DailyTotal{
+				{Day: january, Currency: "IDR", Amount: money.Amount(400000)},
+				{Day: february, Currency: "EUR", Amount: money.Amount(500)},
+			}, nil)
+		mockRateService.On("Convert", mock.Anything, money.Amount(1000000), "IDR", "IDR", january).Return(money.Amount(1000000), nil)
+		mockRateService.On("Convert", mock.Anything, money.Amount(400000), "IDR", "IDR", january).Return(money.Amount(400000), nil)
+		mockRateService.On("Convert", mock.Anything, money.Amount(10000), "USD", "IDR", february).Return(money.Amount(160000000), nil)
+		mockRateService.On("Convert", mock.Anything, money.Amount(500), "EUR", "IDR", february).Return(money.Amount(0), currencyModel.ErrExchangeRateNotFound)
+
+		cashFlow, err := sv.GetCashFlow(context.Background(), userId.String(), &model.CashFlowRequestQueryParam{
+			StartDate: "2024-01-01",
+			EndDate:   "2024-02-29",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "IDR", cashFlow.Currency)
+		require.Equal(t, model.PeriodMonth, cashFlow.Period)
+		require.Len(t, cashFlow.Periods, 2)
+		require.Equal(t, money.Amount(600000), cashFlow.Periods[0].Net)
+		require.Equal(t, 0.6, *cashFlow.Periods[0].SavingsRate)
+		require.Equal(t, money.Amount(160000000), cashFlow.Periods[1].Income)
+		require.Equal(t, money.Amount(0), cashFlow.Periods[1].Expenses)
+		require.Equal(t, []string{"EUR"}, cashFlow.MissingRates)
+		require.Equal(t, money.Amount(161000000), cashFlow.Total.Income)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return bad request when the range starts after it ends", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewIncomeService(&ServiceConfig{PgRepo: mockRepo})
+
+		_, err := sv.GetCashFlow(context.Background(), userId.String(), &model.CashFlowRequestQueryParam{
+			StartDate: "2024-03-01",
+			EndDate:   "2024-02-01",
+		})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.InvalidCashFlowMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "SumByDay")
+	})
+}
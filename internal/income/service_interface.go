@@ -0,0 +1,23 @@
+package income
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/income/model"
+)
+
+// IncomeService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type IncomeService interface {
+	Insert(ctx context.Context, request *model.Income) (*model.Income, error)
+
+	Update(ctx context.Context, request *model.Income) (*model.Income, error)
+
+	Delete(ctx context.Context, id, userId string) error
+
+	GetById(ctx context.Context, id, userId string) (*model.Income, error)
+
+	GetAll(ctx context.Context, userId string, page, limit int) ([]model.Income, int64, error)
+
+	// GetCashFlow sums the incomes and expenses of the user per period in their base currency
+	GetCashFlow(ctx context.Context, userId string, request *model.CashFlowRequestQueryParam) (*model.CashFlow, error)
+}
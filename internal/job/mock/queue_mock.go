@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/job/model"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+// QueueMock is a mock type for job.Queue
+type QueueMock struct {
+	mock.Mock
+}
+
+func (m *QueueMock) Enqueue(ctx context.Context, jobType string, data interface{}) (*model.Job, error) {
+	args := m.Called(ctx, jobType, data)
+	if job, ok := args.Get(0).(*model.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *QueueMock) EnqueueAt(ctx context.Context, jobType string, data interface{}, runAt time.Time) (*model.Job, error) {
+	args := m.Called(ctx, jobType, data, runAt)
+	if job, ok := args.Get(0).(*model.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
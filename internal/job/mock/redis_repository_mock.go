@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/job/model"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+// MockRedisRepository is a mock type for job.RedisRepository
+type MockRedisRepository struct {
+	mock.Mock
+}
+
+func (m *MockRedisRepository) Enqueue(ctx context.Context, job *model.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockRedisRepository) Claim(ctx context.Context, jobType string, now time.Time, lease time.Duration) (*model.Job, error) {
+	args := m.Called(ctx, jobType, now, lease)
+	if job, ok := args.Get(0).(*model.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRedisRepository) Complete(ctx context.Context, job *model.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockRedisRepository) Retry(ctx context.Context, job *model.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockRedisRepository) Bury(ctx context.Context, job *model.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockRedisRepository) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, key, ttl)
+	return args.Bool(0), args.Error(1)
+}
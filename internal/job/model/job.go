@@ -0,0 +1,89 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// types of job run by the worker
+const (
+	TypeEmailSend           = "email.send"
+	TypeNotificationWebhook = "notification.webhook"
+	TypeRetentionPurge      = "retention.purge"
+)
+
+const (
+	// maxBackoff is the longest wait before a retry
+	maxBackoff = time.Hour
+
+	maxErrorLength = 500
+)
+
+// Job is a unit of work of a named type queued for the worker, Payload is what its handler receives
+type Job struct {
+	Id        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	RunAt     int64           `json:"run_at"`
+	LastError string          `json:"last_error,omitempty"`
+	CreatedAt int64           `json:"created_at"`
+}
+
+// Options of a job type, how many jobs of the type run at once, how many times a job
+// is attempted before it is dead and the wait before its first retry, doubled at every retry
+type Options struct {
+	Concurrency int
+	MaxAttempts int
+	Backoff     time.Duration
+	Timeout     time.Duration
+}
+
+// NewJob is a job of the type with data encoded in JSON, due at runAt
+func NewJob(jobType string, data interface{}, runAt time.Time) (*Job, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewJob.json.Marshal")
+	}
+	return &Job{
+		Id:        uuid.New(),
+		Type:      jobType,
+		Payload:   payload,
+		RunAt:     runAt.UnixMilli(),
+		CreatedAt: time.Now().UnixMilli(),
+	}, nil
+}
+
+// Decode decodes the payload of the job into data
+func (job *Job) Decode(data interface{}) error {
+	if err := json.Unmarshal(job.Payload, data); err != nil {
+		return errors.Wrap(err, "Job.Decode.json.Unmarshal")
+	}
+	return nil
+}
+
+// Fail records a failed attempt, counted when the job was claimed. the next one waits backoff doubled
+// at every attempt. it returns false once the job has no attempt left, the job is then dead
+func (job *Job) Fail(now time.Time, cause error, options Options) bool {
+	job.LastError = utils.Truncate(cause.Error(), maxErrorLength)
+	if job.Attempts >= options.MaxAttempts {
+		return false
+	}
+
+	job.RunAt = now.Add(utils.Backoff(options.Backoff, job.Attempts, maxBackoff)).UnixMilli()
+	return true
+}
+
+// Abandon tells whether the job is claimed again after its last attempt, the worker running that attempt
+// stopped before the job ended. the job is then dead without being run again
+func (job *Job) Abandon(options Options) bool {
+	if job.Attempts <= options.MaxAttempts {
+		return false
+	}
+	job.Attempts = options.MaxAttempts
+	job.LastError = "the lease of the last attempt expired before the job ended"
+	return true
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/mrizkisaputra/expenses-api/internal/job"
+	"github.com/mrizkisaputra/expenses-api/internal/job/model"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+const (
+	dataKey     = "jobs:data"
+	attemptsKey = "jobs:attempts"
+	deadKey     = "jobs:dead"
+
+	// maxDead is the number of dead jobs kept
+	maxDead = 1000
+)
+
+// claimScript leases the oldest due job of a queue by moving its score to the end of the lease and counts
+// the attempt, in one step so two workers never claim the same job and a job whose lease ended is counted
+// again. a job without data is dropped
+var claimScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, 1)
+if #ids == 0 then
+	return false
+end
+local data = redis.call('HGET', KEYS[2], ids[1])
+if not data then
+	redis.call('ZREM', KEYS[1], ids[1])
+	redis.call('HDEL', KEYS[3], ids[1])
+	return false
+end
+redis.call('ZADD', KEYS[1], ARGV[2], ids[1])
+local attempts = redis.call('HINCRBY', KEYS[3], ids[1], 1)
+return {data, attempts}
+`)
+
+// jobRedisRepository keeps the jobs in a hash by id and their ids in a sorted set per type,
+// scored by the time they are due. the attempts made are counted in another hash by id
+type jobRedisRepository struct {
+	redisClient *redis.Client
+}
+
+// NewJobRedisRepository is a factory for initializing Job Redis Repository
+func NewJobRedisRepository(redisClient *redis.Client) job.RedisRepository {
+	return &jobRedisRepository{redisClient: redisClient}
+}
+
+func (r *jobRedisRepository) Enqueue(ctx context.Context, job *model.Job) error {
+	if err := r.save(ctx, job); err != nil {
+		return errors.Wrap(err, "JobRedisRepository.Enqueue")
+	}
+	return nil
+}
+
+func (r *jobRedisRepository) Claim(ctx context.Context, jobType string, now time.Time, lease time.Duration) (*model.Job, error) {
+	result, err := claimScript.Run(ctx, r.redisClient,
+		[]string{queueKey(jobType), dataKey, attemptsKey},
+		now.UnixMilli(), now.Add(lease).UnixMilli(),
+	).Slice()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "JobRedisRepository.Claim.claimScript.Run")
+	}
+	data, _ := result[0].(string)
+	attempts, _ := result[1].(int64)
+
+	claimed := new(model.Job)
+	if err := json.Unmarshal([]byte(data), claimed); err != nil {
+		return nil, errors.Wrap(err, "JobRedisRepository.Claim.json.Unmarshal")
+	}
+	claimed.Attempts = int(attempts)
+	return claimed, nil
+}
+
+func (r *jobRedisRepository) Complete(ctx context.Context, job *model.Job) error {
+	_, err := r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, queueKey(job.Type), job.Id.String())
+		pipe.HDel(ctx, dataKey, job.Id.String())
+		pipe.HDel(ctx, attemptsKey, job.Id.String())
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "JobRedisRepository.Complete.redisClient.TxPipelined")
+	}
+	return nil
+}
+
+func (r *jobRedisRepository) Retry(ctx context.Context, job *model.Job) error {
+	if err := r.save(ctx, job); err != nil {
+		return errors.Wrap(err, "JobRedisRepository.Retry")
+	}
+	return nil
+}
+
+func (r *jobRedisRepository) Bury(ctx context.Context, job *model.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return errors.Wrap(err, "JobRedisRepository.Bury.json.Marshal")
+	}
+
+	_, err = r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, queueKey(job.Type), job.Id.String())
+		pipe.HDel(ctx, dataKey, job.Id.String())
+		pipe.HDel(ctx, attemptsKey, job.Id.String())
+		pipe.LPush(ctx, deadKey, data)
+		pipe.LTrim(ctx, deadKey, 0, maxDead-1)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "JobRedisRepository.Bury.redisClient.TxPipelined")
+	}
+	return nil
+}
+
+func (r *jobRedisRepository) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	locked, err := r.redisClient.SetNX(ctx, "jobs:lock:"+key, time.Now().UnixMilli(), ttl).Result()
+	if err != nil {
+		return false, errors.Wrap(err, "JobRedisRepository.TryLock.redisClient.SetNX")
+	}
+	return locked, nil
+}
+
+// save writes the job and queues its id, due at its RunAt
+func (r *jobRedisRepository) save(ctx context.Context, job *model.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+
+	_, err = r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, dataKey, job.Id.String(), data)
+		pipe.ZAdd(ctx, queueKey(job.Type), redis.Z{Score: float64(job.RunAt), Member: job.Id.String()})
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "redisClient.TxPipelined")
+	}
+	return nil
+}
+
+// queueKey is the sorted set of the ids of the jobs of a type
+func queueKey(jobType string) string {
+	return "jobs:queue:" + jobType
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/mrizkisaputra/expenses-api/internal/job"
+	"github.com/mrizkisaputra/expenses-api/internal/job/model"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"log"
+	"testing"
+	"time"
+)
+
+func SetupRedis() (job.RedisRepository, *redis.Client) {
+	server, err := miniredis.Run()
+	if err != nil {
+		log.Fatalf("could not connet to redis server, got error %v", err)
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: server.Addr(),
+	})
+
+	return NewJobRedisRepository(redisClient), redisClient
+}
+
+func TestJobRedisRepository_Claim(t *testing.T) {
+	repo, _ := SetupRedis()
+	ctx := context.Background()
+	now := time.Now()
+
+	first, _ := model.NewJob(model.TypeEmailSend, map[string]string{"to": "budi@example.com"}, now.Add(-time.Minute))
+	second, _ := model.NewJob(model.TypeEmailSend, map[string]string{}, now)
+	later, _ := model.NewJob(model.TypeEmailSend, map[string]string{}, now.Add(time.Hour))
+	for _, queued := range []*model.Job{later, second, first} {
+		require.NoError(t, repo.Enqueue(ctx, queued))
+	}
+
+	// the due jobs are claimed oldest first, the one due later is left
+	claimed, err := repo.Claim(ctx, model.TypeEmailSend, now, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, first.Id, claimed.Id)
+	require.Equal(t, 1, claimed.Attempts)
+	require.JSONEq(t, `{"to":"budi@example.com"}`, string(claimed.Payload))
+
+	claimed, err = repo.Claim(ctx, model.TypeEmailSend, now, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, second.Id, claimed.Id)
+
+	claimed, err = repo.Claim(ctx, model.TypeEmailSend, now, time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, claimed)
+
+	// a job whose lease ended is due again with its lost attempt counted, a completed one is not
+	require.NoError(t, repo.Complete(ctx, first))
+	claimed, err = repo.Claim(ctx, model.TypeEmailSend, now.Add(2*time.Minute), time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, second.Id, claimed.Id)
+	require.Equal(t, 2, claimed.Attempts)
+
+	claimed, err = repo.Claim(ctx, model.TypeRetentionPurge, now, time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, claimed)
+}
+
+func TestJobRedisRepository_RetryAndBury(t *testing.T) {
+	repo, redisClient := SetupRedis()
+	ctx := context.Background()
+	now := time.Now()
+
+	queued, _ := model.NewJob(model.TypeRetentionPurge, map[string]string{}, now)
+	require.NoError(t, repo.Enqueue(ctx, queued))
+	claimed, err := repo.Claim(ctx, model.TypeRetentionPurge, now, time.Minute)
+	require.NoError(t, err)
+
+	// a retried job is due at its new time, claiming it counts its second attempt
+	require.True(t, claimed.Fail(now, errors.New("connection refused"), model.Options{MaxAttempts: 3, Backoff: time.Second}))
+	require.NoError(t, repo.Retry(ctx, claimed))
+	retried, err := repo.Claim(ctx, model.TypeRetentionPurge, now.Add(time.Second), time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 2, retried.Attempts)
+	require.Equal(t, "connection refused", retried.LastError)
+
+	require.NoError(t, repo.Bury(ctx, retried))
+	claimed, err = repo.Claim(ctx, model.TypeRetentionPurge, now.Add(time.Hour), time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, claimed)
+	require.Equal(t, int64(1), redisClient.LLen(ctx, deadKey).Val())
+	require.Equal(t, int64(0), redisClient.HLen(ctx, attemptsKey).Val())
+}
+
+func TestJobRedisRepository_TryLock(t *testing.T) {
+	repo, _ := SetupRedis()
+
+	locked, err := repo.TryLock(context.Background(), "schedule:retention.purge", time.Hour)
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	locked, err = repo.TryLock(context.Background(), "schedule:retention.purge", time.Hour)
+	require.NoError(t, err)
+	require.False(t, locked)
+}
@@ -0,0 +1,31 @@
+package job
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/job/model"
+	"time"
+)
+
+// RedisRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type RedisRepository interface {
+	// Enqueue queues the job, it is due at its RunAt
+	Enqueue(ctx context.Context, job *model.Job) error
+
+	// Claim leases the oldest due job of the type, nil when none is due. its Attempts counts the attempts
+	// made, this one included. a job not completed, retried or buried within lease is due again, so a job
+	// of a crashed worker is run again and its lost attempt is counted
+	Claim(ctx context.Context, jobType string, now time.Time, lease time.Duration) (*model.Job, error)
+
+	// Complete removes a job run successfully
+	Complete(ctx context.Context, job *model.Job) error
+
+	// Retry queues the job again, due at its RunAt
+	Retry(ctx context.Context, job *model.Job) error
+
+	// Bury moves a job without attempt left to the dead jobs, only the most recent ones are kept
+	Bury(ctx context.Context, job *model.Job) error
+
+	// TryLock takes the lock named key for ttl, false when it is already taken
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/job"
+	"github.com/mrizkisaputra/expenses-api/internal/job/model"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+const (
+	defaultConcurrency  = 4
+	defaultPollInterval = time.Second
+	defaultMaxAttempts  = 5
+	defaultBackoff      = 10 * time.Second
+	defaultTimeout      = time.Minute
+)
+
+type ServiceConfig struct {
+	RedisRepo job.RedisRepository
+	Config    *config.Config
+	Logger    *logrus.Logger
+}
+
+// queue acts as a struct for injecting an implementation of Queue interface
+// for use in service methods.
+type queue struct {
+	redisRepo job.RedisRepository
+}
+
+// NewQueue is a factory function for
+// initializing a queue with its repository layer dependencies
+func NewQueue(config *ServiceConfig) job.Queue {
+	return &queue{redisRepo: config.RedisRepo}
+}
+
+func (q *queue) Enqueue(ctx context.Context, jobType string, data interface{}) (*model.Job, error) {
+	return q.EnqueueAt(ctx, jobType, data, time.Now())
+}
+
+func (q *queue) EnqueueAt(ctx context.Context, jobType string, data interface{}, runAt time.Time) (*model.Job, error) {
+	queued, err := model.NewJob(jobType, data, runAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "queue.EnqueueAt.NewJob")
+	}
+	if err := q.redisRepo.Enqueue(ctx, queued); err != nil {
+		return nil, errors.Wrap(err, "queue.EnqueueAt.Enqueue")
+	}
+	return queued, nil
+}
+
+// registration is a job type run by the processor
+type registration struct {
+	options model.Options
+	handler job.Handler
+}
+
+// schedule is a job queued every interval
+type schedule struct {
+	jobType string
+	every   time.Duration
+	data    interface{}
+}
+
+// processor acts as a struct for injecting an implementation of Processor interface
+// for use in service methods.
+type processor struct {
+	*queue
+	handlers     map[string]registration
+	schedules    []schedule
+	defaults     model.Options
+	pollInterval time.Duration
+	logger       *logrus.Logger
+}
+
+// NewProcessor is a factory function for
+// initializing a processor with its repository layer dependencies
+func NewProcessor(config *ServiceConfig) job.Processor {
+	service := &processor{
+		queue:    &queue{redisRepo: config.RedisRepo},
+		handlers: map[string]registration{},
+		defaults: model.Options{
+			Concurrency: defaultConcurrency,
+			MaxAttempts: defaultMaxAttempts,
+			Backoff:     defaultBackoff,
+			Timeout:     defaultTimeout,
+		},
+		pollInterval: defaultPollInterval,
+		logger:       config.Logger,
+	}
+	if config.Config != nil {
+		if config.Config.Worker.Concurrency > 0 {
+			service.defaults.Concurrency = config.Config.Worker.Concurrency
+		}
+		if config.Config.Worker.MaxAttempts > 0 {
+			service.defaults.MaxAttempts = config.Config.Worker.MaxAttempts
+		}
+		if config.Config.Worker.Backoff > 0 {
+			service.defaults.Backoff = config.Config.Worker.Backoff * time.Second
+		}
+		if config.Config.Worker.PollInterval > 0 {
+			service.pollInterval = config.Config.Worker.PollInterval * time.Second
+		}
+	}
+	return service
+}
+
+func (p *processor) Register(jobType string, options model.Options, handler job.Handler) {
+	if options.Concurrency <= 0 {
+		options.Concurrency = p.defaults.Concurrency
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = p.defaults.MaxAttempts
+	}
+	if options.Backoff <= 0 {
+		options.Backoff = p.defaults.Backoff
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = p.defaults.Timeout
+	}
+	p.handlers[jobType] = registration{options: options, handler: handler}
+}
+
+func (p *processor) Schedule(jobType string, every time.Duration, data interface{}) {
+	p.schedules = append(p.schedules, schedule{jobType: jobType, every: every, data: data})
+}
+
+// Run starts Concurrency workers per job type and the schedules, on ctx done the workers
+// stop claiming jobs and the running jobs are waited for
+func (p *processor) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for jobType, registered := range p.handlers {
+		for i := 0; i < registered.options.Concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.work(ctx, jobType, registered)
+			}()
+		}
+	}
+	for _, scheduled := range p.schedules {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.schedule(ctx, scheduled)
+		}()
+	}
+	wg.Wait()
+}
+
+// work claims and runs the jobs of the type one after the other, it waits a poll interval
+// whenever no job is due
+func (p *processor) work(ctx context.Context, jobType string, registered registration) {
+	for ctx.Err() == nil {
+		// the lease outlasts the timeout of the job, the job is only run again once surely stopped
+		claimed, err := p.redisRepo.Claim(ctx, jobType, time.Now(), 2*registered.options.Timeout)
+		if err != nil && ctx.Err() == nil {
+			p.logger.WithError(err).WithField("type", jobType).Warn("processor.work.Claim")
+		}
+		if err != nil || claimed == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(p.pollInterval):
+			}
+			continue
+		}
+		p.process(ctx, claimed, registered)
+	}
+}
+
+// process runs a claimed job to its end even when ctx is done, so a shutdown does not cut it
+func (p *processor) process(ctx context.Context, claimed *model.Job, registered registration) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), registered.options.Timeout)
+	defer cancel()
+
+	logger := p.logger.WithField("jobId", claimed.Id).WithField("type", claimed.Type)
+	if claimed.Abandon(registered.options) {
+		logger.WithField("attempts", claimed.Attempts).Error("processor.process: job is dead, its last attempt was abandoned")
+		if err := p.redisRepo.Bury(ctx, claimed); err != nil {
+			logger.WithError(err).Warn("processor.process.Bury")
+		}
+		return
+	}
+
+	err := run(ctx, claimed, registered.handler)
+	if err == nil {
+		if err := p.redisRepo.Complete(ctx, claimed); err != nil {
+			logger.WithError(err).Warn("processor.process.Complete")
+		}
+		return
+	}
+
+	if claimed.Fail(time.Now(), err, registered.options) {
+		logger.WithError(err).WithField("attempts", claimed.Attempts).Warn("processor.process.handler")
+		if err := p.redisRepo.Retry(ctx, claimed); err != nil {
+			logger.WithError(err).Warn("processor.process.Retry")
+		}
+		return
+	}
+
+	logger.WithError(err).WithField("attempts", claimed.Attempts).Error("processor.process.handler: job is dead")
+	if err := p.redisRepo.Bury(ctx, claimed); err != nil {
+		logger.WithError(err).Warn("processor.process.Bury")
+	}
+}
+
+// schedule queues a job every interval, the lock taken for the interval makes a single
+// worker queue it when several run
+func (p *processor) schedule(ctx context.Context, scheduled schedule) {
+	ticker := time.NewTicker(scheduled.every)
+	defer ticker.Stop()
+
+	for {
+		locked, err := p.redisRepo.TryLock(ctx, "schedule:"+scheduled.jobType, scheduled.every)
+		if err != nil && ctx.Err() == nil {
+			p.logger.WithError(err).WithField("type", scheduled.jobType).Warn("processor.schedule.TryLock")
+		}
+		if locked {
+			if _, err := p.Enqueue(ctx, scheduled.jobType, scheduled.data); err != nil {
+				p.logger.WithError(err).WithField("type", scheduled.jobType).Warn("processor.schedule.Enqueue")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run runs the handler of the job, a panic of the handler fails the job
+func run(ctx context.Context, claimed *model.Job, handler job.Handler) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = errors.Errorf("panic: %v", recovered)
+		}
+	}()
+	return handler(ctx, claimed)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/config"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/job/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/job/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var cfg = &config.Config{
+	Logger: config.LoggerConfig{Level: "panic"},
+}
+
+// runUntil runs the processor until done is closed, Run must then return
+func runUntil(t *testing.T, sv interface{ Run(ctx context.Context) }, done chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		sv.Run(ctx)
+		close(stopped)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the job was not processed")
+	}
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the processor did not stop")
+	}
+}
+
+func TestProcessor_Run(t *testing.T) {
+	options := model.Options{Concurrency: 1, MaxAttempts: 2, Backoff: time.Minute, Timeout: time.Second}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] A job run successfully is completed", func(t *testing.T) {
+		mockRepo := new(mockObject.MockRedisRepository)
+		sv := NewProcessor(&ServiceConfig{RedisRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		claimed, _ := model.NewJob(model.TypeEmailSend, map[string]string{"to": "budi@example.com"}, time.Now())
+		var received map[string]string
+		sv.Register(model.TypeEmailSend, options, func(ctx context.Context, job *model.Job) error {
+			return job.Decode(&received)
+		})
+
+		done := make(chan struct{})
+		mockRepo.On("Claim", mock.Anything, model.TypeEmailSend, mock.Anything, 2*time.Second).Return(claimed, nil).Once()
+		mockRepo.On("Claim", mock.Anything, model.TypeEmailSend, mock.Anything, mock.Anything).Return(nil, nil)
+		mockRepo.On("Complete", mock.Anything, claimed).Return(nil).Once().Run(func(args mock.Arguments) { close(done) })
+
+		runUntil(t, sv, done)
+		require.Equal(t, "budi@example.com", received["to"])
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] A failed job is retried with backoff", func(t *testing.T) {
+		mockRepo := new(mockObject.MockRedisRepository)
+		sv := NewProcessor(&ServiceConfig{RedisRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+		sv.Register(model.TypeEmailSend, options, func(ctx context.Context, job *model.Job) error {
+			return errors.New("connection refused")
+		})
+
+		claimed, _ := model.NewJob(model.TypeEmailSend, map[string]string{}, time.Now())
+		claimed.Attempts = 1
+		done := make(chan struct{})
+		mockRepo.On("Claim", mock.Anything, model.TypeEmailSend, mock.Anything, mock.Anything).Return(claimed, nil).Once()
+		mockRepo.On("Claim", mock.Anything, model.TypeEmailSend, mock.Anything, mock.Anything).Return(nil, nil)
+		mockRepo.On("Retry", mock.Anything, mock.MatchedBy(func(job *model.Job) bool {
+			return job.Attempts == 1 && job.LastError == "connection refused" && job.RunAt > time.Now().Add(50*time.Second).UnixMilli()
+		})).Return(nil).Once().Run(func(args mock.Arguments) { close(done) })
+
+		runUntil(t, sv, done)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] A job panicking on its last attempt is dead", func(t *testing.T) {
+		mockRepo := new(mockObject.MockRedisRepository)
+		sv := NewProcessor(&ServiceConfig{RedisRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+		sv.Register(model.TypeRetentionPurge, options, func(ctx context.Context, job *model.Job) error {
+			panic("nil pointer")
+		})
+
+		claimed, _ := model.NewJob(model.TypeRetentionPurge, map[string]string{}, time.Now())
+		claimed.Attempts = 2
+		done := make(chan struct{})
+		mockRepo.On("Claim", mock.Anything, model.TypeRetentionPurge, mock.Anything, mock.Anything).Return(claimed, nil).Once()
+		mockRepo.On("Claim", mock.Anything, model.TypeRetentionPurge, mock.Anything, mock.Anything).Return(nil, nil)
+		mockRepo.On("Bury", mock.Anything, mock.MatchedBy(func(job *model.Job) bool {
+			return job.Attempts == 2 && job.LastError == "panic: nil pointer"
+		})).Return(nil).Once().Run(func(args mock.Arguments) { close(done) })
+
+		runUntil(t, sv, done)
+		mockRepo.AssertNotCalled(t, "Retry", mock.Anything, mock.Anything)
+	})
+
+	// scenario test case #4
+	t.Run("[Test Case #4] A job claimed again after its last attempt was abandoned is dead without running", func(t *testing.T) {
+		mockRepo := new(mockObject.MockRedisRepository)
+		sv := NewProcessor(&ServiceConfig{RedisRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+		ran := false
+		sv.Register(model.TypeEmailSend, options, func(ctx context.Context, job *model.Job) error {
+			ran = true
+			return nil
+		})
+
+		// the worker running its second and last attempt crashed, the lease expired
+		claimed, _ := model.NewJob(model.TypeEmailSend, map[string]string{}, time.Now())
+		claimed.Attempts = 3
+		done := make(chan struct{})
+		mockRepo.On("Claim", mock.Anything, model.TypeEmailSend, mock.Anything, mock.Anything).Return(claimed, nil).Once()
+		mockRepo.On("Claim", mock.Anything, model.TypeEmailSend, mock.Anything, mock.Anything).Return(nil, nil)
+		mockRepo.On("Bury", mock.Anything, mock.MatchedBy(func(job *model.Job) bool {
+			return job.Attempts == 2 && job.LastError != ""
+		})).Return(nil).Once().Run(func(args mock.Arguments) { close(done) })
+
+		runUntil(t, sv, done)
+		require.False(t, ran)
+		mockRepo.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProcessor_Schedule(t *testing.T) {
+	mockRepo := new(mockObject.MockRedisRepository)
+	sv := NewProcessor(&ServiceConfig{RedisRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+	sv.Schedule(model.TypeRetentionPurge, time.Hour, map[string]string{})
+
+	done := make(chan struct{})
+	mockRepo.On("TryLock", mock.Anything, "schedule:retention.purge", time.Hour).Return(true, nil).Once()
+	mockRepo.On("Enqueue", mock.Anything, mock.MatchedBy(func(job *model.Job) bool {
+		return job.Type == model.TypeRetentionPurge
+	})).Return(nil).Once().Run(func(args mock.Arguments) { close(done) })
+
+	runUntil(t, sv, done)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,33 @@
+package job
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/job/model"
+	"time"
+)
+
+// Handler runs a job, a job whose handler fails is retried with backoff
+type Handler func(ctx context.Context, job *model.Job) error
+
+// Queue queues the jobs run by the worker
+type Queue interface {
+	// Enqueue queues a job of the type with data as payload, due at once
+	Enqueue(ctx context.Context, jobType string, data interface{}) (*model.Job, error)
+
+	// EnqueueAt queues a job of the type with data as payload, due at runAt
+	EnqueueAt(ctx context.Context, jobType string, data interface{}, runAt time.Time) (*model.Job, error)
+}
+
+// Processor runs the queued jobs of the registered types
+type Processor interface {
+	Queue
+
+	// Register runs the jobs of the type with handler, the options not given are the configured ones
+	Register(jobType string, options model.Options, handler Handler)
+
+	// Schedule queues a job of the type every interval, once for all the workers
+	Schedule(jobType string, every time.Duration, data interface{})
+
+	// Run runs the jobs until ctx is done, then waits for the running jobs to end
+	Run(ctx context.Context)
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"strings"
+)
+
+// AdminMiddleware is a middleware that only lets through users whose email is
+// listed in the server AdminEmails config, it must run after AuthJwtMiddleware
+func (mw *MiddlewareManager) AdminMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := GetAuth(ctx)
+
+		for _, email := range mw.cfg.Server.AdminEmails {
+			if strings.EqualFold(strings.TrimSpace(email), auth.Email) {
+				ctx.Next()
+				return
+			}
+		}
+
+		errResponse := httpErrors.NewForbiddenError("MiddlewareManager.AdminMiddleware: " + auth.Email + " is not an admin")
+		utils.LogErrorResponse(ctx, mw.logger, errResponse)
+		ctx.JSON(httpErrors.ErrorResponse(ctx, errResponse))
+		ctx.Abort()
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/contextutils"
 	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
 	"github.com/mrizkisaputra/expenses-api/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -65,6 +66,7 @@ func (mw *MiddlewareManager) AuthJwtMiddleware() gin.HandlerFunc {
 			Email: claims.Email,
 		}
 		ctx.Set("auth", auth)
+		contextutils.SetActorId(ctx, auth.Id)
 
 		ctx.Next()
 	}
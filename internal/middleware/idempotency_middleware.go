@@ -0,0 +1,232 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	idempotencyKeyPrefix      = "idempotency"
+	idempotencyMaxKeyLength   = 255
+	idempotencyDefaultTTL     = 24 * time.Hour
+
+	// idempotencyDefaultLease is how long a key stays locked by a request when the server has no write timeout
+	idempotencyDefaultLease = time.Minute
+)
+
+// idempotencyStoredHeaders are the response headers replayed with the stored response,
+// a client retrying an update still gets the ETag for its next If-Match
+var idempotencyStoredHeaders = []string{"ETag", "Location"}
+
+// idempotentRequest is the state of an Idempotency-Key stored in redis, it is
+// locked (Done false) while the first request runs and holds its response afterwards
+type idempotentRequest struct {
+	Fingerprint string            `json:"fingerprint"`
+	Done        bool              `json:"done"`
+	Status      int               `json:"status,omitempty"`
+	ContentType string            `json:"content_type,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// responseRecorder copies the response body while it is written to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware makes a mutating request safe to retry when it carries an Idempotency-Key header.
+// the response of the first request is stored in redis per user and key, a retry with the same
+// payload gets the stored response replayed and the same key with a different payload is rejected.
+// server errors are not stored so the request can be retried. while the first request runs the key is
+// only locked for the write timeout of the server, a process dying mid-request does not block the key
+// for the whole ttl. it must run after AuthJwtMiddleware
+func (mw *MiddlewareManager) IdempotencyMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		idempotencyKey := ctx.GetHeader(idempotencyKeyHeader)
+		if idempotencyKey == "" {
+			ctx.Next()
+			return
+		}
+		if len(idempotencyKey) > idempotencyMaxKeyLength {
+			mw.abortWithError(ctx, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidIdempotencyKeyMsg, nil))
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			mw.abortWithError(ctx, httpErrors.NewBadRequestError(errors.Wrap(err, "MiddlewareManager.IdempotencyMiddleware.ReadAll")))
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := utils.GenerateRedisKey(idempotencyKeyPrefix, GetAuth(ctx).Id.String()+":"+idempotencyKey)
+		fingerprint := requestFingerprint(ctx.Request.Method, ctx.Request.URL.RequestURI(), body)
+		ttl := mw.idempotencyTTL()
+
+		// the first request locks the key, concurrent and later requests read it
+		locked, err := mw.storeIdempotentRequest(ctx, key, &idempotentRequest{Fingerprint: fingerprint}, mw.idempotencyLease(), true)
+		if err != nil {
+			mw.abortWithError(ctx, httpErrors.NewInternalServerError(err))
+			return
+		}
+		if !locked {
+			mw.replayIdempotentRequest(ctx, key, fingerprint)
+			return
+		}
+
+		// the context of the request may be canceled once the response is written
+		storeCtx := context.WithoutCancel(ctx.Request.Context())
+		// the key is released unless the response is stored, when the handler panics too
+		stored := false
+		defer func() {
+			if stored {
+				return
+			}
+			if err := mw.redisClient.Del(storeCtx, key).Err(); err != nil {
+				mw.logger.WithError(err).Error("MiddlewareManager.IdempotencyMiddleware.Del")
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer, body: new(bytes.Buffer)}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		if status := recorder.Status(); status >= http.StatusInternalServerError {
+			return
+		}
+		// the request is done, the lock is replaced by its response kept for the whole ttl
+		stored = true
+		if _, err := mw.storeIdempotentRequest(storeCtx, key, &idempotentRequest{
+			Fingerprint: fingerprint,
+			Done:        true,
+			Status:      recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Headers:     storedHeaders(recorder.Header()),
+			Body:        recorder.body.Bytes(),
+		}, ttl, false); err != nil {
+			mw.logger.WithError(err).Error("MiddlewareManager.IdempotencyMiddleware.store")
+		}
+	}
+}
+
+// replayIdempotentRequest answers a retry with the stored response of the first request
+func (mw *MiddlewareManager) replayIdempotentRequest(ctx *gin.Context, key, fingerprint string) {
+	data, err := mw.redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		mw.abortWithError(ctx, httpErrors.NewInternalServerError(errors.Wrap(err, "MiddlewareManager.replayIdempotentRequest.Get")))
+		return
+	}
+
+	stored := new(idempotentRequest)
+	if err := json.Unmarshal(data, stored); err != nil {
+		mw.abortWithError(ctx, httpErrors.NewInternalServerError(errors.Wrap(err, "MiddlewareManager.replayIdempotentRequest.Unmarshal")))
+		return
+	}
+
+	switch {
+	case stored.Fingerprint != fingerprint:
+		mw.abortWithError(ctx, httpErrors.NewError(http.StatusUnprocessableEntity, httpErrors.IdempotencyKeyReusedMsg, nil))
+	case !stored.Done:
+		mw.abortWithError(ctx, httpErrors.NewError(http.StatusConflict, httpErrors.IdempotencyKeyInFlightMsg, nil))
+	default:
+		for name, value := range stored.Headers {
+			ctx.Header(name, value)
+		}
+		ctx.Header(idempotencyReplayedHeader, "true")
+		ctx.Data(stored.Status, stored.ContentType, stored.Body)
+		ctx.Abort()
+	}
+}
+
+// storeIdempotentRequest saves the state of a key, with onlyIfAbsent it is only saved
+// when the key does not exist yet and stored reports whether it was
+func (mw *MiddlewareManager) storeIdempotentRequest(
+	ctx context.Context,
+	key string,
+	request *idempotentRequest,
+	ttl time.Duration,
+	onlyIfAbsent bool,
+) (stored bool, err error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return false, errors.Wrap(err, "MiddlewareManager.storeIdempotentRequest.Marshal")
+	}
+
+	if onlyIfAbsent {
+		stored, err = mw.redisClient.SetNX(ctx, key, data, ttl).Result()
+	} else {
+		err = mw.redisClient.Set(ctx, key, data, ttl).Err()
+		stored = err == nil
+	}
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, errors.Wrap(err, "MiddlewareManager.storeIdempotentRequest.Set")
+	}
+	return stored, nil
+}
+
+func (mw *MiddlewareManager) idempotencyTTL() time.Duration {
+	if mw.cfg == nil || mw.cfg.Idempotency.TTL <= 0 {
+		return idempotencyDefaultTTL
+	}
+	return mw.cfg.Idempotency.TTL * time.Second
+}
+
+// idempotencyLease is the write timeout of the server, a request holding the key longer has no answer anyway
+func (mw *MiddlewareManager) idempotencyLease() time.Duration {
+	if mw.cfg == nil || mw.cfg.Server.WriteTimeout <= 0 {
+		return idempotencyDefaultLease
+	}
+	return mw.cfg.Server.WriteTimeout * time.Second
+}
+
+func (mw *MiddlewareManager) abortWithError(ctx *gin.Context, err error) {
+	utils.LogErrorResponse(ctx, mw.logger, err)
+	ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+	ctx.Abort()
+}
+
+// storedHeaders picks the headers of a response kept with it, nil when it has none of them
+func storedHeaders(header http.Header) map[string]string {
+	var headers map[string]string
+	for _, name := range idempotencyStoredHeaders {
+		if value := header.Get(name); value != "" {
+			if headers == nil {
+				headers = make(map[string]string, len(idempotencyStoredHeaders))
+			}
+			headers[name] = value
+		}
+	}
+	return headers
+}
+
+// requestFingerprint identifies the payload of a request, a key reused on another url counts as another payload
+func requestFingerprint(method, url string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + url + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
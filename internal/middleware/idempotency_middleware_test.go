@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupIdempotencyRouter(t *testing.T, status int) (*gin.Engine, *miniredis.Miniredis, *int) {
+	server := miniredis.RunT(t)
+	cfg := &config.Config{
+		Logger:      config.LoggerConfig{Level: "panic"},
+		Idempotency: config.IdempotencyConfig{TTL: 60},
+	}
+	mw := NewMiddlewareManager(&MiddlewareConfig{
+		Logger:      logger.NewLogrusLogger(cfg),
+		Config:      cfg,
+		RedisClient: redis.NewClient(&redis.Options{Addr: server.Addr()}),
+	})
+
+	calls := new(int)
+	userId := uuid.New()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/expenses", func(ctx *gin.Context) {
+		ctx.Set("auth", &auth{Id: userId})
+	}, mw.IdempotencyMiddleware(), func(ctx *gin.Context) {
+		*calls++
+		ctx.JSON(status, gin.H{"call": *calls})
+	})
+	return router, server, calls
+}
+
+func postWithKey(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/expenses", strings.NewReader(body))
+	request.Header.Set("Idempotency-Key", key)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestMiddlewareManager_IdempotencyMiddleware(t *testing.T) {
+	// scenario test case #1
+	t.Run("[Test Case #1] A retry replays the stored response", func(t *testing.T) {
+		router, server, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+		first := postWithKey(router, "key-1", `{"amount":"10.00"}`)
+		retry := postWithKey(router, "key-1", `{"amount":"10.00"}`)
+
+		require.Equal(t, 1, *calls)
+		require.Equal(t, http.StatusCreated, retry.Code)
+		require.JSONEq(t, first.Body.String(), retry.Body.String())
+		require.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+		require.Equal(t, 60*time.Second, server.TTL(server.Keys()[0]))
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] The same key with another payload is rejected", func(t *testing.T) {
+		router, _, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+		postWithKey(router, "key-1", `{"amount":"10.00"}`)
+		reused := postWithKey(router, "key-1", `{"amount":"99.00"}`)
+
+		require.Equal(t, 1, *calls)
+		require.Equal(t, http.StatusUnprocessableEntity, reused.Code)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Server errors are not stored so the request can be retried", func(t *testing.T) {
+		router, server, calls := setupIdempotencyRouter(t, http.StatusInternalServerError)
+
+		postWithKey(router, "key-1", `{}`)
+		postWithKey(router, "key-1", `{}`)
+
+		require.Equal(t, 2, *calls)
+		require.Empty(t, server.Keys())
+	})
+
+	// scenario test case #4
+	t.Run("[Test Case #4] A request still in flight is a conflict", func(t *testing.T) {
+		router, server, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+		// a first request with the same key and payload is still running
+		first := postWithKey(router, "key-2", `{}`)
+		require.Equal(t, http.StatusCreated, first.Code)
+		key := server.Keys()[0]
+		value, _ := server.Get(key)
+		require.NoError(t, server.Set(key, strings.Replace(value, `"done":true`, `"done":false`, 1)))
+
+		inFlight := postWithKey(router, "key-2", `{}`)
+		require.Equal(t, 1, *calls)
+		require.Equal(t, http.StatusConflict, inFlight.Code)
+	})
+
+	// scenario test case #5
+	t.Run("[Test Case #5] The key is locked for the write timeout only and released when the handler panics", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		cfg := &config.Config{
+			Logger:      config.LoggerConfig{Level: "panic"},
+			Server:      config.ServerConfig{WriteTimeout: 10},
+			Idempotency: config.IdempotencyConfig{TTL: 60},
+		}
+		mw := NewMiddlewareManager(&MiddlewareConfig{
+			Logger:      logger.NewLogrusLogger(cfg),
+			Config:      cfg,
+			RedisClient: redis.NewClient(&redis.Options{Addr: server.Addr()}),
+		})
+
+		var lease time.Duration
+		router := gin.New()
+		router.Use(gin.CustomRecovery(func(ctx *gin.Context, recovered any) {
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+		}))
+		router.POST("/expenses", func(ctx *gin.Context) {
+			ctx.Set("auth", &auth{Id: uuid.New()})
+		}, mw.IdempotencyMiddleware(), func(ctx *gin.Context) {
+			lease = server.TTL(server.Keys()[0])
+			panic("handler failed")
+		})
+
+		response := postWithKey(router, "key-3", `{}`)
+		require.Equal(t, http.StatusInternalServerError, response.Code)
+		require.Equal(t, 10*time.Second, lease)
+		require.Empty(t, server.Keys())
+	})
+	// scenario test case #6
+	t.Run("[Test Case #6] A retry replays the ETag and Location of the stored response", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		cfg := &config.Config{
+			Logger:      config.LoggerConfig{Level: "panic"},
+			Idempotency: config.IdempotencyConfig{TTL: 60},
+		}
+		mw := NewMiddlewareManager(&MiddlewareConfig{
+			Logger:      logger.NewLogrusLogger(cfg),
+			Config:      cfg,
+			RedisClient: redis.NewClient(&redis.Options{Addr: server.Addr()}),
+		})
+
+		calls := 0
+		userId := uuid.New()
+		router := gin.New()
+		router.PATCH("/expenses/:id", func(ctx *gin.Context) {
+			ctx.Set("auth", &auth{Id: userId})
+		}, mw.IdempotencyMiddleware(), func(ctx *gin.Context) {
+			calls++
+			ctx.Header("ETag", `"2"`)
+			ctx.Header("Location", "/api/v1/expenses/"+ctx.Param("id"))
+			ctx.Header("X-Not-Stored", "1")
+			ctx.JSON(http.StatusOK, gin.H{"version": 2})
+		})
+
+		patch := func() *httptest.ResponseRecorder {
+			request := httptest.NewRequest(http.MethodPatch, "/expenses/42", strings.NewReader(`{"amount":"10.00"}`))
+			request.Header.Set("Idempotency-Key", "key-4")
+			request.Header.Set("If-Match", `"1"`)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			return recorder
+		}
+		patch()
+		retry := patch()
+
+		require.Equal(t, 1, calls)
+		require.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+		require.Equal(t, `"2"`, retry.Header().Get("ETag"))
+		require.Equal(t, "/api/v1/expenses/42", retry.Header().Get("Location"))
+		require.Empty(t, retry.Header().Get("X-Not-Stored"))
+		require.JSONEq(t, `{"version":2}`, retry.Body.String())
+	})
+}
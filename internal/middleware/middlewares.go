@@ -2,24 +2,28 @@ package middleware
 
 import (
 	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
 type MiddlewareConfig struct {
-	Logger *logrus.Logger
-	Config *config.Config
+	Logger      *logrus.Logger
+	Config      *config.Config
+	RedisClient *redis.Client
 }
 
 // MiddlewareManager defines methods middleware
 type MiddlewareManager struct {
-	logger *logrus.Logger
-	cfg    *config.Config
+	logger      *logrus.Logger
+	cfg         *config.Config
+	redisClient *redis.Client
 }
 
 // NewMiddlewareManager is a factory function for instance MiddlewareManager
 func NewMiddlewareManager(config *MiddlewareConfig) *MiddlewareManager {
 	return &MiddlewareManager{
-		logger: config.Logger,
-		cfg:    config.Config,
+		logger:      config.Logger,
+		cfg:         config.Config,
+		redisClient: config.RedisClient,
 	}
 }
@@ -5,12 +5,17 @@ import (
 	"github.com/mrizkisaputra/expenses-api/pkg/contextutils"
 )
 
+// maxRequestIdLength is the size of the column keeping the request id in the expense history
+const maxRequestIdLength = 100
+
 // RequestIdMiddleware is a middleware for assign request id
 func (mw *MiddlewareManager) RequestIdMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		// before request
+		// before request, a request id given by the client is kept only when it can be stored and logged as is
 		requestId := ctx.GetHeader("X-Request-Id")
-		if requestId == "" {
+		if validRequestId(requestId) {
+			contextutils.SetRequestId(ctx, requestId)
+		} else {
 			requestId = contextutils.AssignRequestId(ctx)
 		}
 
@@ -20,3 +25,16 @@ func (mw *MiddlewareManager) RequestIdMiddleware() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// validRequestId accepts at most maxRequestIdLength printable ASCII characters, without spaces
+func validRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > maxRequestIdLength {
+		return false
+	}
+	for i := 0; i < len(requestId); i++ {
+		if requestId[i] < '!' || requestId[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
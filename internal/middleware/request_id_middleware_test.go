@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/pkg/contextutils"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareManager_RequestIdMiddleware(t *testing.T) {
+	cfg := &config.Config{Logger: config.LoggerConfig{Level: "panic"}}
+	mw := NewMiddlewareManager(&MiddlewareConfig{Logger: logger.NewLogrusLogger(cfg), Config: cfg})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var stored string
+	router.GET("/", mw.RequestIdMiddleware(), func(ctx *gin.Context) {
+		stored = contextutils.GetRequestId(ctx)
+	})
+	get := func(requestId string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("X-Request-Id", requestId)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] The request id of the client is kept", func(t *testing.T) {
+		recorder := get("checkout-7f3a")
+		require.Equal(t, "checkout-7f3a", stored)
+		require.Equal(t, "checkout-7f3a", recorder.Header().Get("X-Request-Id"))
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] A request id too long or not printable is replaced", func(t *testing.T) {
+		for _, requestId := range []string{"", strings.Repeat("a", maxRequestIdLength+1), "two words", "café", "tab\tid"} {
+			recorder := get(requestId)
+			_, err := uuid.Parse(stored)
+			require.NoError(t, err, requestId)
+			require.Equal(t, stored, recorder.Header().Get("X-Request-Id"))
+		}
+
+		get(strings.Repeat("a", maxRequestIdLength))
+		require.Equal(t, strings.Repeat("a", maxRequestIdLength), stored)
+	})
+}
@@ -0,0 +1,137 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	jobMock "github.com/mrizkisaputra/expenses-api/internal/job/mock"
+	jobModel "github.com/mrizkisaputra/expenses-api/internal/job/model"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+	userMock "github.com/mrizkisaputra/expenses-api/internal/user/mock"
+	userModel "github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+)
+
+func TestWebhookChannel_Deliver(t *testing.T) {
+	notification := &model.Notification{Id: uuid.New(), UserId: uuid.New(), Kind: model.KindSecurityLogin, Title: "New sign-in"}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] The notification is posted as JSON without holding the delivery", func(t *testing.T) {
+		release := make(chan struct{})
+		received := make(chan model.Notification, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			var body model.Notification
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			w.WriteHeader(http.StatusNoContent)
+			received <- body
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		channel := NewWebhookChannel(&config.NotificationConfig{WebhookURL: server.URL}, nil, logrus.New())
+		require.NoError(t, channel.Deliver(ctx, notification))
+
+		// the request publishing the notification is over before the URL answers
+		cancel()
+		close(release)
+		body := <-received
+		require.Equal(t, notification.Id, body.Id)
+		require.Equal(t, "New sign-in", body.Title)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should fail when the endpoint does not answer 2xx", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		sender := newWebhookSender(&config.NotificationConfig{WebhookURL: server.URL})
+		require.Error(t, sender.Send(context.Background(), notification))
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] The notification is queued to the worker with a job queue", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("the webhook must not be posted while the notification is delivered")
+		}))
+		defer server.Close()
+
+		mockQueue := new(jobMock.QueueMock)
+		mockQueue.On("Enqueue", mock.Anything, jobModel.TypeNotificationWebhook, notification).Return(&jobModel.Job{}, nil).Once()
+
+		channel := NewWebhookChannel(&config.NotificationConfig{WebhookURL: server.URL}, mockQueue, logrus.New())
+		require.NoError(t, channel.Deliver(context.Background(), notification))
+		mockQueue.AssertExpectations(t)
+
+		// the worker posts the queued notification
+		var received model.Notification
+		worker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		}))
+		defer worker.Close()
+		queued, _ := jobModel.NewJob(jobModel.TypeNotificationWebhook, notification, time.Now())
+		require.NoError(t, NewWebhookJobHandler(&config.NotificationConfig{WebhookURL: worker.URL})(context.Background(), queued))
+		require.Equal(t, notification.Id, received.Id)
+	})
+}
+
+func TestEmailChannel_Deliver(t *testing.T) {
+	userId := uuid.New()
+	mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+	mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: userId, Email: "budi@example.com"}, nil)
+
+	notificationConfig := &config.NotificationConfig{SmtpHost: "smtp.example.com", SmtpPort: 587, From: "noreply@example.com"}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] The email is sent at once without job queue", func(t *testing.T) {
+		channel := NewEmailChannel(notificationConfig, mockUserRepo, nil).(*emailChannel)
+		var sent []byte
+		var to []string
+		channel.send = func(addr string, auth smtp.Auth, from string, recipients []string, msg []byte) error {
+			require.Equal(t, "smtp.example.com:587", addr)
+			to, sent = recipients, msg
+			return nil
+		}
+
+		err := channel.Deliver(context.Background(), &model.Notification{
+			UserId: userId,
+			Title:  "New sign-in\r\nBcc: someone@example.com",
+			Body:   "Your account was signed in to",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"budi@example.com"}, to)
+		require.Contains(t, string(sent), "Subject: New sign-in  Bcc: someone@example.com\r\n")
+		require.Contains(t, string(sent), "\r\n\r\nYour account was signed in to")
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] The email is queued to the worker with a job queue", func(t *testing.T) {
+		mockQueue := new(jobMock.QueueMock)
+		channel := NewEmailChannel(notificationConfig, mockUserRepo, mockQueue).(*emailChannel)
+		channel.send = func(addr string, auth smtp.Auth, from string, recipients []string, msg []byte) error {
+			t.Fatal("the email must not be sent while the notification is delivered")
+			return nil
+		}
+
+		mockQueue.On("Enqueue", mock.Anything, jobModel.TypeEmailSend, &model.Email{
+			To:      "budi@example.com",
+			Subject: "New sign-in",
+			Body:    "Your account was signed in to",
+		}).Return(&jobModel.Job{}, nil).Once()
+
+		err := channel.Deliver(context.Background(), &model.Notification{UserId: userId, Title: "New sign-in", Body: "Your account was signed in to"})
+		require.NoError(t, err)
+		mockQueue.AssertExpectations(t)
+	})
+}
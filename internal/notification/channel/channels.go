@@ -0,0 +1,26 @@
+package channel
+
+import (
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/event"
+	"github.com/mrizkisaputra/expenses-api/internal/job"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	"github.com/mrizkisaputra/expenses-api/internal/user"
+	"github.com/sirupsen/logrus"
+)
+
+// NewChannels returns the in-app channel, the realtime channel when an event publisher is given,
+// followed by the channels enabled in the configuration. the emails and webhooks are queued to the worker when a job queue is given
+func NewChannels(cfg *config.Config, pgRepo notification.PostgresRepository, userPgRepo user.UserPostgresRepository, eventPublisher event.Publisher, jobQueue job.Queue, logger *logrus.Logger) []notification.Channel {
+	channels := []notification.Channel{NewInAppChannel(pgRepo)}
+	if eventPublisher != nil {
+		channels = append(channels, NewRealtimeChannel(eventPublisher))
+	}
+	if cfg.Notification.SmtpHost != "" {
+		channels = append(channels, NewEmailChannel(&cfg.Notification, userPgRepo, jobQueue))
+	}
+	if cfg.Notification.WebhookURL != "" {
+		channels = append(channels, NewWebhookChannel(&cfg.Notification, jobQueue, logger))
+	}
+	return channels
+}
@@ -0,0 +1,107 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/job"
+	jobModel "github.com/mrizkisaputra/expenses-api/internal/job/model"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+	"github.com/mrizkisaputra/expenses-api/internal/user"
+	userModel "github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"github.com/pkg/errors"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// emailChannel mails the notifications to the address of their user through an SMTP server,
+// with a job queue the emails are queued and sent by the worker
+type emailChannel struct {
+	*emailSender
+	userPgRepo user.UserPostgresRepository
+	jobQueue   job.Queue
+}
+
+// emailSender sends the emails through the SMTP server of the configuration
+type emailSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+	send func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewEmailChannel(cfg *config.NotificationConfig, userPgRepo user.UserPostgresRepository, jobQueue job.Queue) notification.Channel {
+	return &emailChannel{
+		emailSender: newEmailSender(cfg),
+		userPgRepo:  userPgRepo,
+		jobQueue:    jobQueue,
+	}
+}
+
+// NewEmailJobHandler is the handler of the job 'email.send' run by the worker
+func NewEmailJobHandler(cfg *config.NotificationConfig) job.Handler {
+	sender := newEmailSender(cfg)
+	return func(ctx context.Context, job *jobModel.Job) error {
+		email := new(model.Email)
+		if err := job.Decode(email); err != nil {
+			return err
+		}
+		return sender.Send(email)
+	}
+}
+
+func newEmailSender(cfg *config.NotificationConfig) *emailSender {
+	var auth smtp.Auth
+	if cfg.SmtpUsername != "" {
+		auth = smtp.PlainAuth("", cfg.SmtpUsername, cfg.SmtpPassword, cfg.SmtpHost)
+	}
+	return &emailSender{
+		addr: net.JoinHostPort(cfg.SmtpHost, strconv.Itoa(cfg.SmtpPort)),
+		from: cfg.From,
+		auth: auth,
+		send: smtp.SendMail,
+	}
+}
+
+func (c *emailChannel) Name() string {
+	return model.ChannelEmail
+}
+
+func (c *emailChannel) Deliver(ctx context.Context, notification *model.Notification) error {
+	found, err := c.userPgRepo.FindById(ctx, &userModel.User{Id: notification.UserId})
+	if err != nil {
+		return errors.Wrap(err, "emailChannel.Deliver.FindById")
+	}
+
+	email := &model.Email{To: found.Email, Subject: notification.Title, Body: notification.Body}
+	if c.jobQueue != nil {
+		if _, err := c.jobQueue.Enqueue(ctx, jobModel.TypeEmailSend, email); err != nil {
+			return errors.Wrap(err, "emailChannel.Deliver.Enqueue")
+		}
+		return nil
+	}
+	return c.Send(email)
+}
+
+func (s *emailSender) Send(email *model.Email) error {
+	if err := s.send(s.addr, s.auth, s.from, []string{email.To}, s.message(email)); err != nil {
+		return errors.Wrap(err, "emailSender.Send.SendMail")
+	}
+	return nil
+}
+
+// message is the plain text email, line breaks are removed
+// from the headers so a subject cannot add headers of its own
+func (s *emailSender) message(email *model.Email) []byte {
+	header := strings.NewReplacer("\r", " ", "\n", " ")
+	return []byte(fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		header.Replace(s.from),
+		header.Replace(email.To),
+		header.Replace(email.Subject),
+		email.Body,
+	))
+}
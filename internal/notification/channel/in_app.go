@@ -0,0 +1,24 @@
+package channel
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+)
+
+// inAppChannel keeps the notifications in the inbox of their user
+type inAppChannel struct {
+	pgRepo notification.PostgresRepository
+}
+
+func NewInAppChannel(pgRepo notification.PostgresRepository) notification.Channel {
+	return &inAppChannel{pgRepo: pgRepo}
+}
+
+func (c *inAppChannel) Name() string {
+	return model.ChannelInApp
+}
+
+func (c *inAppChannel) Deliver(ctx context.Context, notification *model.Notification) error {
+	return c.pgRepo.Create(ctx, notification)
+}
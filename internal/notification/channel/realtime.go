@@ -0,0 +1,26 @@
+package channel
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/event"
+	eventModel "github.com/mrizkisaputra/expenses-api/internal/event/model"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+)
+
+// realtimeChannel pushes the notifications to the connected clients of their user
+type realtimeChannel struct {
+	eventPublisher event.Publisher
+}
+
+func NewRealtimeChannel(eventPublisher event.Publisher) notification.Channel {
+	return &realtimeChannel{eventPublisher: eventPublisher}
+}
+
+func (c *realtimeChannel) Name() string {
+	return model.ChannelRealtime
+}
+
+func (c *realtimeChannel) Deliver(ctx context.Context, notification *model.Notification) error {
+	return c.eventPublisher.Publish(ctx, notification.UserId, eventModel.TypeNotificationCreated, notification)
+}
@@ -0,0 +1,114 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/job"
+	jobModel "github.com/mrizkisaputra/expenses-api/internal/job/model"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+// defaultTimeout is how long a webhook delivery may take without configured timeout
+const defaultTimeout = 10 * time.Second
+
+// webhookChannel posts the notifications as JSON to an URL in the background,
+// so a slow URL does not hold the request publishing them. a failed post is logged.
+// with a job queue the notifications are queued and posted by the worker
+type webhookChannel struct {
+	*webhookSender
+	jobQueue job.Queue
+	logger   *logrus.Logger
+}
+
+// webhookSender posts a notification to the URL of the configuration, any answer other than 2xx is a failure
+type webhookSender struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookChannel(cfg *config.NotificationConfig, jobQueue job.Queue, logger *logrus.Logger) notification.Channel {
+	return &webhookChannel{
+		webhookSender: newWebhookSender(cfg),
+		jobQueue:      jobQueue,
+		logger:        logger,
+	}
+}
+
+// NewWebhookJobHandler is the handler of the job 'notification.webhook' run by the worker
+func NewWebhookJobHandler(cfg *config.NotificationConfig) job.Handler {
+	sender := newWebhookSender(cfg)
+	return func(ctx context.Context, job *jobModel.Job) error {
+		notification := new(model.Notification)
+		if err := job.Decode(notification); err != nil {
+			return err
+		}
+		return sender.Send(ctx, notification)
+	}
+}
+
+func newWebhookSender(cfg *config.NotificationConfig) *webhookSender {
+	timeout := cfg.Timeout * time.Second
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &webhookSender{
+		url:    cfg.WebhookURL,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *webhookChannel) Name() string {
+	return model.ChannelWebhook
+}
+
+// Deliver returns at once, without job queue the post outlives the request through a context that is not canceled
+// with it and is bounded by the timeout of the client
+func (c *webhookChannel) Deliver(ctx context.Context, notification *model.Notification) error {
+	if c.jobQueue != nil {
+		if _, err := c.jobQueue.Enqueue(ctx, jobModel.TypeNotificationWebhook, notification); err != nil {
+			return errors.Wrap(err, "webhookChannel.Deliver.Enqueue")
+		}
+		return nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := c.Send(ctx, notification); err != nil {
+			c.logger.WithError(err).
+				WithField("notificationId", notification.Id).
+				Warn("webhookChannel.Deliver.Send")
+		}
+	}()
+	return nil
+}
+
+func (s *webhookSender) Send(ctx context.Context, notification *model.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return errors.Wrap(err, "webhookSender.Send.Marshal")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "webhookSender.Send.NewRequest")
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "webhookSender.Send.Do")
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return errors.Errorf("webhookSender.Send: %s answered %d", s.url, response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package notification
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+)
+
+// Channel delivers notifications to their user through one medium: the in-app inbox, email or webhook.
+// a new medium only needs a new implementation registered in the service
+type Channel interface {
+	// Name is the name of the channel, one of the Channel constants of the model
+	Name() string
+
+	Deliver(ctx context.Context, notification *model.Notification) error
+}
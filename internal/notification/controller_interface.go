@@ -0,0 +1,13 @@
+package notification
+
+import "github.com/gin-gonic/gin"
+
+// NotificationController defines methods the routes expects
+// any controllers it interacts with to implement
+type NotificationController interface {
+	GetAllNotification() gin.HandlerFunc
+
+	MarkNotificationRead() gin.HandlerFunc
+
+	MarkAllNotificationRead() gin.HandlerFunc
+}
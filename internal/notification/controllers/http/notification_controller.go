@@ -0,0 +1,132 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/sirupsen/logrus"
+	"math"
+	"net/http"
+)
+
+type ControllerConfig struct {
+	NotificationService notification.NotificationService
+	Logger              *logrus.Logger
+}
+
+// notificationController acts as a struct for injecting an implementation of NotificationController interface
+// for use in controller methods
+type notificationController struct {
+	notificationService notification.NotificationService
+	logger              *logrus.Logger
+}
+
+// NewNotificationController is a factory function
+// initializing a notificationController with its service layer dependencies
+func NewNotificationController(config *ControllerConfig) notification.NotificationController {
+	return &notificationController{
+		notificationService: config.NotificationService,
+		logger:              config.Logger,
+	}
+}
+
+// GetAllNotification is the inbox of the user, the most recent first, only the unread notifications with unread=true
+func (nc notificationController) GetAllNotification() gin.HandlerFunc {
+	type GetAllNotificationRequest struct {
+		Page   int  `form:"page" validate:"omitempty,numeric,min=1"`
+		Limit  int  `form:"limit" validate:"omitempty,numeric,min=10"`
+		Unread bool `form:"unread"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(GetAllNotificationRequest)
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, nc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if request.Page == 0 {
+			request.Page = 1
+		}
+		if request.Limit == 0 {
+			request.Limit = 10
+		}
+
+		notifications, total, err := nc.notificationService.GetAll(ctx, auth.Id.String(), request.Unread, request.Page, request.Limit)
+		if err != nil {
+			utils.LogErrorResponse(ctx, nc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       notifications,
+			Page:       request.Page,
+			Limit:      request.Limit,
+			TotalItems: total,
+			TotalPages: int64(math.Ceil(float64(total) / float64(request.Limit))),
+		})
+	}
+}
+
+func (nc notificationController) MarkNotificationRead() gin.HandlerFunc {
+	type MarkNotificationReadRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &MarkNotificationReadRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, nc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := nc.notificationService.MarkRead(ctx, request.Id, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, nc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (nc notificationController) MarkAllNotificationRead() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		count, err := nc.notificationService.MarkAllRead(ctx, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, nc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    &model.MarkAllReadResult{Marked: count},
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+)
+
+func MapNotificationRoutes(notificationGroup *gin.RouterGroup, controller notification.NotificationController, mw *middleware.MiddlewareManager) {
+	notificationGroup.Use(mw.AuthJwtMiddleware())
+	notificationGroup.GET("/", controller.GetAllNotification())
+	notificationGroup.POST("/read", mw.IdempotencyMiddleware(), controller.MarkAllNotificationRead())
+	notificationGroup.POST("/:id/read", mw.IdempotencyMiddleware(), controller.MarkNotificationRead())
+}
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// ChannelMock is a mock type for notification.Channel
+type ChannelMock struct {
+	mock.Mock
+	ChannelName string
+}
+
+func (m *ChannelMock) Name() string {
+	return m.ChannelName
+}
+
+func (m *ChannelMock) Deliver(ctx context.Context, notification *model.Notification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+// PublisherMock is a mock type for notification.Publisher
+type PublisherMock struct {
+	mock.Mock
+}
+
+func (m *PublisherMock) Publish(ctx context.Context, notification *model.Notification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
@@ -0,0 +1,9 @@
+package model
+
+// Email is a notification mailed to the address of its user, it is the payload
+// of the job 'email.send' when the emails are sent by the worker
+type Email struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// kinds of notification
+const (
+	KindBudgetBreach      = "budget_breach"
+	KindRecurringReminder = "recurring_reminder"
+	KindLedgerInvite      = "ledger_invite"
+	KindSecurityLogin     = "security_login"
+	KindSpendingAlert     = "spending_alert"
+)
+
+// names of the delivery channels
+const (
+	ChannelInApp    = "in_app"
+	ChannelEmail    = "email"
+	ChannelWebhook  = "webhook"
+	ChannelRealtime = "realtime"
+)
+
+const (
+	maxTitleLength = 100
+	maxBodyLength  = 500
+)
+
+// Notification mapping table 'notifications'.
+// a message to a user published by another module, it is kept in the inbox of the user
+// and may be delivered by email or webhook too. Data holds the ids the client needs to link it
+type Notification struct {
+	Id        uuid.UUID `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	UserId    uuid.UUID `json:"user_id" gorm:"column:id_user"`
+	Kind      string    `json:"kind" gorm:"column:kind"`
+	Title     string    `json:"title" gorm:"column:title"`
+	Body      string    `json:"body" gorm:"column:body"`
+	Data      Data      `json:"data" gorm:"column:data;type:jsonb"`
+	ReadAt    *int64    `json:"read_at" gorm:"column:read_at"`
+	CreatedAt int64     `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt int64     `json:"updated_at" gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (notification *Notification) TableName() string {
+	return "notifications"
+}
+
+// PrepareCreate trims the title and body of a new notification and cuts them to the length of their columns
+func (notification *Notification) PrepareCreate() {
+	notification.Title = utils.Truncate(strings.TrimSpace(notification.Title), maxTitleLength)
+	notification.Body = utils.Truncate(strings.TrimSpace(notification.Body), maxBodyLength)
+	if notification.Data == nil {
+		notification.Data = Data{}
+	}
+}
+
+// MarkAllReadResult is the number of notifications marked as read at once
+type MarkAllReadResult struct {
+	Marked int64 `json:"marked"`
+}
+
+// Data are the values attached to a notification by their name, such as "expense_id"
+type Data map[string]string
+
+func (data Data) Value() (driver.Value, error) {
+	return json.Marshal(data)
+}
+
+func (data *Data) Scan(src interface{}) error {
+	var raw []byte
+	switch value := src.(type) {
+	case []byte:
+		raw = value
+	case string:
+		raw = []byte(value)
+	default:
+		return errors.Errorf("Data.Scan: unsupported type %T", src)
+	}
+	return json.Unmarshal(raw, data)
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationPgRepository(db *gorm.DB) notification.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (p *postgresRepository) Create(ctx context.Context, notification *model.Notification) error {
+	/**
+	SQL: INSERT INTO "notifications" ("id_user","kind","title","body","data","read_at","created_at","updated_at")
+	VALUES (?,?,?,?,?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(notification).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Create")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Notification, error) {
+	/**
+	SQL: SELECT * FROM "notifications" WHERE id = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	notification := new(model.Notification)
+	if err := DB.Where("id = ? AND id_user = ?", id, userId).Take(notification).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByIdAndUserId")
+	}
+	return notification, nil
+}
+
+func (p *postgresRepository) FindAllByUserId(ctx context.Context, userId string, unreadOnly bool, page, limit int) ([]model.Notification, int64, error) {
+	DB := p.db.WithContext(ctx)
+	query := func() *gorm.DB {
+		query := DB.Model(&model.Notification{}).Where("id_user = ?", userId)
+		if unreadOnly {
+			query = query.Where("read_at IS NULL")
+		}
+		return query
+	}
+
+	/**
+	SQL: SELECT * FROM "notifications" WHERE id_user = ? [AND read_at IS NULL] ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?
+	*/
+	var notifications []model.Notification
+	if err := query().
+		Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&notifications).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindAllByUserId.Find")
+	}
+
+	/**
+	SQL: SELECT count(*) FROM "notifications" WHERE id_user = ? [AND read_at IS NULL]
+	*/
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindAllByUserId.Count")
+	}
+
+	return notifications, total, nil
+}
+
+func (p *postgresRepository) Update(ctx context.Context, notification *model.Notification) error {
+	/**
+	SQL: UPDATE "notifications" SET ...,"read_at"=?,"updated_at"=? WHERE (id = ? AND id_user = ?) AND "id" = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", notification.Id, notification.UserId).Updates(notification).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Update")
+	}
+	return nil
+}
+
+func (p *postgresRepository) MarkAllRead(ctx context.Context, userId string, readAt int64) (int64, error) {
+	/**
+	SQL: UPDATE "notifications" SET "read_at"=?,"updated_at"=? WHERE id_user = ? AND read_at IS NULL
+	*/
+	DB := p.db.WithContext(ctx)
+	result := DB.Model(&model.Notification{}).
+		Where("id_user = ? AND read_at IS NULL", userId).
+		Updates(map[string]interface{}{"read_at": readAt, "updated_at": readAt})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "postgresRepository.MarkAllRead")
+	}
+	return result.RowsAffected, nil
+}
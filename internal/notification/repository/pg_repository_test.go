@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+)
+
+var (
+	repository notification.PostgresRepository
+	mock       sqlmock.Sqlmock
+)
+
+// Test Before and After
+func TestMain(m *testing.M) {
+	// initialize mock sql db
+	mockDb, mocking, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("Failed to open mock sql db, got error %v", err)
+	}
+	defer mockDb.Close()
+	mock = mocking
+
+	// initialize db connection
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDb}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database, got error %v", err)
+	}
+
+	// initialize repository
+	repository = NewNotificationPgRepository(db)
+
+	os.Exit(m.Run())
+}
+
+func TestNotificationPostgresRepository_FindAllByUserId(t *testing.T) {
+	userId, expenseId := uuid.New(), uuid.New()
+
+	// define expect sql
+	rows := sqlmock.NewRows([]string{"id", "id_user", "kind", "title", "body", "data", "read_at", "created_at", "updated_at"}).
+		AddRow(uuid.New(), userId, model.KindSpendingAlert, "Unusual expense", "'laptop' is 4.0x the usual amount",
+			`{"expense_id":"`+expenseId.String()+`"}`, nil, 1767225600000, 1767225600000)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "notifications" WHERE id_user = $1 AND read_at IS NULL ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`)).
+		WithArgs(userId.String(), 10, 10).
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "notifications" WHERE id_user = $1 AND read_at IS NULL`)).
+		WithArgs(userId.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+	notifications, total, err := repository.FindAllByUserId(context.Background(), userId.String(), true, 2, 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(11), total)
+	require.Len(t, notifications, 1)
+	require.Equal(t, expenseId.String(), notifications[0].Data["expense_id"])
+	require.Nil(t, notifications[0].ReadAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationPostgresRepository_MarkAllRead(t *testing.T) {
+	userId := uuid.New()
+	readAt := int64(1767225600000)
+
+	// define expect sql
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "notifications" SET "read_at"=$1,"updated_at"=$2 WHERE id_user = $3 AND read_at IS NULL`)).
+		WithArgs(readAt, readAt, userId.String()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	count, err := repository.MarkAllRead(context.Background(), userId.String(), readAt)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
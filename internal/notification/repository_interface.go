@@ -0,0 +1,22 @@
+package notification
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	Create(ctx context.Context, notification *model.Notification) error
+
+	FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Notification, error)
+
+	// FindAllByUserId finds a page of the inbox of the user, the most recent first
+	FindAllByUserId(ctx context.Context, userId string, unreadOnly bool, page, limit int) ([]model.Notification, int64, error)
+
+	Update(ctx context.Context, notification *model.Notification) error
+
+	// MarkAllRead marks every unread notification of the user as read at readAt (unix milliseconds), it returns their number
+	MarkAllRead(ctx context.Context, userId string, readAt int64) (int64, error)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type ServiceConfig struct {
+	PgRepo   notification.PostgresRepository
+	Channels []notification.Channel
+	Config   *config.Config
+	Logger   *logrus.Logger
+}
+
+// notificationService acts as a struct for injecting an implementation of NotificationService interface
+// for use in service methods.
+type notificationService struct {
+	pgRepo   notification.PostgresRepository
+	channels []notification.Channel
+	logger   *logrus.Logger
+}
+
+// NewNotificationService is a factory function for
+// initializing a notificationService with its repository layer dependencies
+func NewNotificationService(config *ServiceConfig) notification.NotificationService {
+	return &notificationService{
+		pgRepo:   config.PgRepo,
+		channels: config.Channels,
+		logger:   config.Logger,
+	}
+}
+
+// Publish delivers the notification through the channels in their order, the in-app channel first so the
+// notification has its id for the others. a failing channel does not stop the next ones, it is only logged
+func (n *notificationService) Publish(ctx context.Context, request *model.Notification) error {
+	request.PrepareCreate()
+
+	delivered := 0
+	var last error
+	for _, channel := range n.channels {
+		if err := channel.Deliver(ctx, request); err != nil {
+			n.logger.WithError(err).
+				WithField("userId", request.UserId).
+				WithField("channel", channel.Name()).
+				Warn("notificationService.Publish.Deliver")
+			last = err
+			continue
+		}
+		delivered++
+	}
+
+	if delivered == 0 {
+		if last == nil {
+			last = errors.New("no delivery channel")
+		}
+		return httpErrors.NewInternalServerError(errors.Wrap(last, "notificationService.Publish"))
+	}
+	return nil
+}
+
+func (n *notificationService) GetAll(ctx context.Context, userId string, unreadOnly bool, page, limit int) ([]model.Notification, int64, error) {
+	notifications, total, err := n.pgRepo.FindAllByUserId(ctx, userId, unreadOnly, page, limit)
+	if err != nil {
+		return nil, 0, httpErrors.NewInternalServerError(err)
+	}
+	return notifications, total, nil
+}
+
+func (n *notificationService) MarkRead(ctx context.Context, id, userId string) (*model.Notification, error) {
+	found, err := n.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	if found.ReadAt != nil {
+		return found, nil
+	}
+
+	readAt := time.Now().UnixMilli()
+	found.ReadAt = &readAt
+	if err := n.pgRepo.Update(ctx, found); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return found, nil
+}
+
+func (n *notificationService) MarkAllRead(ctx context.Context, userId string) (int64, error) {
+	count, err := n.pgRepo.MarkAllRead(ctx, userId, time.Now().UnixMilli())
+	if err != nil {
+		return 0, httpErrors.NewInternalServerError(err)
+	}
+	return count, nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/notification/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var cfg = &config.Config{
+	Logger: config.LoggerConfig{Level: "panic"},
+}
+
+func TestNotificationService_Publish(t *testing.T) {
+	userId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] A failing channel does not stop the others", func(t *testing.T) {
+		inApp := &mockObject.ChannelMock{ChannelName: model.ChannelInApp}
+		email := &mockObject.ChannelMock{ChannelName: model.ChannelEmail}
+		webhook := &mockObject.ChannelMock{ChannelName: model.ChannelWebhook}
+		sv := NewNotificationService(&ServiceConfig{
+			Channels: []notification.Channel{inApp, email, webhook},
+			Logger:   logger.NewLogrusLogger(cfg),
+		})
+
+		inApp.On("Deliver", mock.Anything, mock.Anything).Return(nil)
+		email.On("Deliver", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+		webhook.On("Deliver", mock.Anything, mock.Anything).Return(nil)
+
+		request := &model.Notification{
+			UserId: userId,
+			Kind:   model.KindSecurityLogin,
+			Title:  "  New sign-in  ",
+			Body:   strings.Repeat("a", 600),
+		}
+		require.NoError(t, sv.Publish(context.Background(), request))
+		require.Equal(t, "New sign-in", request.Title)
+		require.Len(t, []rune(request.Body), 500)
+		require.NotNil(t, request.Data)
+		inApp.AssertExpectations(t)
+		email.AssertExpectations(t)
+		webhook.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return internal server error when no channel delivered", func(t *testing.T) {
+		inApp := &mockObject.ChannelMock{ChannelName: model.ChannelInApp}
+		sv := NewNotificationService(&ServiceConfig{Channels: []notification.Channel{inApp}, Logger: logger.NewLogrusLogger(cfg)})
+
+		inApp.On("Deliver", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+
+		err := sv.Publish(context.Background(), &model.Notification{UserId: userId, Kind: model.KindSpendingAlert})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusInternalServerError, er.Status)
+	})
+}
+
+func TestNotificationService_MarkRead(t *testing.T) {
+	userId, id := uuid.New(), uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] An unread notification is marked as read", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewNotificationService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindByIdAndUserId", mock.Anything, id.String(), userId.String()).
+			Return(&model.Notification{Id: id, UserId: userId}, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		response, err := sv.MarkRead(context.Background(), id.String(), userId.String())
+		require.NoError(t, err)
+		require.NotNil(t, response.ReadAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return not found when the notification is not the user's", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewNotificationService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindByIdAndUserId", mock.Anything, id.String(), userId.String()).Return(nil, errors.New("record not found"))
+
+		response, err := sv.MarkRead(context.Background(), id.String(), userId.String())
+		require.Nil(t, response)
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusNotFound, er.Status)
+		mockRepo.AssertNotCalled(t, "Update")
+	})
+}
@@ -0,0 +1,25 @@
+package notification
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/notification/model"
+)
+
+// Publisher is what the other modules publish notifications with, without knowing how they are delivered
+type Publisher interface {
+	// Publish delivers the notification through every channel, it fails only when no channel delivered it
+	Publish(ctx context.Context, notification *model.Notification) error
+}
+
+// NotificationService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type NotificationService interface {
+	Publisher
+
+	GetAll(ctx context.Context, userId string, unreadOnly bool, page, limit int) ([]model.Notification, int64, error)
+
+	MarkRead(ctx context.Context, id, userId string) (*model.Notification, error)
+
+	// MarkAllRead marks every unread notification of the user as read, it returns their number
+	MarkAllRead(ctx context.Context, userId string) (int64, error)
+}
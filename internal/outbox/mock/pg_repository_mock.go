@@ -0,0 +1,62 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockPostgresRepository is a mock type for outbox.PostgresRepository
+type MockPostgresRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostgresRepository) Create(ctx context.Context, event *model.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindPending(ctx context.Context, now int64, limit int) ([]model.Event, error) {
+	args := m.Called(ctx, now, limit)
+	if events, ok := args.Get(0).([]model.Event); ok {
+		return events, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) MarkPublished(ctx context.Context, ids []int64, publishedAt int64) error {
+	args := m.Called(ctx, ids, publishedAt)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) MarkFailed(ctx context.Context, event *model.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) DeletePublishedBefore(ctx context.Context, before int64) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPostgresRepository) TryLock(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+// Transaction records the call and runs fn without transaction, the error of the
+// expectation (if any) is returned instead of running fn
+func (m *MockPostgresRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	args := m.Called(ctx, fn)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(nil)
+}
+
+// WithTx returns the mock itself so expectations hold inside transactions
+func (m *MockPostgresRepository) WithTx(tx *gorm.DB) outbox.PostgresRepository {
+	return m
+}
@@ -0,0 +1,22 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// SubscriberMock is a mock type for outbox.Subscriber
+type SubscriberMock struct {
+	mock.Mock
+	SubscriberName string
+}
+
+func (m *SubscriberMock) Name() string {
+	return m.SubscriberName
+}
+
+func (m *SubscriberMock) Handle(ctx context.Context, event *model.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// types of aggregate, the events of one aggregate are dispatched in order
+const (
+	AggregateExpense = "expense"
+	AggregateUser    = "user"
+)
+
+// types of domain event
+const (
+	TypeExpenseCreated = "expense.created"
+	TypeExpenseUpdated = "expense.updated"
+	TypeExpenseDeleted = "expense.deleted"
+	TypeUserUpdated    = "user.updated"
+)
+
+// statuses of an event
+const (
+	StatusPending   = "pending"
+	StatusPublished = "published"
+	StatusDead      = "dead"
+)
+
+const (
+	// maxBackoff is the longest wait before a retry
+	maxBackoff = 10 * time.Minute
+
+	maxErrorLength = 500
+)
+
+// Event mapping table 'outbox_events'.
+// a change of an aggregate written along the change itself, Payload is what the subscribers receive
+type Event struct {
+	Id            int64           `json:"id" gorm:"column:id;primary_key;<-:create"` // allow read and create
+	AggregateType string          `json:"aggregate_type" gorm:"column:aggregate_type"`
+	AggregateId   uuid.UUID       `json:"aggregate_id" gorm:"column:aggregate_id"`
+	UserId        uuid.UUID       `json:"user_id" gorm:"column:id_user"`
+	Type          string          `json:"type" gorm:"column:type"`
+	Payload       json.RawMessage `json:"payload" gorm:"column:payload;type:jsonb"`
+	Status        string          `json:"status" gorm:"column:status"`
+	Handled       []string        `json:"handled" gorm:"column:handled;type:jsonb;serializer:json"`
+	Attempts      int             `json:"attempts" gorm:"column:attempts"`
+	NextAttemptAt int64           `json:"next_attempt_at" gorm:"column:next_attempt_at"`
+	LastError     string          `json:"last_error" gorm:"column:last_error"`
+	PublishedAt   *int64          `json:"published_at" gorm:"column:published_at"`
+	CreatedAt     int64           `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+}
+
+func (event *Event) TableName() string {
+	return "outbox_events"
+}
+
+// NewEvent is a pending event of the aggregate with data encoded in JSON, due at once
+func NewEvent(aggregateType string, aggregateId, userId uuid.UUID, eventType string, data interface{}) (*Event, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewEvent.json.Marshal")
+	}
+	return &Event{
+		AggregateType: aggregateType,
+		AggregateId:   aggregateId,
+		UserId:        userId,
+		Type:          eventType,
+		Payload:       payload,
+		Status:        StatusPending,
+		Handled:       []string{},
+		NextAttemptAt: time.Now().UnixMilli(),
+	}, nil
+}
+
+// Aggregate is the key of the aggregate of the event, its events are dispatched in order
+func (event *Event) Aggregate() string {
+	return event.AggregateType + ":" + event.AggregateId.String()
+}
+
+// HandledBy tells whether the subscriber already handled the event in a previous dispatch
+func (event *Event) HandledBy(subscriber string) bool {
+	for _, name := range event.Handled {
+		if name == subscriber {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle records the subscriber handled the event, a retry does not hand it the event again
+func (event *Event) Handle(subscriber string) {
+	if !event.HandledBy(subscriber) {
+		event.Handled = append(event.Handled, subscriber)
+	}
+}
+
+// Fail records a failed dispatch, the next one waits backoff doubled at every attempt.
+// the event is dead once maxAttempts are made, it then no longer blocks its aggregate
+func (event *Event) Fail(now time.Time, cause error, maxAttempts int, backoff time.Duration) {
+	event.Attempts++
+	event.LastError = utils.Truncate(cause.Error(), maxErrorLength)
+
+	if event.Attempts >= maxAttempts {
+		event.Status = StatusDead
+		return
+	}
+	event.NextAttemptAt = now.Add(utils.Backoff(backoff, event.Attempts, maxBackoff)).UnixMilli()
+}
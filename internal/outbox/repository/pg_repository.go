@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// dispatcherLock is the key of the advisory lock of the dispatcher, one instance dispatches at a time
+// so the events of an aggregate are never published out of order
+const dispatcherLock = 7300421
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxPgRepository(db *gorm.DB) outbox.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (p *postgresRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	/**
+	SQL: BEGIN; ...; COMMIT (or ROLLBACK when fn fails)
+	*/
+	DB := p.db.WithContext(ctx)
+	return DB.Transaction(fn)
+}
+
+func (p *postgresRepository) WithTx(tx *gorm.DB) outbox.PostgresRepository {
+	return &postgresRepository{
+		db: tx,
+	}
+}
+
+func (p *postgresRepository) Create(ctx context.Context, event *model.Event) error {
+	/**
+	SQL: INSERT INTO "outbox_events" ("aggregate_type","aggregate_id","id_user","type","payload","attempts","next_attempt_at",
+	"last_error","published_at","created_at") VALUES (?,?,?,?,?,?,?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(event).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Create")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindPending(ctx context.Context, now int64, limit int) ([]model.Event, error) {
+	/**
+	SQL: SELECT * FROM "outbox_events" WHERE status = ? AND (aggregate_type, aggregate_id) NOT IN
+	(SELECT aggregate_type, aggregate_id FROM outbox_events WHERE status = ? AND next_attempt_at > ?)
+	ORDER BY id LIMIT ?
+	*/
+	DB := p.db.WithContext(ctx)
+	var events []model.Event
+	if err := DB.Where("status = ? AND (aggregate_type, aggregate_id) NOT IN "+
+		"(SELECT aggregate_type, aggregate_id FROM outbox_events WHERE status = ? AND next_attempt_at > ?)",
+		model.StatusPending, model.StatusPending, now).
+		Order("id").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindPending")
+	}
+	return events, nil
+}
+
+func (p *postgresRepository) MarkPublished(ctx context.Context, ids []int64, publishedAt int64) error {
+	/**
+	SQL: UPDATE "outbox_events" SET "published_at"=?,"status"=? WHERE id IN (?)
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Model(&model.Event{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"published_at": publishedAt,
+		"status":       model.StatusPublished,
+	}).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.MarkPublished")
+	}
+	return nil
+}
+
+func (p *postgresRepository) MarkFailed(ctx context.Context, event *model.Event) error {
+	/**
+	SQL: UPDATE "outbox_events" SET "status"=?,"handled"=?,"attempts"=?,"next_attempt_at"=?,"last_error"=? WHERE "id" = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Model(event).
+		Select("status", "handled", "attempts", "next_attempt_at", "last_error").
+		Updates(event).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.MarkFailed")
+	}
+	return nil
+}
+
+func (p *postgresRepository) DeletePublishedBefore(ctx context.Context, before int64) (int64, error) {
+	/**
+	SQL: DELETE FROM "outbox_events" WHERE status = ? AND published_at < ?
+	*/
+	DB := p.db.WithContext(ctx)
+	result := DB.Where("status = ? AND published_at < ?", model.StatusPublished, before).Delete(&model.Event{})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "postgresRepository.DeletePublishedBefore")
+	}
+	return result.RowsAffected, nil
+}
+
+func (p *postgresRepository) TryLock(ctx context.Context) (bool, error) {
+	/**
+	SQL: SELECT pg_try_advisory_xact_lock(?)
+	*/
+	DB := p.db.WithContext(ctx)
+	var locked bool
+	if err := DB.Raw("SELECT pg_try_advisory_xact_lock(?)", dispatcherLock).Scan(&locked).Error; err != nil {
+		return false, errors.Wrap(err, "postgresRepository.TryLock")
+	}
+	return locked, nil
+}
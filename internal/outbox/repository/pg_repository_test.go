@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	repository outbox.PostgresRepository
+	mock       sqlmock.Sqlmock
+)
+
+// Test Before and After
+func TestMain(m *testing.M) {
+	// initialize mock sql db
+	mockDb, mocking, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("Failed to open mock sql db, got error %v", err)
+	}
+	defer mockDb.Close()
+	mock = mocking
+
+	// initialize db connection
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDb}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database, got error %v", err)
+	}
+
+	// initialize repository
+	repository = NewOutboxPgRepository(db)
+
+	os.Exit(m.Run())
+}
+
+func TestOutboxPostgresRepository_FindPending(t *testing.T) {
+	now := time.Now().UnixMilli()
+	aggregateId := uuid.New()
+
+	// define expect sql
+	rows := sqlmock.NewRows([]string{"id", "aggregate_type", "aggregate_id", "id_user", "type", "payload", "status", "handled",
+		"attempts", "next_attempt_at", "last_error", "published_at", "created_at"}).
+		AddRow(1, model.AggregateExpense, aggregateId, uuid.New(), model.TypeExpenseCreated, []byte(`{}`), model.StatusPending,
+			[]byte(`["realtime"]`), 1, now, "", nil, now)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox_events" WHERE status = $1 AND (aggregate_type, aggregate_id) NOT IN `+
+		`(SELECT aggregate_type, aggregate_id FROM outbox_events WHERE status = $2 AND next_attempt_at > $3) ORDER BY id LIMIT $4`)).
+		WithArgs(model.StatusPending, model.StatusPending, now, 100).
+		WillReturnRows(rows)
+
+	events, err := repository.FindPending(context.Background(), now, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "expense:"+aggregateId.String(), events[0].Aggregate())
+	require.True(t, events[0].HandledBy("realtime"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxPostgresRepository_MarkFailed(t *testing.T) {
+	event := &model.Event{Id: 1, Status: model.StatusDead, Handled: []string{"realtime"}, Attempts: 10,
+		NextAttemptAt: time.Now().UnixMilli(), LastError: "connection refused"}
+
+	// define expect sql
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox_events" SET "status"=$1,"handled"=$2,"attempts"=$3,"next_attempt_at"=$4,`+
+		`"last_error"=$5 WHERE "id" = $6`)).
+		WithArgs(model.StatusDead, `["realtime"]`, 10, event.NextAttemptAt, "connection refused", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repository.MarkFailed(context.Background(), event))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxPostgresRepository_TryLock(t *testing.T) {
+	// define expect sql
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_xact_lock($1)`)).
+		WithArgs(dispatcherLock).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(true))
+
+	locked, err := repository.TryLock(context.Background())
+	require.NoError(t, err)
+	require.True(t, locked)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxPostgresRepository_DeletePublishedBefore(t *testing.T) {
+	before := time.Now().UnixMilli()
+
+	// define expect sql
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "outbox_events" WHERE status = $1 AND published_at < $2`)).
+		WithArgs(model.StatusPublished, before).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	purged, err := repository.DeletePublishedBefore(context.Background(), before)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), purged)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
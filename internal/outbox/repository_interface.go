@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+	"gorm.io/gorm"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	// Create writes the event, given the transaction of the change with WithTx
+	Create(ctx context.Context, event *model.Event) error
+
+	// FindPending finds at most limit pending events by ascending id, the aggregates with a pending event
+	// not due at now (unix milliseconds) are left out so their events stay in order
+	FindPending(ctx context.Context, now int64, limit int) ([]model.Event, error)
+
+	MarkPublished(ctx context.Context, ids []int64, publishedAt int64) error
+
+	// MarkFailed saves the status, the subscribers which handled the event and the next attempt of a failed event
+	MarkFailed(ctx context.Context, event *model.Event) error
+
+	// DeletePublishedBefore deletes the events published before (unix milliseconds), it returns their number.
+	// the dead events are kept to be looked into
+	DeletePublishedBefore(ctx context.Context, before int64) (int64, error)
+
+	// TryLock takes the lock of the dispatcher until the end of the transaction, it returns
+	// false when another instance holds it. it only works with WithTx
+	TryLock(ctx context.Context) (bool, error)
+
+	// Transaction runs fn in one database transaction, committed when fn returns nil and
+	// rolled back otherwise. repositories given tx with WithTx run their queries in it
+	Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+
+	// WithTx returns a repository running its queries in the transaction tx
+	WithTx(tx *gorm.DB) PostgresRepository
+}
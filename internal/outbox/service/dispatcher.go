@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	// dispatchBatchSize is the number of pending events read at once
+	dispatchBatchSize = 100
+
+	defaultInterval    = time.Second
+	defaultMaxAttempts = 10
+	defaultBackoff     = 5 * time.Second
+)
+
+type ServiceConfig struct {
+	PgRepo outbox.PostgresRepository
+	Config *config.Config
+	Logger *logrus.Logger
+}
+
+// dispatcher acts as a struct for injecting an implementation of Dispatcher interface
+// for use in service methods.
+type dispatcher struct {
+	pgRepo      outbox.PostgresRepository
+	subscribers map[string][]outbox.Subscriber
+	interval    time.Duration
+	maxAttempts int
+	backoff     time.Duration
+	logger      *logrus.Logger
+}
+
+// NewDispatcher is a factory function for
+// initializing a dispatcher with its repository layer dependencies
+func NewDispatcher(config *ServiceConfig) outbox.Dispatcher {
+	service := &dispatcher{
+		pgRepo:      config.PgRepo,
+		subscribers: map[string][]outbox.Subscriber{},
+		interval:    defaultInterval,
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+		logger:      config.Logger,
+	}
+	if config.Config != nil {
+		if config.Config.Outbox.Interval > 0 {
+			service.interval = config.Config.Outbox.Interval * time.Second
+		}
+		if config.Config.Outbox.MaxAttempts > 0 {
+			service.maxAttempts = config.Config.Outbox.MaxAttempts
+		}
+		if config.Config.Outbox.Backoff > 0 {
+			service.backoff = config.Config.Outbox.Backoff * time.Second
+		}
+	}
+	return service
+}
+
+func (d *dispatcher) Subscribe(eventType string, subscriber outbox.Subscriber) {
+	d.subscribers[eventType] = append(d.subscribers[eventType], subscriber)
+}
+
+// Dispatch runs in one transaction holding the lock of the dispatcher, so a single instance dispatches
+// at a time. an event failing to dispatch blocks the next events of its aggregate until it is retried
+// or dead, the events are marked as published only once handed to every subscriber, at least once
+func (d *dispatcher) Dispatch(ctx context.Context) (int, error) {
+	published := 0
+	err := d.pgRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		txRepo := d.pgRepo.WithTx(tx)
+		locked, err := txRepo.TryLock(ctx)
+		if err != nil || !locked {
+			return err
+		}
+
+		now := time.Now()
+		events, err := txRepo.FindPending(ctx, now.UnixMilli(), dispatchBatchSize)
+		if err != nil {
+			return err
+		}
+
+		blocked := map[string]bool{}
+		ids := make([]int64, 0, len(events))
+		for i := range events {
+			event := &events[i]
+			if blocked[event.Aggregate()] {
+				continue
+			}
+			if err := d.handle(ctx, event); err != nil {
+				event.Fail(time.Now(), err, d.maxAttempts, d.backoff)
+				if err := txRepo.MarkFailed(ctx, event); err != nil {
+					return err
+				}
+				if event.Status == model.StatusDead {
+					d.logger.WithError(err).WithField("eventId", event.Id).Error("dispatcher.Dispatch.Dead")
+					continue
+				}
+				blocked[event.Aggregate()] = true
+				continue
+			}
+			ids = append(ids, event.Id)
+		}
+
+		if len(ids) > 0 {
+			if err := txRepo.MarkPublished(ctx, ids, now.UnixMilli()); err != nil {
+				return err
+			}
+		}
+		published = len(ids)
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "dispatcher.Dispatch.Transaction")
+	}
+	return published, nil
+}
+
+func (d *dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// a full batch means more events are probably pending
+			for {
+				published, err := d.Dispatch(ctx)
+				if err != nil {
+					d.logger.WithError(err).Warn("dispatcher.Run.Dispatch")
+				}
+				if err != nil || published < dispatchBatchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (d *dispatcher) Purge(ctx context.Context, before time.Time) (int64, error) {
+	purged, err := d.pgRepo.DeletePublishedBefore(ctx, before.UnixMilli())
+	if err != nil {
+		return 0, errors.Wrap(err, "dispatcher.Purge.DeletePublishedBefore")
+	}
+	return purged, nil
+}
+
+// handle hands the event to every subscriber of its type which did not handle it yet, a failing
+// subscriber does not stop the next ones and the first error is returned
+func (d *dispatcher) handle(ctx context.Context, event *model.Event) error {
+	var first error
+	for _, subscriber := range d.subscribers[event.Type] {
+		if event.HandledBy(subscriber.Name()) {
+			continue
+		}
+		if err := subscriber.Handle(ctx, event); err != nil {
+			d.logger.WithError(err).
+				WithField("eventId", event.Id).
+				WithField("subscriber", subscriber.Name()).
+				Warn("dispatcher.handle.Handle")
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		event.Handle(subscriber.Name())
+	}
+	return first
+}
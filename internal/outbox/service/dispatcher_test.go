@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/outbox/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var cfg = &config.Config{
+	Logger: config.LoggerConfig{Level: "panic"},
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	userId := uuid.New()
+	first, second := uuid.New(), uuid.New()
+	newEvent := func(id int64, aggregateId uuid.UUID, eventType string) model.Event {
+		return model.Event{Id: id, AggregateType: model.AggregateExpense, AggregateId: aggregateId, UserId: userId,
+			Type: eventType, Payload: json.RawMessage(`{}`)}
+	}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] A failing event blocks the next events of its aggregate only", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		subscriber := &mockObject.SubscriberMock{SubscriberName: "realtime"}
+		sv := NewDispatcher(&ServiceConfig{PgRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+		sv.Subscribe(model.TypeExpenseCreated, subscriber)
+		sv.Subscribe(model.TypeExpenseUpdated, subscriber)
+
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("TryLock", mock.Anything).Return(true, nil)
+		mockRepo.On("FindPending", mock.Anything, mock.Anything, dispatchBatchSize).Return([]model.Event{
+			newEvent(1, first, model.TypeExpenseCreated),
+			newEvent(2, second, model.TypeExpenseCreated),
+			newEvent(3, first, model.TypeExpenseUpdated),
+			newEvent(4, second, model.TypeExpenseUpdated),
+			newEvent(5, second, model.TypeUserUpdated),
+		}, nil)
+		subscriber.On("Handle", mock.Anything, mock.MatchedBy(func(event *model.Event) bool { return event.Id == 1 })).
+			Return(errors.New("connection refused")).Once()
+		subscriber.On("Handle", mock.Anything, mock.MatchedBy(func(event *model.Event) bool { return event.Id != 1 })).Return(nil)
+		mockRepo.On("MarkFailed", mock.Anything, mock.MatchedBy(func(event *model.Event) bool {
+			return event.Id == 1 && event.Attempts == 1 && event.NextAttemptAt > time.Now().UnixMilli()
+		})).Return(nil).Once()
+		// an event without subscriber is published too
+		mockRepo.On("MarkPublished", mock.Anything, []int64{2, 4, 5}, mock.Anything).Return(nil).Once()
+
+		published, err := sv.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 3, published)
+		subscriber.AssertNotCalled(t, "Handle", mock.Anything, mock.MatchedBy(func(event *model.Event) bool { return event.Id == 3 }))
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Nothing is dispatched while another instance holds the lock", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewDispatcher(&ServiceConfig{PgRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("TryLock", mock.Anything).Return(false, nil)
+
+		published, err := sv.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 0, published)
+		mockRepo.AssertNotCalled(t, "FindPending", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] A poison event is dead at its last attempt and no longer blocks its aggregate", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		subscriber := &mockObject.SubscriberMock{SubscriberName: "realtime"}
+		sv := NewDispatcher(&ServiceConfig{PgRepo: mockRepo, Logger: logger.NewLogrusLogger(cfg), Config: &config.Config{
+			Logger: cfg.Logger,
+			Outbox: config.OutboxConfig{MaxAttempts: 3},
+		}})
+		sv.Subscribe(model.TypeExpenseCreated, subscriber)
+		sv.Subscribe(model.TypeExpenseUpdated, subscriber)
+
+		poison := newEvent(1, first, model.TypeExpenseCreated)
+		poison.Attempts = 2
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("TryLock", mock.Anything).Return(true, nil)
+		mockRepo.On("FindPending", mock.Anything, mock.Anything, dispatchBatchSize).Return([]model.Event{
+			poison,
+			newEvent(2, first, model.TypeExpenseUpdated),
+		}, nil)
+		subscriber.On("Handle", mock.Anything, mock.MatchedBy(func(event *model.Event) bool { return event.Id == 1 })).
+			Return(errors.New("invalid payload")).Once()
+		subscriber.On("Handle", mock.Anything, mock.MatchedBy(func(event *model.Event) bool { return event.Id == 2 })).
+			Return(nil).Once()
+		mockRepo.On("MarkFailed", mock.Anything, mock.MatchedBy(func(event *model.Event) bool {
+			return event.Id == 1 && event.Attempts == 3 && event.Status == model.StatusDead && event.LastError == "invalid payload"
+		})).Return(nil).Once()
+		mockRepo.On("MarkPublished", mock.Anything, []int64{2}, mock.Anything).Return(nil).Once()
+
+		published, err := sv.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, published)
+		subscriber.AssertExpectations(t)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #4
+	t.Run("[Test Case #4] A retry only hands the event to the subscribers which did not handle it", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		realtime := &mockObject.SubscriberMock{SubscriberName: "realtime"}
+		webhook := &mockObject.SubscriberMock{SubscriberName: "webhook"}
+		sv := NewDispatcher(&ServiceConfig{PgRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+		sv.Subscribe(model.TypeExpenseCreated, realtime)
+		sv.Subscribe(model.TypeExpenseCreated, webhook)
+
+		event := newEvent(1, first, model.TypeExpenseCreated)
+		mockRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("TryLock", mock.Anything).Return(true, nil)
+		mockRepo.On("FindPending", mock.Anything, mock.Anything, dispatchBatchSize).Return([]model.Event{event}, nil).Once()
+		realtime.On("Handle", mock.Anything, mock.Anything).Return(nil).Once()
+		webhook.On("Handle", mock.Anything, mock.Anything).Return(errors.New("connection refused")).Once()
+		mockRepo.On("MarkFailed", mock.Anything, mock.MatchedBy(func(event *model.Event) bool {
+			return event.Status != model.StatusDead && len(event.Handled) == 1 && event.HandledBy("realtime")
+		})).Return(nil).Once()
+
+		published, err := sv.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 0, published)
+
+		// the retry reads the handled subscribers back
+		event.Attempts = 1
+		event.Handled = []string{"realtime"}
+		mockRepo.On("FindPending", mock.Anything, mock.Anything, dispatchBatchSize).Return([]model.Event{event}, nil).Once()
+		webhook.On("Handle", mock.Anything, mock.Anything).Return(nil).Once()
+		mockRepo.On("MarkPublished", mock.Anything, []int64{1}, mock.Anything).Return(nil).Once()
+
+		published, err = sv.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, published)
+		realtime.AssertNumberOfCalls(t, "Handle", 1)
+		webhook.AssertNumberOfCalls(t, "Handle", 2)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,34 @@
+package outbox
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+	"time"
+)
+
+// Subscriber is a side effect of the domain events, such as pushing them to the connected clients.
+// a retry only hands the event to the subscribers which failed, yet an event is delivered at least once:
+// it is handed again when the dispatch could not be saved, so Handle must be idempotent
+type Subscriber interface {
+	// Name identifies the subscriber in the logs and among the subscribers which handled an event,
+	// it is unique among the subscribers of an event type
+	Name() string
+
+	Handle(ctx context.Context, event *model.Event) error
+}
+
+// Dispatcher defines methods the server expects.
+// any dispatchers it interacts with to implement.
+type Dispatcher interface {
+	// Subscribe adds a subscriber to the events of the type, before the dispatcher runs
+	Subscribe(eventType string, subscriber Subscriber)
+
+	// Dispatch hands the pending events to their subscribers once, it returns the number of published events
+	Dispatch(ctx context.Context) (int, error)
+
+	// Run dispatches the pending events at every interval until ctx is done
+	Run(ctx context.Context)
+
+	// Purge deletes the events published before, it returns their number
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,27 @@
+package subscriber
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/event"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+)
+
+// publisherSubscriber hands the events to an event publisher, such as the connected
+// clients of the user or their webhooks
+type publisherSubscriber struct {
+	name      string
+	publisher event.Publisher
+}
+
+func NewPublisherSubscriber(name string, publisher event.Publisher) outbox.Subscriber {
+	return &publisherSubscriber{name: name, publisher: publisher}
+}
+
+func (s *publisherSubscriber) Name() string {
+	return s.name
+}
+
+func (s *publisherSubscriber) Handle(ctx context.Context, event *model.Event) error {
+	return s.publisher.Publish(ctx, event.UserId, event.Type, event.Payload)
+}
@@ -0,0 +1,19 @@
+package rule
+
+import "github.com/gin-gonic/gin"
+
+// RuleController defines methods the routes expects
+// any controllers it interacts with to implement
+type RuleController interface {
+	CreateRule() gin.HandlerFunc
+
+	UpdateRule() gin.HandlerFunc
+
+	DeleteRule() gin.HandlerFunc
+
+	GetRuleById() gin.HandlerFunc
+
+	GetAllRule() gin.HandlerFunc
+
+	ApplyRules() gin.HandlerFunc
+}
@@ -0,0 +1,17 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/internal/rule"
+)
+
+func MapRuleRoutes(ruleGroup *gin.RouterGroup, controller rule.RuleController, mw *middleware.MiddlewareManager) {
+	ruleGroup.Use(mw.AuthJwtMiddleware())
+	ruleGroup.POST("/", mw.IdempotencyMiddleware(), controller.CreateRule())
+	ruleGroup.GET("/", controller.GetAllRule())
+	ruleGroup.POST("/apply", mw.IdempotencyMiddleware(), controller.ApplyRules())
+	ruleGroup.GET("/:id", controller.GetRuleById())
+	ruleGroup.PATCH("/:id", mw.IdempotencyMiddleware(), controller.UpdateRule())
+	ruleGroup.DELETE("/:id", mw.IdempotencyMiddleware(), controller.DeleteRule())
+}
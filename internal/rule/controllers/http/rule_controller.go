@@ -0,0 +1,262 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/internal/rule"
+	"github.com/mrizkisaputra/expenses-api/internal/rule/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type ControllerConfig struct {
+	RuleService rule.RuleService
+	Logger      *logrus.Logger
+}
+
+// ruleController acts as a struct for injecting an implementation of RuleController interface
+// for use in controller methods
+type ruleController struct {
+	ruleService rule.RuleService
+	logger      *logrus.Logger
+}
+
+// NewRuleController is a factory function
+// initializing a ruleController with its service layer dependencies
+func NewRuleController(config *ControllerConfig) rule.RuleController {
+	return &ruleController{
+		ruleService: config.RuleService,
+		logger:      config.Logger,
+	}
+}
+
+// ruleRequest is the body of a rule, every field is optional on update
+type ruleRequest struct {
+	Name                string        `json:"name" validate:"omitempty,max=100"`
+	Priority            *int          `json:"priority"`
+	Enabled             *bool         `json:"enabled"`
+	DescriptionContains string        `json:"description_contains" validate:"omitempty,max=100"`
+	DescriptionRegex    string        `json:"description_regex" validate:"omitempty,max=200"`
+	MinAmount           *money.Amount `json:"min_amount" validate:"omitempty,gt=0"`
+	MaxAmount           *money.Amount `json:"max_amount" validate:"omitempty,gt=0"`
+	AccountId           string        `json:"account_id" validate:"omitempty,uuid"`
+	CategoryId          string        `json:"category_id" validate:"omitempty,uuid"`
+	AddTags             []string      `json:"add_tags" validate:"omitempty,max=20,dive,max=50"`
+	RenameDescription   string        `json:"rename_description" validate:"omitempty,max=255"`
+}
+
+func (request *ruleRequest) entity(userId uuid.UUID) *model.Rule {
+	return &model.Rule{
+		UserId:              userId,
+		Name:                request.Name,
+		Priority:            request.Priority,
+		Enabled:             request.Enabled,
+		DescriptionContains: request.DescriptionContains,
+		DescriptionRegex:    request.DescriptionRegex,
+		MinAmount:           request.MinAmount,
+		MaxAmount:           request.MaxAmount,
+		AccountId:           utils.ParseOptionalId(request.AccountId),
+		CategoryId:          utils.ParseOptionalId(request.CategoryId),
+		AddTags:             request.AddTags,
+		RenameDescription:   request.RenameDescription,
+	}
+}
+
+func (rc ruleController) CreateRule() gin.HandlerFunc {
+	type CreateRuleRequest struct {
+		ruleRequest
+		Name string `json:"name" validate:"required,max=100"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(CreateRuleRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := request.entity(auth.Id)
+		entity.Name = request.Name
+		response, err := rc.ruleService.Create(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, &expenseModel.ApiResponse{
+			Status:  http.StatusCreated,
+			Message: "Created",
+			Data:    response,
+		})
+	}
+}
+
+func (rc ruleController) UpdateRule() gin.HandlerFunc {
+	type UpdateRuleRequest struct {
+		ruleRequest
+		Id string `json:"-" validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(UpdateRuleRequest)
+		request.Id = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := request.entity(auth.Id)
+		entity.Id = uuid.MustParse(request.Id)
+		response, err := rc.ruleService.Update(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, &expenseModel.ApiResponse{
+			Status:  http.StatusAccepted,
+			Message: "Accepted",
+			Data:    response,
+		})
+	}
+}
+
+func (rc ruleController) DeleteRule() gin.HandlerFunc {
+	type DeleteRuleRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &DeleteRuleRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if err := rc.ruleService.Delete(ctx, request.Id, auth.Id.String()); err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusNoContent, &expenseModel.ApiResponse{
+			Status:  http.StatusNoContent,
+			Message: "No Content",
+		})
+	}
+}
+
+func (rc ruleController) GetRuleById() gin.HandlerFunc {
+	type GetRuleRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &GetRuleRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := rc.ruleService.GetById(ctx, request.Id, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (rc ruleController) GetAllRule() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		rules, err := rc.ruleService.GetAll(ctx, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       rules,
+			TotalItems: int64(len(rules)),
+		})
+	}
+}
+
+// ApplyRules applies the rules again to the past expenses, with dry_run it only reports the changes it would make
+func (rc ruleController) ApplyRules() gin.HandlerFunc {
+	type ApplyRulesRequest struct {
+		RuleIds   []string `json:"rule_ids" validate:"omitempty,max=100,dive,uuid"`
+		StartDate string   `json:"start_date" validate:"omitempty,datetime=2006-01-02"`
+		EndDate   string   `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+		DryRun    bool     `json:"dry_run"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(ApplyRulesRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.ApplyRequest{
+			UserId:    auth.Id,
+			StartDate: request.StartDate,
+			EndDate:   request.EndDate,
+			DryRun:    request.DryRun,
+		}
+		for _, id := range request.RuleIds {
+			entity.RuleIds = append(entity.RuleIds, uuid.MustParse(id))
+		}
+		response, err := rc.ruleService.Apply(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, rc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
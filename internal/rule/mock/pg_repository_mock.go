@@ -0,0 +1,59 @@
+package mock
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/rule/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPostgresRepository is a mock type for rule.PostgresRepository
+type MockPostgresRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostgresRepository) Create(ctx context.Context, rule *model.Rule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Rule, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Rule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Rule, error) {
+	args := m.Called(ctx, name, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Rule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindAllByUserId(ctx context.Context, userId string) ([]model.Rule, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Rule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindEnabledByUserId(ctx context.Context, userId string) ([]model.Rule, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Rule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) Update(ctx context.Context, rule *model.Rule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) Remove(ctx context.Context, rule *model.Rule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
@@ -0,0 +1,312 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"github.com/google/uuid"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	tagModel "github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+	"regexp"
+	"strings"
+)
+
+// Rule mapping table 'rules'.
+// a rule applies its actions to an expense matching every condition it sets, the description
+// conditions are case insensitive. rules are applied by ascending priority, then by creation
+type Rule struct {
+	Id       uuid.UUID `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	UserId   uuid.UUID `json:"user_id" gorm:"column:id_user"`
+	Name     string    `json:"name" gorm:"column:name"`
+	Priority *int      `json:"priority" gorm:"column:priority"`
+	Enabled  *bool     `json:"enabled" gorm:"column:enabled"`
+
+	// conditions
+	DescriptionContains string        `json:"description_contains" gorm:"column:description_contains"`
+	DescriptionRegex    string        `json:"description_regex" gorm:"column:description_regex"`
+	MinAmount           *money.Amount `json:"min_amount" gorm:"column:min_amount"`
+	MaxAmount           *money.Amount `json:"max_amount" gorm:"column:max_amount"`
+	AccountId           *uuid.UUID    `json:"account_id" gorm:"column:id_account"`
+
+	// actions
+	CategoryId        *uuid.UUID `json:"category_id" gorm:"column:id_category"`
+	AddTags           Tags       `json:"add_tags" gorm:"column:add_tags;type:jsonb"`
+	RenameDescription string     `json:"rename_description" gorm:"column:rename_description"`
+
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+
+	pattern *regexp.Regexp
+}
+
+func (rule *Rule) TableName() string {
+	return "rules"
+}
+
+func (rule *Rule) PrepareCreate() {
+	rule.Name = strings.Join(strings.Fields(rule.Name), " ")
+	rule.DescriptionContains = strings.ToLower(strings.TrimSpace(rule.DescriptionContains))
+	rule.RenameDescription = strings.ToLower(strings.TrimSpace(rule.RenameDescription))
+	rule.AddTags = tagModel.NormalizeNames(rule.AddTags)
+	if rule.Priority == nil {
+		rule.Priority = new(int)
+	}
+	if rule.Enabled == nil {
+		enabled := true
+		rule.Enabled = &enabled
+	}
+}
+
+func (rule *Rule) PrepareUpdate(oldRule *Rule) {
+	if rule.Name != "" {
+		oldRule.Name = strings.Join(strings.Fields(rule.Name), " ")
+	}
+
+	if rule.Priority != nil {
+		oldRule.Priority = rule.Priority
+	}
+
+	if rule.Enabled != nil {
+		oldRule.Enabled = rule.Enabled
+	}
+
+	if rule.DescriptionContains != "" {
+		oldRule.DescriptionContains = strings.ToLower(strings.TrimSpace(rule.DescriptionContains))
+	}
+
+	if rule.DescriptionRegex != "" {
+		oldRule.DescriptionRegex = rule.DescriptionRegex
+		oldRule.pattern = nil
+	}
+
+	if rule.MinAmount != nil {
+		oldRule.MinAmount = rule.MinAmount
+	}
+
+	if rule.MaxAmount != nil {
+		oldRule.MaxAmount = rule.MaxAmount
+	}
+
+	if rule.AccountId != nil {
+		oldRule.AccountId = rule.AccountId
+	}
+
+	if rule.CategoryId != nil {
+		oldRule.CategoryId = rule.CategoryId
+	}
+
+	if rule.AddTags != nil {
+		oldRule.AddTags = tagModel.NormalizeNames(rule.AddTags)
+	}
+
+	if rule.RenameDescription != "" {
+		oldRule.RenameDescription = strings.ToLower(strings.TrimSpace(rule.RenameDescription))
+	}
+}
+
+// Validate checks the rule sets at least one condition and one action, its regular
+// expression compiles and its tags are valid names
+func (rule *Rule) Validate() error {
+	if rule.DescriptionContains == "" && rule.DescriptionRegex == "" &&
+		rule.MinAmount == nil && rule.MaxAmount == nil && rule.AccountId == nil {
+		return errors.New("rule has no condition")
+	}
+	if rule.CategoryId == nil && len(rule.AddTags) == 0 && rule.RenameDescription == "" {
+		return errors.New("rule has no action")
+	}
+	if rule.MinAmount != nil && rule.MaxAmount != nil && *rule.MinAmount > *rule.MaxAmount {
+		return errors.New("min_amount is greater than max_amount")
+	}
+	for _, name := range rule.AddTags {
+		if !tagModel.ValidName(name) {
+			return errors.Errorf("invalid tag '%s'", name)
+		}
+	}
+	return rule.compile()
+}
+
+func (rule *Rule) compile() error {
+	if rule.pattern != nil || rule.DescriptionRegex == "" {
+		return nil
+	}
+	pattern, err := regexp.Compile("(?i)" + rule.DescriptionRegex)
+	if err != nil {
+		return errors.Wrap(err, "Rule.compile")
+	}
+	rule.pattern = pattern
+	return nil
+}
+
+// Matches reports whether the expense matches every condition of the rule,
+// a rule whose regular expression does not compile matches nothing
+func (rule *Rule) Matches(expense *expenseModel.Expense) bool {
+	description := strings.ToLower(expense.Description)
+	if rule.DescriptionContains != "" && !strings.Contains(description, rule.DescriptionContains) {
+		return false
+	}
+	if rule.DescriptionRegex != "" {
+		if err := rule.compile(); err != nil || !rule.pattern.MatchString(description) {
+			return false
+		}
+	}
+	if rule.MinAmount != nil && (expense.Amount == nil || *expense.Amount < *rule.MinAmount) {
+		return false
+	}
+	if rule.MaxAmount != nil && (expense.Amount == nil || *expense.Amount > *rule.MaxAmount) {
+		return false
+	}
+	if rule.AccountId != nil && (expense.AccountId == nil || *expense.AccountId != *rule.AccountId) {
+		return false
+	}
+	return true
+}
+
+// Apply applies the enabled rules, in their order, to the expense: the first matching rule setting
+// the category or the description sets it, the tags of every matching rule are added.
+// the conditions are matched against the expense as it was before any rule applied,
+// it returns the ids of the matching rules
+func Apply(rules []Rule, expense *expenseModel.Expense) []uuid.UUID {
+	original := *expense
+	categorySet, descriptionSet := false, false
+
+	var matched []uuid.UUID
+	for i := range rules {
+		rule := &rules[i]
+		if (rule.Enabled != nil && !*rule.Enabled) || !rule.Matches(&original) {
+			continue
+		}
+		matched = append(matched, rule.Id)
+
+		if rule.CategoryId != nil && !categorySet {
+			expense.CategoryId = *rule.CategoryId
+			categorySet = true
+		}
+		if rule.RenameDescription != "" && !descriptionSet {
+			expense.Description = rule.RenameDescription
+			descriptionSet = true
+		}
+		if len(rule.AddTags) > 0 {
+			expense.Tags = tagModel.NormalizeNames(append(append([]string{}, expense.Tags...), rule.AddTags...))
+		}
+	}
+	return matched
+}
+
+// Tags are the names of the tags a rule adds, stored as a json array
+type Tags []string
+
+func (tags Tags) Value() (driver.Value, error) {
+	if tags == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(tags))
+}
+
+func (tags *Tags) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return errors.Errorf("Tags.Scan: unsupported type %T", src)
+	}
+	return json.Unmarshal(data, tags)
+}
+
+// ApplyRequest selects the past expenses the rules are applied to again, all of them
+// without range. only the rules RuleIds are applied when given, and nothing is changed on DryRun
+type ApplyRequest struct {
+	UserId    uuid.UUID
+	RuleIds   []uuid.UUID
+	StartDate string
+	EndDate   string
+	DryRun    bool
+}
+
+// ExpenseChange is the change the rules make to an expense, the fields are only set when they change.
+// Error is set when applying the change failed
+type ExpenseChange struct {
+	ExpenseId       uuid.UUID   `json:"expense_id"`
+	RuleIds         []uuid.UUID `json:"rule_ids"`
+	Description     string      `json:"description"`
+	NewDescription  *string     `json:"new_description,omitempty"`
+	CategoryId      uuid.UUID   `json:"category_id"`
+	NewCategoryId   *uuid.UUID  `json:"new_category_id,omitempty"`
+	AddedTags       []string    `json:"added_tags,omitempty"`
+	Error           string      `json:"error,omitempty"`
+	updatedTags     []string
+	expectedVersion int64
+}
+
+// ApplyResult reports the past expenses checked, matched and changed by the rules
+type ApplyResult struct {
+	DryRun  bool            `json:"dry_run"`
+	Checked int             `json:"checked"`
+	Matched int             `json:"matched"`
+	Changed int             `json:"changed"`
+	Failed  int             `json:"failed"`
+	Changes []ExpenseChange `json:"changes"`
+}
+
+// Diff applies the rules to a copy of the expense and returns the resulting change,
+// nil when no rule matches. a change without new field only matched
+func Diff(rules []Rule, expense *expenseModel.Expense) *ExpenseChange {
+	updated := *expense
+	matched := Apply(rules, &updated)
+	if len(matched) == 0 {
+		return nil
+	}
+
+	change := &ExpenseChange{
+		ExpenseId:       expense.Id,
+		RuleIds:         matched,
+		Description:     expense.Description,
+		CategoryId:      expense.CategoryId,
+		expectedVersion: expense.Version,
+	}
+	if updated.Description != expense.Description {
+		change.NewDescription = &updated.Description
+	}
+	if updated.CategoryId != expense.CategoryId {
+		change.NewCategoryId = &updated.CategoryId
+	}
+
+	existing := make(map[string]bool, len(expense.Tags))
+	for _, name := range expense.Tags {
+		existing[name] = true
+	}
+	for _, name := range updated.Tags {
+		if !existing[name] {
+			change.AddedTags = append(change.AddedTags, name)
+		}
+	}
+	if len(change.AddedTags) > 0 {
+		change.updatedTags = updated.Tags
+	}
+	return change
+}
+
+// Changes reports whether the change sets any field
+func (change *ExpenseChange) Changes() bool {
+	return change.NewDescription != nil || change.NewCategoryId != nil || len(change.AddedTags) > 0
+}
+
+// Update is the partial update of the expense making the change
+func (change *ExpenseChange) Update(userId uuid.UUID) *expenseModel.Expense {
+	update := &expenseModel.Expense{
+		Id:      change.ExpenseId,
+		UserId:  userId,
+		Version: change.expectedVersion,
+		Tags:    change.updatedTags,
+	}
+	if change.NewDescription != nil {
+		update.Description = *change.NewDescription
+	}
+	if change.NewCategoryId != nil {
+		update.CategoryId = *change.NewCategoryId
+	}
+	return update
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	accountId := uuid.New()
+	transportId := uuid.New()
+	foodId := uuid.New()
+	minAmount := money.Amount(1000000)
+	enabled, disabled := true, false
+
+	rules := []Rule{
+		{Id: uuid.New(), Enabled: &enabled, DescriptionRegex: `^(grab|gojek)\b`, CategoryId: &transportId, AddTags: Tags{"ride"}},
+		{Id: uuid.New(), Enabled: &disabled, DescriptionContains: "grab", RenameDescription: "disabled"},
+		{Id: uuid.New(), Enabled: &enabled, DescriptionContains: "food", CategoryId: &foodId, AddTags: Tags{"food", "ride"}},
+		{Id: uuid.New(), Enabled: &enabled, MinAmount: &minAmount, AccountId: &accountId, AddTags: Tags{"big"}},
+	}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] The first matching rule sets the category and the tags of every matching rule are added", func(t *testing.T) {
+		amount := money.Amount(5000000)
+		expense := &expenseModel.Expense{Description: "grab food senayan", Amount: &amount, AccountId: &accountId, Tags: []string{"jakarta"}}
+
+		matched := Apply(rules, expense)
+		require.Equal(t, []uuid.UUID{rules[0].Id, rules[2].Id, rules[3].Id}, matched)
+		require.Equal(t, transportId, expense.CategoryId)
+		require.Equal(t, "grab food senayan", expense.Description)
+		require.Equal(t, []string{"jakarta", "ride", "food", "big"}, expense.Tags)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Rules not matching every condition are skipped", func(t *testing.T) {
+		amount := money.Amount(5000000)
+		expense := &expenseModel.Expense{Description: "Ojek Grab", Amount: &amount}
+
+		matched := Apply(rules, expense)
+		require.Empty(t, matched)
+		require.Nil(t, expense.Tags)
+	})
+}
+
+func TestDiff(t *testing.T) {
+	categoryId := uuid.New()
+	rules := []Rule{{Id: uuid.New(), DescriptionContains: "netflix", CategoryId: &categoryId, AddTags: Tags{"subscription"}}}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] A matching rule with nothing to change only matches", func(t *testing.T) {
+		expense := &expenseModel.Expense{Id: uuid.New(), Description: "netflix", CategoryId: categoryId, Tags: []string{"subscription"}}
+
+		change := Diff(rules, expense)
+		require.NotNil(t, change)
+		require.False(t, change.Changes())
+		require.Nil(t, change.Update(uuid.New()).Tags)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Diff leaves the expense unchanged", func(t *testing.T) {
+		expense := &expenseModel.Expense{Id: uuid.New(), Description: "netflix", CategoryId: uuid.New(), Version: 2}
+
+		change := Diff(rules, expense)
+		require.True(t, change.Changes())
+		require.Nil(t, expense.Tags)
+		update := change.Update(uuid.New())
+		require.Equal(t, categoryId, update.CategoryId)
+		require.Equal(t, []string{"subscription"}, update.Tags)
+		require.Equal(t, int64(2), update.Version)
+	})
+}
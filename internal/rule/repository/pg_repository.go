@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/rule"
+	"github.com/mrizkisaputra/expenses-api/internal/rule/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewRulePgRepository(db *gorm.DB) rule.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (p *postgresRepository) Create(ctx context.Context, rule *model.Rule) error {
+	/**
+	SQL: INSERT INTO "rules" ("id_user","name","priority","enabled","description_contains","description_regex","min_amount",
+	"max_amount","id_account","id_category","add_tags","rename_description","created_at","updated_at")
+	VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(rule).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Create")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Rule, error) {
+	/**
+	SQL: SELECT * FROM "rules" WHERE id = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	rule := new(model.Rule)
+	if err := DB.Where("id = ? AND id_user = ?", id, userId).Take(rule).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByIdAndUserId")
+	}
+	return rule, nil
+}
+
+func (p *postgresRepository) FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Rule, error) {
+	/**
+	SQL: SELECT * FROM "rules" WHERE name = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	rule := new(model.Rule)
+	if err := DB.Where("name = ? AND id_user = ?", name, userId).Take(rule).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByNameAndUserId")
+	}
+	return rule, nil
+}
+
+func (p *postgresRepository) FindAllByUserId(ctx context.Context, userId string) ([]model.Rule, error) {
+	/**
+	SQL: SELECT * FROM "rules" WHERE id_user = ? ORDER BY priority, created_at, id
+	*/
+	DB := p.db.WithContext(ctx)
+	var rules []model.Rule
+	if err := DB.Where("id_user = ?", userId).Order("priority, created_at, id").Find(&rules).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindAllByUserId")
+	}
+	return rules, nil
+}
+
+func (p *postgresRepository) FindEnabledByUserId(ctx context.Context, userId string) ([]model.Rule, error) {
+	/**
+	SQL: SELECT * FROM "rules" WHERE id_user = ? AND enabled ORDER BY priority, created_at, id
+	*/
+	DB := p.db.WithContext(ctx)
+	var rules []model.Rule
+	if err := DB.Where("id_user = ? AND enabled", userId).Order("priority, created_at, id").Find(&rules).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindEnabledByUserId")
+	}
+	return rules, nil
+}
+
+func (p *postgresRepository) Update(ctx context.Context, rule *model.Rule) error {
+	/**
+	SQL: UPDATE "rules" SET "id_user"=?,"name"=?,"priority"=?,"enabled"=?,...,"updated_at"=?
+	WHERE (id = ? AND id_user = ?) AND "id" = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", rule.Id, rule.UserId).Updates(rule).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Update")
+	}
+	return nil
+}
+
+func (p *postgresRepository) Remove(ctx context.Context, rule *model.Rule) error {
+	/**
+	SQL: DELETE FROM "rules" WHERE id = ? AND id_user = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", rule.Id, rule.UserId).Delete(&model.Rule{}).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Remove")
+	}
+	return nil
+}
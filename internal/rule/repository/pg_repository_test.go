@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/rule"
+	"github.com/mrizkisaputra/expenses-api/internal/rule/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	repository rule.PostgresRepository
+	mock       sqlmock.Sqlmock
+)
+
+// Test Before and After
+func TestMain(m *testing.M) {
+	// initialize mock sql db
+	mockDb, mocking, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("Failed to open mock sql db, got error %v", err)
+	}
+	defer mockDb.Close()
+	mock = mocking
+
+	// initialize db connection
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDb}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database, got error %v", err)
+	}
+
+	// initialize repository
+	repository = NewRulePgRepository(db)
+
+	os.Exit(m.Run())
+}
+
+func TestRulePostgresRepository_FindEnabledByUserId(t *testing.T) {
+	userId := uuid.New()
+	categoryId := uuid.New()
+
+	// define expect sql
+	rows := sqlmock.NewRows([]string{"id", "id_user", "name", "priority", "enabled", "description_contains", "description_regex",
+		"min_amount", "max_amount", "id_account", "id_category", "add_tags", "rename_description", "created_at", "updated_at"}).
+		AddRow(uuid.New(), userId, "grab", 0, true, "grab", "", nil, nil, nil, categoryId, []byte(`["ride"]`), "", time.Now().UnixMilli(), time.Now().UnixMilli())
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "rules" WHERE id_user = $1 AND enabled ORDER BY priority, created_at, id`)).
+		WithArgs(userId.String()).
+		WillReturnRows(rows)
+
+	rules, err := repository.FindEnabledByUserId(context.Background(), userId.String())
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, model.Tags{"ride"}, rules[0].AddTags)
+	require.Equal(t, categoryId, *rules[0].CategoryId)
+	require.Nil(t, rules[0].MinAmount)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,26 @@
+package rule
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/rule/model"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	Create(ctx context.Context, rule *model.Rule) error
+
+	FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Rule, error)
+
+	FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Rule, error)
+
+	// FindAllByUserId finds the rules of the user in the order they are applied
+	FindAllByUserId(ctx context.Context, userId string) ([]model.Rule, error)
+
+	// FindEnabledByUserId finds the enabled rules of the user in the order they are applied
+	FindEnabledByUserId(ctx context.Context, userId string) ([]model.Rule, error)
+
+	Update(ctx context.Context, rule *model.Rule) error
+
+	Remove(ctx context.Context, rule *model.Rule) error
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/account"
+	"github.com/mrizkisaputra/expenses-api/internal/category"
+	categoryModel "github.com/mrizkisaputra/expenses-api/internal/category/model"
+	"github.com/mrizkisaputra/expenses-api/internal/expense"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/rule"
+	"github.com/mrizkisaputra/expenses-api/internal/rule/model"
+	"github.com/mrizkisaputra/expenses-api/internal/tag"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+// applyBatchSize is the number of past expenses read at once when applying the rules again
+const applyBatchSize = 200
+
+type ServiceConfig struct {
+	PgRepo         rule.PostgresRepository
+	ExpensePgRepo  expense.PostgresRepository
+	TagPgRepo      tag.PostgresRepository
+	CategoryPgRepo category.PostgresRepository
+	AccountPgRepo  account.PostgresRepository
+	ExpenseService expense.ExpenseService
+	Config         *config.Config
+	Logger         *logrus.Logger
+}
+
+// ruleService acts as a struct for injecting an implementation of RuleService interface
+// for use in service methods.
+type ruleService struct {
+	pgRepo         rule.PostgresRepository
+	expensePgRepo  expense.PostgresRepository
+	tagPgRepo      tag.PostgresRepository
+	categoryPgRepo category.PostgresRepository
+	accountPgRepo  account.PostgresRepository
+	expenseService expense.ExpenseService
+	logger         *logrus.Logger
+}
+
+// NewRuleService is a factory function for
+// initializing a ruleService with its repository layer dependencies
+func NewRuleService(config *ServiceConfig) rule.RuleService {
+	return &ruleService{
+		pgRepo:         config.PgRepo,
+		expensePgRepo:  config.ExpensePgRepo,
+		tagPgRepo:      config.TagPgRepo,
+		categoryPgRepo: config.CategoryPgRepo,
+		accountPgRepo:  config.AccountPgRepo,
+		expenseService: config.ExpenseService,
+		logger:         config.Logger,
+	}
+}
+
+func (r *ruleService) Create(ctx context.Context, request *model.Rule) (*model.Rule, error) {
+	request.PrepareCreate()
+
+	if err := request.Validate(); err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidRuleMsg, err)
+	}
+	if err := r.ensureUniqueName(ctx, request); err != nil {
+		return nil, err
+	}
+	if err := r.ensureReferences(ctx, request); err != nil {
+		return nil, err
+	}
+
+	if err := r.pgRepo.Create(ctx, request); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return request, nil
+}
+
+func (r *ruleService) Update(ctx context.Context, request *model.Rule) (*model.Rule, error) {
+	found, err := r.pgRepo.FindByIdAndUserId(ctx, request.Id.String(), request.UserId.String())
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+
+	request.PrepareUpdate(found)
+	if err := found.Validate(); err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidRuleMsg, err)
+	}
+	if err := r.ensureUniqueName(ctx, found); err != nil {
+		return nil, err
+	}
+	if err := r.ensureReferences(ctx, request); err != nil {
+		return nil, err
+	}
+
+	if err := r.pgRepo.Update(ctx, found); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return found, nil
+}
+
+func (r *ruleService) Delete(ctx context.Context, id, userId string) error {
+	found, err := r.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return httpErrors.NewNotFoundError(err)
+	}
+
+	if err := r.pgRepo.Remove(ctx, found); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+func (r *ruleService) GetById(ctx context.Context, id, userId string) (*model.Rule, error) {
+	found, err := r.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	return found, nil
+}
+
+func (r *ruleService) GetAll(ctx context.Context, userId string) ([]model.Rule, error) {
+	rules, err := r.pgRepo.FindAllByUserId(ctx, userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return rules, nil
+}
+
+// Apply walks the past expenses of the range from the most recent one and applies the rules to each of them.
+// the changes are made through the expense service, so they are validated, versioned and recorded in the
+// history like any update, an expense failing to change is reported with its error and the others go on
+func (r *ruleService) Apply(ctx context.Context, request *model.ApplyRequest) (*model.ApplyResult, error) {
+	filter, err := applyFilter(request)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := r.rulesToApply(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ApplyResult{DryRun: request.DryRun, Changes: []model.ExpenseChange{}}
+	userId := request.UserId.String()
+	var after *expenseModel.ExpenseCursor
+	for len(rules) > 0 {
+		expenses, err := r.expensePgRepo.FindAllAfter(ctx, userId, filter, &expenseModel.DefaultExpenseSort, after, applyBatchSize)
+		if err != nil {
+			return nil, httpErrors.NewInternalServerError(err)
+		}
+		if err := r.attachTags(ctx, expenses); err != nil {
+			return nil, err
+		}
+
+		for i := range expenses {
+			result.Checked++
+			change := model.Diff(rules, &expenses[i])
+			if change == nil {
+				continue
+			}
+			result.Matched++
+			if !change.Changes() {
+				continue
+			}
+
+			if !request.DryRun {
+				if _, err := r.expenseService.Update(ctx, change.Update(request.UserId)); err != nil {
+					r.logger.WithError(err).WithField("expenseId", change.ExpenseId).Debug("ruleService.Apply.Update")
+					change.Error = errorMessage(err)
+					result.Failed++
+					result.Changes = append(result.Changes, *change)
+					continue
+				}
+			}
+			result.Changed++
+			result.Changes = append(result.Changes, *change)
+		}
+
+		if len(expenses) < applyBatchSize {
+			break
+		}
+		after = expenseModel.NewExpenseCursor(expenseModel.DefaultExpenseSort, &expenses[len(expenses)-1])
+	}
+	return result, nil
+}
+
+// rulesToApply returns the enabled rules of the user, or the rules RuleIds of the request even when they are
+// disabled, so that a rule can be tried with a dry run before enabling it
+func (r *ruleService) rulesToApply(ctx context.Context, request *model.ApplyRequest) ([]model.Rule, error) {
+	if len(request.RuleIds) == 0 {
+		rules, err := r.pgRepo.FindEnabledByUserId(ctx, request.UserId.String())
+		if err != nil {
+			return nil, httpErrors.NewInternalServerError(err)
+		}
+		return rules, nil
+	}
+
+	all, err := r.pgRepo.FindAllByUserId(ctx, request.UserId.String())
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	selected := make(map[uuid.UUID]bool, len(request.RuleIds))
+	for _, id := range request.RuleIds {
+		selected[id] = true
+	}
+
+	enabled := true
+	rules := make([]model.Rule, 0, len(selected))
+	for _, found := range all {
+		if selected[found.Id] {
+			found.Enabled = &enabled
+			rules = append(rules, found)
+		}
+	}
+	if len(rules) != len(selected) {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.UnknownRuleMsg, nil)
+	}
+	return rules, nil
+}
+
+// applyFilter narrows the expenses to the days of the request, both included
+func applyFilter(request *model.ApplyRequest) (*expenseModel.ExpenseFilter, error) {
+	filter := new(expenseModel.ExpenseFilter)
+	if request.StartDate != "" {
+		start, err := time.Parse("2006-01-02", request.StartDate)
+		if err != nil {
+			return nil, httpErrors.NewBadRequestError(errors.Wrap(err, "ruleService.applyFilter.time.Parse"))
+		}
+		filter.CreatedFrom = start.Unix()
+	}
+	if request.EndDate != "" {
+		end, err := time.Parse("2006-01-02", request.EndDate)
+		if err != nil {
+			return nil, httpErrors.NewBadRequestError(errors.Wrap(err, "ruleService.applyFilter.time.Parse"))
+		}
+		filter.CreatedTo = end.AddDate(0, 0, 1).Unix() - 1
+	}
+	if filter.CreatedFrom != 0 && filter.CreatedTo != 0 && filter.CreatedFrom > filter.CreatedTo {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidRuleApplyMsg, nil)
+	}
+	return filter, nil
+}
+
+// attachTags fills the tag names of every expense, the rules only add the tags an expense does not have yet
+func (r *ruleService) attachTags(ctx context.Context, expenses []expenseModel.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(expenses))
+	for i := range expenses {
+		ids[i] = expenses[i].Id
+	}
+	tagged, err := r.tagPgRepo.FindByExpenseIds(ctx, ids)
+	if err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+
+	names := make(map[uuid.UUID][]string, len(expenses))
+	for _, t := range tagged {
+		names[t.ExpenseId] = append(names[t.ExpenseId], t.Name)
+	}
+	for i := range expenses {
+		expenses[i].Tags = names[expenses[i].Id]
+	}
+	return nil
+}
+
+// errorMessage is the message of an error safe to report to the user
+func errorMessage(err error) string {
+	var er *httpErrors.Error
+	if errors.As(err, &er) {
+		return er.Message
+	}
+	return httpErrors.InternalServerErrorMsg
+}
+
+func (r *ruleService) ensureUniqueName(ctx context.Context, request *model.Rule) error {
+	found, err := r.pgRepo.FindByNameAndUserId(ctx, request.Name, request.UserId.String())
+	if err == nil && found != nil && found.Id != request.Id {
+		return httpErrors.NewError(http.StatusConflict, httpErrors.RuleAlreadyExistsMsg, nil)
+	}
+	return nil
+}
+
+// ensureReferences ensures the category and the account the request refers to, if any, belong to the user,
+// the category must be an expense category
+func (r *ruleService) ensureReferences(ctx context.Context, request *model.Rule) error {
+	if request.CategoryId != nil {
+		found, err := r.categoryPgRepo.FindByIdAndUserId(ctx, request.CategoryId.String(), request.UserId.String())
+		if err != nil {
+			return httpErrors.NewError(http.StatusBadRequest, httpErrors.UnknownCategoryMsg, err)
+		}
+		if found.Kind == categoryModel.KindIncome {
+			return httpErrors.NewError(http.StatusBadRequest, httpErrors.CategoryKindMismatchMsg, nil)
+		}
+	}
+
+	if request.AccountId != nil {
+		if _, err := r.accountPgRepo.FindByIdAndUserId(ctx, request.AccountId.String(), request.UserId.String()); err != nil {
+			return httpErrors.NewError(http.StatusBadRequest, httpErrors.UnknownAccountMsg, err)
+		}
+	}
+	return nil
+}
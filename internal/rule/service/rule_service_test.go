@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	expenseMock "github.com/mrizkisaputra/expenses-api/internal/expense/mock"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/rule/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/rule/model"
+	tagMock "github.com/mrizkisaputra/expenses-api/internal/tag/mock"
+	tagModel "github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+var cfg = &config.Config{
+	Logger: config.LoggerConfig{Level: "panic"},
+}
+
+func TestRuleService_Create(t *testing.T) {
+	userId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Should reject a rule with an invalid regular expression", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewRuleService(&ServiceConfig{PgRepo: mockRepo})
+
+		_, err := sv.Create(context.Background(), &model.Rule{
+			UserId:            userId,
+			Name:              "grab",
+			DescriptionRegex:  "grab(",
+			RenameDescription: "grab ride",
+		})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.InvalidRuleMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should reject a rule without action", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewRuleService(&ServiceConfig{PgRepo: mockRepo})
+
+		_, err := sv.Create(context.Background(), &model.Rule{UserId: userId, Name: "grab", DescriptionContains: "grab"})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, httpErrors.InvalidRuleMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestRuleService_Apply(t *testing.T) {
+	userId := uuid.New()
+	categoryId := uuid.New()
+	transportId := uuid.New()
+	amount := money.Amount(2500000)
+	grab := expenseModel.Expense{Id: uuid.New(), UserId: userId, Description: "grab jkt", Amount: &amount, CategoryId: categoryId, Version: 3}
+	coffee := expenseModel.Expense{Id: uuid.New(), UserId: userId, Description: "coffee", Amount: &amount, CategoryId: categoryId, Version: 1}
+	rules := []model.Rule{
+		{Id: uuid.New(), DescriptionContains: "grab", CategoryId: &transportId, AddTags: model.Tags{"ride"}},
+	}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Dry run reports the changes without making them", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockExpenseRepo := new(expenseMock.MockPostgresRepository)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		mockExpenseService := new(expenseMock.ExpenseServiceMock)
+		sv := NewRuleService(&ServiceConfig{
+			PgRepo:         mockRepo,
+			ExpensePgRepo:  mockExpenseRepo,
+			TagPgRepo:      mockTagRepo,
+			ExpenseService: mockExpenseService,
+			Logger:         logger.NewLogrusLogger(cfg),
+		})
+
+		mockRepo.On("FindEnabledByUserId", mock.Anything, userId.String()).Return(rules, nil)
+		mockExpenseRepo.On("FindAllAfter", mock.Anything, userId.String(), &expenseModel.ExpenseFilter{CreatedFrom: 1704067200, CreatedTo: 1706745599},
+			&expenseModel.DefaultExpenseSort, (*expenseModel.ExpenseCursor)(nil), applyBatchSize).
+			Return([]expenseModel.Expense{grab, coffee}, nil)
+		mockTagRepo.On("FindByExpenseIds", mock.Anything, []uuid.UUID{grab.Id, coffee.Id}).
+			Return([]tagModel.TaggedExpense{{ExpenseId: grab.Id, Name: "jakarta"}}, nil)
+
+		result, err := sv.Apply(context.Background(), &model.ApplyRequest{
+			UserId:    userId,
+			StartDate: "2024-01-01",
+			EndDate:   "2024-01-31",
+			DryRun:    true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Checked)
+		require.Equal(t, 1, result.Matched)
+		require.Equal(t, 1, result.Changed)
+		require.Len(t, result.Changes, 1)
+		require.Equal(t, grab.Id, result.Changes[0].ExpenseId)
+		require.Equal(t, transportId, *result.Changes[0].NewCategoryId)
+		require.Nil(t, result.Changes[0].NewDescription)
+		require.Equal(t, []string{"ride"}, result.Changes[0].AddedTags)
+		mockExpenseService.AssertNotCalled(t, "Update")
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Changes are made through the expense service at the version read", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockExpenseRepo := new(expenseMock.MockPostgresRepository)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		mockExpenseService := new(expenseMock.ExpenseServiceMock)
+		sv := NewRuleService(&ServiceConfig{
+			PgRepo:         mockRepo,
+			ExpensePgRepo:  mockExpenseRepo,
+			TagPgRepo:      mockTagRepo,
+			ExpenseService: mockExpenseService,
+			Logger:         logger.NewLogrusLogger(cfg),
+		})
+
+		mockRepo.On("FindEnabledByUserId", mock.Anything, userId.String()).Return(rules, nil)
+		mockExpenseRepo.On("FindAllAfter", mock.Anything, userId.String(), mock.Anything, mock.Anything, mock.Anything, applyBatchSize).
+			Return([]expenseModel.Expense{grab}, nil)
+		mockTagRepo.On("FindByExpenseIds", mock.Anything, mock.Anything).
+			Return([]tagModel.TaggedExpense{{ExpenseId: grab.Id, Name: "jakarta"}}, nil)
+		mockExpenseService.On("Update", mock.Anything, &expenseModel.Expense{
+			Id:         grab.Id,
+			UserId:     userId,
+			CategoryId: transportId,
+			Tags:       []string{"jakarta", "ride"},
+			Version:    3,
+		}).Return(nil, httpErrors.NewError(http.StatusPreconditionFailed, httpErrors.PreconditionFailedMsg, nil))
+
+		result, err := sv.Apply(context.Background(), &model.ApplyRequest{UserId: userId})
+		require.NoError(t, err)
+		require.Equal(t, 0, result.Changed)
+		require.Equal(t, 1, result.Failed)
+		require.Equal(t, httpErrors.PreconditionFailedMsg, result.Changes[0].Error)
+		mockExpenseService.AssertExpectations(t)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Should reject a rule of another user", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockExpenseRepo := new(expenseMock.MockPostgresRepository)
+		sv := NewRuleService(&ServiceConfig{PgRepo: mockRepo, ExpensePgRepo: mockExpenseRepo})
+
+		mockRepo.On("FindAllByUserId", mock.Anything, userId.String()).Return(rules, nil)
+
+		_, err := sv.Apply(context.Background(), &model.ApplyRequest{UserId: userId, RuleIds: []uuid.UUID{uuid.New()}})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.UnknownRuleMsg, er.Message)
+		mockExpenseRepo.AssertNotCalled(t, "FindAllAfter")
+	})
+}
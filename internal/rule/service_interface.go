@@ -0,0 +1,23 @@
+package rule
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/rule/model"
+)
+
+// RuleService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type RuleService interface {
+	Create(ctx context.Context, request *model.Rule) (*model.Rule, error)
+
+	Update(ctx context.Context, request *model.Rule) (*model.Rule, error)
+
+	Delete(ctx context.Context, id, userId string) error
+
+	GetById(ctx context.Context, id, userId string) (*model.Rule, error)
+
+	GetAll(ctx context.Context, userId string) ([]model.Rule, error)
+
+	// Apply applies the rules again to the past expenses, on dry run it only reports the changes it would make
+	Apply(ctx context.Context, request *model.ApplyRequest) (*model.ApplyResult, error)
+}
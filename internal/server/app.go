@@ -1,16 +1,73 @@
 package server
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
+	accountController "github.com/mrizkisaputra/expenses-api/internal/account/controllers/http"
+	accountRoute "github.com/mrizkisaputra/expenses-api/internal/account/controllers/http"
+	accountRepository "github.com/mrizkisaputra/expenses-api/internal/account/repository"
+	accountService "github.com/mrizkisaputra/expenses-api/internal/account/service"
+	alertController "github.com/mrizkisaputra/expenses-api/internal/alert/controllers/http"
+	alertRoute "github.com/mrizkisaputra/expenses-api/internal/alert/controllers/http"
+	alertRepository "github.com/mrizkisaputra/expenses-api/internal/alert/repository"
+	alertService "github.com/mrizkisaputra/expenses-api/internal/alert/service"
+	categoryController "github.com/mrizkisaputra/expenses-api/internal/category/controllers/http"
+	categoryRoute "github.com/mrizkisaputra/expenses-api/internal/category/controllers/http"
+	categoryRepository "github.com/mrizkisaputra/expenses-api/internal/category/repository"
+	categoryService "github.com/mrizkisaputra/expenses-api/internal/category/service"
+	currencyController "github.com/mrizkisaputra/expenses-api/internal/currency/controllers/http"
+	currencyRoute "github.com/mrizkisaputra/expenses-api/internal/currency/controllers/http"
+	currencyRepository "github.com/mrizkisaputra/expenses-api/internal/currency/repository"
+	currencyService "github.com/mrizkisaputra/expenses-api/internal/currency/service"
+	eventController "github.com/mrizkisaputra/expenses-api/internal/event/controllers/http"
+	eventRoute "github.com/mrizkisaputra/expenses-api/internal/event/controllers/http"
+	eventRepository "github.com/mrizkisaputra/expenses-api/internal/event/repository"
+	eventService "github.com/mrizkisaputra/expenses-api/internal/event/service"
 	expenseController "github.com/mrizkisaputra/expenses-api/internal/expense/controllers/http"
 	expenseRoute "github.com/mrizkisaputra/expenses-api/internal/expense/controllers/http"
 	expenseRepository "github.com/mrizkisaputra/expenses-api/internal/expense/repository"
 	expenseService "github.com/mrizkisaputra/expenses-api/internal/expense/service"
+	goalController "github.com/mrizkisaputra/expenses-api/internal/goal/controllers/http"
+	goalRoute "github.com/mrizkisaputra/expenses-api/internal/goal/controllers/http"
+	goalRepository "github.com/mrizkisaputra/expenses-api/internal/goal/repository"
+	goalService "github.com/mrizkisaputra/expenses-api/internal/goal/service"
+	incomeController "github.com/mrizkisaputra/expenses-api/internal/income/controllers/http"
+	incomeRoute "github.com/mrizkisaputra/expenses-api/internal/income/controllers/http"
+	incomeRepository "github.com/mrizkisaputra/expenses-api/internal/income/repository"
+	incomeService "github.com/mrizkisaputra/expenses-api/internal/income/service"
+	jobRepository "github.com/mrizkisaputra/expenses-api/internal/job/repository"
+	jobService "github.com/mrizkisaputra/expenses-api/internal/job/service"
 	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	notificationChannel "github.com/mrizkisaputra/expenses-api/internal/notification/channel"
+	notificationController "github.com/mrizkisaputra/expenses-api/internal/notification/controllers/http"
+	notificationRoute "github.com/mrizkisaputra/expenses-api/internal/notification/controllers/http"
+	notificationRepository "github.com/mrizkisaputra/expenses-api/internal/notification/repository"
+	notificationService "github.com/mrizkisaputra/expenses-api/internal/notification/service"
+	outboxRepository "github.com/mrizkisaputra/expenses-api/internal/outbox/repository"
+	ruleController "github.com/mrizkisaputra/expenses-api/internal/rule/controllers/http"
+	ruleRoute "github.com/mrizkisaputra/expenses-api/internal/rule/controllers/http"
+	ruleRepository "github.com/mrizkisaputra/expenses-api/internal/rule/repository"
+	ruleService "github.com/mrizkisaputra/expenses-api/internal/rule/service"
+	splitController "github.com/mrizkisaputra/expenses-api/internal/split/controllers/http"
+	splitRoute "github.com/mrizkisaputra/expenses-api/internal/split/controllers/http"
+	splitRepository "github.com/mrizkisaputra/expenses-api/internal/split/repository"
+	splitService "github.com/mrizkisaputra/expenses-api/internal/split/service"
+	syncController "github.com/mrizkisaputra/expenses-api/internal/sync/controllers/http"
+	syncRoute "github.com/mrizkisaputra/expenses-api/internal/sync/controllers/http"
+	syncRepository "github.com/mrizkisaputra/expenses-api/internal/sync/repository"
+	syncService "github.com/mrizkisaputra/expenses-api/internal/sync/service"
+	tagController "github.com/mrizkisaputra/expenses-api/internal/tag/controllers/http"
+	tagRoute "github.com/mrizkisaputra/expenses-api/internal/tag/controllers/http"
+	tagRepository "github.com/mrizkisaputra/expenses-api/internal/tag/repository"
+	tagService "github.com/mrizkisaputra/expenses-api/internal/tag/service"
 	userController "github.com/mrizkisaputra/expenses-api/internal/user/controllers/http"
 	userRoute "github.com/mrizkisaputra/expenses-api/internal/user/controllers/http"
 	userRepository "github.com/mrizkisaputra/expenses-api/internal/user/repository"
 	userService "github.com/mrizkisaputra/expenses-api/internal/user/service"
+	webhookController "github.com/mrizkisaputra/expenses-api/internal/webhook/controllers/http"
+	webhookRoute "github.com/mrizkisaputra/expenses-api/internal/webhook/controllers/http"
+	webhookRepository "github.com/mrizkisaputra/expenses-api/internal/webhook/repository"
+	webhookService "github.com/mrizkisaputra/expenses-api/internal/webhook/service"
 	"net/http"
 )
 
@@ -23,11 +80,69 @@ func (s *Server) Bootstrap() error {
 
 	expenseRepo := expenseRepository.NewExpensePgRepository(s.db)
 
+	splitRepo := splitRepository.NewSplitPgRepository(s.db)
+
+	exchangeRateRepo := currencyRepository.NewExchangeRatePgRepository(s.db)
+
+	categoryRepo := categoryRepository.NewCategoryPgRepository(s.db)
+
+	tagRepo := tagRepository.NewTagPgRepository(s.db)
+
+	syncRepo := syncRepository.NewSyncPgRepository(s.db)
+
+	incomeRepo := incomeRepository.NewIncomePgRepository(s.db)
+
+	accountRepo := accountRepository.NewAccountPgRepository(s.db)
+
+	goalRepo := goalRepository.NewGoalPgRepository(s.db)
+
+	ruleRepo := ruleRepository.NewRulePgRepository(s.db)
+
+	alertRepo := alertRepository.NewAlertPgRepository(s.db)
+
+	notificationRepo := notificationRepository.NewNotificationPgRepository(s.db)
+
+	eventRepo := eventRepository.NewEventRedisRepository(s.redisClient, s.cfg.Events.History)
+
+	webhookRepo := webhookRepository.NewWebhookPgRepository(s.db)
+
+	outboxRepo := outboxRepository.NewOutboxPgRepository(s.db)
+
+	jobRepo := jobRepository.NewJobRedisRepository(s.redisClient)
+
 	// -----------------------------------------------------------------------------------------------------------
 	// create a new instance services
+	// the jobs are queued to the worker (cmd/worker), they are not run while a request is served
+	jobQueue := jobService.NewQueue(&jobService.ServiceConfig{
+		RedisRepo: jobRepo,
+		Logger:    s.logger,
+		Config:    s.cfg,
+	})
+
+	eventSV := eventService.NewEventService(&eventService.ServiceConfig{
+		RedisRepo: eventRepo,
+		Logger:    s.logger,
+		Config:    s.cfg,
+	})
+
+	webhookSV := webhookService.NewWebhookService(&webhookService.ServiceConfig{
+		PgRepo: webhookRepo,
+		Logger: s.logger,
+		Config: s.cfg,
+	})
+
+	notificationSV := notificationService.NewNotificationService(&notificationService.ServiceConfig{
+		PgRepo:   notificationRepo,
+		Channels: notificationChannel.NewChannels(s.cfg, notificationRepo, userPostgresRepo, eventSV, jobQueue, s.logger),
+		Logger:   s.logger,
+		Config:   s.cfg,
+	})
+
 	authSV := userService.NewAuthService(&userService.ServiceConfig{
 		Config:                 s.cfg,
+		Logger:                 s.logger,
 		UserPostgresRepository: userPostgresRepo,
+		Publisher:              notificationSV,
 	})
 	userSV := userService.NewUserService(&userService.ServiceConfig{
 		Config:                 s.cfg,
@@ -35,14 +150,113 @@ func (s *Server) Bootstrap() error {
 		UserPostgresRepository: userPostgresRepo,
 		UserRedisRepository:    userRedisRepo,
 		AwsUserRepository:      userAwsRepo,
+		OutboxRepository:       outboxRepo,
+	})
+
+	exchangeRateSV := currencyService.NewExchangeRateService(&currencyService.ServiceConfig{
+		PgRepo: exchangeRateRepo,
+		Logger: s.logger,
+		Config: s.cfg,
+	})
+
+	// import the exchange rates file configured for this environment
+	if s.cfg.Currency.RatesFile != "" {
+		total, err := exchangeRateSV.LoadFile(context.Background(), s.cfg.Currency.RatesFile)
+		if err != nil {
+			s.logger.WithError(err).Error("Exchange rates file import failed")
+		} else {
+			s.logger.Infof("Imported %d exchange rates from %s", total, s.cfg.Currency.RatesFile)
+		}
+	}
+
+	alertSV := alertService.NewAlertService(&alertService.ServiceConfig{
+		PgRepo:    alertRepo,
+		Publisher: notificationSV,
+		Logger:    s.logger,
+		Config:    s.cfg,
 	})
 
 	expenseSV := expenseService.NewExpenseService(&expenseService.ServiceConfig{
-		PgRepo: expenseRepo,
+		PgRepo:              expenseRepo,
+		UserPgRepo:          userPostgresRepo,
+		CategoryPgRepo:      categoryRepo,
+		TagPgRepo:           tagRepo,
+		AccountPgRepo:       accountRepo,
+		RulePgRepo:          ruleRepo,
+		ExchangeRateService: exchangeRateSV,
+		AlertService:        alertSV,
+		OutboxRepo:          outboxRepo,
+		Logger:              s.logger,
+		Config:              s.cfg,
+	})
+
+	categorySV := categoryService.NewCategoryService(&categoryService.ServiceConfig{
+		PgRepo: categoryRepo,
 		Logger: s.logger,
 		Config: s.cfg,
 	})
 
+	tagSV := tagService.NewTagService(&tagService.ServiceConfig{
+		PgRepo: tagRepo,
+		Logger: s.logger,
+		Config: s.cfg,
+	})
+
+	syncSV := syncService.NewSyncService(&syncService.ServiceConfig{
+		PgRepo:         syncRepo,
+		TagPgRepo:      tagRepo,
+		ExpenseService: expenseSV,
+		Logger:         s.logger,
+		Config:         s.cfg,
+	})
+
+	incomeSV := incomeService.NewIncomeService(&incomeService.ServiceConfig{
+		PgRepo:              incomeRepo,
+		ExpensePgRepo:       expenseRepo,
+		UserPgRepo:          userPostgresRepo,
+		CategoryPgRepo:      categoryRepo,
+		AccountPgRepo:       accountRepo,
+		ExchangeRateService: exchangeRateSV,
+		Logger:              s.logger,
+		Config:              s.cfg,
+	})
+
+	accountSV := accountService.NewAccountService(&accountService.ServiceConfig{
+		PgRepo:              accountRepo,
+		UserPgRepo:          userPostgresRepo,
+		ExchangeRateService: exchangeRateSV,
+		Logger:              s.logger,
+		Config:              s.cfg,
+	})
+
+	goalSV := goalService.NewGoalService(&goalService.ServiceConfig{
+		PgRepo:              goalRepo,
+		AccountPgRepo:       accountRepo,
+		UserPgRepo:          userPostgresRepo,
+		ExchangeRateService: exchangeRateSV,
+		Logger:              s.logger,
+		Config:              s.cfg,
+	})
+
+	ruleSV := ruleService.NewRuleService(&ruleService.ServiceConfig{
+		PgRepo:         ruleRepo,
+		ExpensePgRepo:  expenseRepo,
+		TagPgRepo:      tagRepo,
+		CategoryPgRepo: categoryRepo,
+		AccountPgRepo:  accountRepo,
+		ExpenseService: expenseSV,
+		Logger:         s.logger,
+		Config:         s.cfg,
+	})
+
+	splitSV := splitService.NewSplitService(&splitService.ServiceConfig{
+		PgRepo:        splitRepo,
+		ExpensePgRepo: expenseRepo,
+		UserPgRepo:    userPostgresRepo,
+		Logger:        s.logger,
+		Config:        s.cfg,
+	})
+
 	// -----------------------------------------------------------------------------------------------------------
 	// create a new instance controllers
 	authController := userController.NewAuthController(&userController.ControllerConfig{
@@ -58,14 +272,82 @@ func (s *Server) Bootstrap() error {
 
 	expenseCntrl := expenseController.NewExpenseController(&expenseController.ControllerConfig{
 		ExpenseService: expenseSV,
+		Config:         s.cfg,
 		Logger:         s.logger,
 	})
 
+	splitCntrl := splitController.NewSplitController(&splitController.ControllerConfig{
+		SplitService: splitSV,
+		Logger:       s.logger,
+	})
+
+	categoryCntrl := categoryController.NewCategoryController(&categoryController.ControllerConfig{
+		CategoryService: categorySV,
+		Logger:          s.logger,
+	})
+
+	tagCntrl := tagController.NewTagController(&tagController.ControllerConfig{
+		TagService: tagSV,
+		Logger:     s.logger,
+	})
+
+	syncCntrl := syncController.NewSyncController(&syncController.ControllerConfig{
+		SyncService: syncSV,
+		Logger:      s.logger,
+	})
+
+	incomeCntrl := incomeController.NewIncomeController(&incomeController.ControllerConfig{
+		IncomeService: incomeSV,
+		Logger:        s.logger,
+	})
+
+	accountCntrl := accountController.NewAccountController(&accountController.ControllerConfig{
+		AccountService: accountSV,
+		Logger:         s.logger,
+	})
+
+	goalCntrl := goalController.NewGoalController(&goalController.ControllerConfig{
+		GoalService: goalSV,
+		Logger:      s.logger,
+	})
+
+	ruleCntrl := ruleController.NewRuleController(&ruleController.ControllerConfig{
+		RuleService: ruleSV,
+		Logger:      s.logger,
+	})
+
+	alertCntrl := alertController.NewAlertController(&alertController.ControllerConfig{
+		AlertService: alertSV,
+		Logger:       s.logger,
+	})
+
+	notificationCntrl := notificationController.NewNotificationController(&notificationController.ControllerConfig{
+		NotificationService: notificationSV,
+		Logger:              s.logger,
+	})
+
+	eventCntrl := eventController.NewEventController(&eventController.ControllerConfig{
+		EventService: eventSV,
+		Logger:       s.logger,
+		Config:       s.cfg,
+	})
+
+	webhookCntrl := webhookController.NewWebhookController(&webhookController.ControllerConfig{
+		WebhookService: webhookSV,
+		Logger:         s.logger,
+	})
+
+	exchangeRateCntrl := currencyController.NewExchangeRateController(&currencyController.ControllerConfig{
+		ExchangeRateService: exchangeRateSV,
+		Logger:              s.logger,
+	})
+
 	// -----------------------------------------------------------------------------------------------------------
 	// create a new instance middleware
 	middlewareManager := middleware.NewMiddlewareManager(&middleware.MiddlewareConfig{
-		Logger: s.logger,
-		Config: s.cfg,
+		Logger:      s.logger,
+		Config:      s.cfg,
+		RedisClient: s.redisClient,
 	})
 
 	s.app.Use(middlewareManager.RequestIdMiddleware())
@@ -87,6 +369,88 @@ func (s *Server) Bootstrap() error {
 		{
 			expenseRoute.MapExpenseRoutes(expenseGroup, expenseCntrl, middlewareManager)
 		}
+
+		// group split and settle-up routes, under the prefix of the expense routes
+		splitGroup := apiV1.Group("/expenses")
+		{
+			splitRoute.MapSplitRoutes(splitGroup, splitCntrl, middlewareManager)
+		}
+
+		// group category routes
+		categoryGroup := apiV1.Group("/categories")
+		{
+			categoryRoute.MapCategoryRoutes(categoryGroup, categoryCntrl, middlewareManager)
+		}
+
+		// group tag routes
+		tagGroup := apiV1.Group("/tags")
+		{
+			tagRoute.MapTagRoutes(tagGroup, tagCntrl, middlewareManager)
+		}
+
+		// group sync routes
+		syncGroup := apiV1.Group("/sync")
+		{
+			syncRoute.MapSyncRoutes(syncGroup, syncCntrl, middlewareManager)
+		}
+
+		// group income and cash flow routes
+		incomeGroup := apiV1.Group("/incomes")
+		{
+			incomeRoute.MapIncomeRoutes(incomeGroup, incomeCntrl, middlewareManager)
+		}
+		cashFlowGroup := apiV1.Group("/cash-flow")
+		{
+			incomeRoute.MapCashFlowRoutes(cashFlowGroup, incomeCntrl, middlewareManager)
+		}
+
+		// group account, transfer and balance routes
+		accountGroup := apiV1.Group("/accounts")
+		{
+			accountRoute.MapAccountRoutes(accountGroup, accountCntrl, middlewareManager)
+		}
+
+		// group savings goal routes
+		goalGroup := apiV1.Group("/goals")
+		{
+			goalRoute.MapGoalRoutes(goalGroup, goalCntrl, middlewareManager)
+		}
+
+		// group expense rule routes
+		ruleGroup := apiV1.Group("/rules")
+		{
+			ruleRoute.MapRuleRoutes(ruleGroup, ruleCntrl, middlewareManager)
+		}
+
+		// group spending alert routes
+		alertGroup := apiV1.Group("/alerts")
+		{
+			alertRoute.MapAlertRoutes(alertGroup, alertCntrl, middlewareManager)
+		}
+
+		// group notification routes
+		notificationGroup := apiV1.Group("/notifications")
+		{
+			notificationRoute.MapNotificationRoutes(notificationGroup, notificationCntrl, middlewareManager)
+		}
+
+		// group real-time event routes
+		eventGroup := apiV1.Group("/events")
+		{
+			eventRoute.MapEventRoutes(eventGroup, eventCntrl, middlewareManager)
+		}
+
+		// group outgoing webhook routes
+		webhookGroup := apiV1.Group("/webhooks")
+		{
+			webhookRoute.MapWebhookRoutes(webhookGroup, webhookCntrl, middlewareManager)
+		}
+
+		// group exchange rate routes
+		exchangeRateGroup := apiV1.Group("/exchange-rates")
+		{
+			currencyRoute.MapExchangeRateRoutes(exchangeRateGroup, exchangeRateCntrl, middlewareManager)
+		}
 	}
 
 	apiV1.GET("/ping", middlewareManager.AuthJwtMiddleware(), func(ctx *gin.Context) {
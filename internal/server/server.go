@@ -6,10 +6,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/minio/minio-go/v7"
 	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/pkg/contextutils"
 	"github.com/pkg/errors"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -63,6 +65,14 @@ func (s *Server) Run() error {
 		Handler:      s.app,
 	}
 
+	// the shutdown does not cancel the requests in progress, the streams end on this context instead
+	shutdown, stopStreams := context.WithCancel(context.Background())
+	defer stopStreams()
+	server.BaseContext = func(net.Listener) context.Context {
+		return contextutils.WithShutdown(context.Background(), shutdown)
+	}
+	server.RegisterOnShutdown(stopStreams)
+
 	// setup semua komponen aplikasi
 	if err := s.Bootstrap(); err != nil {
 		return errors.Wrap(err, "Server.Run.Bootstrap")
@@ -76,7 +86,7 @@ func (s *Server) Run() error {
 		}()
 
 		// listen signal interrupt/terminate from os
-		quit := make(chan os.Signal)
+		quit := make(chan os.Signal, 1)
 		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
 		select {
@@ -0,0 +1,19 @@
+package split
+
+import "github.com/gin-gonic/gin"
+
+// SplitController defines methods the routes expects
+// any controllers it interacts with to implement
+type SplitController interface {
+	SplitExpense() gin.HandlerFunc
+
+	GetExpenseSplits() gin.HandlerFunc
+
+	DeleteExpenseSplits() gin.HandlerFunc
+
+	GetBalances() gin.HandlerFunc
+
+	CreateSettlement() gin.HandlerFunc
+
+	GetAllSettlement() gin.HandlerFunc
+}
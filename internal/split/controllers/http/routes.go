@@ -0,0 +1,18 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/internal/split"
+)
+
+// MapSplitRoutes maps the split and settle-up routes, splitGroup shares the prefix of the expense routes
+func MapSplitRoutes(splitGroup *gin.RouterGroup, controller split.SplitController, mw *middleware.MiddlewareManager) {
+	splitGroup.Use(mw.AuthJwtMiddleware())
+	splitGroup.POST("/:id/splits", mw.IdempotencyMiddleware(), controller.SplitExpense())
+	splitGroup.GET("/:id/splits", controller.GetExpenseSplits())
+	splitGroup.DELETE("/:id/splits", mw.IdempotencyMiddleware(), controller.DeleteExpenseSplits())
+	splitGroup.GET("/balances", controller.GetBalances())
+	splitGroup.POST("/settlements", mw.IdempotencyMiddleware(), controller.CreateSettlement())
+	splitGroup.GET("/settlements", controller.GetAllSettlement())
+}
@@ -0,0 +1,285 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/internal/split"
+	"github.com/mrizkisaputra/expenses-api/internal/split/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"math"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+type ControllerConfig struct {
+	SplitService split.SplitService
+	Logger       *logrus.Logger
+}
+
+// splitController acts as a struct for injecting an implementation of SplitController interface
+// for use in controller methods
+type splitController struct {
+	splitService split.SplitService
+	logger       *logrus.Logger
+}
+
+// NewSplitController is a factory function
+// initializing a splitController with its service layer dependencies
+func NewSplitController(config *ControllerConfig) split.SplitController {
+	return &splitController{
+		splitService: config.SplitService,
+		logger:       config.Logger,
+	}
+}
+
+func (sc splitController) SplitExpense() gin.HandlerFunc {
+	type ParticipantRequest struct {
+		UserId string       `json:"user_id" validate:"required,uuid"`
+		Value  *json.Number `json:"value" validate:"omitempty,numeric"`
+	}
+	type SplitExpenseRequest struct {
+		Id           string               `json:"-" validate:"required,uuid"`
+		Method       string               `json:"method" validate:"required,oneof=equal exact percentage shares"`
+		Participants []ParticipantRequest `json:"participants" validate:"required,min=1,dive"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(SplitExpenseRequest)
+		request.Id = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		// values are read as exact rationals, a float would already have lost precision
+		participants := make([]model.Participant, len(request.Participants))
+		for i, p := range request.Participants {
+			participants[i] = model.Participant{UserId: uuid.MustParse(p.UserId)}
+			if p.Value != nil {
+				value, ok := new(big.Rat).SetString(p.Value.String())
+				if !ok {
+					err := httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidSplitMsg, model.ErrInvalidValue)
+					utils.LogErrorResponse(ctx, sc.logger, err)
+					ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+					return
+				}
+				participants[i].Value = value
+			}
+		}
+
+		response, err := sc.splitService.SplitExpense(ctx, auth.Id.String(), request.Id, request.Method, participants)
+		if err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, &expenseModel.ApiResponse{
+			Status:  http.StatusCreated,
+			Message: "Created",
+			Data:    response,
+		})
+	}
+}
+
+func (sc splitController) GetExpenseSplits() gin.HandlerFunc {
+	type GetExpenseSplitsRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &GetExpenseSplitsRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := sc.splitService.GetByExpenseId(ctx, auth.Id.String(), request.Id)
+		if err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (sc splitController) DeleteExpenseSplits() gin.HandlerFunc {
+	type DeleteExpenseSplitsRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &DeleteExpenseSplitsRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if err := sc.splitService.DeleteByExpenseId(ctx, auth.Id.String(), request.Id); err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusNoContent, &expenseModel.ApiResponse{
+			Status:  http.StatusNoContent,
+			Message: "No Content",
+		})
+	}
+}
+
+func (sc splitController) GetBalances() gin.HandlerFunc {
+	type GetBalancesRequest struct {
+		Users string `form:"users" validate:"omitempty,max=2000"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(GetBalancesRequest)
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		// users is an optional comma separated list of user ids narrowing the group
+		var memberIds []uuid.UUID
+		if request.Users != "" {
+			for _, raw := range strings.Split(request.Users, ",") {
+				id, err := uuid.Parse(strings.TrimSpace(raw))
+				if err != nil {
+					err := httpErrors.NewBadRequestError(errors.Wrap(err, "SplitController.GetBalances.uuid.Parse"))
+					utils.LogErrorResponse(ctx, sc.logger, err)
+					ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+					return
+				}
+				memberIds = append(memberIds, id)
+			}
+		}
+
+		response, err := sc.splitService.GetBalances(ctx, auth.Id.String(), memberIds)
+		if err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (sc splitController) CreateSettlement() gin.HandlerFunc {
+	type CreateSettlementRequest struct {
+		PayeeId  string        `json:"payee_id" validate:"required,uuid"`
+		Amount   *money.Amount `json:"amount" validate:"required,gt=0"`
+		Currency string        `json:"currency" validate:"omitempty,iso4217"`
+		Note     string        `json:"note" validate:"omitempty,max=200"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(CreateSettlementRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Settlement{
+			PayerId:  auth.Id,
+			PayeeId:  uuid.MustParse(request.PayeeId),
+			Amount:   request.Amount,
+			Currency: strings.ToUpper(request.Currency),
+			Note:     strings.TrimSpace(request.Note),
+		}
+		response, err := sc.splitService.Settle(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, &expenseModel.ApiResponse{
+			Status:  http.StatusCreated,
+			Message: "Created",
+			Data:    response,
+		})
+	}
+}
+
+func (sc splitController) GetAllSettlement() gin.HandlerFunc {
+	type GetAllSettlementRequest struct {
+		Page  int `form:"page" validate:"omitempty,numeric,min=1"`
+		Limit int `form:"limit" validate:"omitempty,numeric,min=10"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(GetAllSettlementRequest)
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if request.Page == 0 {
+			request.Page = 1
+		}
+		if request.Limit == 0 {
+			request.Limit = 10
+		}
+
+		settlements, total, err := sc.splitService.GetSettlements(ctx, auth.Id.String(), request.Page, request.Limit)
+		if err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       settlements,
+			Page:       request.Page,
+			Limit:      request.Limit,
+			TotalItems: total,
+			TotalPages: int64(math.Ceil(float64(total) / float64(request.Limit))),
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package mock
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/split/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPostgresRepository is a mock type for split.PostgresRepository
+type MockPostgresRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostgresRepository) ReplaceByExpenseId(ctx context.Context, expenseId string, splits []model.Split) error {
+	args := m.Called(ctx, expenseId, splits)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindByExpenseId(ctx context.Context, expenseId string) ([]model.Split, error) {
+	args := m.Called(ctx, expenseId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Split), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) RemoveByExpenseId(ctx context.Context, expenseId string) error {
+	args := m.Called(ctx, expenseId)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) CountUsersByIds(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPostgresRepository) CreateSettlement(ctx context.Context, settlement *model.Settlement) error {
+	args := m.Called(ctx, settlement)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindSettlementsByUserId(ctx context.Context, userId string, offset, limit int) ([]model.Settlement, int64, error) {
+	args := m.Called(ctx, userId, offset, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Settlement), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockPostgresRepository) FindCounterpartIds(ctx context.Context, userId string) ([]uuid.UUID, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]uuid.UUID), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindDebtsAmong(ctx context.Context, userIds []uuid.UUID) ([]model.Debt, error) {
+	args := m.Called(ctx, userIds)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Debt), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
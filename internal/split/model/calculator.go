@@ -0,0 +1,233 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrNoParticipants       = errors.New("split requires at least one participant")
+	ErrDuplicateParticipant = errors.New("split participant is listed more than once")
+	ErrUnknownMethod        = errors.New("split method is not supported")
+	ErrMissingValue         = errors.New("split value is required for this method")
+	ErrInvalidValue         = errors.New("split value must be greater than zero")
+	ErrExactSumMismatch     = errors.New("exact split amounts must add up to the expense amount")
+	ErrPercentageSum        = errors.New("split percentages must add up to 100")
+)
+
+// Participant is a user taking part in a split, Value is interpreted
+// according to the split method (ignored for equal, amount for exact,
+// percent for percentage and weight for shares). it is kept as an exact
+// rational so no precision is lost before the amounts are allocated
+type Participant struct {
+	UserId uuid.UUID
+	Value  *big.Rat
+}
+
+// CalculateSplits divides total, in the currency of the expense, between participants with the given method.
+// amounts are computed in minor units of the currency and the rounding remainder is handed out
+// with the largest remainder method, so the splits always add up to total
+func CalculateSplits(expenseId uuid.UUID, total money.Amount, currency, method string, participants []Participant) ([]Split, error) {
+	if len(participants) == 0 {
+		return nil, ErrNoParticipants
+	}
+
+	seen := make(map[uuid.UUID]bool, len(participants))
+	for _, p := range participants {
+		if seen[p.UserId] {
+			return nil, ErrDuplicateParticipant
+		}
+		seen[p.UserId] = true
+	}
+
+	var amounts []money.Amount
+
+	switch method {
+	case MethodEqual:
+		{
+			weights := make([]*big.Rat, len(participants))
+			for i := range weights {
+				weights[i] = big.NewRat(1, 1)
+			}
+			amounts = total.AllocateIn(currency, weights)
+		}
+	case MethodExact:
+		{
+			amounts = make([]money.Amount, len(participants))
+			for i, p := range participants {
+				if p.Value == nil {
+					return nil, ErrMissingValue
+				}
+				if p.Value.Sign() < 0 {
+					return nil, ErrInvalidValue
+				}
+				minor := new(big.Rat).Mul(p.Value, big.NewRat(100, 1))
+				if !minor.IsInt() || !minor.Num().IsInt64() {
+					return nil, ErrInvalidValue
+				}
+				amounts[i] = money.FromMinor(minor.Num().Int64())
+				if !amounts[i].ValidFor(currency) {
+					return nil, ErrInvalidValue
+				}
+			}
+			if money.Sum(amounts...) != total {
+				return nil, ErrExactSumMismatch
+			}
+		}
+	case MethodPercentage:
+		{
+			weights, err := values(participants)
+			if err != nil {
+				return nil, err
+			}
+			sum := new(big.Rat)
+			for _, w := range weights {
+				sum.Add(sum, w)
+			}
+			if sum.Cmp(big.NewRat(100, 1)) != 0 {
+				return nil, ErrPercentageSum
+			}
+			amounts = total.AllocateIn(currency, weights)
+		}
+	case MethodShares:
+		{
+			weights, err := values(participants)
+			if err != nil {
+				return nil, err
+			}
+			amounts = total.AllocateIn(currency, weights)
+		}
+	default:
+		return nil, ErrUnknownMethod
+	}
+
+	splits := make([]Split, len(participants))
+	for i, p := range participants {
+		amount := amounts[i]
+		splits[i] = Split{
+			ExpenseId: expenseId,
+			UserId:    p.UserId,
+			Method:    method,
+			Amount:    &amount,
+			Currency:  currency,
+		}
+		if method != MethodEqual {
+			splits[i].Value = decimal(p.Value)
+		}
+	}
+	return splits, nil
+}
+
+// SimplifyDebts nets every debt into a balance per user and currency and returns, for every currency,
+// the minimal list of transfers (greedy largest creditor / largest debtor) that settles the group.
+// amounts in different currencies are never netted against each other
+func SimplifyDebts(debts []Debt) *GroupBalance {
+	byCurrency := make(map[string][]Debt)
+	for _, d := range debts {
+		byCurrency[d.Currency] = append(byCurrency[d.Currency], d)
+	}
+	currencies := make([]string, 0, len(byCurrency))
+	for currency := range byCurrency {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	group := &GroupBalance{
+		Balances:  make([]Balance, 0),
+		Transfers: make([]Transfer, 0),
+	}
+	for _, currency := range currencies {
+		balances, transfers := simplifyDebts(currency, byCurrency[currency])
+		group.Balances = append(group.Balances, balances...)
+		group.Transfers = append(group.Transfers, transfers...)
+	}
+	return group
+}
+
+// simplifyDebts settles the debts of a single currency
+func simplifyDebts(currency string, debts []Debt) ([]Balance, []Transfer) {
+	net := make(map[uuid.UUID]money.Amount)
+	for _, d := range debts {
+		net[d.CreditorId] = net[d.CreditorId].Add(d.Amount)
+		net[d.DebtorId] = net[d.DebtorId].Sub(d.Amount)
+	}
+
+	type position struct {
+		userId uuid.UUID
+		amount money.Amount
+	}
+	var creditors, debtors []position
+	balances := make([]Balance, 0, len(net))
+	for userId, amount := range net {
+		balances = append(balances, Balance{UserId: userId, Currency: currency, Net: amount})
+		switch {
+		case amount > 0:
+			creditors = append(creditors, position{userId, amount})
+		case amount < 0:
+			debtors = append(debtors, position{userId, amount.Neg()})
+		}
+	}
+
+	// deterministic ordering so the same debts always give the same transfers
+	byAmount := func(p []position) func(i, j int) bool {
+		return func(i, j int) bool {
+			if p[i].amount != p[j].amount {
+				return p[i].amount > p[j].amount
+			}
+			return p[i].userId.String() < p[j].userId.String()
+		}
+	}
+	sort.Slice(creditors, byAmount(creditors))
+	sort.Slice(debtors, byAmount(debtors))
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].UserId.String() < balances[j].UserId.String()
+	})
+
+	var transfers []Transfer
+	for c, d := 0, 0; c < len(creditors) && d < len(debtors); {
+		amount := min(creditors[c].amount, debtors[d].amount)
+		transfers = append(transfers, Transfer{
+			From:     debtors[d].userId,
+			To:       creditors[c].userId,
+			Currency: currency,
+			Amount:   amount,
+		})
+		creditors[c].amount -= amount
+		debtors[d].amount -= amount
+		if creditors[c].amount == 0 {
+			c++
+		}
+		if debtors[d].amount == 0 {
+			d++
+		}
+	}
+	return balances, transfers
+}
+
+func values(participants []Participant) ([]*big.Rat, error) {
+	weights := make([]*big.Rat, len(participants))
+	for i, p := range participants {
+		if p.Value == nil {
+			return nil, ErrMissingValue
+		}
+		if p.Value.Sign() <= 0 {
+			return nil, ErrInvalidValue
+		}
+		weights[i] = p.Value
+	}
+	return weights, nil
+}
+
+// decimal formats a split value with the 4 decimals kept by the 'value' column
+func decimal(value *big.Rat) *string {
+	if value == nil {
+		return nil
+	}
+	formatted := value.FloatString(4)
+	formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+	return &formatted
+}
@@ -0,0 +1,193 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"testing/quick"
+)
+
+func value(v int64) *big.Rat {
+	return big.NewRat(v, 1)
+}
+
+func sum(splits []Split) money.Amount {
+	var total money.Amount
+	for _, s := range splits {
+		total = total.Add(*s.Amount)
+	}
+	return total
+}
+
+func TestCalculateSplits(t *testing.T) {
+	expenseId := uuid.New()
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Equal split hands out the remainder cent", func(t *testing.T) {
+		splits, err := CalculateSplits(expenseId, money.Amount(10000), "USD", MethodEqual, []Participant{{UserId: a}, {UserId: b}, {UserId: c}})
+		require.NoError(t, err)
+		require.Len(t, splits, 3)
+		require.Equal(t, money.Amount(10000), sum(splits))
+		require.Equal(t, money.Amount(3334), *splits[0].Amount)
+		require.Equal(t, money.Amount(3333), *splits[1].Amount)
+		require.Equal(t, money.Amount(3333), *splits[2].Amount)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Exact split must add up to the expense amount", func(t *testing.T) {
+		splits, err := CalculateSplits(expenseId, money.Amount(5000), "USD", MethodExact, []Participant{
+			{UserId: a, Value: value(20)},
+			{UserId: b, Value: value(30)},
+		})
+		require.NoError(t, err)
+		require.Equal(t, money.Amount(2000), *splits[0].Amount)
+		require.Equal(t, money.Amount(3000), *splits[1].Amount)
+
+		_, err = CalculateSplits(expenseId, money.Amount(5000), "USD", MethodExact, []Participant{
+			{UserId: a, Value: value(20)},
+			{UserId: b, Value: value(20)},
+		})
+		require.ErrorIs(t, err, ErrExactSumMismatch)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Percentage split must add up to 100", func(t *testing.T) {
+		splits, err := CalculateSplits(expenseId, money.Amount(8000), "USD", MethodPercentage, []Participant{
+			{UserId: a, Value: value(25)},
+			{UserId: b, Value: value(75)},
+		})
+		require.NoError(t, err)
+		require.Equal(t, money.Amount(2000), *splits[0].Amount)
+		require.Equal(t, money.Amount(6000), *splits[1].Amount)
+
+		_, err = CalculateSplits(expenseId, money.Amount(8000), "USD", MethodPercentage, []Participant{
+			{UserId: a, Value: value(25)},
+			{UserId: b, Value: value(70)},
+		})
+		require.ErrorIs(t, err, ErrPercentageSum)
+	})
+
+	// scenario test case #4
+	t.Run("[Test Case #4] Shares split is proportional to the weights", func(t *testing.T) {
+		splits, err := CalculateSplits(expenseId, money.Amount(1000), "USD", MethodShares, []Participant{
+			{UserId: a, Value: value(1)},
+			{UserId: b, Value: value(2)},
+		})
+		require.NoError(t, err)
+		require.Equal(t, money.Amount(1000), sum(splits))
+		require.Equal(t, money.Amount(333), *splits[0].Amount)
+		require.Equal(t, money.Amount(667), *splits[1].Amount)
+	})
+
+	// scenario test case #5
+	t.Run("[Test Case #5] Invalid participants are rejected", func(t *testing.T) {
+		_, err := CalculateSplits(expenseId, money.Amount(1000), "USD", MethodEqual, nil)
+		require.ErrorIs(t, err, ErrNoParticipants)
+
+		_, err = CalculateSplits(expenseId, money.Amount(1000), "USD", MethodEqual, []Participant{{UserId: a}, {UserId: a}})
+		require.ErrorIs(t, err, ErrDuplicateParticipant)
+
+		_, err = CalculateSplits(expenseId, money.Amount(1000), "USD", MethodShares, []Participant{{UserId: a}})
+		require.ErrorIs(t, err, ErrMissingValue)
+
+		_, err = CalculateSplits(expenseId, money.Amount(1000), "IDR", "unknown", []Participant{{UserId: a}})
+		require.ErrorIs(t, err, ErrUnknownMethod)
+	})
+}
+
+func TestSimplifyDebts(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Chain of debts collapses into a single transfer", func(t *testing.T) {
+		// c owes b 10, b owes a 10 => c pays a 10
+		result := SimplifyDebts([]Debt{
+			{CreditorId: b, DebtorId: c, Amount: 1000},
+			{CreditorId: a, DebtorId: b, Amount: 1000},
+		})
+		require.Len(t, result.Transfers, 1)
+		require.Equal(t, Transfer{From: c, To: a, Amount: 1000}, result.Transfers[0])
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Settlement cancels the debt", func(t *testing.T) {
+		result := SimplifyDebts([]Debt{
+			{CreditorId: a, DebtorId: b, Amount: 2550},
+			{CreditorId: b, DebtorId: a, Amount: 2550},
+		})
+		require.Empty(t, result.Transfers)
+		for _, balance := range result.Balances {
+			require.Zero(t, balance.Net)
+		}
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Net balances add up to zero", func(t *testing.T) {
+		result := SimplifyDebts([]Debt{
+			{CreditorId: a, DebtorId: b, Amount: 3000},
+			{CreditorId: a, DebtorId: c, Amount: 3000},
+			{CreditorId: b, DebtorId: c, Amount: 500},
+		})
+		var net money.Amount
+		for _, balance := range result.Balances {
+			net += balance.Net
+		}
+		require.Zero(t, net)
+		require.Len(t, result.Transfers, 2)
+	})
+
+	// scenario test case #4
+	t.Run("[Test Case #4] Debts in different currencies are settled separately", func(t *testing.T) {
+		// b owes a 30.00 USD, a owes b 450000.00 IDR and c owes b 100000.00 IDR
+		result := SimplifyDebts([]Debt{
+			{CreditorId: a, DebtorId: b, Amount: 3000, Currency: "USD"},
+			{CreditorId: b, DebtorId: a, Amount: 45000000, Currency: "IDR"},
+			{CreditorId: b, DebtorId: c, Amount: 10000000, Currency: "IDR"},
+		})
+
+		require.Equal(t, []Transfer{
+			{From: a, To: b, Currency: "IDR", Amount: 45000000},
+			{From: c, To: b, Currency: "IDR", Amount: 10000000},
+			{From: b, To: a, Currency: "USD", Amount: 3000},
+		}, result.Transfers)
+
+		net := make(map[string]money.Amount)
+		for _, balance := range result.Balances {
+			net[balance.Currency] += balance.Net
+			if balance.UserId == b && balance.Currency == "IDR" {
+				require.Equal(t, money.Amount(55000000), balance.Net)
+			}
+		}
+		require.Equal(t, map[string]money.Amount{"IDR": 0, "USD": 0}, net)
+		require.Len(t, result.Balances, 5)
+	})
+}
+
+// property: whatever the method, the splits always add up to the expense amount
+func TestCalculateSplits_Property_SumIsTotal(t *testing.T) {
+	expenseId := uuid.New()
+	property := func(total uint32, weights []uint8) bool {
+		if len(weights) == 0 {
+			return true
+		}
+		equal := make([]Participant, len(weights))
+		shares := make([]Participant, len(weights))
+		for i, w := range weights {
+			id := uuid.New()
+			equal[i] = Participant{UserId: id}
+			shares[i] = Participant{UserId: id, Value: value(int64(w) + 1)}
+		}
+
+		for method, participants := range map[string][]Participant{MethodEqual: equal, MethodShares: shares} {
+			splits, err := CalculateSplits(expenseId, money.Amount(total), "IDR", method, participants)
+			if err != nil || sum(splits) != money.Amount(total) {
+				return false
+			}
+		}
+		return true
+	}
+	require.NoError(t, quick.Check(property, nil))
+}
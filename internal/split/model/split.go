@@ -0,0 +1,81 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+)
+
+// split methods supported when dividing an expense between users
+const (
+	MethodEqual      = "equal"
+	MethodExact      = "exact"
+	MethodPercentage = "percentage"
+	MethodShares     = "shares"
+)
+
+// Split mapping table 'expense_splits'.
+// one row is the portion of an expense owed by a single user to the payer (owner of the expense),
+// in the currency of the expense
+type Split struct {
+	Id        uuid.UUID     `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	ExpenseId uuid.UUID     `json:"expense_id" gorm:"column:id_expense"`
+	UserId    uuid.UUID     `json:"user_id" gorm:"column:id_user"`
+	Method    string        `json:"method" gorm:"column:method"`
+	Value     *string       `json:"value,omitempty" gorm:"column:value"`
+	Amount    *money.Amount `json:"amount" gorm:"column:amount"`
+	Currency  string        `json:"currency" gorm:"column:currency"`
+	CreatedAt int64         `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt int64         `json:"updated_at" gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (split *Split) TableName() string {
+	return "expense_splits"
+}
+
+// Settlement mapping table 'settlements'.
+// a payment from payer to payee which pays off (part of) the payer's debt
+type Settlement struct {
+	Id        uuid.UUID     `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	PayerId   uuid.UUID     `json:"payer_id" gorm:"column:id_payer"`
+	PayeeId   uuid.UUID     `json:"payee_id" gorm:"column:id_payee"`
+	Amount    *money.Amount `json:"amount" gorm:"column:amount"`
+	Currency  string        `json:"currency" gorm:"column:currency"`
+	Note      string        `json:"note" gorm:"column:note"`
+	CreatedAt int64         `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt int64         `json:"updated_at" gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (settlement *Settlement) TableName() string {
+	return "settlements"
+}
+
+// Debt is a single obligation: debtor owes creditor amount in currency.
+// it is read from splits (participant owes the payer) and settlements (reversed flow)
+type Debt struct {
+	CreditorId uuid.UUID    `json:"creditor_id" gorm:"column:id_creditor"`
+	DebtorId   uuid.UUID    `json:"debtor_id" gorm:"column:id_debtor"`
+	Amount     money.Amount `json:"amount" gorm:"column:amount"`
+	Currency   string       `json:"currency" gorm:"column:currency"`
+}
+
+// Balance is the net position of a user inside a group in one currency,
+// positive means the user is owed money, negative means the user owes money
+type Balance struct {
+	UserId   uuid.UUID    `json:"user_id"`
+	Currency string       `json:"currency"`
+	Net      money.Amount `json:"net"`
+}
+
+// Transfer is a suggested payment that settles the group in one currency
+type Transfer struct {
+	From     uuid.UUID    `json:"from"`
+	To       uuid.UUID    `json:"to"`
+	Currency string       `json:"currency"`
+	Amount   money.Amount `json:"amount"`
+}
+
+// GroupBalance is the response of the balance calculator
+type GroupBalance struct {
+	Balances  []Balance  `json:"balances"`
+	Transfers []Transfer `json:"transfers"`
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/split"
+	"github.com/mrizkisaputra/expenses-api/internal/split/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewSplitPgRepository(db *gorm.DB) split.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (p *postgresRepository) ReplaceByExpenseId(ctx context.Context, expenseId string, splits []model.Split) error {
+	DB := p.db.WithContext(ctx)
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		/**
+		SQL: DELETE FROM "expense_splits" WHERE id_expense = ?
+		*/
+		if err := tx.Where("id_expense = ?", expenseId).Delete(&model.Split{}).Error; err != nil {
+			return errors.Wrap(err, "postgresRepository.ReplaceByExpenseId.Delete")
+		}
+
+		/**
+		SQL: INSERT INTO "expense_splits" ("id_expense","id_user","method","value","amount","currency","created_at","updated_at")
+		VALUES (?,?,?,?,?,?,?,?),... RETURNING "id"
+		*/
+		if err := tx.Create(&splits).Error; err != nil {
+			return errors.Wrap(err, "postgresRepository.ReplaceByExpenseId.Create")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindByExpenseId(ctx context.Context, expenseId string) ([]model.Split, error) {
+	/**
+	SQL: SELECT * FROM "expense_splits" WHERE id_expense = ? ORDER BY amount DESC
+	*/
+	DB := p.db.WithContext(ctx)
+	var splits []model.Split
+	if err := DB.Where("id_expense = ?", expenseId).Order("amount DESC").Find(&splits).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByExpenseId")
+	}
+	return splits, nil
+}
+
+func (p *postgresRepository) RemoveByExpenseId(ctx context.Context, expenseId string) error {
+	/**
+	SQL: DELETE FROM "expense_splits" WHERE id_expense = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id_expense = ?", expenseId).Delete(&model.Split{}).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.RemoveByExpenseId")
+	}
+	return nil
+}
+
+func (p *postgresRepository) CountUsersByIds(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	/**
+	SQL: SELECT count(*) FROM "users" WHERE id IN (?)
+	*/
+	DB := p.db.WithContext(ctx)
+	var count int64
+	if err := DB.Table("users").Where("id IN ?", ids).Count(&count).Error; err != nil {
+		return 0, errors.Wrap(err, "postgresRepository.CountUsersByIds")
+	}
+	return count, nil
+}
+
+func (p *postgresRepository) CreateSettlement(ctx context.Context, settlement *model.Settlement) error {
+	/**
+	SQL: INSERT INTO "settlements" ("id_payer","id_payee","amount","currency","note","created_at","updated_at")
+	VALUES (?,?,?,?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(settlement).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.CreateSettlement")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindSettlementsByUserId(ctx context.Context, userId string, offset, limit int) ([]model.Settlement, int64, error) {
+	DB := p.db.WithContext(ctx)
+
+	/**
+	SQL: SELECT * FROM "settlements" WHERE id_payer = ? OR id_payee = ? ORDER BY created_at DESC LIMIT ? OFFSET ?
+	*/
+	var settlements []model.Settlement
+	if err := DB.Where("id_payer = ? OR id_payee = ?", userId, userId).
+		Order("created_at DESC").
+		Offset((offset - 1) * limit).
+		Limit(limit).
+		Find(&settlements).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindSettlementsByUserId.Find")
+	}
+
+	/**
+	SQL: SELECT count(*) FROM "settlements" WHERE id_payer = ? OR id_payee = ?
+	*/
+	var total int64
+	if err := DB.Model(&model.Settlement{}).
+		Where("id_payer = ? OR id_payee = ?", userId, userId).
+		Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindSettlementsByUserId.Count")
+	}
+
+	return settlements, total, nil
+}
+
+func (p *postgresRepository) FindCounterpartIds(ctx context.Context, userId string) ([]uuid.UUID, error) {
+	/**
+	SQL: every user that shares a split expense or a settlement with the given user
+	*/
+	DB := p.db.WithContext(ctx)
+	var ids []uuid.UUID
+	err := DB.Raw(`
+		SELECT CASE WHEN e.id_user = @user THEN s.id_user ELSE e.id_user END
+		FROM expense_splits s JOIN expenses e ON e.id = s.id_expense
+		WHERE e.deleted_at IS NULL AND s.id_user <> e.id_user AND (e.id_user = @user OR s.id_user = @user)
+		UNION
+		SELECT CASE WHEN id_payer = @user THEN id_payee ELSE id_payer END
+		FROM settlements
+		WHERE id_payer = @user OR id_payee = @user`,
+		map[string]interface{}{"user": userId},
+	).Scan(&ids).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindCounterpartIds")
+	}
+	return ids, nil
+}
+
+func (p *postgresRepository) FindDebtsAmong(ctx context.Context, userIds []uuid.UUID) ([]model.Debt, error) {
+	/**
+	SQL: split participants owe the payer of the expense, a settlement pays back
+	so it is read as a debt in the reverse direction (payee owes payer). every debt keeps its currency
+	*/
+	DB := p.db.WithContext(ctx)
+	var debts []model.Debt
+	err := DB.Raw(`
+		SELECT e.id_user AS id_creditor, s.id_user AS id_debtor, s.amount, s.currency
+		FROM expense_splits s JOIN expenses e ON e.id = s.id_expense
+		WHERE e.deleted_at IS NULL AND s.id_user <> e.id_user AND e.id_user IN @users AND s.id_user IN @users
+		UNION ALL
+		SELECT id_payer AS id_creditor, id_payee AS id_debtor, amount, currency
+		FROM settlements
+		WHERE id_payer IN @users AND id_payee IN @users`,
+		map[string]interface{}{"users": userIds},
+	).Scan(&debts).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindDebtsAmong")
+	}
+	return debts, nil
+}
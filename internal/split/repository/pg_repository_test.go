@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/split"
+	"github.com/mrizkisaputra/expenses-api/internal/split/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	repository split.PostgresRepository
+	mock       sqlmock.Sqlmock
+)
+
+// Test Before and After
+func TestMain(m *testing.M) {
+	// initialize mock sql db
+	mockDb, mocking, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("Failed to open mock sql db, got error %v", err)
+	}
+	defer mockDb.Close()
+	mock = mocking
+
+	// initialize db connection
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDb}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database, got error %v", err)
+	}
+
+	// initialize repository
+	repository = NewSplitPgRepository(db)
+
+	os.Exit(m.Run())
+}
+
+func TestSplitPostgresRepository_FindByExpenseId(t *testing.T) {
+	expenseId := uuid.New()
+	userId := uuid.New()
+
+	// define expect sql
+	expectedSQL := regexp.QuoteMeta(`SELECT * FROM "expense_splits" WHERE id_expense = $1 ORDER BY amount DESC`)
+	rows := sqlmock.NewRows([]string{"id", "id_expense", "id_user", "method", "value", "amount", "currency", "created_at", "updated_at"}).
+		AddRow(uuid.New(), expenseId, userId, model.MethodEqual, nil, 4500, "USD", time.Now().UnixMilli(), time.Now().UnixMilli())
+	mock.ExpectQuery(expectedSQL).WithArgs(expenseId.String()).WillReturnRows(rows)
+
+	splits, err := repository.FindByExpenseId(context.Background(), expenseId.String())
+	require.NoError(t, err)
+	require.Len(t, splits, 1)
+	require.Equal(t, userId, splits[0].UserId)
+	require.Equal(t, money.Amount(4500), *splits[0].Amount)
+	require.Equal(t, "USD", splits[0].Currency)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
+
+func TestSplitPostgresRepository_CreateSettlement(t *testing.T) {
+	amount := money.Amount(2500)
+	settlement := &model.Settlement{
+		PayerId:   uuid.New(),
+		PayeeId:   uuid.New(),
+		Amount:    &amount,
+		Currency:  "USD",
+		Note:      "dinner",
+		CreatedAt: time.Now().UnixMilli(),
+		UpdatedAt: time.Now().UnixMilli(),
+	}
+
+	// define expect sql
+	expectedSQL := regexp.QuoteMeta(`INSERT INTO "settlements" ("id_payer","id_payee","amount","currency","note","created_at","updated_at")
+	VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)
+	mock.ExpectBegin()
+	mock.ExpectQuery(expectedSQL).
+		WithArgs(settlement.PayerId, settlement.PayeeId, settlement.Amount, settlement.Currency, settlement.Note, settlement.CreatedAt, settlement.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	err := repository.CreateSettlement(context.Background(), settlement)
+	require.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
+
+func TestSplitPostgresRepository_FindDebtsAmong(t *testing.T) {
+	userId, friendId := uuid.New(), uuid.New()
+
+	// define expect sql
+	expectedSQL := `SELECT e.id_user AS id_creditor, s.id_user AS id_debtor, s.amount, s.currency .+ UNION ALL SELECT id_payer AS id_creditor, id_payee AS id_debtor, amount, currency`
+	rows := sqlmock.NewRows([]string{"id_creditor", "id_debtor", "amount", "currency"}).
+		AddRow(userId, friendId, 4500, "IDR").
+		AddRow(friendId, userId, 1250, "USD")
+	mock.ExpectQuery(expectedSQL).WithArgs(userId, friendId, userId, friendId, userId, friendId, userId, friendId).WillReturnRows(rows)
+
+	debts, err := repository.FindDebtsAmong(context.Background(), []uuid.UUID{userId, friendId})
+	require.NoError(t, err)
+	require.Equal(t, []model.Debt{
+		{CreditorId: userId, DebtorId: friendId, Amount: 4500, Currency: "IDR"},
+		{CreditorId: friendId, DebtorId: userId, Amount: 1250, Currency: "USD"},
+	}, debts)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
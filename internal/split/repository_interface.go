@@ -0,0 +1,27 @@
+package split
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/split/model"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	ReplaceByExpenseId(ctx context.Context, expenseId string, splits []model.Split) error
+
+	FindByExpenseId(ctx context.Context, expenseId string) ([]model.Split, error)
+
+	RemoveByExpenseId(ctx context.Context, expenseId string) error
+
+	CountUsersByIds(ctx context.Context, ids []uuid.UUID) (int64, error)
+
+	CreateSettlement(ctx context.Context, settlement *model.Settlement) error
+
+	FindSettlementsByUserId(ctx context.Context, userId string, offset, limit int) ([]model.Settlement, int64, error)
+
+	FindCounterpartIds(ctx context.Context, userId string) ([]uuid.UUID, error)
+
+	FindDebtsAmong(ctx context.Context, userIds []uuid.UUID) ([]model.Debt, error)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/expense"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/split"
+	"github.com/mrizkisaputra/expenses-api/internal/split/model"
+	"github.com/mrizkisaputra/expenses-api/internal/user"
+	userModel "github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type ServiceConfig struct {
+	PgRepo        split.PostgresRepository
+	ExpensePgRepo expense.PostgresRepository
+	UserPgRepo    user.UserPostgresRepository
+	Config        *config.Config
+	Logger        *logrus.Logger
+}
+
+// splitService acts as a struct for injecting an implementation of SplitService interface
+// for use in service methods.
+type splitService struct {
+	pgRepo        split.PostgresRepository
+	expensePgRepo expense.PostgresRepository
+	userPgRepo    user.UserPostgresRepository
+}
+
+// NewSplitService is a factory function for
+// initializing a splitService with its repository layer dependencies
+func NewSplitService(config *ServiceConfig) split.SplitService {
+	return &splitService{
+		pgRepo:        config.PgRepo,
+		expensePgRepo: config.ExpensePgRepo,
+		userPgRepo:    config.UserPgRepo,
+	}
+}
+
+func (s *splitService) SplitExpense(
+	ctx context.Context,
+	userId, expenseId, method string,
+	participants []model.Participant,
+) ([]model.Split, error) {
+	// only the payer (owner of the expense) can split it
+	expenses := new(expenseModel.Expense)
+	if err := s.expensePgRepo.FindByIdAndUserId(ctx, expenses, expenseId, userId); err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+
+	splits, err := model.CalculateSplits(expenses.Id, *expenses.Amount, expenses.Currency, method, participants)
+	if err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidSplitMsg, err)
+	}
+
+	// ensure every participant is a registered user
+	ids := make([]uuid.UUID, len(participants))
+	for i, p := range participants {
+		ids[i] = p.UserId
+	}
+	count, err := s.pgRepo.CountUsersByIds(ctx, ids)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	if count != int64(len(ids)) {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.UnknownParticipantMsg, nil)
+	}
+
+	if err := s.pgRepo.ReplaceByExpenseId(ctx, expenseId, splits); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return splits, nil
+}
+
+func (s *splitService) GetByExpenseId(ctx context.Context, userId, expenseId string) ([]model.Split, error) {
+	splits, err := s.pgRepo.FindByExpenseId(ctx, expenseId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+
+	// splits are visible to the payer and to every participant
+	for _, sp := range splits {
+		if sp.UserId.String() == userId {
+			return splits, nil
+		}
+	}
+	expenses := new(expenseModel.Expense)
+	if err := s.expensePgRepo.FindByIdAndUserId(ctx, expenses, expenseId, userId); err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	return splits, nil
+}
+
+func (s *splitService) DeleteByExpenseId(ctx context.Context, userId, expenseId string) error {
+	expenses := new(expenseModel.Expense)
+	if err := s.expensePgRepo.FindByIdAndUserId(ctx, expenses, expenseId, userId); err != nil {
+		return httpErrors.NewNotFoundError(err)
+	}
+
+	if err := s.pgRepo.RemoveByExpenseId(ctx, expenseId); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+func (s *splitService) Settle(ctx context.Context, request *model.Settlement) (*model.Settlement, error) {
+	if request.PayerId == request.PayeeId {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidSettlementMsg, nil)
+	}
+
+	count, err := s.pgRepo.CountUsersByIds(ctx, []uuid.UUID{request.PayeeId})
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	if count == 0 {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.UnknownParticipantMsg, nil)
+	}
+
+	// without currency the payment is made in the base currency of the payer
+	if request.Currency == "" {
+		found, err := s.userPgRepo.FindById(ctx, &userModel.User{Id: request.PayerId})
+		if err != nil {
+			return nil, httpErrors.NewNotFoundError(errors.Wrap(err, "splitService.Settle.FindById"))
+		}
+		request.Currency = found.BaseCurrency
+		if request.Currency == "" {
+			request.Currency = userModel.DefaultBaseCurrency
+		}
+	}
+
+	if err := s.pgRepo.CreateSettlement(ctx, request); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return request, nil
+}
+
+func (s *splitService) GetSettlements(ctx context.Context, userId string, page, limit int) ([]model.Settlement, int64, error) {
+	settlements, total, err := s.pgRepo.FindSettlementsByUserId(ctx, userId, page, limit)
+	if err != nil {
+		return nil, 0, httpErrors.NewInternalServerError(err)
+	}
+	return settlements, total, nil
+}
+
+// GetBalances computes the balances of the group made of the user and everyone
+// they share split expenses or settlements with. memberIds optionally narrows the
+// group down, ids that are not counterparts of the user are ignored
+func (s *splitService) GetBalances(ctx context.Context, userId string, memberIds []uuid.UUID) (*model.GroupBalance, error) {
+	parsedId, err := uuid.Parse(userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "splitService.GetBalances.uuid.Parse"))
+	}
+
+	counterparts, err := s.pgRepo.FindCounterpartIds(ctx, userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+
+	group := []uuid.UUID{parsedId}
+	if len(memberIds) == 0 {
+		group = append(group, counterparts...)
+	} else {
+		known := make(map[uuid.UUID]bool, len(counterparts))
+		for _, id := range counterparts {
+			known[id] = true
+		}
+		for _, id := range memberIds {
+			if known[id] {
+				group = append(group, id)
+				delete(known, id)
+			}
+		}
+	}
+
+	if len(group) == 1 {
+		return model.SimplifyDebts(nil), nil
+	}
+
+	debts, err := s.pgRepo.FindDebtsAmong(ctx, group)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return model.SimplifyDebts(debts), nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	expenseMock "github.com/mrizkisaputra/expenses-api/internal/expense/mock"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/split/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/split/model"
+	userMock "github.com/mrizkisaputra/expenses-api/internal/user/mock"
+	userModel "github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+)
+
+func TestSplitService_SplitExpense(t *testing.T) {
+	userId := uuid.New()
+	friendId := uuid.New()
+	expenseId := uuid.New()
+	amount := money.Amount(9000)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Successfully split an expense equally", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockExpenseRepo := new(expenseMock.MockPostgresRepository)
+		sv := NewSplitService(&ServiceConfig{PgRepo: mockRepo, ExpensePgRepo: mockExpenseRepo})
+
+		mockExpenseRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, expenseId.String(), userId.String()).
+			Return(&expenseModel.Expense{Id: expenseId, UserId: userId, Amount: &amount, Currency: "USD"}, nil)
+		mockRepo.On("CountUsersByIds", mock.Anything, []uuid.UUID{userId, friendId}).Return(int64(2), nil)
+		mockRepo.On("ReplaceByExpenseId", mock.Anything, expenseId.String(), mock.Anything).Return(nil)
+
+		splits, err := sv.SplitExpense(context.Background(), userId.String(), expenseId.String(), model.MethodEqual,
+			[]model.Participant{{UserId: userId}, {UserId: friendId}})
+		require.NoError(t, err)
+		require.Len(t, splits, 2)
+		require.Equal(t, money.Amount(4500), *splits[0].Amount)
+		require.Equal(t, money.Amount(4500), *splits[1].Amount)
+		require.Equal(t, "USD", splits[0].Currency)
+
+		mockRepo.AssertExpectations(t)
+		mockExpenseRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return not found when the expense is not owned by the user", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockExpenseRepo := new(expenseMock.MockPostgresRepository)
+		sv := NewSplitService(&ServiceConfig{PgRepo: mockRepo, ExpensePgRepo: mockExpenseRepo})
+
+		mockExpenseRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, expenseId.String(), userId.String()).
+			Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := sv.SplitExpense(context.Background(), userId.String(), expenseId.String(), model.MethodEqual,
+			[]model.Participant{{UserId: friendId}})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusNotFound, er.Status)
+		mockRepo.AssertNotCalled(t, "ReplaceByExpenseId")
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Should return bad request when a participant does not exist", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockExpenseRepo := new(expenseMock.MockPostgresRepository)
+		sv := NewSplitService(&ServiceConfig{PgRepo: mockRepo, ExpensePgRepo: mockExpenseRepo})
+
+		mockExpenseRepo.On("FindByIdAndUserId", mock.Anything, mock.Anything, expenseId.String(), userId.String()).
+			Return(&expenseModel.Expense{Id: expenseId, UserId: userId, Amount: &amount}, nil)
+		mockRepo.On("CountUsersByIds", mock.Anything, mock.Anything).Return(int64(1), nil)
+
+		_, err := sv.SplitExpense(context.Background(), userId.String(), expenseId.String(), model.MethodEqual,
+			[]model.Participant{{UserId: userId}, {UserId: friendId}})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.Equal(t, httpErrors.UnknownParticipantMsg, er.Message)
+		mockRepo.AssertNotCalled(t, "ReplaceByExpenseId")
+	})
+}
+
+func TestSplitService_Settle(t *testing.T) {
+	payerId := uuid.New()
+	payeeId := uuid.New()
+	amount := money.Amount(2500)
+
+	// scenario test case #1
+	t.Run("[Test Case #1] A settlement without currency is paid in the base currency of the payer", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		sv := NewSplitService(&ServiceConfig{PgRepo: mockRepo, UserPgRepo: mockUserRepo})
+
+		mockRepo.On("CountUsersByIds", mock.Anything, []uuid.UUID{payeeId}).Return(int64(1), nil)
+		mockUserRepo.On("FindById", mock.Anything, mock.Anything).Return(&userModel.User{Id: payerId, BaseCurrency: "EUR"}, nil)
+		mockRepo.On("CreateSettlement", mock.Anything, mock.Anything).Return(nil)
+
+		settlement, err := sv.Settle(context.Background(), &model.Settlement{PayerId: payerId, PayeeId: payeeId, Amount: &amount})
+		require.NoError(t, err)
+		require.Equal(t, "EUR", settlement.Currency)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] A settlement keeps its currency", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockUserRepo := new(userMock.UserPostgresRepositoryMock)
+		sv := NewSplitService(&ServiceConfig{PgRepo: mockRepo, UserPgRepo: mockUserRepo})
+
+		mockRepo.On("CountUsersByIds", mock.Anything, []uuid.UUID{payeeId}).Return(int64(1), nil)
+		mockRepo.On("CreateSettlement", mock.Anything, mock.Anything).Return(nil)
+
+		settlement, err := sv.Settle(context.Background(), &model.Settlement{PayerId: payerId, PayeeId: payeeId, Amount: &amount, Currency: "USD"})
+		require.NoError(t, err)
+		require.Equal(t, "USD", settlement.Currency)
+		mockUserRepo.AssertNotCalled(t, "FindById")
+	})
+}
+
+func TestSplitService_GetBalances(t *testing.T) {
+	userId := uuid.New()
+	friendId := uuid.New()
+	strangerId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Members that are not counterparts are ignored", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewSplitService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindCounterpartIds", mock.Anything, userId.String()).Return([]uuid.UUID{friendId}, nil)
+		mockRepo.On("FindDebtsAmong", mock.Anything, []uuid.UUID{userId, friendId}).
+			Return([]model.Debt{{CreditorId: userId, DebtorId: friendId, Amount: 1250, Currency: "IDR"}}, nil)
+
+		balance, err := sv.GetBalances(context.Background(), userId.String(), []uuid.UUID{friendId, strangerId})
+		require.NoError(t, err)
+		require.Equal(t, []model.Transfer{{From: friendId, To: userId, Currency: "IDR", Amount: 1250}}, balance.Transfers)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] User without counterparts has an empty balance", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewSplitService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindCounterpartIds", mock.Anything, userId.String()).Return([]uuid.UUID{}, nil)
+
+		balance, err := sv.GetBalances(context.Background(), userId.String(), nil)
+		require.NoError(t, err)
+		require.Empty(t, balance.Transfers)
+		mockRepo.AssertNotCalled(t, "FindDebtsAmong")
+	})
+}
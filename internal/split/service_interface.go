@@ -0,0 +1,23 @@
+package split
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/split/model"
+)
+
+// SplitService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type SplitService interface {
+	SplitExpense(ctx context.Context, userId, expenseId, method string, participants []model.Participant) ([]model.Split, error)
+
+	GetByExpenseId(ctx context.Context, userId, expenseId string) ([]model.Split, error)
+
+	DeleteByExpenseId(ctx context.Context, userId, expenseId string) error
+
+	Settle(ctx context.Context, request *model.Settlement) (*model.Settlement, error)
+
+	GetSettlements(ctx context.Context, userId string, page, limit int) ([]model.Settlement, int64, error)
+
+	GetBalances(ctx context.Context, userId string, memberIds []uuid.UUID) (*model.GroupBalance, error)
+}
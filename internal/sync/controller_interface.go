@@ -0,0 +1,11 @@
+package sync
+
+import "github.com/gin-gonic/gin"
+
+// SyncController defines methods the routes expects
+// any controllers it interacts with to implement
+type SyncController interface {
+	Pull() gin.HandlerFunc
+
+	Push() gin.HandlerFunc
+}
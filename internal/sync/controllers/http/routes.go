@@ -0,0 +1,13 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/internal/sync"
+)
+
+func MapSyncRoutes(syncGroup *gin.RouterGroup, controller sync.SyncController, mw *middleware.MiddlewareManager) {
+	syncGroup.Use(mw.AuthJwtMiddleware())
+	syncGroup.GET("", controller.Pull())
+	syncGroup.POST("/push", mw.IdempotencyMiddleware(), controller.Push())
+}
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/internal/sync"
+	"github.com/mrizkisaputra/expenses-api/internal/sync/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type ControllerConfig struct {
+	SyncService sync.SyncService
+	Logger      *logrus.Logger
+}
+
+// syncController acts as a struct for injecting an implementation of SyncController interface
+// for use in controller methods
+type syncController struct {
+	syncService sync.SyncService
+	logger      *logrus.Logger
+}
+
+// NewSyncController is a factory function
+// initializing a syncController with its service layer dependencies
+func NewSyncController(config *ControllerConfig) sync.SyncController {
+	return &syncController{
+		syncService: config.SyncService,
+		logger:      config.Logger,
+	}
+}
+
+// expenseData is the full state of an expense pushed by a client
+type expenseData struct {
+	Description string        `json:"description" validate:"required,max=200"`
+	Amount      *money.Amount `json:"amount" validate:"required,gt=0"`
+	Currency    string        `json:"currency" validate:"omitempty,iso4217"`
+	CategoryId  string        `json:"category_id" validate:"required,uuid"`
+	Tags        []string      `json:"tags" validate:"omitempty,max=20,dive,required,max=50"`
+}
+
+func (sc syncController) Pull() gin.HandlerFunc {
+	type PullRequest struct {
+		Since string `form:"since" validate:"omitempty,max=200"`
+		Limit int    `form:"limit" validate:"omitempty,min=1,max=1000"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(PullRequest)
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+		if request.Limit == 0 {
+			request.Limit = 500
+		}
+
+		feed, err := sc.syncService.Pull(ctx, auth.Id.String(), request.Since, request.Limit)
+		if err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    feed,
+		})
+	}
+}
+
+func (sc syncController) Push() gin.HandlerFunc {
+	type ChangeRequest struct {
+		Op          string          `json:"op" validate:"required,oneof=upsert delete"`
+		Id          string          `json:"id" validate:"required,uuid"`
+		BaseVersion int64           `json:"base_version" validate:"min=0"`
+		ModifiedAt  int64           `json:"modified_at" validate:"required,gt=0"`
+		Data        json.RawMessage `json:"data"`
+	}
+	type PushRequest struct {
+		Strategy string          `json:"strategy" validate:"omitempty,oneof=report last_writer_wins"`
+		Changes  []ChangeRequest `json:"changes" validate:"required,min=1,max=100,dive"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(PushRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		// the data of every change is validated on its own so an invalid
+		// change is reported in its result instead of rejecting the push
+		changes := make([]model.Change, len(request.Changes))
+		for i, change := range request.Changes {
+			entity := &expenseModel.Expense{
+				Id:      uuid.MustParse(change.Id),
+				UserId:  auth.Id,
+				Version: change.BaseVersion,
+			}
+			changes[i] = model.Change{Op: change.Op, Expense: entity, ModifiedAt: change.ModifiedAt}
+			if change.Op == model.OpUpsert {
+				data := new(expenseData)
+				if changes[i].Err = readChangeData(ctx, change.Data, data); changes[i].Err != nil {
+					continue
+				}
+				entity.Description = data.Description
+				entity.Amount = data.Amount
+				entity.Currency = data.Currency
+				entity.CategoryId = uuid.MustParse(data.CategoryId)
+				entity.Tags = data.Tags
+				if entity.Tags == nil {
+					entity.Tags = []string{}
+				}
+			}
+		}
+
+		response, err := sc.syncService.Push(ctx, changes, request.Strategy)
+		if err != nil {
+			utils.LogErrorResponse(ctx, sc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+		for i := range response.Results {
+			if response.Results[i].Err != nil {
+				info := httpErrors.ParseErrors(ctx, response.Results[i].Err).ErrorInfo
+				response.Results[i].Error = &info
+			}
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+// readChangeData decodes and validates the expense of one pushed change
+func readChangeData(ctx *gin.Context, raw json.RawMessage, data interface{}) error {
+	if len(raw) == 0 {
+		raw = json.RawMessage("{}")
+	}
+	if err := json.Unmarshal(raw, data); err != nil {
+		return httpErrors.NewBadRequestError(errors.Wrap(err, "readChangeData.Unmarshal"))
+	}
+	if err := validator.ValidateStruct(ctx, data); err != nil {
+		return errors.Wrap(err, "readChangeData.ValidateStruct")
+	}
+	return nil
+}
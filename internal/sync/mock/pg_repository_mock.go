@@ -0,0 +1,72 @@
+package mock
+
+import (
+	"context"
+	categoryModel "github.com/mrizkisaputra/expenses-api/internal/category/model"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/sync"
+	"github.com/mrizkisaputra/expenses-api/internal/sync/model"
+	tagModel "github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockPostgresRepository is a mock type for sync.PostgresRepository
+type MockPostgresRepository struct {
+	mock.Mock
+}
+
+// Snapshot records the call and runs fn without transaction, the error of the
+// expectation (if any) is returned instead of running fn
+func (m *MockPostgresRepository) Snapshot(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	args := m.Called(ctx, fn)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(nil)
+}
+
+// WithTx returns the mock itself so expectations hold inside transactions
+func (m *MockPostgresRepository) WithTx(tx *gorm.DB) sync.PostgresRepository {
+	return m
+}
+
+func (m *MockPostgresRepository) FindExpensesSince(ctx context.Context, userId string, since model.Position, limit int) ([]expenseModel.Expense, error) {
+	args := m.Called(ctx, userId, since, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]expenseModel.Expense), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindCategoriesSince(ctx context.Context, userId string, since model.Position, limit int) ([]categoryModel.Category, error) {
+	args := m.Called(ctx, userId, since, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]categoryModel.Category), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindTagsSince(ctx context.Context, userId string, since model.Position, limit int) ([]tagModel.Tag, error) {
+	args := m.Called(ctx, userId, since, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]tagModel.Tag), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindTombstonesSince(ctx context.Context, userId string, since model.Position, limit int) ([]model.Tombstone, error) {
+	args := m.Called(ctx, userId, since, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Tombstone), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindExpense(ctx context.Context, id, userId string) (*expenseModel.Expense, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*expenseModel.Expense), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
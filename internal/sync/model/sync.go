@@ -0,0 +1,96 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	categoryModel "github.com/mrizkisaputra/expenses-api/internal/category/model"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	tagModel "github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+)
+
+const (
+	EntityExpense  = "expense"
+	EntityCategory = "category"
+	EntityTag      = "tag"
+)
+
+const (
+	OpUpsert = "upsert"
+	OpDelete = "delete"
+)
+
+const (
+	// StrategyReport never overwrites a change made on the server since the client read it
+	StrategyReport = "report"
+	// StrategyLastWriterWins applies the change when it was made after the server copy was last updated
+	StrategyLastWriterWins = "last_writer_wins"
+)
+
+const (
+	StatusApplied  = "applied"
+	StatusConflict = "conflict"
+	StatusFailed   = "failed"
+)
+
+// Tombstone mapping table 'sync_tombstones', a deleted entity of the change feed
+type Tombstone struct {
+	UserId    uuid.UUID `json:"-" gorm:"column:id_user"`
+	Entity    string    `json:"entity" gorm:"column:entity"`
+	EntityId  uuid.UUID `json:"id" gorm:"column:id_entity"`
+	DeletedAt int64     `json:"deleted_at" gorm:"column:deleted_at"`
+	ChangeXid int64     `json:"-" gorm:"column:change_xid;->"`
+	ChangeSeq int64     `json:"-" gorm:"column:change_seq;->"`
+}
+
+func (tombstone *Tombstone) TableName() string {
+	return "sync_tombstones"
+}
+
+// ChangeFeed is every entity written since a token, in the order they were written.
+// deleted entities are only listed in Deleted, NextToken is the token of the next pull
+type ChangeFeed struct {
+	Expenses   []expenseModel.Expense   `json:"expenses"`
+	Categories []categoryModel.Category `json:"categories"`
+	Tags       []tagModel.Tag           `json:"tags"`
+	Deleted    []Tombstone              `json:"deleted"`
+	NextToken  string                   `json:"next_token"`
+	HasMore    bool                     `json:"has_more"`
+}
+
+// Change is one expense written by a client while offline. Expense.Version is the
+// version the client last pulled (0 for an expense created on the client) and
+// ModifiedAt the time of the change on the client in unix milliseconds.
+// a change rejected before it runs (invalid data) carries Err
+type Change struct {
+	Op         string
+	Expense    *expenseModel.Expense
+	ModifiedAt int64
+	Err        error
+}
+
+// PushResult is the outcome of one change, Data is the server copy of the expense
+// after the change, or the copy that won the conflict
+type PushResult struct {
+	Index  int                   `json:"index"`
+	Id     uuid.UUID             `json:"id"`
+	Status string                `json:"status"`
+	Data   *expenseModel.Expense `json:"data,omitempty"`
+	Error  *httpErrors.ErrorInfo `json:"error,omitempty"`
+	Err    error                 `json:"-"`
+}
+
+// Fail records the error of the change
+func (result *PushResult) Fail(err error) {
+	result.Err = err
+	result.Data = nil
+	result.Status = StatusFailed
+}
+
+// PushResponse holds the results in the order of the changes
+type PushResponse struct {
+	Strategy  string       `json:"strategy"`
+	Applied   int          `json:"applied"`
+	Conflicts int          `json:"conflicts"`
+	Failed    int          `json:"failed"`
+	Results   []PushResult `json:"results"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidToken = errors.New("invalid sync token")
+
+// Position is a place in the change feed. the writes are ordered by the transaction that made them
+// (Xid) then by the order they were made in it (Seq), so a transaction committing late never lands
+// before a position already pulled
+type Position struct {
+	Xid int64
+	Seq int64
+}
+
+// Before tells whether the position comes first in the change feed
+func (position Position) Before(other Position) bool {
+	if position.Xid != other.Xid {
+		return position.Xid < other.Xid
+	}
+	return position.Seq < other.Seq
+}
+
+// tokenPayload is the encoded form of a change token, clients only see it as an opaque string
+type tokenPayload struct {
+	Xid *int64 `json:"x"`
+	Seq *int64 `json:"s"`
+}
+
+// EncodeToken returns the opaque (url safe) token of a position in the change feed
+func EncodeToken(position Position) string {
+	payload, _ := json.Marshal(&tokenPayload{Xid: &position.Xid, Seq: &position.Seq})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeToken reads a token returned by EncodeToken, an empty token is the start of the feed
+func DecodeToken(raw string) (Position, error) {
+	if raw == "" {
+		return Position{}, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return Position{}, errors.Wrap(ErrInvalidToken, err.Error())
+	}
+
+	payload := new(tokenPayload)
+	if err := json.Unmarshal(data, payload); err != nil {
+		return Position{}, errors.Wrap(ErrInvalidToken, err.Error())
+	}
+	if payload.Xid == nil || payload.Seq == nil {
+		return Position{}, errors.Wrap(ErrInvalidToken, "incomplete position")
+	}
+	if *payload.Xid < 0 || *payload.Seq < 0 {
+		return Position{}, errors.Wrap(ErrInvalidToken, "negative position")
+	}
+	return Position{Xid: *payload.Xid, Seq: *payload.Seq}, nil
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestChangeToken_EncodeDecode(t *testing.T) {
+	for _, position := range []Position{{}, {Xid: 740, Seq: 1}, {Xid: 9223372036854775807, Seq: 9223372036854775807}} {
+		decoded, err := DecodeToken(EncodeToken(position))
+		require.NoError(t, err)
+		require.Equal(t, position, decoded)
+	}
+
+	// an empty token is the start of the feed
+	decoded, err := DecodeToken("")
+	require.NoError(t, err)
+	require.Equal(t, Position{}, decoded)
+}
+
+func TestPosition_Before(t *testing.T) {
+	// a transaction committing late comes after, whatever the sequence it took
+	require.True(t, Position{Xid: 740, Seq: 90}.Before(Position{Xid: 741, Seq: 12}))
+	require.True(t, Position{Xid: 740, Seq: 12}.Before(Position{Xid: 740, Seq: 13}))
+	require.False(t, Position{Xid: 740, Seq: 13}.Before(Position{Xid: 740, Seq: 13}))
+}
+
+func TestChangeToken_DecodeInvalid(t *testing.T) {
+	for _, raw := range []string{"not a token", "eyJ4IjoxLCJzIjoiYWJjIn0", "eyJ4IjoxLCJzIjotMX0", "eyJzIjo0Mn0"} {
+		_, err := DecodeToken(raw)
+		require.ErrorIs(t, err, ErrInvalidToken, raw)
+	}
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	categoryModel "github.com/mrizkisaputra/expenses-api/internal/category/model"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/sync"
+	"github.com/mrizkisaputra/expenses-api/internal/sync/model"
+	tagModel "github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewSyncPgRepository(db *gorm.DB) sync.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+// committedBefore keeps the writes of the transactions older than every one still running, a write
+// of a running transaction may not come before them once committed
+const committedBefore = "change_xid < pg_snapshot_xmin(pg_current_snapshot())::TEXT::BIGINT"
+
+func (p *postgresRepository) Snapshot(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	/**
+	SQL: BEGIN ISOLATION LEVEL REPEATABLE READ READ ONLY; ...; COMMIT
+	*/
+	DB := p.db.WithContext(ctx)
+	return DB.Transaction(fn, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
+
+func (p *postgresRepository) WithTx(tx *gorm.DB) sync.PostgresRepository {
+	return &postgresRepository{db: tx}
+}
+
+func (p *postgresRepository) FindExpensesSince(ctx context.Context, userId string, since model.Position, limit int) ([]expenseModel.Expense, error) {
+	/**
+	SQL: SELECT * FROM "expenses" WHERE id_user = ? AND (change_xid, change_seq) > (?, ?) AND change_xid < pg_snapshot_xmin(pg_current_snapshot())::TEXT::BIGINT
+	ORDER BY change_xid, change_seq LIMIT ?
+	*/
+	DB := p.db.WithContext(ctx)
+	var expenses []expenseModel.Expense
+	err := DB.Unscoped().
+		Where("id_user = ? AND (change_xid, change_seq) > (?, ?) AND "+committedBefore, userId, since.Xid, since.Seq).
+		Order("change_xid, change_seq").
+		Limit(limit).
+		Find(&expenses).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindExpensesSince")
+	}
+	return expenses, nil
+}
+
+func (p *postgresRepository) FindCategoriesSince(ctx context.Context, userId string, since model.Position, limit int) ([]categoryModel.Category, error) {
+	/**
+	SQL: SELECT * FROM "categories" WHERE id_user = ? AND (change_xid, change_seq) > (?, ?) AND change_xid < pg_snapshot_xmin(pg_current_snapshot())::TEXT::BIGINT
+	ORDER BY change_xid, change_seq LIMIT ?
+	*/
+	DB := p.db.WithContext(ctx)
+	var categories []categoryModel.Category
+	err := DB.Where("id_user = ? AND (change_xid, change_seq) > (?, ?) AND "+committedBefore, userId, since.Xid, since.Seq).
+		Order("change_xid, change_seq").
+		Limit(limit).
+		Find(&categories).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindCategoriesSince")
+	}
+	return categories, nil
+}
+
+func (p *postgresRepository) FindTagsSince(ctx context.Context, userId string, since model.Position, limit int) ([]tagModel.Tag, error) {
+	/**
+	SQL: SELECT * FROM "tags" WHERE id_user = ? AND (change_xid, change_seq) > (?, ?) AND change_xid < pg_snapshot_xmin(pg_current_snapshot())::TEXT::BIGINT
+	ORDER BY change_xid, change_seq LIMIT ?
+	*/
+	DB := p.db.WithContext(ctx)
+	var tags []tagModel.Tag
+	err := DB.Where("id_user = ? AND (change_xid, change_seq) > (?, ?) AND "+committedBefore, userId, since.Xid, since.Seq).
+		Order("change_xid, change_seq").
+		Limit(limit).
+		Find(&tags).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindTagsSince")
+	}
+	return tags, nil
+}
+
+func (p *postgresRepository) FindTombstonesSince(ctx context.Context, userId string, since model.Position, limit int) ([]model.Tombstone, error) {
+	/**
+	SQL: SELECT * FROM "sync_tombstones" WHERE id_user = ? AND (change_xid, change_seq) > (?, ?) AND change_xid < pg_snapshot_xmin(pg_current_snapshot())::TEXT::BIGINT
+	ORDER BY change_xid, change_seq LIMIT ?
+	*/
+	DB := p.db.WithContext(ctx)
+	var tombstones []model.Tombstone
+	err := DB.Where("id_user = ? AND (change_xid, change_seq) > (?, ?) AND "+committedBefore, userId, since.Xid, since.Seq).
+		Order("change_xid, change_seq").
+		Limit(limit).
+		Find(&tombstones).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindTombstonesSince")
+	}
+	return tombstones, nil
+}
+
+func (p *postgresRepository) FindExpense(ctx context.Context, id, userId string) (*expenseModel.Expense, error) {
+	/**
+	SQL: SELECT * FROM "expenses" WHERE id = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	expense := new(expenseModel.Expense)
+	err := DB.Unscoped().Where("id = ? AND id_user = ?", id, userId).Take(expense).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindExpense")
+	}
+	return expense, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/sync"
+	"github.com/mrizkisaputra/expenses-api/internal/sync/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	repository sync.PostgresRepository
+	mock       sqlmock.Sqlmock
+)
+
+// Test Before and After
+func TestMain(m *testing.M) {
+	// initialize mock sql db
+	mockDb, mocking, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("Failed to open mock sql db, got error %v", err)
+	}
+	defer mockDb.Close()
+	mock = mocking
+
+	// initialize db connection
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDb}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database, got error %v", err)
+	}
+
+	// initialize repository
+	repository = NewSyncPgRepository(db)
+
+	os.Exit(m.Run())
+}
+
+func TestSyncPostgresRepository_FindExpensesSince(t *testing.T) {
+	userId := uuid.New()
+
+	// define expect sql, deleted expenses are read as tombstones
+	expectedSQL := regexp.QuoteMeta(`SELECT * FROM "expenses" WHERE id_user = $1 AND (change_xid, change_seq) > ($2, $3) AND change_xid < pg_snapshot_xmin(pg_current_snapshot())::TEXT::BIGINT ORDER BY change_xid, change_seq LIMIT $4`)
+	rows := sqlmock.NewRows([]string{"id", "id_user", "description", "amount", "currency", "id_category", "created_at", "updated_at", "deleted_at", "version", "change_xid", "change_seq"}).
+		AddRow(uuid.New(), userId, "coffee", 2500, "IDR", uuid.New(), time.Now().Unix(), time.Now().Unix(), nil, 1, 700, 41).
+		AddRow(uuid.New(), userId, "taxi", 9000, "IDR", uuid.New(), time.Now().Unix(), time.Now().Unix(), time.Now(), 3, 701, 42)
+	mock.ExpectQuery(expectedSQL).WithArgs(userId.String(), int64(700), int64(40), 11).WillReturnRows(rows)
+
+	expenses, err := repository.FindExpensesSince(context.Background(), userId.String(), model.Position{Xid: 700, Seq: 40}, 11)
+	require.NoError(t, err)
+	require.Len(t, expenses, 2)
+	require.Equal(t, int64(41), expenses[0].ChangeSeq)
+	require.Equal(t, int64(701), expenses[1].ChangeXid)
+	require.True(t, expenses[1].DeletedAt.Valid)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncPostgresRepository_Snapshot(t *testing.T) {
+	userId := uuid.New()
+
+	// the feed is read in one transaction
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories"`)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "sync_tombstones"`)).WillReturnRows(sqlmock.NewRows([]string{"id_entity"}))
+	mock.ExpectCommit()
+
+	err := repository.Snapshot(context.Background(), func(tx *gorm.DB) error {
+		txRepo := repository.WithTx(tx)
+		if _, err := txRepo.FindCategoriesSince(context.Background(), userId.String(), model.Position{}, 101); err != nil {
+			return err
+		}
+		_, err := txRepo.FindTombstonesSince(context.Background(), userId.String(), model.Position{}, 101)
+		return err
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncPostgresRepository_FindExpense(t *testing.T) {
+	userId := uuid.New()
+	id := uuid.New()
+
+	// define expect sql
+	expectedSQL := regexp.QuoteMeta(`SELECT * FROM "expenses" WHERE id = $1 AND id_user = $2 LIMIT $3`)
+	mock.ExpectQuery(expectedSQL).WithArgs(id.String(), userId.String(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	expense, err := repository.FindExpense(context.Background(), id.String(), userId.String())
+	require.NoError(t, err)
+	require.Nil(t, expense)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,34 @@
+package sync
+
+import (
+	"context"
+	categoryModel "github.com/mrizkisaputra/expenses-api/internal/category/model"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/sync/model"
+	tagModel "github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"gorm.io/gorm"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	// Snapshot runs fn in one read only transaction, every query of the repositories given tx
+	// with WithTx sees the same snapshot
+	Snapshot(ctx context.Context, fn func(tx *gorm.DB) error) error
+
+	// WithTx returns a repository running its queries in the transaction tx
+	WithTx(tx *gorm.DB) PostgresRepository
+
+	// FindExpensesSince returns the expenses written after the position since, deleted ones included.
+	// the Find*Since methods only return the writes of transactions older than every running one
+	FindExpensesSince(ctx context.Context, userId string, since model.Position, limit int) ([]expenseModel.Expense, error)
+
+	FindCategoriesSince(ctx context.Context, userId string, since model.Position, limit int) ([]categoryModel.Category, error)
+
+	FindTagsSince(ctx context.Context, userId string, since model.Position, limit int) ([]tagModel.Tag, error)
+
+	FindTombstonesSince(ctx context.Context, userId string, since model.Position, limit int) ([]model.Tombstone, error)
+
+	// FindExpense returns the expense even when it is deleted, nil when it never existed
+	FindExpense(ctx context.Context, id, userId string) (*expenseModel.Expense, error)
+}
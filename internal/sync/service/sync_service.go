@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	categoryModel "github.com/mrizkisaputra/expenses-api/internal/category/model"
+	"github.com/mrizkisaputra/expenses-api/internal/expense"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/sync"
+	"github.com/mrizkisaputra/expenses-api/internal/sync/model"
+	"github.com/mrizkisaputra/expenses-api/internal/tag"
+	tagModel "github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"sort"
+	"time"
+)
+
+type ServiceConfig struct {
+	PgRepo         sync.PostgresRepository
+	TagPgRepo      tag.PostgresRepository
+	ExpenseService expense.ExpenseService
+	Config         *config.Config
+	Logger         *logrus.Logger
+}
+
+// syncService acts as a struct for injecting an implementation of SyncService interface
+// for use in service methods.
+type syncService struct {
+	pgRepo         sync.PostgresRepository
+	tagPgRepo      tag.PostgresRepository
+	expenseService expense.ExpenseService
+	logger         *logrus.Logger
+}
+
+// NewSyncService is a factory function for
+// initializing a syncService with its repository layer dependencies
+func NewSyncService(config *ServiceConfig) sync.SyncService {
+	return &syncService{
+		pgRepo:         config.PgRepo,
+		tagPgRepo:      config.TagPgRepo,
+		expenseService: config.ExpenseService,
+		logger:         config.Logger,
+	}
+}
+
+func (s *syncService) Pull(ctx context.Context, userId, token string, limit int) (*model.ChangeFeed, error) {
+	since, err := model.DecodeToken(token)
+	if err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidSyncTokenMsg, err)
+	}
+
+	// every kind of entity reads one more than the limit, so the limit oldest writes
+	// of all kinds together are known and whether there is more after them. the kinds are
+	// read from one snapshot so a write in between is not seen by some of them only
+	var (
+		expenses   []expenseModel.Expense
+		categories []categoryModel.Category
+		tags       []tagModel.Tag
+		tombstones []model.Tombstone
+	)
+	err = s.pgRepo.Snapshot(ctx, func(tx *gorm.DB) error {
+		txRepo := s.pgRepo.WithTx(tx)
+		var err error
+		if expenses, err = txRepo.FindExpensesSince(ctx, userId, since, limit+1); err != nil {
+			return err
+		}
+		if categories, err = txRepo.FindCategoriesSince(ctx, userId, since, limit+1); err != nil {
+			return err
+		}
+		if tags, err = txRepo.FindTagsSince(ctx, userId, since, limit+1); err != nil {
+			return err
+		}
+		tombstones, err = txRepo.FindTombstonesSince(ctx, userId, since, limit+1)
+		return err
+	})
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "syncService.Pull.Snapshot"))
+	}
+
+	positions := make([]model.Position, 0, len(expenses)+len(categories)+len(tags)+len(tombstones))
+	for i := range expenses {
+		positions = append(positions, model.Position{Xid: expenses[i].ChangeXid, Seq: expenses[i].ChangeSeq})
+	}
+	for i := range categories {
+		positions = append(positions, model.Position{Xid: categories[i].ChangeXid, Seq: categories[i].ChangeSeq})
+	}
+	for i := range tags {
+		positions = append(positions, model.Position{Xid: tags[i].ChangeXid, Seq: tags[i].ChangeSeq})
+	}
+	for i := range tombstones {
+		positions = append(positions, model.Position{Xid: tombstones[i].ChangeXid, Seq: tombstones[i].ChangeSeq})
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].Before(positions[j]) })
+
+	// the feed ends on the last write returned, the next pull starts right after it
+	feed := &model.ChangeFeed{
+		Expenses:   []expenseModel.Expense{},
+		Categories: []categoryModel.Category{},
+		Tags:       []tagModel.Tag{},
+		Deleted:    []model.Tombstone{},
+	}
+	last := since
+	if len(positions) > limit {
+		feed.HasMore = true
+		last = positions[limit-1]
+	} else if len(positions) > 0 {
+		last = positions[len(positions)-1]
+	}
+	feed.NextToken = model.EncodeToken(last)
+
+	for _, expense := range expenses {
+		if last.Before(model.Position{Xid: expense.ChangeXid, Seq: expense.ChangeSeq}) {
+			continue
+		}
+		if expense.DeletedAt.Valid {
+			feed.Deleted = append(feed.Deleted, model.Tombstone{
+				UserId:    expense.UserId,
+				Entity:    model.EntityExpense,
+				EntityId:  expense.Id,
+				DeletedAt: expense.DeletedAt.Time.UnixMilli(),
+				ChangeXid: expense.ChangeXid,
+				ChangeSeq: expense.ChangeSeq,
+			})
+			continue
+		}
+		feed.Expenses = append(feed.Expenses, expense)
+	}
+	for _, category := range categories {
+		if !last.Before(model.Position{Xid: category.ChangeXid, Seq: category.ChangeSeq}) {
+			feed.Categories = append(feed.Categories, category)
+		}
+	}
+	for _, tag := range tags {
+		if !last.Before(model.Position{Xid: tag.ChangeXid, Seq: tag.ChangeSeq}) {
+			feed.Tags = append(feed.Tags, tag)
+		}
+	}
+	for _, tombstone := range tombstones {
+		if !last.Before(model.Position{Xid: tombstone.ChangeXid, Seq: tombstone.ChangeSeq}) {
+			feed.Deleted = append(feed.Deleted, tombstone)
+		}
+	}
+	sort.SliceStable(feed.Deleted, func(i, j int) bool {
+		return model.Position{Xid: feed.Deleted[i].ChangeXid, Seq: feed.Deleted[i].ChangeSeq}.
+			Before(model.Position{Xid: feed.Deleted[j].ChangeXid, Seq: feed.Deleted[j].ChangeSeq})
+	})
+
+	if err := s.attachTags(ctx, feed.Expenses); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+func (s *syncService) Push(ctx context.Context, changes []model.Change, strategy string) (*model.PushResponse, error) {
+	if strategy == "" {
+		strategy = model.StrategyReport
+	}
+
+	response := &model.PushResponse{
+		Strategy: strategy,
+		Results:  make([]model.PushResult, len(changes)),
+	}
+	for i := range changes {
+		result := s.apply(ctx, i, &changes[i], strategy)
+		switch result.Status {
+		case model.StatusApplied:
+			response.Applied++
+		case model.StatusConflict:
+			response.Conflicts++
+		default:
+			response.Failed++
+		}
+		response.Results[i] = result
+	}
+	return response, nil
+}
+
+// apply writes one change unless the server copy changed since the client pulled it
+// and the strategy keeps the server copy, the result then carries the server copy
+func (s *syncService) apply(ctx context.Context, index int, change *model.Change, strategy string) model.PushResult {
+	result := model.PushResult{Index: index, Id: change.Expense.Id}
+	if change.Err != nil {
+		result.Fail(change.Err)
+		return result
+	}
+
+	current, err := s.pgRepo.FindExpense(ctx, change.Expense.Id.String(), change.Expense.UserId.String())
+	if err != nil {
+		result.Fail(httpErrors.NewInternalServerError(err))
+		return result
+	}
+
+	switch {
+	case current == nil && change.Op == model.OpDelete:
+		// created and deleted on the client before it was ever pushed
+		result.Status = model.StatusApplied
+		return result
+	case current == nil:
+		created, err := s.expenseService.Insert(ctx, change.Expense)
+		if err != nil {
+			result.Fail(err)
+			return result
+		}
+		result.Status, result.Data = model.StatusApplied, created
+		return result
+	case current.DeletedAt.Valid && change.Op == model.OpDelete:
+		result.Status = model.StatusApplied
+		return result
+	case current.DeletedAt.Valid:
+		// a deleted expense is never brought back by a client that did not see the deletion
+		result.Status, result.Data = model.StatusConflict, current
+		return result
+	case change.Expense.Version != current.Version && !wins(change, current, strategy):
+		return s.conflict(ctx, result, current)
+	}
+
+	// the change was made on the current version, or wins over it
+	change.Expense.Version = current.Version
+	if change.Op == model.OpDelete {
+		err = s.expenseService.Delete(ctx, change.Expense)
+	} else {
+		result.Data, err = s.expenseService.Update(ctx, change.Expense)
+	}
+	if err != nil {
+		// another writer updated the expense between the read and the write
+		var er *httpErrors.Error
+		if errors.As(err, &er) && er.Status == http.StatusPreconditionFailed {
+			return s.conflict(ctx, result, current)
+		}
+		result.Fail(err)
+		return result
+	}
+	result.Status = model.StatusApplied
+	return result
+}
+
+// conflict reports the change as not applied along with the current server copy
+func (s *syncService) conflict(ctx context.Context, result model.PushResult, current *expenseModel.Expense) model.PushResult {
+	result.Status, result.Data = model.StatusConflict, current
+	if latest, err := s.expenseService.GetById(ctx, current.Id.String(), current.UserId.String()); err == nil {
+		result.Data = latest
+	}
+	return result
+}
+
+// wins reports whether a change made on an older version overwrites the server copy,
+// only last-writer-wins does so when the change is at least as recent as the server copy
+func wins(change *model.Change, current *expenseModel.Expense, strategy string) bool {
+	if strategy != model.StrategyLastWriterWins {
+		return false
+	}
+	return time.UnixMilli(change.ModifiedAt).Unix() >= current.UpdatedAt
+}
+
+// attachTags loads the tag names of the expenses, an expense without tag has an empty list
+func (s *syncService) attachTags(ctx context.Context, expenses []expenseModel.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(expenses))
+	for i := range expenses {
+		ids[i] = expenses[i].Id
+		expenses[i].Tags = []string{}
+	}
+	tagged, err := s.tagPgRepo.FindByExpenseIds(ctx, ids)
+	if err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+
+	index := make(map[uuid.UUID]int, len(expenses))
+	for i := range expenses {
+		index[expenses[i].Id] = i
+	}
+	for _, t := range tagged {
+		if i, ok := index[t.ExpenseId]; ok {
+			expenses[i].Tags = append(expenses[i].Tags, t.Name)
+		}
+	}
+	return nil
+}
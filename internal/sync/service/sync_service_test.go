@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	categoryModel "github.com/mrizkisaputra/expenses-api/internal/category/model"
+	expenseMock "github.com/mrizkisaputra/expenses-api/internal/expense/mock"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/sync/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/sync/model"
+	tagMock "github.com/mrizkisaputra/expenses-api/internal/tag/mock"
+	tagModel "github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"github.com/mrizkisaputra/expenses-api/pkg/money"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var cfg = &config.Config{
+	Logger: config.LoggerConfig{Level: "panic"},
+}
+
+func TestSyncService_Pull(t *testing.T) {
+	userId := uuid.New()
+	live, deleted := uuid.New(), uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] The limit oldest writes of every entity are returned in one page", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		sv := NewSyncService(&ServiceConfig{PgRepo: mockRepo, TagPgRepo: mockTagRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		// the tombstone took its sequence first but its transaction committed after the others
+		since := model.Position{Xid: 700, Seq: 10}
+		mockRepo.On("Snapshot", mock.Anything, mock.Anything).Return(nil).Once()
+		mockRepo.On("FindExpensesSince", mock.Anything, userId.String(), since, 4).Return([]expenseModel.Expense{
+			{Id: live, UserId: userId, Description: "coffee", ChangeXid: 700, ChangeSeq: 11},
+			{Id: deleted, UserId: userId, DeletedAt: gorm.DeletedAt{Time: time.UnixMilli(1767225600000), Valid: true}, ChangeXid: 703, ChangeSeq: 14},
+			{Id: uuid.New(), UserId: userId, ChangeXid: 704, ChangeSeq: 20},
+		}, nil)
+		mockRepo.On("FindCategoriesSince", mock.Anything, userId.String(), since, 4).Return([]categoryModel.Category{
+			{Id: uuid.New(), UserId: userId, Name: "food", ChangeXid: 701, ChangeSeq: 12},
+		}, nil)
+		mockRepo.On("FindTagsSince", mock.Anything, userId.String(), since, 4).Return([]tagModel.Tag{}, nil)
+		mockRepo.On("FindTombstonesSince", mock.Anything, userId.String(), since, 4).Return([]model.Tombstone{
+			{UserId: userId, Entity: model.EntityTag, EntityId: uuid.New(), ChangeXid: 702, ChangeSeq: 9},
+		}, nil)
+		mockTagRepo.On("FindByExpenseIds", mock.Anything, []uuid.UUID{live}).
+			Return([]tagModel.TaggedExpense{{ExpenseId: live, Name: "business"}}, nil)
+
+		feed, err := sv.Pull(context.Background(), userId.String(), model.EncodeToken(since), 3)
+		require.NoError(t, err)
+		require.True(t, feed.HasMore)
+		require.Len(t, feed.Expenses, 1)
+		require.Equal(t, []string{"business"}, feed.Expenses[0].Tags)
+		require.Len(t, feed.Categories, 1)
+		require.Len(t, feed.Deleted, 1)
+		require.Equal(t, model.EntityTag, feed.Deleted[0].Entity)
+
+		next, err := model.DecodeToken(feed.NextToken)
+		require.NoError(t, err)
+		require.Equal(t, model.Position{Xid: 702, Seq: 9}, next)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Deleted expenses are returned as tombstones", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockTagRepo := new(tagMock.MockPostgresRepository)
+		sv := NewSyncService(&ServiceConfig{PgRepo: mockRepo, TagPgRepo: mockTagRepo, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("Snapshot", mock.Anything, mock.Anything).Return(nil).Once()
+		mockRepo.On("FindExpensesSince", mock.Anything, userId.String(), model.Position{}, 101).Return([]expenseModel.Expense{
+			{Id: deleted, UserId: userId, DeletedAt: gorm.DeletedAt{Time: time.UnixMilli(1767225600000), Valid: true}, ChangeXid: 700, ChangeSeq: 14},
+		}, nil)
+		mockRepo.On("FindCategoriesSince", mock.Anything, userId.String(), model.Position{}, 101).Return([]categoryModel.Category{}, nil)
+		mockRepo.On("FindTagsSince", mock.Anything, userId.String(), model.Position{}, 101).Return([]tagModel.Tag{}, nil)
+		mockRepo.On("FindTombstonesSince", mock.Anything, userId.String(), model.Position{}, 101).Return([]model.Tombstone{}, nil)
+
+		feed, err := sv.Pull(context.Background(), userId.String(), "", 100)
+		require.NoError(t, err)
+		require.False(t, feed.HasMore)
+		require.Empty(t, feed.Expenses)
+		require.Equal(t, []model.Tombstone{{
+			UserId:    userId,
+			Entity:    model.EntityExpense,
+			EntityId:  deleted,
+			DeletedAt: 1767225600000,
+			ChangeXid: 700,
+			ChangeSeq: 14,
+		}}, feed.Deleted)
+		mockTagRepo.AssertNotCalled(t, "FindByExpenseIds")
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Should return bad request for an invalid token", func(t *testing.T) {
+		sv := NewSyncService(&ServiceConfig{PgRepo: new(mockObject.MockPostgresRepository), Logger: logger.NewLogrusLogger(cfg)})
+
+		_, err := sv.Pull(context.Background(), userId.String(), "not a token", 100)
+		var httpErr *httpErrors.Error
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, http.StatusBadRequest, httpErr.Status)
+	})
+}
+
+func TestSyncService_Push(t *testing.T) {
+	userId := uuid.New()
+	id := uuid.New()
+	amount := money.Amount(4000)
+	serverUpdatedAt := int64(1767225600)
+	current := &expenseModel.Expense{Id: id, UserId: userId, Description: "dinner", Amount: &amount, Version: 5, UpdatedAt: serverUpdatedAt}
+	newChange := func(version int64, modifiedAt int64) []model.Change {
+		return []model.Change{{
+			Op:         model.OpUpsert,
+			Expense:    &expenseModel.Expense{Id: id, UserId: userId, Description: "lunch", Amount: &amount, Version: version},
+			ModifiedAt: modifiedAt,
+		}}
+	}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] A change on the current version is applied", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockExpenseSV := new(expenseMock.ExpenseServiceMock)
+		sv := NewSyncService(&ServiceConfig{PgRepo: mockRepo, ExpenseService: mockExpenseSV, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("FindExpense", mock.Anything, id.String(), userId.String()).Return(current, nil)
+		mockExpenseSV.On("Update", mock.Anything, mock.MatchedBy(func(e *expenseModel.Expense) bool { return e.Version == 5 })).
+			Return(&expenseModel.Expense{Id: id, Description: "lunch", Version: 6}, nil)
+
+		response, err := sv.Push(context.Background(), newChange(5, serverUpdatedAt*1000), "")
+		require.NoError(t, err)
+		require.Equal(t, model.StrategyReport, response.Strategy)
+		require.Equal(t, 1, response.Applied)
+		require.Equal(t, int64(6), response.Results[0].Data.Version)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] A change on a stale version is reported as conflict", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockExpenseSV := new(expenseMock.ExpenseServiceMock)
+		sv := NewSyncService(&ServiceConfig{PgRepo: mockRepo, ExpenseService: mockExpenseSV, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("FindExpense", mock.Anything, id.String(), userId.String()).Return(current, nil)
+		mockExpenseSV.On("GetById", mock.Anything, id.String(), userId.String()).Return(current, nil)
+
+		response, err := sv.Push(context.Background(), newChange(4, (serverUpdatedAt+60)*1000), model.StrategyReport)
+		require.NoError(t, err)
+		require.Equal(t, 1, response.Conflicts)
+		require.Equal(t, model.StatusConflict, response.Results[0].Status)
+		require.Equal(t, "dinner", response.Results[0].Data.Description)
+		mockExpenseSV.AssertNotCalled(t, "Update")
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Last writer wins overwrites an older server copy only", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockExpenseSV := new(expenseMock.ExpenseServiceMock)
+		sv := NewSyncService(&ServiceConfig{PgRepo: mockRepo, ExpenseService: mockExpenseSV, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("FindExpense", mock.Anything, id.String(), userId.String()).Return(current, nil)
+		mockExpenseSV.On("GetById", mock.Anything, id.String(), userId.String()).Return(current, nil)
+		mockExpenseSV.On("Update", mock.Anything, mock.MatchedBy(func(e *expenseModel.Expense) bool { return e.Version == 5 })).
+			Return(&expenseModel.Expense{Id: id, Description: "lunch", Version: 6}, nil)
+
+		changes := append(newChange(4, (serverUpdatedAt+60)*1000), newChange(4, (serverUpdatedAt-60)*1000)...)
+		response, err := sv.Push(context.Background(), changes, model.StrategyLastWriterWins)
+		require.NoError(t, err)
+		require.Equal(t, model.StatusApplied, response.Results[0].Status)
+		require.Equal(t, model.StatusConflict, response.Results[1].Status)
+		mockExpenseSV.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	// scenario test case #4
+	t.Run("[Test Case #4] An expense created offline is inserted and a deleted one is not brought back", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		mockExpenseSV := new(expenseMock.ExpenseServiceMock)
+		sv := NewSyncService(&ServiceConfig{PgRepo: mockRepo, ExpenseService: mockExpenseSV, Logger: logger.NewLogrusLogger(cfg)})
+
+		created, removed := uuid.New(), uuid.New()
+		mockRepo.On("FindExpense", mock.Anything, created.String(), userId.String()).Return(nil, nil)
+		mockRepo.On("FindExpense", mock.Anything, removed.String(), userId.String()).
+			Return(&expenseModel.Expense{Id: removed, UserId: userId, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}, nil)
+		mockExpenseSV.On("Insert", mock.Anything, mock.Anything).Return(&expenseModel.Expense{Id: created, Version: 1}, nil)
+
+		response, err := sv.Push(context.Background(), []model.Change{
+			{Op: model.OpUpsert, Expense: &expenseModel.Expense{Id: created, UserId: userId, Amount: &amount}, ModifiedAt: time.Now().UnixMilli()},
+			{Op: model.OpUpsert, Expense: &expenseModel.Expense{Id: removed, UserId: userId, Amount: &amount}, ModifiedAt: time.Now().UnixMilli()},
+			{Op: model.OpUpsert, Expense: &expenseModel.Expense{Id: uuid.New(), UserId: userId}, Err: httpErrors.NewBadRequestError(nil)},
+		}, model.StrategyLastWriterWins)
+		require.NoError(t, err)
+		require.Equal(t, 1, response.Applied)
+		require.Equal(t, 1, response.Conflicts)
+		require.Equal(t, 1, response.Failed)
+		require.Equal(t, model.StatusConflict, response.Results[1].Status)
+	})
+}
@@ -0,0 +1,16 @@
+package sync
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/sync/model"
+)
+
+// SyncService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type SyncService interface {
+	// Pull returns at most limit entities written since the token
+	Pull(ctx context.Context, userId, token string, limit int) (*model.ChangeFeed, error)
+
+	// Push applies the changes made by a client while offline
+	Push(ctx context.Context, changes []model.Change, strategy string) (*model.PushResponse, error)
+}
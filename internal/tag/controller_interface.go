@@ -0,0 +1,17 @@
+package tag
+
+import "github.com/gin-gonic/gin"
+
+// TagController defines methods the routes expects
+// any controllers it interacts with to implement
+type TagController interface {
+	CreateTag() gin.HandlerFunc
+
+	UpdateTag() gin.HandlerFunc
+
+	DeleteTag() gin.HandlerFunc
+
+	GetTagById() gin.HandlerFunc
+
+	GetAllTag() gin.HandlerFunc
+}
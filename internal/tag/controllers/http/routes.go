@@ -0,0 +1,16 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/internal/tag"
+)
+
+func MapTagRoutes(tagGroup *gin.RouterGroup, controller tag.TagController, mw *middleware.MiddlewareManager) {
+	tagGroup.Use(mw.AuthJwtMiddleware())
+	tagGroup.POST("/", mw.IdempotencyMiddleware(), controller.CreateTag())
+	tagGroup.GET("/", controller.GetAllTag())
+	tagGroup.GET("/:id", controller.GetTagById())
+	tagGroup.PATCH("/:id", mw.IdempotencyMiddleware(), controller.UpdateTag())
+	tagGroup.DELETE("/:id", mw.IdempotencyMiddleware(), controller.DeleteTag())
+}
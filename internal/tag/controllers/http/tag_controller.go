@@ -0,0 +1,194 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/internal/tag"
+	"github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type ControllerConfig struct {
+	TagService tag.TagService
+	Logger     *logrus.Logger
+}
+
+// tagController acts as a struct for injecting an implementation of TagController interface
+// for use in controller methods
+type tagController struct {
+	tagService tag.TagService
+	logger     *logrus.Logger
+}
+
+// NewTagController is a factory function
+// initializing a tagController with its service layer dependencies
+func NewTagController(config *ControllerConfig) tag.TagController {
+	return &tagController{
+		tagService: config.TagService,
+		logger:     config.Logger,
+	}
+}
+
+func (tc tagController) CreateTag() gin.HandlerFunc {
+	type CreateTagRequest struct {
+		Name string `json:"name" validate:"required,max=50"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(CreateTagRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, tc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Tag{
+			UserId: auth.Id,
+			Name:   request.Name,
+		}
+		response, err := tc.tagService.Create(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, tc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, &expenseModel.ApiResponse{
+			Status:  http.StatusCreated,
+			Message: "Created",
+			Data:    response,
+		})
+	}
+}
+
+func (tc tagController) UpdateTag() gin.HandlerFunc {
+	type UpdateTagRequest struct {
+		Id   string `json:"-" validate:"required,uuid"`
+		Name string `json:"name" validate:"required,max=50"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(UpdateTagRequest)
+		request.Id = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, tc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Tag{
+			Id:     uuid.MustParse(request.Id),
+			UserId: auth.Id,
+			Name:   request.Name,
+		}
+		response, err := tc.tagService.Update(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, tc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, &expenseModel.ApiResponse{
+			Status:  http.StatusAccepted,
+			Message: "Accepted",
+			Data:    response,
+		})
+	}
+}
+
+func (tc tagController) DeleteTag() gin.HandlerFunc {
+	type DeleteTagRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &DeleteTagRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, tc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := &model.Tag{
+			Id:     uuid.MustParse(request.Id),
+			UserId: auth.Id,
+		}
+		if err := tc.tagService.Delete(ctx, entity); err != nil {
+			utils.LogErrorResponse(ctx, tc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusNoContent, &expenseModel.ApiResponse{
+			Status:  http.StatusNoContent,
+			Message: "No Content",
+		})
+	}
+}
+
+func (tc tagController) GetTagById() gin.HandlerFunc {
+	type GetTagRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &GetTagRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, tc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := tc.tagService.GetById(ctx, request.Id, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, tc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (tc tagController) GetAllTag() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		tags, err := tc.tagService.GetAll(ctx, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, tc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       tags,
+			TotalItems: int64(len(tags)),
+		})
+	}
+}
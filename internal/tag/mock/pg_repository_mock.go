@@ -0,0 +1,80 @@
+package mock
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/tag"
+	"github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockPostgresRepository is a mock type for tag.PostgresRepository
+type MockPostgresRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostgresRepository) Create(ctx context.Context, tag *model.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Tag, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Tag), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Tag, error) {
+	args := m.Called(ctx, name, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Tag), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindAllByUserId(ctx context.Context, userId string) ([]model.Tag, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Tag), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) Update(ctx context.Context, tag *model.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) Remove(ctx context.Context, tag *model.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindOrCreateByNames(ctx context.Context, userId uuid.UUID, names []string) ([]model.Tag, error) {
+	args := m.Called(ctx, userId, names)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.Tag), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) ReplaceForExpense(ctx context.Context, expenseId uuid.UUID, tagIds []uuid.UUID) error {
+	args := m.Called(ctx, expenseId, tagIds)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindByExpenseIds(ctx context.Context, expenseIds []uuid.UUID) ([]model.TaggedExpense, error) {
+	args := m.Called(ctx, expenseIds)
+	if args.Get(0) != nil {
+		return args.Get(0).([]model.TaggedExpense), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// WithTx returns the mock itself so expectations hold inside transactions
+func (m *MockPostgresRepository) WithTx(tx *gorm.DB) tag.PostgresRepository {
+	return m
+}
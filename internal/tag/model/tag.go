@@ -0,0 +1,80 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"regexp"
+	"strings"
+)
+
+// validName is a lowercase label such as "business" or "trip-bali-2026"
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,49}$`)
+
+// Tag mapping table 'tags'.
+// tags are owned by a user and attached to any number of expenses
+type Tag struct {
+	Id         uuid.UUID `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	UserId     uuid.UUID `json:"user_id" gorm:"column:id_user"`
+	Name       string    `json:"name" gorm:"column:name"`
+	UsageCount *int64    `json:"usage_count,omitempty" gorm:"column:usage_count;->"`                 // read only, filled on listing
+	CreatedAt  int64     `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt  int64     `json:"updated_at" gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+	ChangeXid  int64     `json:"-" gorm:"column:change_xid;->"` // transaction of the last write in the sync change feed
+	ChangeSeq  int64     `json:"-" gorm:"column:change_seq;->"` // position of the last write in the sync change feed
+}
+
+func (tag *Tag) TableName() string {
+	return "tags"
+}
+
+func (tag *Tag) PrepareCreate() {
+	tag.Name = NormalizeName(tag.Name)
+}
+
+func (tag *Tag) PrepareUpdate(oldTag *Tag) {
+	if tag.Name != "" {
+		oldTag.Name = NormalizeName(tag.Name)
+	}
+}
+
+// ExpenseTag mapping table 'expense_tags', the join table between expenses and tags
+type ExpenseTag struct {
+	ExpenseId uuid.UUID `gorm:"column:id_expense;primary_key"`
+	TagId     uuid.UUID `gorm:"column:id_tag;primary_key"`
+	CreatedAt int64     `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (expenseTag *ExpenseTag) TableName() string {
+	return "expense_tags"
+}
+
+// TaggedExpense is a tag attached to an expense, read when loading the tags of many expenses at once
+type TaggedExpense struct {
+	ExpenseId uuid.UUID `gorm:"column:id_expense"`
+	Id        uuid.UUID `gorm:"column:id"`
+	Name      string    `gorm:"column:name"`
+}
+
+// NormalizeName lowercases the name and trims whitespaces
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ValidName reports whether an already normalized name can be used as a tag
+func ValidName(name string) bool {
+	return validName.MatchString(name)
+}
+
+// NormalizeNames normalizes names, drops duplicates and keeps the order of first appearance
+func NormalizeNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = NormalizeName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
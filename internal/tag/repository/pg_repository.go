@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/tag"
+	"github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewTagPgRepository(db *gorm.DB) tag.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (p *postgresRepository) WithTx(tx *gorm.DB) tag.PostgresRepository {
+	return &postgresRepository{
+		db: tx,
+	}
+}
+
+func (p *postgresRepository) Create(ctx context.Context, tag *model.Tag) error {
+	/**
+	SQL: INSERT INTO "tags" ("id_user","name","created_at","updated_at") VALUES (?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(tag).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Create")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Tag, error) {
+	/**
+	SQL: SELECT * FROM "tags" WHERE id = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	tag := new(model.Tag)
+	if err := DB.Where("id = ? AND id_user = ?", id, userId).Take(tag).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByIdAndUserId")
+	}
+	return tag, nil
+}
+
+func (p *postgresRepository) FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Tag, error) {
+	/**
+	SQL: SELECT * FROM "tags" WHERE name = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	tag := new(model.Tag)
+	if err := DB.Where("name = ? AND id_user = ?", name, userId).Take(tag).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByNameAndUserId")
+	}
+	return tag, nil
+}
+
+func (p *postgresRepository) FindAllByUserId(ctx context.Context, userId string) ([]model.Tag, error) {
+	/**
+	SQL: SELECT tags.*, COUNT(expenses.id) AS usage_count FROM "tags"
+	LEFT JOIN expense_tags ON expense_tags.id_tag = tags.id
+	LEFT JOIN expenses ON expenses.id = expense_tags.id_expense AND expenses.deleted_at IS NULL
+	WHERE tags.id_user = ? GROUP BY "tags"."id" ORDER BY tags.name
+	*/
+	DB := p.db.WithContext(ctx)
+	var tags []model.Tag
+	if err := DB.Select("tags.*, COUNT(expenses.id) AS usage_count").
+		Joins("LEFT JOIN expense_tags ON expense_tags.id_tag = tags.id").
+		Joins("LEFT JOIN expenses ON expenses.id = expense_tags.id_expense AND expenses.deleted_at IS NULL").
+		Where("tags.id_user = ?", userId).
+		Group("tags.id").
+		Order("tags.name").
+		Find(&tags).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindAllByUserId")
+	}
+	return tags, nil
+}
+
+func (p *postgresRepository) Update(ctx context.Context, tag *model.Tag) error {
+	/**
+	SQL: UPDATE "tags" SET "id_user"=?,"name"=?,"updated_at"=? WHERE (id = ? AND id_user = ?) AND "id" = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", tag.Id, tag.UserId).Updates(tag).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Update")
+	}
+	return nil
+}
+
+func (p *postgresRepository) Remove(ctx context.Context, tag *model.Tag) error {
+	/**
+	SQL: DELETE FROM "tags" WHERE id = ? AND id_user = ?
+	the links to expenses are removed by the foreign key cascade
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", tag.Id, tag.UserId).Delete(&model.Tag{}).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.Remove")
+	}
+	return nil
+}
+
+// FindOrCreateByNames returns the tags of the user with the given (normalized) names,
+// the missing ones are created
+func (p *postgresRepository) FindOrCreateByNames(ctx context.Context, userId uuid.UUID, names []string) ([]model.Tag, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	DB := p.db.WithContext(ctx)
+	tags := make([]model.Tag, len(names))
+	for i, name := range names {
+		tags[i] = model.Tag{UserId: userId, Name: name}
+	}
+
+	/**
+	SQL: INSERT INTO "tags" ("id_user","name","created_at","updated_at") VALUES (?,?,?,?),...
+	ON CONFLICT ("id_user","name") DO NOTHING RETURNING "id"
+	*/
+	if err := DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id_user"}, {Name: "name"}},
+		DoNothing: true,
+	}).Create(&tags).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindOrCreateByNames.Create")
+	}
+
+	/**
+	SQL: SELECT * FROM "tags" WHERE id_user = ? AND name IN (?) ORDER BY name
+	*/
+	var found []model.Tag
+	if err := DB.Where("id_user = ? AND name IN ?", userId, names).Order("name").Find(&found).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindOrCreateByNames.Find")
+	}
+	return found, nil
+}
+
+// ReplaceForExpense sets the tags of an expense to exactly tagIds
+func (p *postgresRepository) ReplaceForExpense(ctx context.Context, expenseId uuid.UUID, tagIds []uuid.UUID) error {
+	DB := p.db.WithContext(ctx)
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		/**
+		SQL: DELETE FROM "expense_tags" WHERE id_expense = ?
+		*/
+		if err := tx.Where("id_expense = ?", expenseId).Delete(&model.ExpenseTag{}).Error; err != nil {
+			return err
+		}
+		if len(tagIds) == 0 {
+			return nil
+		}
+
+		/**
+		SQL: INSERT INTO "expense_tags" ("id_expense","id_tag","created_at") VALUES (?,?,?),...
+		*/
+		links := make([]model.ExpenseTag, len(tagIds))
+		for i, tagId := range tagIds {
+			links[i] = model.ExpenseTag{ExpenseId: expenseId, TagId: tagId}
+		}
+		return tx.Create(&links).Error
+	})
+	if err != nil {
+		return errors.Wrap(err, "postgresRepository.ReplaceForExpense")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindByExpenseIds(ctx context.Context, expenseIds []uuid.UUID) ([]model.TaggedExpense, error) {
+	if len(expenseIds) == 0 {
+		return nil, nil
+	}
+
+	/**
+	SQL: SELECT expense_tags.id_expense, tags.id, tags.name FROM expense_tags
+	JOIN tags ON tags.id = expense_tags.id_tag WHERE expense_tags.id_expense IN (?) ORDER BY tags.name
+	*/
+	DB := p.db.WithContext(ctx)
+	var tagged []model.TaggedExpense
+	if err := DB.Table("expense_tags").
+		Select("expense_tags.id_expense, tags.id, tags.name").
+		Joins("JOIN tags ON tags.id = expense_tags.id_tag").
+		Where("expense_tags.id_expense IN ?", expenseIds).
+		Order("tags.name").
+		Scan(&tagged).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindByExpenseIds")
+	}
+	return tagged, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/tag"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	repository tag.PostgresRepository
+	mock       sqlmock.Sqlmock
+)
+
+// Test Before and After
+func TestMain(m *testing.M) {
+	// initialize mock sql db
+	mockDb, mocking, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("Failed to open mock sql db, got error %v", err)
+	}
+	defer mockDb.Close()
+	mock = mocking
+
+	// initialize db connection
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDb}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database, got error %v", err)
+	}
+
+	// initialize repository
+	repository = NewTagPgRepository(db)
+
+	os.Exit(m.Run())
+}
+
+func TestTagPostgresRepository_FindAllByUserId(t *testing.T) {
+	userId := uuid.New()
+
+	// define expect sql
+	expectedSQL := regexp.QuoteMeta(`SELECT tags.*, COUNT(expenses.id) AS usage_count FROM "tags" LEFT JOIN expense_tags ON expense_tags.id_tag = tags.id LEFT JOIN expenses ON expenses.id = expense_tags.id_expense AND expenses.deleted_at IS NULL WHERE tags.id_user = $1 GROUP BY "tags"."id" ORDER BY tags.name`)
+	rows := sqlmock.NewRows([]string{"id", "id_user", "name", "created_at", "updated_at", "usage_count"}).
+		AddRow(uuid.New(), userId, "business", time.Now().UnixMilli(), time.Now().UnixMilli(), 7)
+	mock.ExpectQuery(expectedSQL).WithArgs(userId.String()).WillReturnRows(rows)
+
+	tags, err := repository.FindAllByUserId(context.Background(), userId.String())
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	require.Equal(t, "business", tags[0].Name)
+	require.Equal(t, int64(7), *tags[0].UsageCount)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
+
+func TestTagPostgresRepository_ReplaceForExpense(t *testing.T) {
+	expenseId := uuid.New()
+	tagId := uuid.New()
+
+	// define expect sql
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "expense_tags" WHERE id_expense = $1`)).
+		WithArgs(expenseId).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "expense_tags" ("id_expense","id_tag","created_at") VALUES ($1,$2,$3)`)).
+		WithArgs(expenseId, tagId, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repository.ReplaceForExpense(context.Background(), expenseId, []uuid.UUID{tagId})
+	require.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Failed to meet expectations, got error: %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package tag
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"gorm.io/gorm"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	Create(ctx context.Context, tag *model.Tag) error
+
+	FindByIdAndUserId(ctx context.Context, id, userId string) (*model.Tag, error)
+
+	FindByNameAndUserId(ctx context.Context, name, userId string) (*model.Tag, error)
+
+	FindAllByUserId(ctx context.Context, userId string) ([]model.Tag, error)
+
+	Update(ctx context.Context, tag *model.Tag) error
+
+	Remove(ctx context.Context, tag *model.Tag) error
+
+	FindOrCreateByNames(ctx context.Context, userId uuid.UUID, names []string) ([]model.Tag, error)
+
+	ReplaceForExpense(ctx context.Context, expenseId uuid.UUID, tagIds []uuid.UUID) error
+
+	FindByExpenseIds(ctx context.Context, expenseIds []uuid.UUID) ([]model.TaggedExpense, error)
+
+	// WithTx returns a repository running its queries in the transaction tx
+	WithTx(tx *gorm.DB) PostgresRepository
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/tag"
+	"github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type ServiceConfig struct {
+	PgRepo tag.PostgresRepository
+	Config *config.Config
+	Logger *logrus.Logger
+}
+
+// tagService acts as a struct for injecting an implementation of TagService interface
+// for use in service methods.
+type tagService struct {
+	pgRepo tag.PostgresRepository
+}
+
+// NewTagService is a factory function for
+// initializing a tagService with its repository layer dependencies
+func NewTagService(config *ServiceConfig) tag.TagService {
+	return &tagService{
+		pgRepo: config.PgRepo,
+	}
+}
+
+func (t *tagService) Create(ctx context.Context, request *model.Tag) (*model.Tag, error) {
+	request.PrepareCreate()
+
+	if err := t.validate(ctx, request); err != nil {
+		return nil, err
+	}
+
+	if err := t.pgRepo.Create(ctx, request); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return request, nil
+}
+
+func (t *tagService) Update(ctx context.Context, request *model.Tag) (*model.Tag, error) {
+	tags, err := t.pgRepo.FindByIdAndUserId(ctx, request.Id.String(), request.UserId.String())
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+
+	request.PrepareUpdate(tags)
+
+	if err := t.validate(ctx, tags); err != nil {
+		return nil, err
+	}
+
+	if err := t.pgRepo.Update(ctx, tags); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return tags, nil
+}
+
+func (t *tagService) Delete(ctx context.Context, request *model.Tag) error {
+	tags, err := t.pgRepo.FindByIdAndUserId(ctx, request.Id.String(), request.UserId.String())
+	if err != nil {
+		return httpErrors.NewNotFoundError(err)
+	}
+
+	if err := t.pgRepo.Remove(ctx, tags); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+func (t *tagService) GetById(ctx context.Context, id, userId string) (*model.Tag, error) {
+	tags, err := t.pgRepo.FindByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	return tags, nil
+}
+
+// GetAll returns every tag of the user with the number of expenses using it
+func (t *tagService) GetAll(ctx context.Context, userId string) ([]model.Tag, error) {
+	tags, err := t.pgRepo.FindAllByUserId(ctx, userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return tags, nil
+}
+
+// validate ensures the name is a valid label and is not used by another tag of the user
+func (t *tagService) validate(ctx context.Context, request *model.Tag) error {
+	if !model.ValidName(request.Name) {
+		return httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidTagMsg, nil)
+	}
+
+	found, err := t.pgRepo.FindByNameAndUserId(ctx, request.Name, request.UserId.String())
+	if err == nil && found != nil && found.Id != request.Id {
+		return httpErrors.NewError(http.StatusConflict, httpErrors.TagAlreadyExistsMsg, nil)
+	}
+	return nil
+}
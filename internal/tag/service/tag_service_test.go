@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/tag/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/tag/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+)
+
+func TestTagService_Create(t *testing.T) {
+	userId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Successfully create a normalized tag", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewTagService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindByNameAndUserId", mock.Anything, "trip-bali-2026", userId.String()).Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		response, err := sv.Create(context.Background(), &model.Tag{UserId: userId, Name: " Trip-Bali-2026 "})
+		require.NoError(t, err)
+		require.Equal(t, "trip-bali-2026", response.Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return bad request for an invalid name", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewTagService(&ServiceConfig{PgRepo: mockRepo})
+
+		_, err := sv.Create(context.Background(), &model.Tag{UserId: userId, Name: "two words"})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] Should return conflict when the name already exists", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewTagService(&ServiceConfig{PgRepo: mockRepo})
+
+		mockRepo.On("FindByNameAndUserId", mock.Anything, "business", userId.String()).
+			Return(&model.Tag{Id: uuid.New(), UserId: userId, Name: "business"}, nil)
+
+		_, err := sv.Create(context.Background(), &model.Tag{UserId: userId, Name: "Business"})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusConflict, er.Status)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestTagService_Delete(t *testing.T) {
+	userId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Should return not found for a tag of another user", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewTagService(&ServiceConfig{PgRepo: mockRepo})
+
+		id := uuid.New()
+		mockRepo.On("FindByIdAndUserId", mock.Anything, id.String(), userId.String()).Return(nil, gorm.ErrRecordNotFound)
+
+		err := sv.Delete(context.Background(), &model.Tag{Id: id, UserId: userId})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusNotFound, er.Status)
+		mockRepo.AssertNotCalled(t, "Remove")
+	})
+}
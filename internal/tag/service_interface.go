@@ -0,0 +1,20 @@
+package tag
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/tag/model"
+)
+
+// TagService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type TagService interface {
+	Create(ctx context.Context, request *model.Tag) (*model.Tag, error)
+
+	Update(ctx context.Context, request *model.Tag) (*model.Tag, error)
+
+	Delete(ctx context.Context, request *model.Tag) error
+
+	GetById(ctx context.Context, id, userId string) (*model.Tag, error)
+
+	GetAll(ctx context.Context, userId string) ([]model.Tag, error)
+}
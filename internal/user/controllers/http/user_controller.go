@@ -67,8 +67,9 @@ func (u *userController) Update() gin.HandlerFunc {
 		}
 
 		userResponse, err := u.service.Update(context.Background(), &model.User{
-			Id:    auth.Id,
-			Email: request.Email,
+			Id:           auth.Id,
+			Email:        request.Email,
+			BaseCurrency: request.BaseCurrency,
 			Information: model.Information{
 				FirstName:   request.FirstName,
 				LastName:    request.LastName,
@@ -2,8 +2,10 @@ package mock
 
 import (
 	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/user"
 	"github.com/mrizkisaputra/expenses-api/internal/user/model"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 // UserPostgresRepositoryMock is a mock type for user.UserPostgresRepository
@@ -47,3 +49,18 @@ func (u *UserPostgresRepositoryMock) FindAlreadyExistByEmail(ctx context.Context
 	//TODO implement me
 	panic("implement me")
 }
+
+// Transaction records the call and runs fn without transaction, the error of the
+// expectation (if any) is returned instead of running fn
+func (u *UserPostgresRepositoryMock) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	args := u.Called(ctx, fn)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(nil)
+}
+
+// WithTx returns the mock itself so expectations hold inside transactions
+func (u *UserPostgresRepositoryMock) WithTx(tx *gorm.DB) user.UserPostgresRepository {
+	return u
+}
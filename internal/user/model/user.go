@@ -8,15 +8,19 @@ import (
 	"strings"
 )
 
+// DefaultBaseCurrency is the ISO-4217 currency reports are converted to when the user did not choose one
+const DefaultBaseCurrency = "IDR"
+
 // Mapping tabel 'users'
 type User struct {
-	Id          uuid.UUID   `gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
-	Email       string      `gorm:"column:email"`
-	Password    string      `gorm:"column:password"`
-	Avatar      string      `gorm:"column:avatar"`
-	Information Information `gorm:"embedded"`
-	CreatedAt   int64       `gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
-	UpdatedAt   int64       `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+	Id           uuid.UUID   `gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	Email        string      `gorm:"column:email"`
+	Password     string      `gorm:"column:password"`
+	Avatar       string      `gorm:"column:avatar"`
+	Information  Information `gorm:"embedded"`
+	BaseCurrency string      `gorm:"column:base_currency"`
+	CreatedAt    int64       `gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt    int64       `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
 }
 
 func (u *User) TableName() string {
@@ -37,6 +41,10 @@ func (u *User) HashPassword() error {
 func (u *User) PrepareCreate() error {
 	u.Email = strings.ToLower(strings.TrimSpace(u.Email))
 	u.Password = strings.TrimSpace(u.Password)
+	u.BaseCurrency = strings.ToUpper(strings.TrimSpace(u.BaseCurrency))
+	if u.BaseCurrency == "" {
+		u.BaseCurrency = DefaultBaseCurrency
+	}
 
 	if err := u.HashPassword(); err != nil {
 		return err
@@ -82,5 +90,9 @@ func (u *User) PrepareUpdate(oldData *User) error {
 		oldData.Information.PhoneNumber = strings.TrimSpace(u.Information.PhoneNumber)
 	}
 
+	if u.BaseCurrency != "" {
+		oldData.BaseCurrency = strings.ToUpper(strings.TrimSpace(u.BaseCurrency))
+	}
+
 	return nil
 }
@@ -22,7 +22,7 @@ func NewUserPostgresRepository(db *gorm.DB) UserPostgresRepository {
 func (u *userPostgresRepository) Create(ctx context.Context, entity *model.User) (*model.User, error) {
 	db := u.db.WithContext(ctx)
 	/**
-	INSERT INTO "users" ("email","password","first_name","last_name","created_at","updated_at")
+	INSERT INTO "users" ("email","password","first_name","last_name","base_currency","created_at","updated_at")
 	VALUES (?,?,?,?,?,?) RETURNING "id"
 	*/
 	if err := db.Omit("avatar", "city", "phone_number").Create(entity).Error; err != nil {
@@ -75,3 +75,15 @@ func (u *userPostgresRepository) FindAlreadyExistByEmail(ctx context.Context, en
 	}
 	return total, nil
 }
+
+func (u *userPostgresRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	/**
+	SQL: BEGIN; ...; COMMIT (or ROLLBACK when fn fails)
+	*/
+	DB := u.db.WithContext(ctx)
+	return DB.Transaction(fn)
+}
+
+func (u *userPostgresRepository) WithTx(tx *gorm.DB) UserPostgresRepository {
+	return &userPostgresRepository{db: tx}
+}
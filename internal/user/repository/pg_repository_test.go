@@ -18,7 +18,7 @@ import (
 )
 
 var (
-	expectedCreateUserQuery   = regexp.QuoteMeta(`INSERT INTO "users" ("email","password","first_name","last_name","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)
+	expectedCreateUserQuery   = regexp.QuoteMeta(`INSERT INTO "users" ("email","password","first_name","last_name","base_currency","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)
 	expectedUpdateUserQuery   = regexp.QuoteMeta(`UPDATE "users" SET "email"=$1,"password"=$2,"avatar"=$3,"first_name"=$4,"last_name"=$5,"city"=$6,"phone_number"=$7,"updated_at"=$8 WHERE id = $9`)
 	expectedFindUserByIdQuery = regexp.QuoteMeta(`SELECT * FROM "users" WHERE "users"."id" = $1 LIMIT $2`)
 	expectedFindAlreadyEmail  = regexp.QuoteMeta(`SELECT count(*) FROM "users" WHERE "users"."email" = $1`)
@@ -67,14 +67,15 @@ func TestUserPostgresRepository_Create(t *testing.T) {
 			FirstName: "muhammat",
 			LastName:  "saputra",
 		},
-		Email:    "mrizkisaputra6@gmail.com",
-		Password: "secret",
+		Email:        "mrizkisaputra6@gmail.com",
+		Password:     "secret",
+		BaseCurrency: "IDR",
 	}
 
 	// define expected sql query
 	mock.ExpectBegin()
 	mock.ExpectQuery(expectedCreateUserQuery).
-		WithArgs(usr.Email, usr.Password, usr.Information.FirstName, usr.Information.LastName, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs(usr.Email, usr.Password, usr.Information.FirstName, usr.Information.LastName, usr.BaseCurrency, sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
 	mock.ExpectCommit()
 
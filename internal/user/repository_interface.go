@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/minio/minio-go/v7"
 	"github.com/mrizkisaputra/expenses-api/internal/user/model"
+	"gorm.io/gorm"
 	"net/url"
 	"time"
 )
@@ -20,6 +21,13 @@ type UserPostgresRepository interface {
 	FindById(ctx context.Context, entity *model.User) (*model.User, error)
 
 	FindAlreadyExistByEmail(ctx context.Context, entity *model.User) (int64, error)
+
+	// Transaction runs fn in one database transaction, committed when fn returns nil and
+	// rolled back otherwise. repositories given tx with WithTx run their queries in it
+	Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+
+	// WithTx returns a repository running its queries in the transaction tx
+	WithTx(tx *gorm.DB) UserPostgresRepository
 }
 
 // UserRedisRepository defines methods the services layer expects.
@@ -2,29 +2,38 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	notificationModel "github.com/mrizkisaputra/expenses-api/internal/notification/model"
 	"github.com/mrizkisaputra/expenses-api/internal/user"
 	"github.com/mrizkisaputra/expenses-api/internal/user/model"
 	"github.com/mrizkisaputra/expenses-api/internal/user/model/dto"
 	"github.com/mrizkisaputra/expenses-api/pkg/converter"
 	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
 	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/sirupsen/logrus"
 	"net/http"
+	"time"
 )
 
 // authService acts as a struct for injecting an implementation of AuthService interface
 // for use in service methods.
 type authService struct {
-	cfg    *config.Config
-	pgRepo user.UserPostgresRepository
+	cfg       *config.Config
+	pgRepo    user.UserPostgresRepository
+	publisher notification.Publisher
+	logger    *logrus.Logger
 }
 
 // NewAuthService is a factory function for
 // initializing a authService with its repository layer dependencies
 func NewAuthService(config *ServiceConfig) user.AuthService {
 	return &authService{
-		cfg:    config.Config,
-		pgRepo: config.UserPostgresRepository,
+		cfg:       config.Config,
+		pgRepo:    config.UserPostgresRepository,
+		publisher: config.Publisher,
+		logger:    config.Logger,
 	}
 }
 
@@ -66,8 +75,29 @@ func (auth *authService) Login(ctx context.Context, user *model.User) (*dto.JwtT
 		return nil, httpErrors.NewInternalServerError(err)
 	}
 
+	auth.notifyLogin(ctx, foundUser)
 	return &dto.JwtToken{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 	}, nil
 }
+
+// notifyLogin publishes a security notification of the new sign-in of the user,
+// a failure only loses the notification and never fails the login
+func (auth *authService) notifyLogin(ctx context.Context, user *model.User) {
+	if auth.publisher == nil {
+		return
+	}
+	signedInAt := time.Now().UTC()
+	err := auth.publisher.Publish(ctx, &notificationModel.Notification{
+		UserId: user.Id,
+		Kind:   notificationModel.KindSecurityLogin,
+		Title:  "New sign-in to your account",
+		Body: fmt.Sprintf("Your account was signed in to on %s UTC. If it was not you, change your password now.",
+			signedInAt.Format("2006-01-02 15:04")),
+		Data: notificationModel.Data{"signed_in_at": signedInAt.Format(time.RFC3339)},
+	})
+	if err != nil && auth.logger != nil {
+		auth.logger.WithError(err).WithField("userId", user.Id).Warn("authService.notifyLogin.Publish")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"github.com/mrizkisaputra/expenses-api/config"
+	notificationMock "github.com/mrizkisaputra/expenses-api/internal/notification/mock"
+	notificationModel "github.com/mrizkisaputra/expenses-api/internal/notification/model"
 	mockObject "github.com/mrizkisaputra/expenses-api/internal/user/mock"
 	"github.com/mrizkisaputra/expenses-api/internal/user/model"
 	"github.com/mrizkisaputra/expenses-api/internal/user/model/dto"
@@ -117,6 +119,41 @@ func TestAuthService_Login_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestAuthService_Login_PublishesNotification(t *testing.T) {
+	mockRepo := new(mockObject.UserPostgresRepositoryMock)
+	mockPublisher := new(notificationMock.PublisherMock)
+	auth := NewAuthService(&ServiceConfig{
+		Config: &config.Config{
+			Server: config.ServerConfig{
+				JWTSecretKey: "secret_key",
+			},
+		},
+		UserPostgresRepository: mockRepo,
+		Publisher:              mockPublisher,
+	})
+
+	// arrange mock for successfully login, the notification failing does not fail the login
+	userId := uuid.New()
+	mockRepo.On("FindByEmail", mock.Anything, mock.Anything).
+		Return(&model.User{
+			Id:       userId,
+			Email:    "mrizkisaputra@test.com",
+			Password: "$2a$12$WX/or6PO4Ue2CqmlOBMREufvVDcDuSq9cx/AgcqYU/pf.lkiTEP1O", //secret
+		}, nil)
+	mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(notification *notificationModel.Notification) bool {
+		return notification.UserId == userId && notification.Kind == notificationModel.KindSecurityLogin
+	})).Return(errors.New("connection refused"))
+
+	response, err := auth.Login(context.Background(), &model.User{
+		Email:    "mrizkisaputra@test.com",
+		Password: "secret",
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, response)
+	mockPublisher.AssertExpectations(t)
+}
+
 func TestAuthService_Login_EmailInvalid(t *testing.T) {
 	mockRepo := new(mockObject.UserPostgresRepositoryMock)
 	auth := NewAuthService(&ServiceConfig{
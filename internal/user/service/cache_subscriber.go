@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+	"github.com/mrizkisaputra/expenses-api/internal/user"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+// cacheSubscriber deletes the cached user of an event 'user.updated'
+type cacheSubscriber struct {
+	redisRepo user.UserRedisRepository
+}
+
+// NewCacheSubscriber is a factory function for
+// initializing the subscriber invalidating the cache of the updated users
+func NewCacheSubscriber(redisRepo user.UserRedisRepository) outbox.Subscriber {
+	return &cacheSubscriber{redisRepo: redisRepo}
+}
+
+func (c *cacheSubscriber) Name() string {
+	return "user-cache"
+}
+
+func (c *cacheSubscriber) Handle(ctx context.Context, event *model.Event) error {
+	if err := c.redisRepo.Delete(ctx, utils.GetRedisKey(basePrefix, event.AggregateId.String())); err != nil {
+		return errors.Wrap(err, "cacheSubscriber.Handle.Delete")
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/notification"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox"
 	"github.com/mrizkisaputra/expenses-api/internal/user"
 	"github.com/sirupsen/logrus"
 )
@@ -12,6 +14,8 @@ type ServiceConfig struct {
 	UserPostgresRepository user.UserPostgresRepository
 	UserRedisRepository    user.UserRedisRepository
 	AwsUserRepository      user.AWSUserRepository
+	OutboxRepository       outbox.PostgresRepository
+	Publisher              notification.Publisher
 	Logger                 *logrus.Logger
 	Config                 *config.Config
 }
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox"
+	outboxModel "github.com/mrizkisaputra/expenses-api/internal/outbox/model"
 	"github.com/mrizkisaputra/expenses-api/internal/user"
 	"github.com/mrizkisaputra/expenses-api/internal/user/model"
 	"github.com/mrizkisaputra/expenses-api/internal/user/model/dto"
@@ -13,6 +15,7 @@ import (
 	"github.com/mrizkisaputra/expenses-api/pkg/utils"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 const (
@@ -23,22 +26,24 @@ const (
 // userService acts as a struct for injecting an implementation of UserService interface
 // for use in service methods.
 type userService struct {
-	cfg       *config.Config
-	pgRepo    user.UserPostgresRepository
-	redisRepo user.UserRedisRepository
-	awsRepo   user.AWSUserRepository
-	logger    *logrus.Logger
+	cfg        *config.Config
+	pgRepo     user.UserPostgresRepository
+	redisRepo  user.UserRedisRepository
+	awsRepo    user.AWSUserRepository
+	outboxRepo outbox.PostgresRepository
+	logger     *logrus.Logger
 }
 
 // NewUserService is a factory function for
 // initializing a userService with its repository layer dependencies.
 func NewUserService(config *ServiceConfig) user.UserService {
 	return &userService{
-		cfg:       config.Config,
-		pgRepo:    config.UserPostgresRepository,
-		redisRepo: config.UserRedisRepository,
-		awsRepo:   config.AwsUserRepository,
-		logger:    config.Logger,
+		cfg:        config.Config,
+		pgRepo:     config.UserPostgresRepository,
+		redisRepo:  config.UserRedisRepository,
+		awsRepo:    config.AwsUserRepository,
+		outboxRepo: config.OutboxRepository,
+		logger:     config.Logger,
 	}
 }
 
@@ -72,7 +77,8 @@ func (u *userService) GetCurrentUser(ctx context.Context, id string) (*dto.UserR
 	return converter.ToUserResponse(currentUser), nil
 }
 
-// Update update current user
+// Update update current user, the event 'user.updated' is written to the outbox in the same
+// transaction so the cache is invalidated even when the deletion below fails
 func (u *userService) Update(ctx context.Context, user *model.User) (*dto.UserResponse, error) {
 	userFound, err := u.pgRepo.FindById(ctx, &model.User{Id: user.Id})
 	if err != nil {
@@ -83,12 +89,27 @@ func (u *userService) Update(ctx context.Context, user *model.User) (*dto.UserRe
 		return nil, httpErrors.NewInternalServerError(err)
 	}
 
-	updatedUser, err := u.pgRepo.Update(ctx, userFound)
+	var updatedUser *model.User
+	err = u.pgRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		updatedUser, err = u.pgRepo.WithTx(tx).Update(ctx, userFound)
+		if err != nil {
+			return errors.Wrap(err, "UserService.Update.Update")
+		}
+		if u.outboxRepo == nil {
+			return nil
+		}
+
+		event, err := outboxModel.NewEvent(outboxModel.AggregateUser, user.Id, user.Id, outboxModel.TypeUserUpdated, map[string]uuid.UUID{"id": user.Id})
+		if err != nil {
+			return err
+		}
+		return u.outboxRepo.WithTx(tx).Create(ctx, event)
+	})
 	if err != nil {
-		return nil, httpErrors.NewInternalServerError(errors.Wrap(err, "UserService.GetCurrentUser.Update"))
+		return nil, httpErrors.NewInternalServerError(err)
 	}
 
-	// delete redis cache data
+	// delete redis cache data, the subscriber of 'user.updated' deletes it again once committed
 	if err := u.redisRepo.Delete(ctx, utils.GetRedisKey(basePrefix, user.Id.String())); err != nil {
 		u.logger.WithError(err).Error("UserService.Update.redisRepo.Delete")
 	}
@@ -5,6 +5,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"github.com/mrizkisaputra/expenses-api/config"
+	outboxMock "github.com/mrizkisaputra/expenses-api/internal/outbox/mock"
+	outboxModel "github.com/mrizkisaputra/expenses-api/internal/outbox/model"
 	mockObject "github.com/mrizkisaputra/expenses-api/internal/user/mock"
 	"github.com/mrizkisaputra/expenses-api/internal/user/model"
 	"github.com/mrizkisaputra/expenses-api/pkg/converter"
@@ -168,6 +170,7 @@ func TestUserService_Update(t *testing.T) {
 		}
 
 		mockPgRepo.On("FindById", mock.Anything, mock.Anything).Return(oldUser, nil)
+		mockPgRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
 		mockPgRepo.On("Update", mock.Anything, mock.Anything).Return(oldUser, nil)
 		mockRedisRepo.On("Delete", mock.Anything, mock.Anything).Return(nil)
 
@@ -198,6 +201,7 @@ func TestUserService_Update(t *testing.T) {
 		}
 
 		mockPgRepo.On("FindById", mock.Anything, mock.Anything).Return(mockUser, nil)
+		mockPgRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
 		mockPgRepo.On("Update", mock.Anything, mock.Anything).Return(nil, httpErrors.NewInternalServerError(nil))
 
 		response, err := us.Update(context.Background(), mockUser)
@@ -215,7 +219,30 @@ func TestUserService_Update(t *testing.T) {
 	})
 
 	// scenario test case #3
+	t.Run("[Test Case #3] The event user.updated is written to the outbox with the update", func(t *testing.T) {
+		mockPgRepo := new(mockObject.UserPostgresRepositoryMock)
+		mockRedisRepo := new(mockObject.UserRedisRepositoryMock)
+		mockOutboxRepo := new(outboxMock.MockPostgresRepository)
+		us := NewUserService(&ServiceConfig{
+			UserPostgresRepository: mockPgRepo,
+			UserRedisRepository:    mockRedisRepo,
+			OutboxRepository:       mockOutboxRepo,
+		})
 
+		id := uuid.New()
+		mockUser := &model.User{Id: id, Email: "mrizkisaputra@gmail.com"}
+		mockPgRepo.On("FindById", mock.Anything, mock.Anything).Return(mockUser, nil)
+		mockPgRepo.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+		mockPgRepo.On("Update", mock.Anything, mock.Anything).Return(mockUser, nil)
+		mockOutboxRepo.On("Create", mock.Anything, mock.MatchedBy(func(event *outboxModel.Event) bool {
+			return event.Type == outboxModel.TypeUserUpdated && event.AggregateType == outboxModel.AggregateUser && event.AggregateId == id
+		})).Return(nil).Once()
+		mockRedisRepo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+		_, err := us.Update(context.Background(), &model.User{Id: id})
+		require.NoError(t, err)
+		mockOutboxRepo.AssertExpectations(t)
+	})
 }
 
 func TestUserService_UploadAvatar(t *testing.T) {
@@ -0,0 +1,21 @@
+package webhook
+
+import "github.com/gin-gonic/gin"
+
+// WebhookController defines methods the routes expects
+// any controllers it interacts with to implement
+type WebhookController interface {
+	CreateWebhook() gin.HandlerFunc
+
+	GetAllWebhook() gin.HandlerFunc
+
+	GetWebhookById() gin.HandlerFunc
+
+	UpdateWebhook() gin.HandlerFunc
+
+	DeleteWebhook() gin.HandlerFunc
+
+	GetAllWebhookDelivery() gin.HandlerFunc
+
+	RedeliverWebhookDelivery() gin.HandlerFunc
+}
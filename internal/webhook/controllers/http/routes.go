@@ -0,0 +1,18 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook"
+)
+
+func MapWebhookRoutes(webhookGroup *gin.RouterGroup, controller webhook.WebhookController, mw *middleware.MiddlewareManager) {
+	webhookGroup.Use(mw.AuthJwtMiddleware())
+	webhookGroup.POST("/", mw.IdempotencyMiddleware(), controller.CreateWebhook())
+	webhookGroup.GET("/", controller.GetAllWebhook())
+	webhookGroup.GET("/:id", controller.GetWebhookById())
+	webhookGroup.PATCH("/:id", mw.IdempotencyMiddleware(), controller.UpdateWebhook())
+	webhookGroup.DELETE("/:id", mw.IdempotencyMiddleware(), controller.DeleteWebhook())
+	webhookGroup.GET("/:id/deliveries", controller.GetAllWebhookDelivery())
+	webhookGroup.POST("/:id/deliveries/:deliveryId/redeliver", mw.IdempotencyMiddleware(), controller.RedeliverWebhookDelivery())
+}
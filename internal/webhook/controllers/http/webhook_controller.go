@@ -0,0 +1,289 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	expenseModel "github.com/mrizkisaputra/expenses-api/internal/expense/model"
+	"github.com/mrizkisaputra/expenses-api/internal/middleware"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/mrizkisaputra/expenses-api/pkg/validator"
+	"github.com/sirupsen/logrus"
+	"math"
+	"net/http"
+)
+
+type ControllerConfig struct {
+	WebhookService webhook.WebhookService
+	Logger         *logrus.Logger
+}
+
+// webhookController acts as a struct for injecting an implementation of WebhookController interface
+// for use in controller methods
+type webhookController struct {
+	webhookService webhook.WebhookService
+	logger         *logrus.Logger
+}
+
+// NewWebhookController is a factory function
+// initializing a webhookController with its service layer dependencies
+func NewWebhookController(config *ControllerConfig) webhook.WebhookController {
+	return &webhookController{
+		webhookService: config.WebhookService,
+		logger:         config.Logger,
+	}
+}
+
+// webhookRequest is the body of a webhook endpoint, every field is optional on update
+type webhookRequest struct {
+	Url         string   `json:"url" validate:"omitempty,url,max=500"`
+	Description string   `json:"description" validate:"omitempty,max=255"`
+	EventTypes  []string `json:"event_types" validate:"omitempty,max=10,dive,oneof=expense.created expense.updated expense.deleted"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+func (request *webhookRequest) entity(userId uuid.UUID) *model.Endpoint {
+	return &model.Endpoint{
+		UserId:      userId,
+		Url:         request.Url,
+		Description: request.Description,
+		EventTypes:  request.EventTypes,
+		Enabled:     request.Enabled,
+	}
+}
+
+// CreateWebhook registers an endpoint, the response holds its secret, the only time it is returned
+func (wc webhookController) CreateWebhook() gin.HandlerFunc {
+	type CreateWebhookRequest struct {
+		webhookRequest
+		Url        string   `json:"url" validate:"required,url,max=500"`
+		EventTypes []string `json:"event_types" validate:"required,min=1,max=10,dive,oneof=expense.created expense.updated expense.deleted"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(CreateWebhookRequest)
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := request.entity(auth.Id)
+		entity.Url = request.Url
+		entity.EventTypes = request.EventTypes
+		response, err := wc.webhookService.CreateEndpoint(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, &expenseModel.ApiResponse{
+			Status:  http.StatusCreated,
+			Message: "Created",
+			Data:    response,
+		})
+	}
+}
+
+func (wc webhookController) UpdateWebhook() gin.HandlerFunc {
+	type UpdateWebhookRequest struct {
+		webhookRequest
+		Id string `json:"-" validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(UpdateWebhookRequest)
+		request.Id = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.JSON); err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		entity := request.entity(auth.Id)
+		entity.Id = uuid.MustParse(request.Id)
+		response, err := wc.webhookService.UpdateEndpoint(ctx, entity)
+		if err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, &expenseModel.ApiResponse{
+			Status:  http.StatusAccepted,
+			Message: "Accepted",
+			Data:    response,
+		})
+	}
+}
+
+func (wc webhookController) DeleteWebhook() gin.HandlerFunc {
+	type DeleteWebhookRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &DeleteWebhookRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if err := wc.webhookService.DeleteEndpoint(ctx, request.Id, auth.Id.String()); err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusNoContent, &expenseModel.ApiResponse{
+			Status:  http.StatusNoContent,
+			Message: "No Content",
+		})
+	}
+}
+
+func (wc webhookController) GetWebhookById() gin.HandlerFunc {
+	type GetWebhookRequest struct {
+		Id string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &GetWebhookRequest{
+			Id: ctx.Param("id"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := wc.webhookService.GetEndpointById(ctx, request.Id, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:  http.StatusOK,
+			Message: "OK",
+			Data:    response,
+		})
+	}
+}
+
+func (wc webhookController) GetAllWebhook() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		endpoints, err := wc.webhookService.GetAllEndpoints(ctx, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       endpoints,
+			TotalItems: int64(len(endpoints)),
+		})
+	}
+}
+
+// GetAllWebhookDelivery lists the deliveries of an endpoint, the most recent first, only the ones of the status when given
+func (wc webhookController) GetAllWebhookDelivery() gin.HandlerFunc {
+	type GetAllWebhookDeliveryRequest struct {
+		Id     string `form:"-" validate:"required,uuid"`
+		Status string `form:"status" validate:"omitempty,oneof=pending succeeded dead"`
+		Page   int    `form:"page" validate:"omitempty,numeric,min=1"`
+		Limit  int    `form:"limit" validate:"omitempty,numeric,min=10"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := new(GetAllWebhookDeliveryRequest)
+		request.Id = ctx.Param("id")
+		if err := utils.ReadRequest(ctx, request, binding.Query); err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		if request.Page == 0 {
+			request.Page = 1
+		}
+		if request.Limit == 0 {
+			request.Limit = 10
+		}
+
+		deliveries, total, err := wc.webhookService.GetDeliveries(ctx, request.Id, auth.Id.String(), request.Status, request.Page, request.Limit)
+		if err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &expenseModel.ApiResponse{
+			Status:     http.StatusOK,
+			Message:    "OK",
+			Data:       deliveries,
+			Page:       request.Page,
+			Limit:      request.Limit,
+			TotalItems: total,
+			TotalPages: int64(math.Ceil(float64(total) / float64(request.Limit))),
+		})
+	}
+}
+
+// RedeliverWebhookDelivery queues a delivery again with all its attempts, a dead one included
+func (wc webhookController) RedeliverWebhookDelivery() gin.HandlerFunc {
+	type RedeliverWebhookDeliveryRequest struct {
+		Id         string `validate:"required,uuid"`
+		DeliveryId string `validate:"required,uuid"`
+	}
+
+	return func(ctx *gin.Context) {
+		auth := middleware.GetAuth(ctx)
+
+		request := &RedeliverWebhookDeliveryRequest{
+			Id:         ctx.Param("id"),
+			DeliveryId: ctx.Param("deliveryId"),
+		}
+		if err := validator.ValidateStruct(ctx, request); err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		response, err := wc.webhookService.Redeliver(ctx, request.DeliveryId, request.Id, auth.Id.String())
+		if err != nil {
+			utils.LogErrorResponse(ctx, wc.logger, err)
+			ctx.JSON(httpErrors.ErrorResponse(ctx, err))
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, &expenseModel.ApiResponse{
+			Status:  http.StatusAccepted,
+			Message: "Accepted",
+			Data:    response,
+		})
+	}
+}
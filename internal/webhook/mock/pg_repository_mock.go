@@ -0,0 +1,99 @@
+package mock
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPostgresRepository is a mock type for webhook.PostgresRepository
+type MockPostgresRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostgresRepository) CreateEndpoint(ctx context.Context, endpoint *model.Endpoint) error {
+	args := m.Called(ctx, endpoint)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindEndpointByIdAndUserId(ctx context.Context, id, userId string) (*model.Endpoint, error) {
+	args := m.Called(ctx, id, userId)
+	if endpoint, ok := args.Get(0).(*model.Endpoint); ok {
+		return endpoint, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindEndpointsByUserId(ctx context.Context, userId string) ([]model.Endpoint, error) {
+	args := m.Called(ctx, userId)
+	if endpoints, ok := args.Get(0).([]model.Endpoint); ok {
+		return endpoints, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindEndpointsByEventType(ctx context.Context, userId, eventType string) ([]model.Endpoint, error) {
+	args := m.Called(ctx, userId, eventType)
+	if endpoints, ok := args.Get(0).([]model.Endpoint); ok {
+		return endpoints, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindEndpointsByIds(ctx context.Context, ids []uuid.UUID) ([]model.Endpoint, error) {
+	args := m.Called(ctx, ids)
+	if endpoints, ok := args.Get(0).([]model.Endpoint); ok {
+		return endpoints, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) UpdateEndpoint(ctx context.Context, endpoint *model.Endpoint) error {
+	args := m.Called(ctx, endpoint)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) RemoveEndpoint(ctx context.Context, endpoint *model.Endpoint) error {
+	args := m.Called(ctx, endpoint)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) CreateDeliveries(ctx context.Context, deliveries []model.Delivery) error {
+	args := m.Called(ctx, deliveries)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) FindDeliveryByIdAndEndpointId(ctx context.Context, id, endpointId string) (*model.Delivery, error) {
+	args := m.Called(ctx, id, endpointId)
+	if delivery, ok := args.Get(0).(*model.Delivery); ok {
+		return delivery, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) FindDeliveriesByEndpointId(ctx context.Context, endpointId, status string, page, limit int) ([]model.Delivery, int64, error) {
+	args := m.Called(ctx, endpointId, status, page, limit)
+	if deliveries, ok := args.Get(0).([]model.Delivery); ok {
+		return deliveries, args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockPostgresRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil int64, limit int) ([]model.Delivery, error) {
+	args := m.Called(ctx, now, leaseUntil, limit)
+	if deliveries, ok := args.Get(0).([]model.Delivery); ok {
+		return deliveries, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPostgresRepository) UpdateDelivery(ctx context.Context, delivery *model.Delivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockPostgresRepository) DeleteFinishedDeliveriesBefore(ctx context.Context, before int64) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
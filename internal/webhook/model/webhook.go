@@ -0,0 +1,284 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/pkg/utils"
+	"github.com/pkg/errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// statuses of a delivery
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusDead      = "dead"
+)
+
+// headers of a delivery
+const (
+	HeaderId        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const (
+	// secretPrefix tells the secret of an endpoint from other secrets
+	secretPrefix = "whsec_"
+
+	// maxBackoff is the longest wait before a retry
+	maxBackoff = 24 * time.Hour
+
+	maxErrorLength = 500
+)
+
+// ErrPrivateAddress rejects an endpoint reaching the network of the API rather than the internet
+var ErrPrivateAddress = errors.New("webhook url resolves to a private address")
+
+// Endpoint mapping table 'webhook_endpoints'.
+// an URL of a user the events of the types EventTypes are posted to, the secret is only
+// returned once, when the endpoint is created
+type Endpoint struct {
+	Id          uuid.UUID  `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	UserId      uuid.UUID  `json:"user_id" gorm:"column:id_user"`
+	Url         string     `json:"url" gorm:"column:url"`
+	Description string     `json:"description" gorm:"column:description"`
+	Secret      string     `json:"-" gorm:"column:secret;<-:create"` // allow read and create
+	EventTypes  EventTypes `json:"event_types" gorm:"column:event_types;type:jsonb"`
+	Enabled     *bool      `json:"enabled" gorm:"column:enabled"`
+	CreatedAt   int64      `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt   int64      `json:"updated_at" gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (endpoint *Endpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// PrepareCreate generates the secret of a new endpoint, enabled unless told otherwise
+func (endpoint *Endpoint) PrepareCreate() error {
+	endpoint.Url = strings.TrimSpace(endpoint.Url)
+	endpoint.Description = strings.TrimSpace(endpoint.Description)
+	endpoint.EventTypes = normalizeEventTypes(endpoint.EventTypes)
+	if endpoint.Enabled == nil {
+		enabled := true
+		endpoint.Enabled = &enabled
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return errors.Wrap(err, "Endpoint.PrepareCreate.rand.Read")
+	}
+	endpoint.Secret = secretPrefix + hex.EncodeToString(secret)
+	return nil
+}
+
+func (endpoint *Endpoint) PrepareUpdate(oldEndpoint *Endpoint) {
+	if endpoint.Url != "" {
+		oldEndpoint.Url = strings.TrimSpace(endpoint.Url)
+	}
+
+	if endpoint.Description != "" {
+		oldEndpoint.Description = strings.TrimSpace(endpoint.Description)
+	}
+
+	if endpoint.EventTypes != nil {
+		oldEndpoint.EventTypes = normalizeEventTypes(endpoint.EventTypes)
+	}
+
+	if endpoint.Enabled != nil {
+		oldEndpoint.Enabled = endpoint.Enabled
+	}
+}
+
+// Validate checks the endpoint is an absolute http(s) URL subscribed to at least one event type,
+// a host given as address must be public. a host given by name is checked once resolved
+func (endpoint *Endpoint) Validate() error {
+	parsed, err := url.Parse(endpoint.Url)
+	if err != nil {
+		return errors.Wrap(err, "Endpoint.Validate")
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return errors.Errorf("Endpoint.Validate: unsupported url '%s'", endpoint.Url)
+	}
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errors.Wrap(ErrPrivateAddress, "Endpoint.Validate")
+	}
+	if ip := net.ParseIP(host); ip != nil && !PublicAddress(ip) {
+		return errors.Wrap(ErrPrivateAddress, "Endpoint.Validate")
+	}
+	if len(endpoint.EventTypes) == 0 {
+		return errors.New("Endpoint.Validate: no event type")
+	}
+	return nil
+}
+
+// Host is the host name of the URL of the endpoint, without port
+func (endpoint *Endpoint) Host() string {
+	parsed, err := url.Parse(endpoint.Url)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+// PublicAddress tells whether a delivery may be sent to ip, the loopback, private, link-local,
+// multicast and unspecified addresses belong to the network of the API and may not
+func PublicAddress(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 0 {
+		// 0.0.0.0/8 is "this network"
+		return false
+	}
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// CreatedEndpoint is a new endpoint along with its secret, the only time the secret is returned
+type CreatedEndpoint struct {
+	*Endpoint
+	Secret string `json:"secret"`
+}
+
+// EventTypes are the types of the events an endpoint subscribes to, stored as a json array
+type EventTypes []string
+
+func (types EventTypes) Value() (driver.Value, error) {
+	if types == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(types))
+}
+
+func (types *EventTypes) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return errors.Errorf("EventTypes.Scan: unsupported type %T", src)
+	}
+	return json.Unmarshal(data, types)
+}
+
+// normalizeEventTypes drops the duplicated event types, keeping their order
+func normalizeEventTypes(types EventTypes) EventTypes {
+	normalized := EventTypes{}
+	seen := map[string]bool{}
+	for _, eventType := range types {
+		eventType = strings.TrimSpace(eventType)
+		if eventType != "" && !seen[eventType] {
+			seen[eventType] = true
+			normalized = append(normalized, eventType)
+		}
+	}
+	return normalized
+}
+
+// Delivery mapping table 'webhook_deliveries'.
+// an event to post to an endpoint, Payload is the body sent at every attempt
+type Delivery struct {
+	Id             uuid.UUID       `json:"id" gorm:"column:id;primary_key;default:uuid_generate_v4();<-:create"` // allow read and create
+	EndpointId     uuid.UUID       `json:"endpoint_id" gorm:"column:id_endpoint"`
+	UserId         uuid.UUID       `json:"user_id" gorm:"column:id_user"`
+	EventType      string          `json:"event_type" gorm:"column:event_type"`
+	Payload        json.RawMessage `json:"payload" gorm:"column:payload;type:jsonb"`
+	Status         string          `json:"status" gorm:"column:status"`
+	Attempts       int             `json:"attempts" gorm:"column:attempts"`
+	NextAttemptAt  int64           `json:"next_attempt_at" gorm:"column:next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code" gorm:"column:last_status_code"`
+	LastError      string          `json:"last_error" gorm:"column:last_error"`
+	DeliveredAt    *int64          `json:"delivered_at" gorm:"column:delivered_at"`
+	CreatedAt      int64           `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;<-:create"` // allow read and create
+	UpdatedAt      int64           `json:"updated_at" gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (delivery *Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// NewDelivery is a pending delivery of an event to the endpoint, due at once. the payload holds its own
+// id so the receiver can tell a retry from a new event
+func NewDelivery(endpoint *Endpoint, eventType string, data json.RawMessage, now time.Time) (*Delivery, error) {
+	delivery := &Delivery{
+		Id:            uuid.New(),
+		EndpointId:    endpoint.Id,
+		UserId:        endpoint.UserId,
+		EventType:     eventType,
+		Status:        StatusPending,
+		NextAttemptAt: now.UnixMilli(),
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"id":         delivery.Id,
+		"type":       eventType,
+		"created_at": now.UnixMilli(),
+		"data":       data,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "NewDelivery.json.Marshal")
+	}
+	delivery.Payload = payload
+	return delivery, nil
+}
+
+// Succeed records an attempt answered with a 2xx status code
+func (delivery *Delivery) Succeed(now time.Time, statusCode int) {
+	delivered := now.UnixMilli()
+	delivery.Attempts++
+	delivery.Status = StatusSucceeded
+	delivery.LastStatusCode = &statusCode
+	delivery.LastError = ""
+	delivery.DeliveredAt = &delivered
+}
+
+// Fail records a failed attempt, statusCode is 0 without answer. the next attempt waits backoff
+// doubled at every attempt, the delivery is dead once maxAttempts are made
+func (delivery *Delivery) Fail(now time.Time, statusCode int, cause error, maxAttempts int, backoff time.Duration) {
+	delivery.Attempts++
+	delivery.LastStatusCode = nil
+	if statusCode != 0 {
+		delivery.LastStatusCode = &statusCode
+	}
+	delivery.LastError = utils.Truncate(cause.Error(), maxErrorLength)
+
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = StatusDead
+		return
+	}
+	delivery.NextAttemptAt = now.Add(utils.Backoff(backoff, delivery.Attempts, maxBackoff)).UnixMilli()
+}
+
+// Redeliver makes the delivery pending again with all its attempts, due at once
+func (delivery *Delivery) Redeliver(now time.Time) {
+	delivery.Status = StatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now.UnixMilli()
+	delivery.LastStatusCode = nil
+	delivery.LastError = ""
+	delivery.DeliveredAt = nil
+}
+
+// Sign is the value of the signature header of a body sent at timestamp (unix seconds):
+// "t=<timestamp>,v1=<hex HMAC-SHA256 of '<timestamp>.<body>' with the secret>". the receiver
+// computes it again to check the body comes from us and rejects old timestamps against replays
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
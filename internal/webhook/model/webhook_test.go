@@ -0,0 +1,99 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"type":"expense.created"}`)
+
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte("1700000000." + string(body)))
+	expected := "t=1700000000,v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	require.Equal(t, expected, Sign("whsec_test", 1700000000, body))
+	require.NotEqual(t, expected, Sign("whsec_other", 1700000000, body))
+	require.NotEqual(t, expected, Sign("whsec_test", 1700000001, body))
+}
+
+func TestDelivery_Fail(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 15, 0, 0, 0, time.UTC)
+	endpoint := &Endpoint{Id: uuid.New(), UserId: uuid.New()}
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Retries wait twice longer every attempt until the delivery is dead", func(t *testing.T) {
+		delivery, err := NewDelivery(endpoint, "expense.created", json.RawMessage(`{"id":"1"}`), now)
+		require.NoError(t, err)
+		require.Equal(t, StatusPending, delivery.Status)
+		require.Equal(t, now.UnixMilli(), delivery.NextAttemptAt)
+
+		var payload map[string]interface{}
+		require.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+		require.Equal(t, delivery.Id.String(), payload["id"])
+		require.Equal(t, "expense.created", payload["type"])
+
+		delivery.Fail(now, 500, errors.New("internal server error"), 3, time.Minute)
+		require.Equal(t, StatusPending, delivery.Status)
+		require.Equal(t, now.Add(time.Minute).UnixMilli(), delivery.NextAttemptAt)
+		require.Equal(t, 500, *delivery.LastStatusCode)
+
+		delivery.Fail(now, 0, errors.New("connection refused"), 3, time.Minute)
+		require.Equal(t, StatusPending, delivery.Status)
+		require.Equal(t, now.Add(2*time.Minute).UnixMilli(), delivery.NextAttemptAt)
+		require.Nil(t, delivery.LastStatusCode)
+		require.Equal(t, "connection refused", delivery.LastError)
+
+		delivery.Fail(now, 502, errors.New(strings.Repeat("a", 600)), 3, time.Minute)
+		require.Equal(t, StatusDead, delivery.Status)
+		require.Equal(t, 3, delivery.Attempts)
+		require.Len(t, delivery.LastError, 500)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] A redelivered dead delivery gets all its attempts back", func(t *testing.T) {
+		delivery, err := NewDelivery(endpoint, "expense.deleted", json.RawMessage(`{}`), now)
+		require.NoError(t, err)
+		delivery.Fail(now, 500, errors.New("internal server error"), 1, time.Minute)
+		require.Equal(t, StatusDead, delivery.Status)
+
+		later := now.Add(time.Hour)
+		delivery.Redeliver(later)
+		require.Equal(t, StatusPending, delivery.Status)
+		require.Equal(t, 0, delivery.Attempts)
+		require.Equal(t, later.UnixMilli(), delivery.NextAttemptAt)
+		require.Empty(t, delivery.LastError)
+
+		delivery.Succeed(later, 204)
+		require.Equal(t, StatusSucceeded, delivery.Status)
+		require.Equal(t, later.UnixMilli(), *delivery.DeliveredAt)
+	})
+}
+
+func TestEndpoint_Validate(t *testing.T) {
+	endpoint := &Endpoint{Url: " https://example.com/hooks ", EventTypes: EventTypes{"expense.created", "expense.created"}}
+	require.NoError(t, endpoint.PrepareCreate())
+	require.True(t, strings.HasPrefix(endpoint.Secret, "whsec_"))
+	require.True(t, *endpoint.Enabled)
+	require.Equal(t, EventTypes{"expense.created"}, endpoint.EventTypes)
+	require.NoError(t, endpoint.Validate())
+
+	require.Error(t, (&Endpoint{Url: "ftp://example.com", EventTypes: EventTypes{"expense.created"}}).Validate())
+	require.Error(t, (&Endpoint{Url: "https://example.com"}).Validate())
+
+	// the network of the API is not reachable
+	for _, target := range []string{"http://localhost:6379", "http://127.0.0.1/hooks", "http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.5", "http://192.168.1.1:8080", "http://[::1]/", "http://0.0.0.0", "http://[::ffff:127.0.0.1]/"} {
+		err := (&Endpoint{Url: target, EventTypes: EventTypes{"expense.created"}}).Validate()
+		require.ErrorIs(t, err, ErrPrivateAddress, target)
+	}
+	require.NoError(t, (&Endpoint{Url: "https://93.184.216.34/hooks", EventTypes: EventTypes{"expense.created"}}).Validate())
+}
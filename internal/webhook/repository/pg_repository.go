@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookPgRepository(db *gorm.DB) webhook.PostgresRepository {
+	return &postgresRepository{
+		db: db,
+	}
+}
+
+func (p *postgresRepository) CreateEndpoint(ctx context.Context, endpoint *model.Endpoint) error {
+	/**
+	SQL: INSERT INTO "webhook_endpoints" ("id_user","url","description","secret","event_types","enabled","created_at","updated_at")
+	VALUES (?,?,?,?,?,?,?,?) RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(endpoint).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.CreateEndpoint")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindEndpointByIdAndUserId(ctx context.Context, id, userId string) (*model.Endpoint, error) {
+	/**
+	SQL: SELECT * FROM "webhook_endpoints" WHERE id = ? AND id_user = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	endpoint := new(model.Endpoint)
+	if err := DB.Where("id = ? AND id_user = ?", id, userId).Take(endpoint).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindEndpointByIdAndUserId")
+	}
+	return endpoint, nil
+}
+
+func (p *postgresRepository) FindEndpointsByUserId(ctx context.Context, userId string) ([]model.Endpoint, error) {
+	/**
+	SQL: SELECT * FROM "webhook_endpoints" WHERE id_user = ? ORDER BY created_at, id
+	*/
+	DB := p.db.WithContext(ctx)
+	var endpoints []model.Endpoint
+	if err := DB.Where("id_user = ?", userId).Order("created_at, id").Find(&endpoints).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindEndpointsByUserId")
+	}
+	return endpoints, nil
+}
+
+func (p *postgresRepository) FindEndpointsByEventType(ctx context.Context, userId, eventType string) ([]model.Endpoint, error) {
+	/**
+	SQL: SELECT * FROM "webhook_endpoints" WHERE id_user = ? AND enabled AND event_types @> ?::jsonb ORDER BY created_at, id
+	*/
+	DB := p.db.WithContext(ctx)
+	var endpoints []model.Endpoint
+	if err := DB.Where("id_user = ? AND enabled AND event_types @> ?::jsonb", userId, model.EventTypes{eventType}).
+		Order("created_at, id").
+		Find(&endpoints).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindEndpointsByEventType")
+	}
+	return endpoints, nil
+}
+
+func (p *postgresRepository) FindEndpointsByIds(ctx context.Context, ids []uuid.UUID) ([]model.Endpoint, error) {
+	/**
+	SQL: SELECT * FROM "webhook_endpoints" WHERE id IN (?)
+	*/
+	DB := p.db.WithContext(ctx)
+	var endpoints []model.Endpoint
+	if err := DB.Where("id IN ?", ids).Find(&endpoints).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindEndpointsByIds")
+	}
+	return endpoints, nil
+}
+
+func (p *postgresRepository) UpdateEndpoint(ctx context.Context, endpoint *model.Endpoint) error {
+	/**
+	SQL: UPDATE "webhook_endpoints" SET "id_user"=?,"url"=?,"description"=?,"event_types"=?,"enabled"=?,"updated_at"=?
+	WHERE (id = ? AND id_user = ?) AND "id" = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", endpoint.Id, endpoint.UserId).Updates(endpoint).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.UpdateEndpoint")
+	}
+	return nil
+}
+
+func (p *postgresRepository) RemoveEndpoint(ctx context.Context, endpoint *model.Endpoint) error {
+	/**
+	SQL: DELETE FROM "webhook_endpoints" WHERE id = ? AND id_user = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Where("id = ? AND id_user = ?", endpoint.Id, endpoint.UserId).Delete(&model.Endpoint{}).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.RemoveEndpoint")
+	}
+	return nil
+}
+
+func (p *postgresRepository) CreateDeliveries(ctx context.Context, deliveries []model.Delivery) error {
+	/**
+	SQL: INSERT INTO "webhook_deliveries" ("id_endpoint","id_user","event_type","payload","status","attempts","next_attempt_at",
+	"last_status_code","last_error","delivered_at","created_at","updated_at","id") VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?),... RETURNING "id"
+	*/
+	DB := p.db.WithContext(ctx)
+	if err := DB.Create(&deliveries).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.CreateDeliveries")
+	}
+	return nil
+}
+
+func (p *postgresRepository) FindDeliveryByIdAndEndpointId(ctx context.Context, id, endpointId string) (*model.Delivery, error) {
+	/**
+	SQL: SELECT * FROM "webhook_deliveries" WHERE id = ? AND id_endpoint = ? LIMIT 1
+	*/
+	DB := p.db.WithContext(ctx)
+	delivery := new(model.Delivery)
+	if err := DB.Where("id = ? AND id_endpoint = ?", id, endpointId).Take(delivery).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.FindDeliveryByIdAndEndpointId")
+	}
+	return delivery, nil
+}
+
+func (p *postgresRepository) FindDeliveriesByEndpointId(ctx context.Context, endpointId, status string, page, limit int) ([]model.Delivery, int64, error) {
+	DB := p.db.WithContext(ctx)
+	query := func() *gorm.DB {
+		query := DB.Model(&model.Delivery{}).Where("id_endpoint = ?", endpointId)
+		if status != "" {
+			query = query.Where("status = ?", status)
+		}
+		return query
+	}
+
+	/**
+	SQL: SELECT * FROM "webhook_deliveries" WHERE id_endpoint = ? [AND status = ?] ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?
+	*/
+	var deliveries []model.Delivery
+	if err := query().
+		Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindDeliveriesByEndpointId.Find")
+	}
+
+	/**
+	SQL: SELECT count(*) FROM "webhook_deliveries" WHERE id_endpoint = ? [AND status = ?]
+	*/
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "postgresRepository.FindDeliveriesByEndpointId.Count")
+	}
+
+	return deliveries, total, nil
+}
+
+func (p *postgresRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil int64, limit int) ([]model.Delivery, error) {
+	DB := p.db.WithContext(ctx)
+	var deliveries []model.Delivery
+	// the locked rows are skipped, they are being claimed by another instance
+	if err := DB.Raw(`UPDATE "webhook_deliveries" SET "next_attempt_at" = ?
+		WHERE id IN (SELECT id FROM "webhook_deliveries" WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at LIMIT ? FOR UPDATE SKIP LOCKED) RETURNING *`,
+		leaseUntil, model.StatusPending, now, limit).
+		Scan(&deliveries).Error; err != nil {
+		return nil, errors.Wrap(err, "postgresRepository.ClaimDueDeliveries")
+	}
+	return deliveries, nil
+}
+
+func (p *postgresRepository) UpdateDelivery(ctx context.Context, delivery *model.Delivery) error {
+	/**
+	SQL: UPDATE "webhook_deliveries" SET "status"=?,"attempts"=?,"next_attempt_at"=?,"last_status_code"=?,"last_error"=?,
+	"delivered_at"=?,"updated_at"=? WHERE id = ? AND id_endpoint = ?
+	*/
+	DB := p.db.WithContext(ctx)
+	// the fields are selected since a retry clears some of them
+	if err := DB.Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "last_status_code", "last_error", "delivered_at", "updated_at").
+		Where("id = ? AND id_endpoint = ?", delivery.Id, delivery.EndpointId).
+		Updates(delivery).Error; err != nil {
+		return errors.Wrap(err, "postgresRepository.UpdateDelivery")
+	}
+	return nil
+}
+
+func (p *postgresRepository) DeleteFinishedDeliveriesBefore(ctx context.Context, before int64) (int64, error) {
+	/**
+	SQL: DELETE FROM "webhook_deliveries" WHERE status IN (?,?) AND updated_at < ?
+	*/
+	DB := p.db.WithContext(ctx)
+	result := DB.Where("status IN ? AND updated_at < ?", []string{model.StatusSucceeded, model.StatusDead}, before).
+		Delete(&model.Delivery{})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "postgresRepository.DeleteFinishedDeliveriesBefore")
+	}
+	return result.RowsAffected, nil
+}
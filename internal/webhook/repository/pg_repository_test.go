@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	repository webhook.PostgresRepository
+	mock       sqlmock.Sqlmock
+)
+
+// Test Before and After
+func TestMain(m *testing.M) {
+	// initialize mock sql db
+	mockDb, mocking, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("Failed to open mock sql db, got error %v", err)
+	}
+	defer mockDb.Close()
+	mock = mocking
+
+	// initialize db connection
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: mockDb}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database, got error %v", err)
+	}
+
+	// initialize repository
+	repository = NewWebhookPgRepository(db)
+
+	os.Exit(m.Run())
+}
+
+func TestWebhookPostgresRepository_FindEndpointsByEventType(t *testing.T) {
+	userId := uuid.New()
+
+	// define expect sql
+	rows := sqlmock.NewRows([]string{"id", "id_user", "url", "description", "secret", "event_types", "enabled", "created_at", "updated_at"}).
+		AddRow(uuid.New(), userId, "https://example.com/hooks", "", "whsec_test", []byte(`["expense.created"]`), true, time.Now().UnixMilli(), time.Now().UnixMilli())
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_endpoints" WHERE id_user = $1 AND enabled AND event_types @> $2::jsonb ORDER BY created_at, id`)).
+		WithArgs(userId.String(), []byte(`["expense.created"]`)).
+		WillReturnRows(rows)
+
+	endpoints, err := repository.FindEndpointsByEventType(context.Background(), userId.String(), "expense.created")
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	require.Equal(t, "whsec_test", endpoints[0].Secret)
+	require.Equal(t, model.EventTypes{"expense.created"}, endpoints[0].EventTypes)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookPostgresRepository_ClaimDueDeliveries(t *testing.T) {
+	now := time.Now().UnixMilli()
+	deliveryId, endpointId := uuid.New(), uuid.New()
+
+	// define expect sql
+	rows := sqlmock.NewRows([]string{"id", "id_endpoint", "id_user", "event_type", "payload", "status", "attempts", "next_attempt_at",
+		"last_status_code", "last_error", "delivered_at", "created_at", "updated_at"}).
+		AddRow(deliveryId, endpointId, uuid.New(), "expense.created", []byte(`{}`), model.StatusPending, 1, now+60000, 500, "", nil, now, now)
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "webhook_deliveries" SET "next_attempt_at" = $1`)).
+		WithArgs(now+60000, model.StatusPending, now, 50).
+		WillReturnRows(rows)
+
+	deliveries, err := repository.ClaimDueDeliveries(context.Background(), now, now+60000, 50)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, deliveryId, deliveries[0].Id)
+	require.Equal(t, 500, *deliveries[0].LastStatusCode)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookPostgresRepository_UpdateDelivery(t *testing.T) {
+	delivery := &model.Delivery{Id: uuid.New(), EndpointId: uuid.New(), Status: model.StatusPending, NextAttemptAt: time.Now().UnixMilli()}
+
+	// define expect sql, the cleared fields are written too
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhook_deliveries" SET "status"=$1,"attempts"=$2,"next_attempt_at"=$3,"last_status_code"=$4,"last_error"=$5,"delivered_at"=$6,"updated_at"=$7 WHERE (id = $8 AND id_endpoint = $9) AND "id" = $10`)).
+		WithArgs(model.StatusPending, 0, delivery.NextAttemptAt, nil, "", nil, sqlmock.AnyArg(), delivery.Id, delivery.EndpointId, delivery.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repository.UpdateDelivery(context.Background(), delivery))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookPostgresRepository_DeleteFinishedDeliveriesBefore(t *testing.T) {
+	before := time.Now().UnixMilli()
+
+	// define expect sql, the pending deliveries are kept
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "webhook_deliveries" WHERE status IN ($1,$2) AND updated_at < $3`)).
+		WithArgs(model.StatusSucceeded, model.StatusDead, before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	purged, err := repository.DeleteFinishedDeliveriesBefore(context.Background(), before)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), purged)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
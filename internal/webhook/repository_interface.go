@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook/model"
+)
+
+// PostgresRepository defines methods the service layer expects.
+// any repositories it interacts with to implement
+type PostgresRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *model.Endpoint) error
+
+	FindEndpointByIdAndUserId(ctx context.Context, id, userId string) (*model.Endpoint, error)
+
+	FindEndpointsByUserId(ctx context.Context, userId string) ([]model.Endpoint, error)
+
+	// FindEndpointsByEventType finds the enabled endpoints of the user subscribed to the event type
+	FindEndpointsByEventType(ctx context.Context, userId, eventType string) ([]model.Endpoint, error)
+
+	FindEndpointsByIds(ctx context.Context, ids []uuid.UUID) ([]model.Endpoint, error)
+
+	UpdateEndpoint(ctx context.Context, endpoint *model.Endpoint) error
+
+	RemoveEndpoint(ctx context.Context, endpoint *model.Endpoint) error
+
+	CreateDeliveries(ctx context.Context, deliveries []model.Delivery) error
+
+	FindDeliveryByIdAndEndpointId(ctx context.Context, id, endpointId string) (*model.Delivery, error)
+
+	// FindDeliveriesByEndpointId finds a page of the deliveries of the endpoint, the most recent first
+	FindDeliveriesByEndpointId(ctx context.Context, endpointId, status string, page, limit int) ([]model.Delivery, int64, error)
+
+	// ClaimDueDeliveries takes at most limit pending deliveries due at now (unix milliseconds) and postpones them
+	// to leaseUntil, so no other instance sends them meanwhile. a delivery not updated before is sent again then
+	ClaimDueDeliveries(ctx context.Context, now, leaseUntil int64, limit int) ([]model.Delivery, error)
+
+	UpdateDelivery(ctx context.Context, delivery *model.Delivery) error
+
+	// DeleteFinishedDeliveriesBefore deletes the succeeded and dead deliveries last updated before
+	// (unix milliseconds), it returns their number
+	DeleteFinishedDeliveriesBefore(ctx context.Context, before int64) (int64, error)
+}
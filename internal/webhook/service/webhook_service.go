@@ -0,0 +1,371 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// dispatchBatchSize is the number of due deliveries claimed at once
+	dispatchBatchSize = 50
+
+	defaultInterval    = 5 * time.Second
+	defaultTimeout     = 10 * time.Second
+	defaultMaxAttempts = 8
+	defaultBackoff     = 30 * time.Second
+)
+
+// errEndpointDisabled kills the deliveries of an endpoint disabled after they were queued
+var errEndpointDisabled = errors.New("endpoint disabled")
+
+type ServiceConfig struct {
+	PgRepo webhook.PostgresRepository
+	Config *config.Config
+	Logger *logrus.Logger
+}
+
+// webhookService acts as a struct for injecting an implementation of WebhookService interface
+// for use in service methods.
+type webhookService struct {
+	pgRepo      webhook.PostgresRepository
+	client      *http.Client
+	lookup      func(ctx context.Context, host string) ([]net.IPAddr, error)
+	interval    time.Duration
+	timeout     time.Duration
+	maxAttempts int
+	backoff     time.Duration
+	logger      *logrus.Logger
+}
+
+// NewWebhookService is a factory function for
+// initializing a webhookService with its repository layer dependencies
+func NewWebhookService(config *ServiceConfig) webhook.WebhookService {
+	service := &webhookService{
+		pgRepo:      config.PgRepo,
+		interval:    defaultInterval,
+		timeout:     defaultTimeout,
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+		lookup:      net.DefaultResolver.LookupIPAddr,
+		logger:      config.Logger,
+	}
+	if config.Config != nil {
+		cfg := config.Config.Webhook
+		if cfg.Interval > 0 {
+			service.interval = cfg.Interval * time.Second
+		}
+		if cfg.Timeout > 0 {
+			service.timeout = cfg.Timeout * time.Second
+		}
+		if cfg.MaxAttempts > 0 {
+			service.maxAttempts = cfg.MaxAttempts
+		}
+		if cfg.Backoff > 0 {
+			service.backoff = cfg.Backoff * time.Second
+		}
+	}
+	service.client = newClient(service.timeout)
+	return service
+}
+
+// newClient is the client sending the deliveries. the address is checked once resolved, when dialing,
+// so a name resolving to a private address after the endpoint was saved is not reached either,
+// and redirects are not followed since their target is not checked
+func newClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return errors.Wrap(err, "webhookService.dial")
+			}
+			if ip := net.ParseIP(host); ip == nil || !model.PublicAddress(ip) {
+				return errors.Wrapf(model.ErrPrivateAddress, "webhookService.dial %s", host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Publish only queues the deliveries, they are sent by Dispatch
+func (w *webhookService) Publish(ctx context.Context, userId uuid.UUID, eventType string, data interface{}) error {
+	endpoints, err := w.pgRepo.FindEndpointsByEventType(ctx, userId.String(), eventType)
+	if err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return httpErrors.NewInternalServerError(errors.Wrap(err, "webhookService.Publish.json.Marshal"))
+	}
+
+	now := time.Now()
+	deliveries := make([]model.Delivery, 0, len(endpoints))
+	for i := range endpoints {
+		delivery, err := model.NewDelivery(&endpoints[i], eventType, payload, now)
+		if err != nil {
+			return httpErrors.NewInternalServerError(err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	if err := w.pgRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+func (w *webhookService) CreateEndpoint(ctx context.Context, request *model.Endpoint) (*model.CreatedEndpoint, error) {
+	if err := request.PrepareCreate(); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	if err := request.Validate(); err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidWebhookMsg, err)
+	}
+	if err := w.checkHost(ctx, request); err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidWebhookMsg, err)
+	}
+
+	if err := w.pgRepo.CreateEndpoint(ctx, request); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return &model.CreatedEndpoint{Endpoint: request, Secret: request.Secret}, nil
+}
+
+func (w *webhookService) UpdateEndpoint(ctx context.Context, request *model.Endpoint) (*model.Endpoint, error) {
+	found, err := w.pgRepo.FindEndpointByIdAndUserId(ctx, request.Id.String(), request.UserId.String())
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+
+	request.PrepareUpdate(found)
+	if err := found.Validate(); err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidWebhookMsg, err)
+	}
+	if err := w.checkHost(ctx, found); err != nil {
+		return nil, httpErrors.NewError(http.StatusBadRequest, httpErrors.InvalidWebhookMsg, err)
+	}
+
+	if err := w.pgRepo.UpdateEndpoint(ctx, found); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return found, nil
+}
+
+func (w *webhookService) DeleteEndpoint(ctx context.Context, id, userId string) error {
+	found, err := w.pgRepo.FindEndpointByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return httpErrors.NewNotFoundError(err)
+	}
+
+	if err := w.pgRepo.RemoveEndpoint(ctx, found); err != nil {
+		return httpErrors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+func (w *webhookService) GetEndpointById(ctx context.Context, id, userId string) (*model.Endpoint, error) {
+	found, err := w.pgRepo.FindEndpointByIdAndUserId(ctx, id, userId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	return found, nil
+}
+
+func (w *webhookService) GetAllEndpoints(ctx context.Context, userId string) ([]model.Endpoint, error) {
+	endpoints, err := w.pgRepo.FindEndpointsByUserId(ctx, userId)
+	if err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return endpoints, nil
+}
+
+func (w *webhookService) GetDeliveries(ctx context.Context, endpointId, userId, status string, page, limit int) ([]model.Delivery, int64, error) {
+	if _, err := w.pgRepo.FindEndpointByIdAndUserId(ctx, endpointId, userId); err != nil {
+		return nil, 0, httpErrors.NewNotFoundError(err)
+	}
+
+	deliveries, total, err := w.pgRepo.FindDeliveriesByEndpointId(ctx, endpointId, status, page, limit)
+	if err != nil {
+		return nil, 0, httpErrors.NewInternalServerError(err)
+	}
+	return deliveries, total, nil
+}
+
+func (w *webhookService) Redeliver(ctx context.Context, id, endpointId, userId string) (*model.Delivery, error) {
+	if _, err := w.pgRepo.FindEndpointByIdAndUserId(ctx, endpointId, userId); err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+	found, err := w.pgRepo.FindDeliveryByIdAndEndpointId(ctx, id, endpointId)
+	if err != nil {
+		return nil, httpErrors.NewNotFoundError(err)
+	}
+
+	found.Redeliver(time.Now())
+	if err := w.pgRepo.UpdateDelivery(ctx, found); err != nil {
+		return nil, httpErrors.NewInternalServerError(err)
+	}
+	return found, nil
+}
+
+// Dispatch claims the due deliveries for twice the timeout, so an instance stopping in the middle
+// only delays them, and records the result of every attempt. the deliveries of the batch are sent
+// at once, each bounded by the timeout, so all of them are done while the lease holds
+func (w *webhookService) Dispatch(ctx context.Context) (int, error) {
+	now := time.Now()
+	deliveries, err := w.pgRepo.ClaimDueDeliveries(ctx, now.UnixMilli(), now.Add(2*w.timeout).UnixMilli(), dispatchBatchSize)
+	if err != nil {
+		return 0, errors.Wrap(err, "webhookService.Dispatch.ClaimDueDeliveries")
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.EndpointId)
+	}
+	endpoints, err := w.pgRepo.FindEndpointsByIds(ctx, ids)
+	if err != nil {
+		return 0, errors.Wrap(err, "webhookService.Dispatch.FindEndpointsByIds")
+	}
+	endpointById := make(map[uuid.UUID]*model.Endpoint, len(endpoints))
+	for i := range endpoints {
+		endpointById[endpoints[i].Id] = &endpoints[i]
+	}
+
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		endpoint, ok := endpointById[delivery.EndpointId]
+		if !ok {
+			// deleted meanwhile, its deliveries are deleted with it
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.attempt(ctx, endpoint, delivery)
+		}()
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// attempt sends a claimed delivery and records the result
+func (w *webhookService) attempt(ctx context.Context, endpoint *model.Endpoint, delivery *model.Delivery) {
+	if endpoint.Enabled != nil && !*endpoint.Enabled {
+		delivery.Fail(time.Now(), 0, errEndpointDisabled, 0, w.backoff)
+	} else if statusCode, err := w.send(ctx, endpoint, delivery); err != nil {
+		delivery.Fail(time.Now(), statusCode, err, w.maxAttempts, w.backoff)
+	} else {
+		delivery.Succeed(time.Now(), statusCode)
+	}
+
+	if err := w.pgRepo.UpdateDelivery(ctx, delivery); err != nil {
+		w.logger.WithError(err).WithField("deliveryId", delivery.Id).Warn("webhookService.Dispatch.UpdateDelivery")
+	}
+}
+
+func (w *webhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// a full batch means more deliveries are probably due
+			for {
+				sent, err := w.Dispatch(ctx)
+				if err != nil {
+					w.logger.WithError(err).Warn("webhookService.Run.Dispatch")
+				}
+				if err != nil || sent < dispatchBatchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (w *webhookService) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	purged, err := w.pgRepo.DeleteFinishedDeliveriesBefore(ctx, before.UnixMilli())
+	if err != nil {
+		return 0, errors.Wrap(err, "webhookService.PurgeDeliveries.DeleteFinishedDeliveriesBefore")
+	}
+	return purged, nil
+}
+
+// checkHost resolves the host of the endpoint, every address it resolves to must be public
+func (w *webhookService) checkHost(ctx context.Context, endpoint *model.Endpoint) error {
+	addresses, err := w.lookup(ctx, endpoint.Host())
+	if err != nil {
+		return errors.Wrap(err, "webhookService.checkHost.lookup")
+	}
+	for _, address := range addresses {
+		if !model.PublicAddress(address.IP) {
+			return errors.Wrapf(model.ErrPrivateAddress, "webhookService.checkHost %s", address.IP)
+		}
+	}
+	return nil
+}
+
+// send posts the payload of the delivery to the endpoint, signed with its secret. it returns the status
+// code of the answer, 0 without answer, and an error unless it is 2xx
+func (w *webhookService) send(ctx context.Context, endpoint *model.Endpoint, delivery *model.Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, errors.Wrap(err, "webhookService.send.NewRequest")
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "expenses-api-webhook")
+	request.Header.Set(model.HeaderId, delivery.Id.String())
+	request.Header.Set(model.HeaderEvent, delivery.EventType)
+	request.Header.Set(model.HeaderSignature, model.Sign(endpoint.Secret, time.Now().Unix(), delivery.Payload))
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return 0, errors.Wrap(err, "webhookService.send.Do")
+	}
+	defer response.Body.Close()
+	// the body is read so the connection is reused, the answer itself does not matter
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, errors.Errorf("webhookService.send: unexpected status %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/mrizkisaputra/expenses-api/config"
+	mockObject "github.com/mrizkisaputra/expenses-api/internal/webhook/mock"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook/model"
+	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
+	"github.com/mrizkisaputra/expenses-api/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var cfg = &config.Config{
+	Logger: config.LoggerConfig{Level: "panic"},
+}
+
+func TestWebhookService_Publish(t *testing.T) {
+	userId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] A delivery is queued for every subscribed endpoint", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewWebhookService(&ServiceConfig{PgRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		endpoints := []model.Endpoint{{Id: uuid.New(), UserId: userId}, {Id: uuid.New(), UserId: userId}}
+		mockRepo.On("FindEndpointsByEventType", mock.Anything, userId.String(), "expense.created").Return(endpoints, nil)
+		mockRepo.On("CreateDeliveries", mock.Anything, mock.MatchedBy(func(deliveries []model.Delivery) bool {
+			return len(deliveries) == 2 &&
+				deliveries[0].EndpointId == endpoints[0].Id &&
+				deliveries[1].EndpointId == endpoints[1].Id &&
+				deliveries[0].Status == model.StatusPending &&
+				strings.Contains(string(deliveries[0].Payload), `"data":{"id":"1"}`)
+		})).Return(nil).Once()
+
+		err := sv.Publish(context.Background(), userId, "expense.created", map[string]string{"id": "1"})
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Nothing is queued without subscribed endpoint", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewWebhookService(&ServiceConfig{PgRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("FindEndpointsByEventType", mock.Anything, userId.String(), "expense.deleted").Return([]model.Endpoint{}, nil)
+
+		require.NoError(t, sv.Publish(context.Background(), userId, "expense.deleted", map[string]string{}))
+		mockRepo.AssertNotCalled(t, "CreateDeliveries", mock.Anything, mock.Anything)
+	})
+}
+
+func TestWebhookService_Dispatch(t *testing.T) {
+	userId := uuid.New()
+	enabled, disabled := true, false
+
+	// scenario test case #1
+	t.Run("[Test Case #1] Signed deliveries succeed on 2xx and are retried later otherwise", func(t *testing.T) {
+		var signed atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			timestamp, _ := strconv.ParseInt(strings.TrimPrefix(strings.Split(r.Header.Get(model.HeaderSignature), ",")[0], "t="), 10, 64)
+			if r.Header.Get(model.HeaderSignature) == model.Sign("whsec_test", timestamp, body) {
+				signed.Add(1)
+			}
+			if r.URL.Path == "/fail" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewWebhookService(&ServiceConfig{PgRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+		// the test server listens on loopback, which the client of the service refuses
+		sv.(*webhookService).client = server.Client()
+
+		okEndpoint := model.Endpoint{Id: uuid.New(), UserId: userId, Url: server.URL + "/ok", Secret: "whsec_test", Enabled: &enabled}
+		failEndpoint := model.Endpoint{Id: uuid.New(), UserId: userId, Url: server.URL + "/fail", Secret: "whsec_test", Enabled: &enabled}
+		okDelivery, _ := model.NewDelivery(&okEndpoint, "expense.created", json.RawMessage(`{}`), time.Now())
+		failDelivery, _ := model.NewDelivery(&failEndpoint, "expense.created", json.RawMessage(`{}`), time.Now())
+
+		mockRepo.On("ClaimDueDeliveries", mock.Anything, mock.Anything, mock.Anything, dispatchBatchSize).
+			Return([]model.Delivery{*okDelivery, *failDelivery}, nil)
+		mockRepo.On("FindEndpointsByIds", mock.Anything, []uuid.UUID{okEndpoint.Id, failEndpoint.Id}).
+			Return([]model.Endpoint{okEndpoint, failEndpoint}, nil)
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(delivery *model.Delivery) bool {
+			return delivery.Id == okDelivery.Id && delivery.Status == model.StatusSucceeded && delivery.Attempts == 1
+		})).Return(nil).Once()
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(delivery *model.Delivery) bool {
+			return delivery.Id == failDelivery.Id && delivery.Status == model.StatusPending &&
+				*delivery.LastStatusCode == http.StatusServiceUnavailable &&
+				delivery.NextAttemptAt > time.Now().Add(20*time.Second).UnixMilli()
+		})).Return(nil).Once()
+
+		sent, err := sv.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 2, sent)
+		require.Equal(t, int32(2), signed.Load())
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Deliveries of a disabled endpoint are dead without being sent", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewWebhookService(&ServiceConfig{PgRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		endpoint := model.Endpoint{Id: uuid.New(), UserId: userId, Url: "http://127.0.0.1:1", Enabled: &disabled}
+		delivery, _ := model.NewDelivery(&endpoint, "expense.updated", json.RawMessage(`{}`), time.Now())
+		mockRepo.On("ClaimDueDeliveries", mock.Anything, mock.Anything, mock.Anything, dispatchBatchSize).Return([]model.Delivery{*delivery}, nil)
+		mockRepo.On("FindEndpointsByIds", mock.Anything, mock.Anything).Return([]model.Endpoint{endpoint}, nil)
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(updated *model.Delivery) bool {
+			return updated.Status == model.StatusDead && updated.LastError == errEndpointDisabled.Error()
+		})).Return(nil).Once()
+
+		sent, err := sv.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, sent)
+		mockRepo.AssertExpectations(t)
+	})
+
+	// scenario test case #3
+	t.Run("[Test Case #3] A private address is not dialed and a redirect is not followed", func(t *testing.T) {
+		var reached bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reached = true
+		}))
+		defer server.Close()
+
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewWebhookService(&ServiceConfig{PgRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		endpoint := model.Endpoint{Id: uuid.New(), UserId: userId, Url: server.URL, Secret: "whsec_test", Enabled: &enabled}
+		delivery, _ := model.NewDelivery(&endpoint, "expense.created", json.RawMessage(`{}`), time.Now())
+		mockRepo.On("ClaimDueDeliveries", mock.Anything, mock.Anything, mock.Anything, dispatchBatchSize).Return([]model.Delivery{*delivery}, nil)
+		mockRepo.On("FindEndpointsByIds", mock.Anything, mock.Anything).Return([]model.Endpoint{endpoint}, nil)
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(updated *model.Delivery) bool {
+			return updated.Status == model.StatusPending && updated.LastStatusCode == nil &&
+				strings.Contains(updated.LastError, model.ErrPrivateAddress.Error())
+		})).Return(nil).Once()
+
+		_, err := sv.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.False(t, reached)
+		mockRepo.AssertExpectations(t)
+
+		redirect := httptest.NewServer(http.RedirectHandler("http://169.254.169.254/latest/meta-data", http.StatusFound))
+		defer redirect.Close()
+		client := sv.(*webhookService).client
+		client.Transport = redirect.Client().Transport
+		response, err := client.Get(redirect.URL)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		require.Equal(t, http.StatusFound, response.StatusCode)
+	})
+}
+
+func TestWebhookService_Dispatch_Lease(t *testing.T) {
+	enabled := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(400 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	mockRepo := new(mockObject.MockPostgresRepository)
+	leaseCfg := &config.Config{Logger: cfg.Logger, Webhook: config.WebhookConfig{Timeout: 1}}
+	sv := NewWebhookService(&ServiceConfig{PgRepo: mockRepo, Config: leaseCfg, Logger: logger.NewLogrusLogger(leaseCfg)})
+	sv.(*webhookService).client = server.Client()
+
+	// a batch whose deliveries take longer one after the other than the lease
+	endpoint := model.Endpoint{Id: uuid.New(), Url: server.URL, Secret: "whsec_test", Enabled: &enabled}
+	deliveries := make([]model.Delivery, 0, 10)
+	for i := 0; i < 10; i++ {
+		delivery, _ := model.NewDelivery(&endpoint, "expense.created", json.RawMessage(`{}`), time.Now())
+		deliveries = append(deliveries, *delivery)
+	}
+	var leaseEnd int64
+	mockRepo.On("ClaimDueDeliveries", mock.Anything, mock.Anything, mock.Anything, dispatchBatchSize).
+		Run(func(args mock.Arguments) { leaseEnd = args.Get(2).(int64) }).Return(deliveries, nil)
+	mockRepo.On("FindEndpointsByIds", mock.Anything, mock.Anything).Return([]model.Endpoint{endpoint}, nil)
+	mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(delivery *model.Delivery) bool {
+		return delivery.Status == model.StatusSucceeded
+	})).Return(nil).Times(10)
+
+	sent, err := sv.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 10, sent)
+	require.Less(t, time.Now().UnixMilli(), leaseEnd)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_CreateEndpoint(t *testing.T) {
+	userId := uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] An endpoint whose host resolves to a private address is rejected", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewWebhookService(&ServiceConfig{PgRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+		sv.(*webhookService).lookup = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("10.0.0.1")}}, nil
+		}
+
+		_, err := sv.CreateEndpoint(context.Background(), &model.Endpoint{
+			UserId: userId, Url: "https://hooks.example.com", EventTypes: model.EventTypes{"expense.created"},
+		})
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusBadRequest, er.Status)
+		require.ErrorIs(t, er.Causes.(error), model.ErrPrivateAddress)
+		mockRepo.AssertNotCalled(t, "CreateEndpoint", mock.Anything, mock.Anything)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] An endpoint on a public address is created with its secret", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewWebhookService(&ServiceConfig{PgRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+		sv.(*webhookService).lookup = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			require.Equal(t, "hooks.example.com", host)
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+		}
+		mockRepo.On("CreateEndpoint", mock.Anything, mock.Anything).Return(nil).Once()
+
+		created, err := sv.CreateEndpoint(context.Background(), &model.Endpoint{
+			UserId: userId, Url: "https://hooks.example.com:8443/in", EventTypes: model.EventTypes{"expense.created"},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, created.Secret)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	userId, endpointId, deliveryId := uuid.New(), uuid.New(), uuid.New()
+
+	// scenario test case #1
+	t.Run("[Test Case #1] A dead delivery is pending again", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewWebhookService(&ServiceConfig{PgRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("FindEndpointByIdAndUserId", mock.Anything, endpointId.String(), userId.String()).
+			Return(&model.Endpoint{Id: endpointId, UserId: userId}, nil)
+		mockRepo.On("FindDeliveryByIdAndEndpointId", mock.Anything, deliveryId.String(), endpointId.String()).
+			Return(&model.Delivery{Id: deliveryId, EndpointId: endpointId, Status: model.StatusDead, Attempts: 8}, nil)
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.Anything).Return(nil).Once()
+
+		response, err := sv.Redeliver(context.Background(), deliveryId.String(), endpointId.String(), userId.String())
+		require.NoError(t, err)
+		require.Equal(t, model.StatusPending, response.Status)
+		require.Equal(t, 0, response.Attempts)
+	})
+
+	// scenario test case #2
+	t.Run("[Test Case #2] Should return not found for an endpoint of another user", func(t *testing.T) {
+		mockRepo := new(mockObject.MockPostgresRepository)
+		sv := NewWebhookService(&ServiceConfig{PgRepo: mockRepo, Config: cfg, Logger: logger.NewLogrusLogger(cfg)})
+
+		mockRepo.On("FindEndpointByIdAndUserId", mock.Anything, endpointId.String(), userId.String()).
+			Return(nil, errors.New("record not found"))
+
+		_, err := sv.Redeliver(context.Background(), deliveryId.String(), endpointId.String(), userId.String())
+		var er *httpErrors.Error
+		require.True(t, errors.As(err, &er))
+		require.Equal(t, http.StatusNotFound, er.Status)
+		mockRepo.AssertNotCalled(t, "UpdateDelivery", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/internal/event"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook/model"
+	"time"
+)
+
+// WebhookService defines methods the layer controller expects.
+// any services it interacts with to implement.
+type WebhookService interface {
+	// Publisher queues a delivery of the event to every enabled endpoint of the user subscribed to its type
+	event.Publisher
+
+	CreateEndpoint(ctx context.Context, request *model.Endpoint) (*model.CreatedEndpoint, error)
+
+	UpdateEndpoint(ctx context.Context, request *model.Endpoint) (*model.Endpoint, error)
+
+	DeleteEndpoint(ctx context.Context, id, userId string) error
+
+	GetEndpointById(ctx context.Context, id, userId string) (*model.Endpoint, error)
+
+	GetAllEndpoints(ctx context.Context, userId string) ([]model.Endpoint, error)
+
+	// GetDeliveries lists the deliveries of an endpoint of the user, only the ones of the status when given
+	GetDeliveries(ctx context.Context, endpointId, userId, status string, page, limit int) ([]model.Delivery, int64, error)
+
+	// Redeliver sends a delivery of an endpoint of the user again, with all its attempts
+	Redeliver(ctx context.Context, id, endpointId, userId string) (*model.Delivery, error)
+
+	// Dispatch sends the due deliveries once, it returns their number
+	Dispatch(ctx context.Context) (int, error)
+
+	// Run dispatches the due deliveries at every interval until ctx is done
+	Run(ctx context.Context)
+
+	// PurgeDeliveries deletes the succeeded and dead deliveries finished before, it returns their number
+	PurgeDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
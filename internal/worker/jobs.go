@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"context"
+	eventRepository "github.com/mrizkisaputra/expenses-api/internal/event/repository"
+	eventService "github.com/mrizkisaputra/expenses-api/internal/event/service"
+	jobModel "github.com/mrizkisaputra/expenses-api/internal/job/model"
+	jobRepository "github.com/mrizkisaputra/expenses-api/internal/job/repository"
+	jobService "github.com/mrizkisaputra/expenses-api/internal/job/service"
+	notificationChannel "github.com/mrizkisaputra/expenses-api/internal/notification/channel"
+	"github.com/mrizkisaputra/expenses-api/internal/outbox"
+	outboxModel "github.com/mrizkisaputra/expenses-api/internal/outbox/model"
+	outboxRepository "github.com/mrizkisaputra/expenses-api/internal/outbox/repository"
+	outboxService "github.com/mrizkisaputra/expenses-api/internal/outbox/service"
+	outboxSubscriber "github.com/mrizkisaputra/expenses-api/internal/outbox/subscriber"
+	userRepository "github.com/mrizkisaputra/expenses-api/internal/user/repository"
+	userService "github.com/mrizkisaputra/expenses-api/internal/user/service"
+	"github.com/mrizkisaputra/expenses-api/internal/webhook"
+	webhookRepository "github.com/mrizkisaputra/expenses-api/internal/webhook/repository"
+	webhookService "github.com/mrizkisaputra/expenses-api/internal/webhook/service"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	// defaultRetentionDays is the days the finished outbox events and webhook deliveries are kept
+	defaultRetentionDays = 30
+
+	// retentionInterval is the time between two retention purges
+	retentionInterval = 24 * time.Hour
+)
+
+func (w *Worker) Bootstrap() error {
+	// -----------------------------------------------------------------------------------------------------------
+	// create a new instance repositories
+	jobRepo := jobRepository.NewJobRedisRepository(w.redisClient)
+
+	outboxRepo := outboxRepository.NewOutboxPgRepository(w.db)
+
+	webhookRepo := webhookRepository.NewWebhookPgRepository(w.db)
+
+	eventRepo := eventRepository.NewEventRedisRepository(w.redisClient, w.cfg.Events.History)
+
+	userRedisRepo := userRepository.NewUserRedisRepository(w.redisClient)
+
+	// -----------------------------------------------------------------------------------------------------------
+	// create a new instance services
+	w.processor = jobService.NewProcessor(&jobService.ServiceConfig{
+		RedisRepo: jobRepo,
+		Logger:    w.logger,
+		Config:    w.cfg,
+	})
+
+	eventSV := eventService.NewEventService(&eventService.ServiceConfig{
+		RedisRepo: eventRepo,
+		Logger:    w.logger,
+		Config:    w.cfg,
+	})
+
+	webhookSV := webhookService.NewWebhookService(&webhookService.ServiceConfig{
+		PgRepo: webhookRepo,
+		Logger: w.logger,
+		Config: w.cfg,
+	})
+	w.loops = append(w.loops, webhookSV.Run)
+
+	// the domain events written to the outbox by the API are dispatched here, a single dispatcher
+	// dispatches at a time whatever the number of workers
+	dispatcher := outboxService.NewDispatcher(&outboxService.ServiceConfig{
+		PgRepo: outboxRepo,
+		Logger: w.logger,
+		Config: w.cfg,
+	})
+	for _, eventType := range []string{outboxModel.TypeExpenseCreated, outboxModel.TypeExpenseUpdated, outboxModel.TypeExpenseDeleted} {
+		dispatcher.Subscribe(eventType, outboxSubscriber.NewPublisherSubscriber("realtime", eventSV))
+		dispatcher.Subscribe(eventType, outboxSubscriber.NewPublisherSubscriber("webhook", webhookSV))
+	}
+	dispatcher.Subscribe(outboxModel.TypeUserUpdated, userService.NewCacheSubscriber(userRedisRepo))
+	w.loops = append(w.loops, dispatcher.Run)
+
+	// -----------------------------------------------------------------------------------------------------------
+	// register the job types
+	if w.cfg.Notification.SmtpHost != "" {
+		w.processor.Register(jobModel.TypeEmailSend, jobModel.Options{}, notificationChannel.NewEmailJobHandler(&w.cfg.Notification))
+	}
+	if w.cfg.Notification.WebhookURL != "" {
+		w.processor.Register(jobModel.TypeNotificationWebhook, jobModel.Options{}, notificationChannel.NewWebhookJobHandler(&w.cfg.Notification))
+	}
+
+	// a single purge runs at a time, the next one purges what this one left
+	w.processor.Register(jobModel.TypeRetentionPurge, jobModel.Options{Concurrency: 1, Timeout: 10 * time.Minute}, w.purgeRetention(dispatcher, webhookSV))
+	w.processor.Schedule(jobModel.TypeRetentionPurge, retentionInterval, struct{}{})
+
+	return nil
+}
+
+// purgeRetention deletes the outbox events published and the webhook deliveries finished for the retention days
+func (w *Worker) purgeRetention(dispatcher outbox.Dispatcher, webhookSV webhook.WebhookService) func(ctx context.Context, job *jobModel.Job) error {
+	days := w.cfg.Worker.RetentionDays
+	if days <= 0 {
+		days = defaultRetentionDays
+	}
+
+	return func(ctx context.Context, job *jobModel.Job) error {
+		before := time.Now().AddDate(0, 0, -days)
+
+		events, err := dispatcher.Purge(ctx, before)
+		if err != nil {
+			return errors.Wrap(err, "Worker.purgeRetention.Purge")
+		}
+		deliveries, err := webhookSV.PurgeDeliveries(ctx, before)
+		if err != nil {
+			return errors.Wrap(err, "Worker.purgeRetention.PurgeDeliveries")
+		}
+
+		w.logger.WithField("events", events).WithField("deliveries", deliveries).Info("Retention purge done")
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"github.com/mrizkisaputra/expenses-api/config"
+	"github.com/mrizkisaputra/expenses-api/internal/job"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// ctxTimeout is the seconds the running jobs are waited for on shutdown, a job still running
+	// after is run again by a worker once its lease ends
+	ctxTimeout = 30
+)
+
+type WorkerConfig struct {
+	Logger      *logrus.Logger
+	Cfg         *config.Config
+	Db          *gorm.DB
+	RedisClient *redis.Client
+}
+
+// Worker runs the background jobs apart from the API server, along the dispatch of the outbox
+// events and the webhook deliveries
+type Worker struct {
+	logger      *logrus.Logger
+	cfg         *config.Config
+	db          *gorm.DB
+	redisClient *redis.Client
+
+	processor job.Processor
+
+	// loops are the background loops running along the jobs until the worker shuts down
+	loops []func(ctx context.Context)
+}
+
+// NewWorker is a factory function
+func NewWorker(config *WorkerConfig) *Worker {
+	return &Worker{
+		logger:      config.Logger,
+		cfg:         config.Cfg,
+		db:          config.Db,
+		redisClient: config.RedisClient,
+	}
+}
+
+// Run runs the jobs and the loops until an interrupt/terminate signal, then waits for them to end
+func (w *Worker) Run() error {
+	// setup semua job worker
+	if err := w.Bootstrap(); err != nil {
+		return errors.Wrap(err, "Worker.Run.Bootstrap")
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	var running sync.WaitGroup
+	for _, run := range append([]func(ctx context.Context){w.processor.Run}, w.loops...) {
+		running.Add(1)
+		go func(run func(ctx context.Context)) {
+			defer running.Done()
+			run(ctx)
+		}(run)
+	}
+	w.logger.Info("Worker processing jobs")
+
+	done := make(chan struct{})
+	go func() {
+		running.Wait()
+		close(done)
+	}()
+
+	// listen signal interrupt/terminate from OS
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	stop()
+	select {
+	case <-done:
+	case <-time.After(ctxTimeout * time.Second):
+		w.logger.Fatalf("Error gracefully shutting down worker: still running after %d seconds", ctxTimeout)
+	}
+	w.logger.Info("Worker Exited Properly")
+	return nil
+}
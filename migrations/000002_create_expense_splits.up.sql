@@ -0,0 +1,58 @@
+CREATE TABLE expense_splits
+(
+    id         UUID DEFAULT uuid_generate_v4(),
+    id_expense UUID           NOT NULL,
+    id_user    UUID           NOT NULL,
+    method     VARCHAR(20)    NOT NULL,
+    value      DECIMAL(12, 4),
+    amount     DECIMAL(10, 2) NOT NULL,
+    created_at BIGINT         NOT NULL,
+    updated_at BIGINT         NOT NULL
+);
+
+CREATE TABLE settlements
+(
+    id         UUID DEFAULT uuid_generate_v4(),
+    id_payer   UUID           NOT NULL,
+    id_payee   UUID           NOT NULL,
+    amount     DECIMAL(10, 2) NOT NULL,
+    note       VARCHAR(200),
+    created_at BIGINT         NOT NULL,
+    updated_at BIGINT         NOT NULL
+);
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE expense_splits
+    ADD CONSTRAINT method_check CHECK ( expense_splits.method IN ('equal', 'exact', 'percentage', 'shares') ),
+    ADD CONSTRAINT split_amount_check CHECK ( expense_splits.amount >= 0 );
+
+ALTER TABLE settlements
+    ADD CONSTRAINT settlement_amount_check CHECK ( settlements.amount > 0 ),
+    ADD CONSTRAINT settlement_users_check CHECK ( settlements.id_payer <> settlements.id_payee );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE expense_splits
+    ADD CONSTRAINT expense_splits_id_pk PRIMARY KEY (id);
+
+ALTER TABLE settlements
+    ADD CONSTRAINT settlements_id_pk PRIMARY KEY (id);
+
+-------------------------------------------- CONSTRAINT FOREIGN KEY --------------------------------------------
+ALTER TABLE expense_splits
+    ADD CONSTRAINT expense_splits_id_expense_fk FOREIGN KEY (id_expense) REFERENCES expenses (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT expense_splits_id_user_fk FOREIGN KEY (id_user) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT expense_splits_expense_user_unique UNIQUE (id_expense, id_user);
+
+ALTER TABLE settlements
+    ADD CONSTRAINT settlements_id_payer_fk FOREIGN KEY (id_payer) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT settlements_id_payee_fk FOREIGN KEY (id_payee) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT;
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS expense_splits_id_expense_index ON expense_splits (id_expense);
+CREATE INDEX IF NOT EXISTS expense_splits_id_user_index ON expense_splits (id_user);
+CREATE INDEX IF NOT EXISTS settlements_id_payer_index ON settlements (id_payer);
+CREATE INDEX IF NOT EXISTS settlements_id_payee_index ON settlements (id_payee);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS exchange_rates;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS base_currency;
+
+ALTER TABLE expenses
+    DROP COLUMN IF EXISTS currency;
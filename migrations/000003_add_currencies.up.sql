@@ -0,0 +1,36 @@
+ALTER TABLE expenses
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE users
+    ADD COLUMN base_currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+-- 1 unit of base_currency is worth 'rate' units of quote_currency on rate_date
+CREATE TABLE exchange_rates
+(
+    id             UUID DEFAULT uuid_generate_v4(),
+    base_currency  CHAR(3)         NOT NULL,
+    quote_currency CHAR(3)         NOT NULL,
+    rate           NUMERIC(20, 10) NOT NULL,
+    rate_date      DATE            NOT NULL,
+    created_at     BIGINT          NOT NULL,
+    updated_at     BIGINT          NOT NULL
+);
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE expenses
+    ADD CONSTRAINT currency_check CHECK ( expenses.currency ~ '^[A-Z]{3}$' );
+
+ALTER TABLE users
+    ADD CONSTRAINT base_currency_check CHECK ( users.base_currency ~ '^[A-Z]{3}$' );
+
+ALTER TABLE exchange_rates
+    ADD CONSTRAINT rate_check CHECK ( exchange_rates.rate > 0 ),
+    ADD CONSTRAINT currency_pair_check CHECK ( exchange_rates.base_currency <> exchange_rates.quote_currency );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE exchange_rates
+    ADD CONSTRAINT exchange_rates_id_pk PRIMARY KEY (id);
+
+-------------------------------------------- CONSTRAINT UNIQUE --------------------------------------------
+ALTER TABLE exchange_rates
+    ADD CONSTRAINT exchange_rates_pair_date_unique UNIQUE (base_currency, quote_currency, rate_date);
@@ -0,0 +1,10 @@
+-- amounts are stored as exact integers in minor units (1/100 of the currency unit),
+-- existing decimal values are multiplied by 100 so no value changes
+ALTER TABLE expenses
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100)::BIGINT;
+
+ALTER TABLE expense_splits
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100)::BIGINT;
+
+ALTER TABLE settlements
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100)::BIGINT;
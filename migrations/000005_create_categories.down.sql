@@ -0,0 +1,14 @@
+ALTER TABLE expenses
+    ADD COLUMN category VARCHAR(100);
+
+UPDATE expenses e
+SET category = c.name
+FROM categories c
+WHERE c.id = e.id_category;
+
+ALTER TABLE expenses
+    ALTER COLUMN category SET NOT NULL,
+    ADD CONSTRAINT category_check CHECK ( expenses.category <> '' ),
+    DROP COLUMN id_category;
+
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,59 @@
+CREATE TABLE categories
+(
+    id         UUID DEFAULT uuid_generate_v4(),
+    id_user    UUID         NOT NULL,
+    id_parent  UUID,
+    name       VARCHAR(100) NOT NULL,
+    color      CHAR(7)      NOT NULL DEFAULT '#9E9E9E',
+    icon       VARCHAR(50)  NOT NULL DEFAULT 'tag',
+    created_at BIGINT       NOT NULL,
+    updated_at BIGINT       NOT NULL
+);
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE categories
+    ADD CONSTRAINT category_name_check CHECK ( categories.name <> '' ),
+    ADD CONSTRAINT category_color_check CHECK ( categories.color ~ '^#[0-9A-F]{6}$' ),
+    ADD CONSTRAINT category_parent_check CHECK ( categories.id_parent <> categories.id );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE categories
+    ADD CONSTRAINT categories_id_pk PRIMARY KEY (id);
+
+-------------------------------------------- CONSTRAINT FOREIGN KEY --------------------------------------------
+ALTER TABLE categories
+    ADD CONSTRAINT categories_id_user_fk FOREIGN KEY (id_user) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT categories_id_parent_fk FOREIGN KEY (id_parent) REFERENCES categories (id)
+        ON DELETE SET NULL ON UPDATE RESTRICT,
+    ADD CONSTRAINT categories_user_name_unique UNIQUE (id_user, name);
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS categories_id_parent_index ON categories (id_parent);
+
+------------------------------------------- MIGRATE FREE TEXT CATEGORY -------------------------------------------
+-- every distinct (normalized) category text of a user becomes a category entity
+INSERT INTO categories (id_user, name, created_at, updated_at)
+SELECT DISTINCT id_user,
+                LOWER(REGEXP_REPLACE(TRIM(category), '\s+', ' ', 'g')),
+                (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT,
+                (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT
+FROM expenses;
+
+ALTER TABLE expenses
+    ADD COLUMN id_category UUID;
+
+UPDATE expenses e
+SET id_category = c.id
+FROM categories c
+WHERE c.id_user = e.id_user
+  AND c.name = LOWER(REGEXP_REPLACE(TRIM(e.category), '\s+', ' ', 'g'));
+
+ALTER TABLE expenses
+    ALTER COLUMN id_category SET NOT NULL,
+    DROP CONSTRAINT IF EXISTS category_check,
+    DROP COLUMN category,
+    ADD CONSTRAINT expenses_id_category_fk FOREIGN KEY (id_category) REFERENCES categories (id)
+        ON DELETE RESTRICT ON UPDATE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS expenses_id_category_index ON expenses (id_category);
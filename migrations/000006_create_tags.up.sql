@@ -0,0 +1,41 @@
+CREATE TABLE tags
+(
+    id         UUID DEFAULT uuid_generate_v4(),
+    id_user    UUID        NOT NULL,
+    name       VARCHAR(50) NOT NULL,
+    created_at BIGINT      NOT NULL,
+    updated_at BIGINT      NOT NULL
+);
+
+CREATE TABLE expense_tags
+(
+    id_expense UUID   NOT NULL,
+    id_tag     UUID   NOT NULL,
+    created_at BIGINT NOT NULL
+);
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE tags
+    ADD CONSTRAINT tag_name_check CHECK ( tags.name ~ '^[a-z0-9][a-z0-9._-]{0,49}$' );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE tags
+    ADD CONSTRAINT tags_id_pk PRIMARY KEY (id);
+
+ALTER TABLE expense_tags
+    ADD CONSTRAINT expense_tags_pk PRIMARY KEY (id_expense, id_tag);
+
+-------------------------------------------- CONSTRAINT FOREIGN KEY --------------------------------------------
+ALTER TABLE tags
+    ADD CONSTRAINT tags_id_user_fk FOREIGN KEY (id_user) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT tags_user_name_unique UNIQUE (id_user, name);
+
+ALTER TABLE expense_tags
+    ADD CONSTRAINT expense_tags_id_expense_fk FOREIGN KEY (id_expense) REFERENCES expenses (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT expense_tags_id_tag_fk FOREIGN KEY (id_tag) REFERENCES tags (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT;
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS expense_tags_id_tag_index ON expense_tags (id_tag);
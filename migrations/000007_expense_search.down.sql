@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS categories_name_trgm_index;
+DROP INDEX IF EXISTS expenses_description_trgm_index;
+DROP INDEX IF EXISTS expenses_search_vector_index;
+
+DROP TRIGGER IF EXISTS categories_search_vector_update ON categories;
+DROP TRIGGER IF EXISTS expenses_search_vector_update ON expenses;
+DROP FUNCTION IF EXISTS categories_search_vector_trigger();
+DROP FUNCTION IF EXISTS expenses_search_vector_trigger();
+DROP FUNCTION IF EXISTS expenses_search_vector(TEXT, TEXT);
+
+ALTER TABLE expenses
+    DROP COLUMN search_vector;
@@ -0,0 +1,58 @@
+-- trigram operators and indexes used as fallback for partial words
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE expenses
+    ADD COLUMN search_vector TSVECTOR NOT NULL DEFAULT ''::TSVECTOR;
+
+------------------------------------------------ SEARCH VECTOR ------------------------------------------------
+-- the description weights more than the category name, the 'simple' configuration is
+-- used because descriptions mix indonesian and english words that should not be stemmed
+CREATE OR REPLACE FUNCTION expenses_search_vector(description TEXT, category TEXT) RETURNS TSVECTOR AS
+$$
+SELECT SETWEIGHT(TO_TSVECTOR('simple', COALESCE(description, '')), 'A') ||
+       SETWEIGHT(TO_TSVECTOR('simple', COALESCE(category, '')), 'B');
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION expenses_search_vector_trigger() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.search_vector := expenses_search_vector(
+            NEW.description,
+            (SELECT name FROM categories WHERE id = NEW.id_category));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER expenses_search_vector_update
+    BEFORE INSERT OR UPDATE OF description, id_category
+    ON expenses
+    FOR EACH ROW
+EXECUTE FUNCTION expenses_search_vector_trigger();
+
+-- renaming a category refreshes the search vector of its expenses
+CREATE OR REPLACE FUNCTION categories_search_vector_trigger() RETURNS TRIGGER AS
+$$
+BEGIN
+    UPDATE expenses
+    SET search_vector = expenses_search_vector(description, NEW.name)
+    WHERE id_category = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER categories_search_vector_update
+    AFTER UPDATE OF name
+    ON categories
+    FOR EACH ROW
+    WHEN (OLD.name IS DISTINCT FROM NEW.name)
+EXECUTE FUNCTION categories_search_vector_trigger();
+
+UPDATE expenses e
+SET search_vector = expenses_search_vector(e.description, c.name)
+FROM categories c
+WHERE c.id = e.id_category;
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS expenses_search_vector_index ON expenses USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS expenses_description_trgm_index ON expenses USING GIN (description gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS categories_name_trgm_index ON categories USING GIN (name gin_trgm_ops);
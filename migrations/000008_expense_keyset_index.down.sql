@@ -0,0 +1 @@
+DROP INDEX IF EXISTS expenses_id_user_created_at_id_index;
@@ -0,0 +1,3 @@
+---------------------------------------------------- INDEX ------------------------------------------------------
+-- serves the default sort of the listing and its keyset (cursor) pagination
+CREATE INDEX IF NOT EXISTS expenses_id_user_created_at_id_index ON expenses (id_user, created_at DESC, id DESC);
@@ -0,0 +1,3 @@
+---------------------------------------------------- COLUMN -----------------------------------------------------
+-- optimistic concurrency, bumped on every update and returned as the ETag of the expense
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,26 @@
+DROP INDEX IF EXISTS tags_id_user_change_seq_index;
+DROP INDEX IF EXISTS categories_id_user_change_seq_index;
+DROP INDEX IF EXISTS expenses_id_user_change_seq_index;
+
+DROP TRIGGER IF EXISTS tags_sync_tombstone ON tags;
+DROP TRIGGER IF EXISTS categories_sync_tombstone ON categories;
+DROP TRIGGER IF EXISTS tags_rename_sync_change ON tags;
+DROP TRIGGER IF EXISTS expense_tags_sync_change ON expense_tags;
+DROP TRIGGER IF EXISTS tags_sync_change ON tags;
+DROP TRIGGER IF EXISTS categories_sync_change ON categories;
+DROP TRIGGER IF EXISTS expenses_sync_change ON expenses;
+DROP FUNCTION IF EXISTS sync_tombstone_trigger();
+DROP FUNCTION IF EXISTS tags_rename_sync_change_trigger();
+DROP FUNCTION IF EXISTS expense_tags_sync_change_trigger();
+DROP FUNCTION IF EXISTS sync_change_seq_trigger();
+
+DROP TABLE IF EXISTS sync_tombstones;
+
+ALTER TABLE tags
+    DROP COLUMN change_seq;
+ALTER TABLE categories
+    DROP COLUMN change_seq;
+ALTER TABLE expenses
+    DROP COLUMN change_seq;
+
+DROP SEQUENCE IF EXISTS sync_change_seq;
@@ -0,0 +1,121 @@
+-- every write to a synced row takes the next value of one global sequence, a sync token is
+-- the last value a client has seen so the changes since then are the rows with a greater value
+CREATE SEQUENCE IF NOT EXISTS sync_change_seq;
+
+ALTER TABLE expenses
+    ADD COLUMN change_seq BIGINT NOT NULL DEFAULT NEXTVAL('sync_change_seq');
+ALTER TABLE categories
+    ADD COLUMN change_seq BIGINT NOT NULL DEFAULT NEXTVAL('sync_change_seq');
+ALTER TABLE tags
+    ADD COLUMN change_seq BIGINT NOT NULL DEFAULT NEXTVAL('sync_change_seq');
+
+-- categories and tags are deleted for real, their deletion is kept as a tombstone.
+-- expenses are soft deleted, their tombstone is the row itself with deleted_at set.
+-- there is no foreign key to users, deleting a user cascades to its categories whose
+-- tombstones would otherwise reference the user being deleted
+CREATE TABLE sync_tombstones
+(
+    id_user    UUID        NOT NULL,
+    entity     VARCHAR(20) NOT NULL,
+    id_entity  UUID        NOT NULL,
+    deleted_at BIGINT      NOT NULL,
+    change_seq BIGINT      NOT NULL DEFAULT NEXTVAL('sync_change_seq')
+);
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE sync_tombstones
+    ADD CONSTRAINT sync_tombstone_entity_check CHECK ( sync_tombstones.entity IN ('category', 'tag') );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE sync_tombstones
+    ADD CONSTRAINT sync_tombstones_pk PRIMARY KEY (change_seq);
+
+------------------------------------------------- CHANGE TRIGGER -------------------------------------------------
+CREATE OR REPLACE FUNCTION sync_change_seq_trigger() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.change_seq := NEXTVAL('sync_change_seq');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER expenses_sync_change
+    BEFORE UPDATE
+    ON expenses
+    FOR EACH ROW
+EXECUTE FUNCTION sync_change_seq_trigger();
+
+CREATE TRIGGER categories_sync_change
+    BEFORE UPDATE
+    ON categories
+    FOR EACH ROW
+EXECUTE FUNCTION sync_change_seq_trigger();
+
+CREATE TRIGGER tags_sync_change
+    BEFORE UPDATE
+    ON tags
+    FOR EACH ROW
+EXECUTE FUNCTION sync_change_seq_trigger();
+
+-- the tags are part of a synced expense, attaching, detaching or renaming a tag changes its expenses
+CREATE OR REPLACE FUNCTION expense_tags_sync_change_trigger() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE expenses SET change_seq = NEXTVAL('sync_change_seq') WHERE id = OLD.id_expense;
+    ELSE
+        UPDATE expenses SET change_seq = NEXTVAL('sync_change_seq') WHERE id = NEW.id_expense;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER expense_tags_sync_change
+    AFTER INSERT OR DELETE
+    ON expense_tags
+    FOR EACH ROW
+EXECUTE FUNCTION expense_tags_sync_change_trigger();
+
+CREATE OR REPLACE FUNCTION tags_rename_sync_change_trigger() RETURNS TRIGGER AS
+$$
+BEGIN
+    UPDATE expenses
+    SET change_seq = NEXTVAL('sync_change_seq')
+    WHERE id IN (SELECT id_expense FROM expense_tags WHERE id_tag = NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tags_rename_sync_change
+    AFTER UPDATE OF name
+    ON tags
+    FOR EACH ROW
+    WHEN (OLD.name IS DISTINCT FROM NEW.name)
+EXECUTE FUNCTION tags_rename_sync_change_trigger();
+
+CREATE OR REPLACE FUNCTION sync_tombstone_trigger() RETURNS TRIGGER AS
+$$
+BEGIN
+    INSERT INTO sync_tombstones (id_user, entity, id_entity, deleted_at)
+    VALUES (OLD.id_user, TG_ARGV[0], OLD.id, (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER categories_sync_tombstone
+    AFTER DELETE
+    ON categories
+    FOR EACH ROW
+EXECUTE FUNCTION sync_tombstone_trigger('category');
+
+CREATE TRIGGER tags_sync_tombstone
+    AFTER DELETE
+    ON tags
+    FOR EACH ROW
+EXECUTE FUNCTION sync_tombstone_trigger('tag');
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS expenses_id_user_change_seq_index ON expenses (id_user, change_seq);
+CREATE INDEX IF NOT EXISTS categories_id_user_change_seq_index ON categories (id_user, change_seq);
+CREATE INDEX IF NOT EXISTS tags_id_user_change_seq_index ON tags (id_user, change_seq);
+CREATE INDEX IF NOT EXISTS sync_tombstones_id_user_change_seq_index ON sync_tombstones (id_user, change_seq);
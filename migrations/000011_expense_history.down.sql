@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS expense_history_immutable ON expense_history;
+DROP FUNCTION IF EXISTS expense_history_immutable_trigger();
+
+DROP TABLE IF EXISTS expense_history;
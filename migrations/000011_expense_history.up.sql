@@ -0,0 +1,43 @@
+CREATE TABLE expense_history
+(
+    id         UUID DEFAULT uuid_generate_v4(),
+    id_expense UUID         NOT NULL,
+    id_user    UUID         NOT NULL,
+    id_actor   UUID,
+    action     VARCHAR(10)  NOT NULL,
+    request_id VARCHAR(100) NOT NULL DEFAULT '',
+    version    BIGINT       NOT NULL,
+    changes    JSONB        NOT NULL,
+    created_at BIGINT       NOT NULL
+);
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE expense_history
+    ADD CONSTRAINT expense_history_action_check CHECK ( expense_history.action IN ('create', 'update', 'delete', 'restore') );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE expense_history
+    ADD CONSTRAINT expense_history_id_pk PRIMARY KEY (id);
+
+-------------------------------------------- CONSTRAINT FOREIGN KEY --------------------------------------------
+ALTER TABLE expense_history
+    ADD CONSTRAINT expense_history_id_expense_fk FOREIGN KEY (id_expense) REFERENCES expenses (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT;
+
+--------------------------------------------------- IMMUTABLE ---------------------------------------------------
+-- history rows are never changed, they only go away with their expense
+CREATE OR REPLACE FUNCTION expense_history_immutable_trigger() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'expense_history rows are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER expense_history_immutable
+    BEFORE UPDATE
+    ON expense_history
+    FOR EACH ROW
+EXECUTE FUNCTION expense_history_immutable_trigger();
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS expense_history_id_expense_created_at_index ON expense_history (id_expense, created_at);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS incomes;
+
+-- income categories cannot be used by expenses, nothing points to them anymore
+DELETE FROM categories WHERE kind = 'income';
+
+ALTER TABLE categories
+    DROP CONSTRAINT IF EXISTS category_kind_check,
+    DROP COLUMN kind;
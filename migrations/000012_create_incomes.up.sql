@@ -0,0 +1,58 @@
+ALTER TABLE categories
+    ADD COLUMN kind VARCHAR(10) NOT NULL DEFAULT 'expense';
+
+-- money received by a user, the counterpart of expenses in the cash flow
+CREATE TABLE incomes
+(
+    id          UUID                     DEFAULT uuid_generate_v4(),
+    id_user     UUID         NOT NULL,
+    id_category UUID         NOT NULL,
+    description VARCHAR(200) NOT NULL,
+    amount      BIGINT       NOT NULL,
+    currency    CHAR(3)      NOT NULL DEFAULT 'IDR',
+    created_at  BIGINT       NOT NULL,
+    updated_at  BIGINT       NOT NULL,
+    deleted_at  TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE categories
+    ADD CONSTRAINT category_kind_check CHECK ( categories.kind IN ('expense', 'income') );
+
+ALTER TABLE incomes
+    ADD CONSTRAINT income_description_check CHECK ( incomes.description <> '' ),
+    ADD CONSTRAINT income_amount_check CHECK ( incomes.amount > 0 ),
+    ADD CONSTRAINT income_currency_check CHECK ( incomes.currency ~ '^[A-Z]{3}$' );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE incomes
+    ADD CONSTRAINT incomes_id_pk PRIMARY KEY (id);
+
+-------------------------------------------- CONSTRAINT FOREIGN KEY --------------------------------------------
+ALTER TABLE incomes
+    ADD CONSTRAINT incomes_id_user_fk FOREIGN KEY (id_user) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT incomes_id_category_fk FOREIGN KEY (id_category) REFERENCES categories (id)
+        ON DELETE RESTRICT ON UPDATE RESTRICT;
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS incomes_id_user_created_at_index ON incomes (id_user, created_at);
+CREATE INDEX IF NOT EXISTS incomes_id_category_index ON incomes (id_category);
+
+------------------------------------------- SEED INCOME CATEGORIES -------------------------------------------
+-- users that already have categories are not seeded again by the api, they get the income defaults here
+INSERT INTO categories (id_user, name, color, icon, kind, created_at, updated_at)
+SELECT u.id,
+       d.name,
+       d.color,
+       d.icon,
+       'income',
+       (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT,
+       (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT
+FROM users u
+         CROSS JOIN (VALUES ('salary', '#66BB6A', 'briefcase'),
+                            ('refunds', '#29B6F6', 'rotate-ccw'),
+                            ('side income', '#FFA726', 'coins'),
+                            ('other income', '#9E9E9E', 'tag')) AS d (name, color, icon)
+WHERE EXISTS (SELECT 1 FROM categories c WHERE c.id_user = u.id)
+ON CONFLICT ON CONSTRAINT categories_user_name_unique DO NOTHING;
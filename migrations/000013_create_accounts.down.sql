@@ -0,0 +1,10 @@
+ALTER TABLE incomes
+    DROP CONSTRAINT IF EXISTS incomes_id_account_fk,
+    DROP COLUMN id_account;
+
+ALTER TABLE expenses
+    DROP CONSTRAINT IF EXISTS expenses_id_account_fk,
+    DROP COLUMN id_account;
+
+DROP TABLE IF EXISTS transfers;
+DROP TABLE IF EXISTS accounts;
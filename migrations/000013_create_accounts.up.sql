@@ -0,0 +1,79 @@
+-- accounts (wallets) of a user the money is spent from, received on and transferred between
+CREATE TABLE accounts
+(
+    id              UUID DEFAULT uuid_generate_v4(),
+    id_user         UUID         NOT NULL,
+    name            VARCHAR(100) NOT NULL,
+    type            VARCHAR(20)  NOT NULL DEFAULT 'other',
+    currency        CHAR(3)      NOT NULL DEFAULT 'IDR',
+    opening_balance BIGINT       NOT NULL DEFAULT 0,
+    created_at      BIGINT       NOT NULL,
+    updated_at      BIGINT       NOT NULL
+);
+
+-- amount leaves the source account in its currency, to_amount arrives on the destination account in its currency
+CREATE TABLE transfers
+(
+    id              UUID DEFAULT uuid_generate_v4(),
+    id_user         UUID   NOT NULL,
+    id_from_account UUID   NOT NULL,
+    id_to_account   UUID   NOT NULL,
+    amount          BIGINT NOT NULL,
+    to_amount       BIGINT NOT NULL,
+    note            VARCHAR(200),
+    created_at      BIGINT NOT NULL,
+    updated_at      BIGINT NOT NULL
+);
+
+ALTER TABLE expenses
+    ADD COLUMN id_account UUID;
+
+ALTER TABLE incomes
+    ADD COLUMN id_account UUID;
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE accounts
+    ADD CONSTRAINT account_name_check CHECK ( accounts.name <> '' ),
+    ADD CONSTRAINT account_type_check CHECK ( accounts.type IN ('cash', 'bank', 'credit_card', 'e_wallet', 'other') ),
+    ADD CONSTRAINT account_currency_check CHECK ( accounts.currency ~ '^[A-Z]{3}$' );
+
+ALTER TABLE transfers
+    ADD CONSTRAINT transfer_amount_check CHECK ( transfers.amount > 0 AND transfers.to_amount > 0 ),
+    ADD CONSTRAINT transfer_accounts_check CHECK ( transfers.id_from_account <> transfers.id_to_account );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE accounts
+    ADD CONSTRAINT accounts_id_pk PRIMARY KEY (id);
+
+ALTER TABLE transfers
+    ADD CONSTRAINT transfers_id_pk PRIMARY KEY (id);
+
+-------------------------------------------- CONSTRAINT FOREIGN KEY --------------------------------------------
+-- deleting an account keeps its expenses and incomes without account, its transfers must be deleted first
+ALTER TABLE accounts
+    ADD CONSTRAINT accounts_id_user_fk FOREIGN KEY (id_user) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT accounts_user_name_unique UNIQUE (id_user, name);
+
+ALTER TABLE transfers
+    ADD CONSTRAINT transfers_id_user_fk FOREIGN KEY (id_user) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT transfers_id_from_account_fk FOREIGN KEY (id_from_account) REFERENCES accounts (id)
+        ON DELETE RESTRICT ON UPDATE RESTRICT,
+    ADD CONSTRAINT transfers_id_to_account_fk FOREIGN KEY (id_to_account) REFERENCES accounts (id)
+        ON DELETE RESTRICT ON UPDATE RESTRICT;
+
+ALTER TABLE expenses
+    ADD CONSTRAINT expenses_id_account_fk FOREIGN KEY (id_account) REFERENCES accounts (id)
+        ON DELETE SET NULL ON UPDATE RESTRICT;
+
+ALTER TABLE incomes
+    ADD CONSTRAINT incomes_id_account_fk FOREIGN KEY (id_account) REFERENCES accounts (id)
+        ON DELETE SET NULL ON UPDATE RESTRICT;
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS expenses_id_account_index ON expenses (id_account, created_at);
+CREATE INDEX IF NOT EXISTS incomes_id_account_index ON incomes (id_account, created_at);
+CREATE INDEX IF NOT EXISTS transfers_id_from_account_index ON transfers (id_from_account, created_at);
+CREATE INDEX IF NOT EXISTS transfers_id_to_account_index ON transfers (id_to_account, created_at);
+CREATE INDEX IF NOT EXISTS transfers_id_user_index ON transfers (id_user, created_at);
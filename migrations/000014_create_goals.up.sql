@@ -0,0 +1,61 @@
+-- savings goals of a user, like "Laptop Rp15jt by June", the money saved toward them is recorded as contributions
+CREATE TABLE goals
+(
+    id            UUID DEFAULT uuid_generate_v4(),
+    id_user       UUID         NOT NULL,
+    name          VARCHAR(100) NOT NULL,
+    target_amount BIGINT       NOT NULL,
+    currency      CHAR(3)      NOT NULL DEFAULT 'IDR',
+    target_date   DATE         NOT NULL,
+    created_at    BIGINT       NOT NULL,
+    updated_at    BIGINT       NOT NULL
+);
+
+-- amount is in the currency of the goal, a contribution may be linked to the transfer the money was saved with
+CREATE TABLE goal_contributions
+(
+    id          UUID DEFAULT uuid_generate_v4(),
+    id_goal     UUID   NOT NULL,
+    id_user     UUID   NOT NULL,
+    id_transfer UUID,
+    amount      BIGINT NOT NULL,
+    note        VARCHAR(200),
+    created_at  BIGINT NOT NULL,
+    updated_at  BIGINT NOT NULL
+);
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE goals
+    ADD CONSTRAINT goal_name_check CHECK ( goals.name <> '' ),
+    ADD CONSTRAINT goal_target_amount_check CHECK ( goals.target_amount > 0 ),
+    ADD CONSTRAINT goal_currency_check CHECK ( goals.currency ~ '^[A-Z]{3}$' );
+
+ALTER TABLE goal_contributions
+    ADD CONSTRAINT goal_contribution_amount_check CHECK ( goal_contributions.amount > 0 );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE goals
+    ADD CONSTRAINT goals_id_pk PRIMARY KEY (id);
+
+ALTER TABLE goal_contributions
+    ADD CONSTRAINT goal_contributions_id_pk PRIMARY KEY (id);
+
+-------------------------------------------- CONSTRAINT FOREIGN KEY --------------------------------------------
+-- deleting a goal deletes its contributions, deleting a transfer deletes the contribution linked to it
+ALTER TABLE goals
+    ADD CONSTRAINT goals_id_user_fk FOREIGN KEY (id_user) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT goals_user_name_unique UNIQUE (id_user, name);
+
+ALTER TABLE goal_contributions
+    ADD CONSTRAINT goal_contributions_id_goal_fk FOREIGN KEY (id_goal) REFERENCES goals (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT goal_contributions_id_user_fk FOREIGN KEY (id_user) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT goal_contributions_id_transfer_fk FOREIGN KEY (id_transfer) REFERENCES transfers (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT goal_contributions_id_transfer_unique UNIQUE (id_transfer);
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS goals_id_user_index ON goals (id_user, target_date);
+CREATE INDEX IF NOT EXISTS goal_contributions_id_goal_index ON goal_contributions (id_goal, created_at);
@@ -0,0 +1,45 @@
+-- rules of a user applied to new expenses: every condition set must match (description contains
+-- and regular expression, both case insensitive, amount range and account) to apply the actions set
+-- (set the category, add tags and rename the description)
+CREATE TABLE rules
+(
+    id                   UUID DEFAULT uuid_generate_v4(),
+    id_user              UUID         NOT NULL,
+    name                 VARCHAR(100) NOT NULL,
+    priority             INT          NOT NULL DEFAULT 0,
+    enabled              BOOLEAN      NOT NULL DEFAULT TRUE,
+    description_contains VARCHAR(100) NOT NULL DEFAULT '',
+    description_regex    VARCHAR(200) NOT NULL DEFAULT '',
+    min_amount           BIGINT,
+    max_amount           BIGINT,
+    id_account           UUID,
+    id_category          UUID,
+    add_tags             JSONB        NOT NULL DEFAULT '[]',
+    rename_description   VARCHAR(255) NOT NULL DEFAULT '',
+    created_at           BIGINT       NOT NULL,
+    updated_at           BIGINT       NOT NULL
+);
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE rules
+    ADD CONSTRAINT rule_name_check CHECK ( rules.name <> '' ),
+    ADD CONSTRAINT rule_amount_check CHECK ( rules.min_amount IS NULL OR rules.max_amount IS NULL OR
+                                             rules.min_amount <= rules.max_amount );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE rules
+    ADD CONSTRAINT rules_id_pk PRIMARY KEY (id);
+
+-------------------------------------------- CONSTRAINT FOREIGN KEY --------------------------------------------
+-- a rule goes away with the account it matches on, it only loses its category action with the category
+ALTER TABLE rules
+    ADD CONSTRAINT rules_id_user_fk FOREIGN KEY (id_user) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT rules_id_account_fk FOREIGN KEY (id_account) REFERENCES accounts (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT rules_id_category_fk FOREIGN KEY (id_category) REFERENCES categories (id)
+        ON DELETE SET NULL ON UPDATE RESTRICT,
+    ADD CONSTRAINT rules_user_name_unique UNIQUE (id_user, name);
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS rules_id_user_index ON rules (id_user, priority, created_at);
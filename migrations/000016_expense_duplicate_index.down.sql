@@ -0,0 +1 @@
+DROP INDEX IF EXISTS expenses_id_user_amount_currency_created_at_index;
@@ -0,0 +1,4 @@
+---------------------------------------------------- INDEX ------------------------------------------------------
+-- serves the lookup of the expenses of the same amount and currency made around the same time (duplicates)
+CREATE INDEX IF NOT EXISTS expenses_id_user_amount_currency_created_at_index
+    ON expenses (id_user, amount, currency, created_at) WHERE deleted_at IS NULL;
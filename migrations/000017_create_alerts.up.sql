@@ -0,0 +1,42 @@
+-- alerts raised when a new expense looks unusual against the spending history of the user:
+-- a category spending far above its weekly average, an abnormally large expense for its category
+-- or a big charge from a merchant (description) never seen before
+CREATE TABLE alerts
+(
+    id          UUID DEFAULT uuid_generate_v4(),
+    id_user     UUID             NOT NULL,
+    id_expense  UUID             NOT NULL,
+    id_category UUID,
+    kind        VARCHAR(20)      NOT NULL,
+    currency    VARCHAR(3)       NOT NULL,
+    amount      BIGINT           NOT NULL,
+    baseline    BIGINT           NOT NULL,
+    score       DOUBLE PRECISION NOT NULL,
+    message     VARCHAR(255)     NOT NULL,
+    read_at     BIGINT,
+    created_at  BIGINT           NOT NULL,
+    updated_at  BIGINT           NOT NULL
+);
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE alerts
+    ADD CONSTRAINT alert_kind_check CHECK ( alerts.kind IN ('category_spike', 'large_expense', 'new_merchant') );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE alerts
+    ADD CONSTRAINT alerts_id_pk PRIMARY KEY (id);
+
+-------------------------------------------- CONSTRAINT FOREIGN KEY --------------------------------------------
+-- an expense raises each kind of alert once, the alert goes away with it
+ALTER TABLE alerts
+    ADD CONSTRAINT alerts_id_user_fk FOREIGN KEY (id_user) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT alerts_id_expense_fk FOREIGN KEY (id_expense) REFERENCES expenses (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT,
+    ADD CONSTRAINT alerts_id_category_fk FOREIGN KEY (id_category) REFERENCES categories (id)
+        ON DELETE SET NULL ON UPDATE RESTRICT,
+    ADD CONSTRAINT alerts_expense_kind_unique UNIQUE (id_expense, kind);
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS alerts_id_user_index ON alerts (id_user, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS alerts_id_category_index ON alerts (id_category, kind, created_at);
@@ -0,0 +1,32 @@
+-- the inbox of notifications of a user: budget breaches, recurring charge reminders, shared ledger invites,
+-- security events and spending alerts. Data holds the ids the client needs to link the notification
+CREATE TABLE notifications
+(
+    id         UUID DEFAULT uuid_generate_v4(),
+    id_user    UUID         NOT NULL,
+    kind       VARCHAR(30)  NOT NULL,
+    title      VARCHAR(100) NOT NULL,
+    body       VARCHAR(500) NOT NULL,
+    data       JSONB        NOT NULL DEFAULT '{}',
+    read_at    BIGINT,
+    created_at BIGINT       NOT NULL,
+    updated_at BIGINT       NOT NULL
+);
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE notifications
+    ADD CONSTRAINT notification_kind_check CHECK ( notifications.kind IN
+        ('budget_breach', 'recurring_reminder', 'ledger_invite', 'security_login', 'spending_alert') );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE notifications
+    ADD CONSTRAINT notifications_id_pk PRIMARY KEY (id);
+
+-------------------------------------------- CONSTRAINT FOREIGN KEY --------------------------------------------
+ALTER TABLE notifications
+    ADD CONSTRAINT notifications_id_user_fk FOREIGN KEY (id_user) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT;
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS notifications_id_user_index ON notifications (id_user, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS notifications_unread_index ON notifications (id_user) WHERE read_at IS NULL;
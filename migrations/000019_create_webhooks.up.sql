@@ -0,0 +1,61 @@
+-- endpoints of a user receiving the events they subscribe to, every delivery is signed with the secret
+-- of its endpoint (HMAC-SHA256)
+CREATE TABLE webhook_endpoints
+(
+    id          UUID DEFAULT uuid_generate_v4(),
+    id_user     UUID         NOT NULL,
+    url         VARCHAR(500) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    secret      VARCHAR(100) NOT NULL,
+    event_types JSONB        NOT NULL DEFAULT '[]',
+    enabled     BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at  BIGINT       NOT NULL,
+    updated_at  BIGINT       NOT NULL
+);
+
+-- a delivery of an event to an endpoint. a pending delivery is sent once next_attempt_at is passed, a failed
+-- attempt is retried later with an exponential backoff and the delivery is dead once the attempts run out
+CREATE TABLE webhook_deliveries
+(
+    id               UUID DEFAULT uuid_generate_v4(),
+    id_endpoint      UUID         NOT NULL,
+    id_user          UUID         NOT NULL,
+    event_type       VARCHAR(50)  NOT NULL,
+    payload          JSONB        NOT NULL,
+    status           VARCHAR(20)  NOT NULL DEFAULT 'pending',
+    attempts         INT          NOT NULL DEFAULT 0,
+    next_attempt_at  BIGINT       NOT NULL,
+    last_status_code INT,
+    last_error       VARCHAR(500) NOT NULL DEFAULT '',
+    delivered_at     BIGINT,
+    created_at       BIGINT       NOT NULL,
+    updated_at       BIGINT       NOT NULL
+);
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE webhook_endpoints
+    ADD CONSTRAINT webhook_endpoint_url_check CHECK ( webhook_endpoints.url ~ '^https?://' );
+
+ALTER TABLE webhook_deliveries
+    ADD CONSTRAINT webhook_delivery_status_check CHECK ( webhook_deliveries.status IN ('pending', 'succeeded', 'dead') );
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE webhook_endpoints
+    ADD CONSTRAINT webhook_endpoints_id_pk PRIMARY KEY (id);
+
+ALTER TABLE webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_id_pk PRIMARY KEY (id);
+
+-------------------------------------------- CONSTRAINT FOREIGN KEY --------------------------------------------
+ALTER TABLE webhook_endpoints
+    ADD CONSTRAINT webhook_endpoints_id_user_fk FOREIGN KEY (id_user) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT;
+
+ALTER TABLE webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_id_endpoint_fk FOREIGN KEY (id_endpoint) REFERENCES webhook_endpoints (id)
+        ON DELETE CASCADE ON UPDATE RESTRICT;
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS webhook_endpoints_id_user_index ON webhook_endpoints (id_user);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_id_endpoint_index ON webhook_deliveries (id_endpoint, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_index ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
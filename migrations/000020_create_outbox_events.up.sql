@@ -0,0 +1,26 @@
+-- domain events written in the same transaction as the change they describe, then dispatched to the
+-- subscribers in the order of their id per aggregate. an event is pending until published_at is set,
+-- a failed dispatch is retried once next_attempt_at is passed
+CREATE TABLE outbox_events
+(
+    id              BIGSERIAL,
+    aggregate_type  VARCHAR(50)  NOT NULL,
+    aggregate_id    UUID         NOT NULL,
+    id_user         UUID         NOT NULL,
+    type            VARCHAR(50)  NOT NULL,
+    payload         JSONB        NOT NULL,
+    attempts        INT          NOT NULL DEFAULT 0,
+    next_attempt_at BIGINT       NOT NULL,
+    last_error      VARCHAR(500) NOT NULL DEFAULT '',
+    published_at    BIGINT,
+    created_at      BIGINT       NOT NULL
+);
+
+-------------------------------------------- CONSTRAINT PRIMARY KEY --------------------------------------------
+ALTER TABLE outbox_events
+    ADD CONSTRAINT outbox_events_id_pk PRIMARY KEY (id);
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+CREATE INDEX IF NOT EXISTS outbox_events_pending_index ON outbox_events (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_events_aggregate_index ON outbox_events (aggregate_type, aggregate_id)
+    WHERE published_at IS NULL;
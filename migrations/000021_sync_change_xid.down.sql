@@ -0,0 +1,25 @@
+DROP INDEX IF EXISTS sync_tombstones_id_user_change_xid_seq_index;
+DROP INDEX IF EXISTS tags_id_user_change_xid_seq_index;
+DROP INDEX IF EXISTS categories_id_user_change_xid_seq_index;
+DROP INDEX IF EXISTS expenses_id_user_change_xid_seq_index;
+CREATE INDEX IF NOT EXISTS expenses_id_user_change_seq_index ON expenses (id_user, change_seq);
+CREATE INDEX IF NOT EXISTS categories_id_user_change_seq_index ON categories (id_user, change_seq);
+CREATE INDEX IF NOT EXISTS tags_id_user_change_seq_index ON tags (id_user, change_seq);
+CREATE INDEX IF NOT EXISTS sync_tombstones_id_user_change_seq_index ON sync_tombstones (id_user, change_seq);
+
+CREATE OR REPLACE FUNCTION sync_change_seq_trigger() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.change_seq := NEXTVAL('sync_change_seq');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE sync_tombstones
+    DROP COLUMN change_xid;
+ALTER TABLE tags
+    DROP COLUMN change_xid;
+ALTER TABLE categories
+    DROP COLUMN change_xid;
+ALTER TABLE expenses
+    DROP COLUMN change_xid;
@@ -0,0 +1,32 @@
+-- a sequence value is taken when a row is written, not when its transaction commits, so a transaction
+-- taking a value and committing after a later one would land behind a position already pulled.
+-- every write also records its transaction id, the feed is ordered by transaction then by sequence
+-- and only returns the transactions older than every one still running
+ALTER TABLE expenses
+    ADD COLUMN change_xid BIGINT NOT NULL DEFAULT pg_current_xact_id()::TEXT::BIGINT;
+ALTER TABLE categories
+    ADD COLUMN change_xid BIGINT NOT NULL DEFAULT pg_current_xact_id()::TEXT::BIGINT;
+ALTER TABLE tags
+    ADD COLUMN change_xid BIGINT NOT NULL DEFAULT pg_current_xact_id()::TEXT::BIGINT;
+ALTER TABLE sync_tombstones
+    ADD COLUMN change_xid BIGINT NOT NULL DEFAULT pg_current_xact_id()::TEXT::BIGINT;
+
+------------------------------------------------- CHANGE TRIGGER -------------------------------------------------
+CREATE OR REPLACE FUNCTION sync_change_seq_trigger() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.change_xid := pg_current_xact_id()::TEXT::BIGINT;
+    NEW.change_seq := NEXTVAL('sync_change_seq');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+DROP INDEX IF EXISTS expenses_id_user_change_seq_index;
+DROP INDEX IF EXISTS categories_id_user_change_seq_index;
+DROP INDEX IF EXISTS tags_id_user_change_seq_index;
+DROP INDEX IF EXISTS sync_tombstones_id_user_change_seq_index;
+CREATE INDEX IF NOT EXISTS expenses_id_user_change_xid_seq_index ON expenses (id_user, change_xid, change_seq);
+CREATE INDEX IF NOT EXISTS categories_id_user_change_xid_seq_index ON categories (id_user, change_xid, change_seq);
+CREATE INDEX IF NOT EXISTS tags_id_user_change_xid_seq_index ON tags (id_user, change_xid, change_seq);
+CREATE INDEX IF NOT EXISTS sync_tombstones_id_user_change_xid_seq_index ON sync_tombstones (id_user, change_xid, change_seq);
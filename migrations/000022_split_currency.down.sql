@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS expense_splits_currency_update ON expenses;
+DROP FUNCTION IF EXISTS expense_splits_currency_trigger();
+
+ALTER TABLE settlements
+    DROP COLUMN IF EXISTS currency;
+ALTER TABLE expense_splits
+    DROP COLUMN IF EXISTS currency;
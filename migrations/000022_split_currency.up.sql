@@ -0,0 +1,39 @@
+-- a split is owed in the currency of its expense and a settlement is paid in a currency,
+-- the balances are netted per currency so amounts of different currencies are never added up
+ALTER TABLE expense_splits
+    ADD COLUMN currency CHAR(3);
+ALTER TABLE settlements
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+UPDATE expense_splits s
+SET currency = e.currency
+FROM expenses e
+WHERE e.id = s.id_expense;
+
+ALTER TABLE expense_splits
+    ALTER COLUMN currency SET NOT NULL;
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE expense_splits
+    ADD CONSTRAINT split_currency_check CHECK ( expense_splits.currency ~ '^[A-Z]{3}$' );
+
+ALTER TABLE settlements
+    ALTER COLUMN currency DROP DEFAULT,
+    ADD CONSTRAINT settlement_currency_check CHECK ( settlements.currency ~ '^[A-Z]{3}$' );
+
+------------------------------------------------ CURRENCY TRIGGER ------------------------------------------------
+-- the splits follow their expense when its currency changes
+CREATE OR REPLACE FUNCTION expense_splits_currency_trigger() RETURNS TRIGGER AS
+$$
+BEGIN
+    UPDATE expense_splits SET currency = NEW.currency WHERE id_expense = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER expense_splits_currency_update
+    AFTER UPDATE OF currency
+    ON expenses
+    FOR EACH ROW
+    WHEN ( OLD.currency IS DISTINCT FROM NEW.currency )
+EXECUTE FUNCTION expense_splits_currency_trigger();
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS outbox_events_pending_index;
+DROP INDEX IF EXISTS outbox_events_aggregate_index;
+CREATE INDEX IF NOT EXISTS outbox_events_pending_index ON outbox_events (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_events_aggregate_index ON outbox_events (aggregate_type, aggregate_id)
+    WHERE published_at IS NULL;
+
+ALTER TABLE outbox_events
+    DROP CONSTRAINT IF EXISTS outbox_event_status_check,
+    DROP COLUMN IF EXISTS handled,
+    DROP COLUMN IF EXISTS status;
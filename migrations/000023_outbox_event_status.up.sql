@@ -0,0 +1,21 @@
+-- an event is pending until handed to every subscriber (published) or out of attempts (dead), a dead event
+-- no longer blocks the next events of its aggregate. handled lists the subscribers which already handled
+-- the event, a retry only hands it to the others
+ALTER TABLE outbox_events
+    ADD COLUMN status  VARCHAR(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN handled JSONB       NOT NULL DEFAULT '[]';
+
+UPDATE outbox_events
+SET status = 'published'
+WHERE published_at IS NOT NULL;
+
+-------------------------------------------- CONSTRAINT TYPE CHECK --------------------------------------------
+ALTER TABLE outbox_events
+    ADD CONSTRAINT outbox_event_status_check CHECK ( outbox_events.status IN ('pending', 'published', 'dead') );
+
+---------------------------------------------------- INDEX ------------------------------------------------------
+DROP INDEX IF EXISTS outbox_events_pending_index;
+DROP INDEX IF EXISTS outbox_events_aggregate_index;
+CREATE INDEX IF NOT EXISTS outbox_events_pending_index ON outbox_events (id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS outbox_events_aggregate_index ON outbox_events (aggregate_type, aggregate_id)
+    WHERE status = 'pending';
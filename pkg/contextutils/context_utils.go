@@ -1,14 +1,19 @@
 package contextutils
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 const (
 	KeyRequestID = "requestId"
+	KeyActorID   = "actorId"
 )
 
+// shutdownKey holds the context cancelled once the server shuts down
+type shutdownKey struct{}
+
 // GetRequestId is a function get request id
 func GetRequestId(ctx *gin.Context) string {
 	requestId := ctx.GetString(KeyRequestID)
@@ -28,3 +33,47 @@ func AssignRequestId(ctx *gin.Context) string {
 
 	return id
 }
+
+// SetRequestId save a request id given by the client in gin context
+func SetRequestId(ctx *gin.Context, requestId string) {
+	ctx.Set(KeyRequestID, requestId)
+}
+
+// SetActorId save the id of the authenticated user making the request in gin context
+func SetActorId(ctx *gin.Context, id uuid.UUID) {
+	ctx.Set(KeyActorID, id)
+}
+
+// RequestIdFromContext is GetRequestId for the service layer, the gin context is passed down as context.Context
+func RequestIdFromContext(ctx context.Context) string {
+	requestId, _ := ctx.Value(KeyRequestID).(string)
+	return requestId
+}
+
+// ActorIdFromContext returns the authenticated user making the request, nil outside of a request
+func ActorIdFromContext(ctx context.Context) *uuid.UUID {
+	if id, ok := ctx.Value(KeyActorID).(uuid.UUID); ok {
+		return &id
+	}
+	return nil
+}
+
+// WithShutdown returns ctx carrying shutdown, a context cancelled once the server shuts down
+func WithShutdown(ctx context.Context, shutdown context.Context) context.Context {
+	return context.WithValue(ctx, shutdownKey{}, shutdown)
+}
+
+// UntilShutdown returns a context cancelled with ctx or once the server shuts down. the shutdown waits
+// for the requests in progress, a request lasting until the client leaves (a stream) has to end itself
+func UntilShutdown(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	shutdown, ok := ctx.Value(shutdownKey{}).(context.Context)
+	if !ok {
+		return ctx, cancel
+	}
+	stop := context.AfterFunc(shutdown, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
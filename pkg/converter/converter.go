@@ -8,15 +8,16 @@ import (
 // converter user to UserResponse
 func ToUserResponse(user *model.User) *dto.UserResponse {
 	return &dto.UserResponse{
-		Id:          user.Id,
-		FirstName:   user.Information.FirstName,
-		LastName:    user.Information.LastName,
-		Email:       user.Email,
-		Password:    user.Password,
-		Avatar:      user.Avatar,
-		City:        user.Information.City,
-		PhoneNumber: user.Information.PhoneNumber,
-		CreatedAt:   user.CreatedAt,
-		UpdatedAt:   user.UpdatedAt,
+		Id:           user.Id,
+		FirstName:    user.Information.FirstName,
+		LastName:     user.Information.LastName,
+		Email:        user.Email,
+		Password:     user.Password,
+		Avatar:       user.Avatar,
+		City:         user.Information.City,
+		PhoneNumber:  user.Information.PhoneNumber,
+		BaseCurrency: user.BaseCurrency,
+		CreatedAt:    user.CreatedAt,
+		UpdatedAt:    user.UpdatedAt,
 	}
 }
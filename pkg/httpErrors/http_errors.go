@@ -16,6 +16,45 @@ const (
 	MaxFileSizeMsg             = "File size exceeds 1MB"
 	NotAllowedImageHeaderMsg   = "Not allowed image header"
 	NotAllowedFileExtensionMsg = "Not allowed file extension"
+	InvalidSplitMsg            = "Invalid expense split"
+	UnknownParticipantMsg      = "Split participant or payee does not exist"
+	InvalidSettlementMsg       = "Cannot settle up with yourself"
+	InvalidExchangeRateMsg     = "Invalid exchange rate"
+	InvalidCurrencyAmountMsg   = "Amount has more decimals than its currency allows"
+	CategoryAlreadyExistsMsg   = "Category with given name already exists"
+	InvalidCategoryParentMsg   = "Invalid parent category"
+	InvalidCategoryMergeMsg    = "Cannot merge a category into itself"
+	UnknownCategoryMsg         = "Category does not exist"
+	CategoryKindMismatchMsg    = "Category is not of the expected kind (expense or income)"
+	TagAlreadyExistsMsg        = "Tag with given name already exists"
+	InvalidTagMsg              = "Tag names may only contain lowercase letters, digits, '.', '_' and '-'"
+	InvalidExpenseFilterMsg    = "Invalid expense filter"
+	BatchRolledBackMsg         = "Not applied, another operation of the atomic batch failed"
+	InvalidIdempotencyKeyMsg   = "Idempotency-Key must be between 1 and 255 characters"
+	IdempotencyKeyReusedMsg    = "Idempotency-Key was already used with a different request"
+	IdempotencyKeyInFlightMsg  = "A request with the same Idempotency-Key is still being processed"
+	InvalidCursorMsg           = "Invalid cursor, it does not match the sort of the query"
+	PreconditionFailedMsg      = "The resource was modified, fetch it again and retry with its current ETag"
+	IfMatchRequiredMsg         = "If-Match header with the ETag of the resource is required"
+	InvalidIfMatchMsg          = "If-Match must be the ETag of the resource"
+	InvalidSyncTokenMsg        = "Invalid sync token, pull again without since"
+	AccountAlreadyExistsMsg    = "Account with given name already exists"
+	UnknownAccountMsg          = "Account does not exist"
+	AccountCurrencyMismatchMsg = "Currency must be the currency of the account"
+	AccountInUseMsg            = "Account has transfers, delete them first"
+	InvalidTransferMsg         = "Invalid transfer, accounts must differ and to_amount is required between currencies without exchange rate"
+	InvalidCashFlowMsg         = "Invalid cash flow range, start_date must not be after end_date and cover at most 400 periods"
+	GoalAlreadyExistsMsg       = "Goal with given name already exists"
+	InvalidGoalDateMsg         = "Target date of the goal must not be in the past"
+	UnknownTransferMsg         = "Transfer does not exist"
+	TransferContributedMsg     = "Transfer is already a contribution to a goal"
+	InvalidContributionMsg     = "Invalid contribution, amount is required without transfer or without exchange rate to the goal currency"
+	RuleAlreadyExistsMsg       = "Rule with given name already exists"
+	InvalidRuleMsg             = "Invalid rule, it needs at least one condition and one action, a valid regular expression and valid tags"
+	UnknownRuleMsg             = "Rule does not exist"
+	InvalidRuleApplyMsg        = "Invalid range, start_date must not be after end_date"
+	NotDuplicateMsg            = "Expenses are not duplicates, they must be different expenses of the same amount and currency"
+	InvalidWebhookMsg          = "Invalid webhook, it needs a public http(s) url and at least one event type"
 )
 
 const (
@@ -69,6 +108,15 @@ func NewUnauthorizedError(causes interface{}) *Error {
 	}
 }
 
+// create a new Error Forbidden instance
+func NewForbiddenError(causes interface{}) *Error {
+	return &Error{
+		Status:  http.StatusForbidden,
+		Message: ForbiddenErrorMsg,
+		Causes:  causes,
+	}
+}
+
 // create a new Error Notfound instance
 func NewNotFoundError(causes interface{}) *Error {
 	return &Error{
@@ -0,0 +1,296 @@
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"github.com/pkg/errors"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimals of an Amount, every amount is stored in 1/100 of the unit of its currency.
+// a currency with fewer decimals (see Decimals) only holds whole multiples of its minor unit, the currencies
+// with 3 decimals in ISO 4217 (BHD, KWD, OMR...) are not supported and are kept to 2 decimals
+const Scale = 2
+
+// zeroDecimalCurrencies are the ISO 4217 currencies without minor unit. IDR has 2 decimals
+// in ISO 4217 but its sen is no longer in use, it is kept without decimals like JPY
+var zeroDecimalCurrencies = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "IDR": true, "ISK": true,
+	"JPY": true, "KMF": true, "KRW": true, "PYG": true, "RWF": true, "UGX": true,
+	"UYI": true, "VND": true, "VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+const minorPerUnit = 100
+
+var (
+	ErrInvalidAmount = errors.New("amount must be a decimal number with at most 2 decimals")
+	ErrOverflow      = errors.New("amount is out of range")
+)
+
+// Amount is an exact money amount in minor units (cents), it is stored as BIGINT
+// and encoded in JSON as a decimal string such as "1250000.50"
+type Amount int64
+
+// FromMinor builds an Amount from minor units
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// Parse reads a decimal string ("12", "12.5", "-0.25") without going through float64
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, fraction, hasDot := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || hasDot && fraction == "" || len(fraction) > Scale {
+		return 0, ErrInvalidAmount
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	for _, part := range []string{whole, fraction} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, ErrInvalidAmount
+			}
+		}
+	}
+	fraction += strings.Repeat("0", Scale-len(fraction))
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/minorPerUnit {
+		return 0, ErrOverflow
+	}
+	cents, _ := strconv.ParseInt(fraction, 10, 64)
+	minor := units*minorPerUnit + cents
+	if minor < 0 {
+		return 0, ErrOverflow
+	}
+	if negative {
+		minor = -minor
+	}
+	return Amount(minor), nil
+}
+
+// Minor returns the amount in minor units
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// String formats the amount with exactly 2 decimals
+func (a Amount) String() string {
+	minor := int64(a)
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+	}
+	abs := new(big.Int).Abs(big.NewInt(minor))
+	units, cents := new(big.Int).QuoRem(abs, big.NewInt(minorPerUnit), new(big.Int))
+	return fmt.Sprintf("%s%s.%02d", sign, units.String(), cents.Int64())
+}
+
+func (a Amount) Add(b Amount) Amount {
+	return a + b
+}
+
+func (a Amount) Sub(b Amount) Amount {
+	return a - b
+}
+
+func (a Amount) Neg() Amount {
+	return -a
+}
+
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+// Rat returns the exact amount in currency units
+func (a Amount) Rat() *big.Rat {
+	return big.NewRat(int64(a), minorPerUnit)
+}
+
+// Mul multiplies the amount by an exact factor, rounding half away from zero to the minor unit
+func (a Amount) Mul(factor *big.Rat) Amount {
+	return round(new(big.Rat).Mul(big.NewRat(int64(a), 1), factor))
+}
+
+// Convert converts the amount with an exact exchange rate, rounding half away from zero to the minor unit
+func (a Amount) Convert(rate Rate) Amount {
+	return a.Mul(rate.Rat())
+}
+
+// Decimals returns the number of decimals an amount of the currency can have
+func Decimals(currency string) int {
+	if zeroDecimalCurrencies[currency] {
+		return 0
+	}
+	return Scale
+}
+
+// MinorUnit returns the smallest amount of the currency, 1 (0.01) unless the currency has no decimals
+func MinorUnit(currency string) Amount {
+	if Decimals(currency) == 0 {
+		return minorPerUnit
+	}
+	return 1
+}
+
+// Round rounds the amount half away from zero to the minor unit of the currency
+func (a Amount) Round(currency string) Amount {
+	unit := MinorUnit(currency)
+	if unit == 1 {
+		return a
+	}
+	return round(big.NewRat(int64(a), int64(unit))) * unit
+}
+
+// ValidFor tells whether the amount has no more decimals than the currency allows
+func (a Amount) ValidFor(currency string) bool {
+	return a%MinorUnit(currency) == 0
+}
+
+// Sum adds up amounts
+func Sum(amounts ...Amount) Amount {
+	var total Amount
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total
+}
+
+// AllocateIn is Allocate in whole minor units of the currency,
+// an amount with more decimals than the currency allows is allocated in 0.01
+func (a Amount) AllocateIn(currency string, weights []*big.Rat) []Amount {
+	if !a.ValidFor(currency) {
+		return a.Allocate(weights)
+	}
+	unit := MinorUnit(currency)
+	parts := (a / unit).Allocate(weights)
+	for i := range parts {
+		parts[i] *= unit
+	}
+	return parts
+}
+
+// Allocate splits the amount proportionally to weights with the largest remainder
+// method, the parts always add up to the amount. weights must be positive
+func (a Amount) Allocate(weights []*big.Rat) []Amount {
+	if len(weights) == 0 {
+		return nil
+	}
+
+	sum := new(big.Rat)
+	for _, w := range weights {
+		sum.Add(sum, w)
+	}
+
+	type remainder struct {
+		index int
+		value *big.Rat
+	}
+	parts := make([]Amount, len(weights))
+	remainders := make([]remainder, len(weights))
+	total := big.NewRat(int64(a), 1)
+	var allocated Amount
+	for i, w := range weights {
+		exact := new(big.Rat).Quo(new(big.Rat).Mul(total, w), sum)
+		floor := new(big.Int).Div(exact.Num(), exact.Denom()) // euclidean, rounds towards -inf
+		parts[i] = Amount(floor.Int64())
+		remainders[i] = remainder{i, new(big.Rat).Sub(exact, new(big.Rat).SetInt(floor))}
+		allocated += parts[i]
+	}
+
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].value.Cmp(remainders[j].value) > 0
+	})
+	for i := 0; allocated < a; i++ {
+		parts[remainders[i%len(remainders)].index]++
+		allocated++
+	}
+	return parts
+}
+
+// MarshalJSON encodes the amount as a decimal string
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+// UnmarshalJSON accepts a decimal string or a plain JSON number, both are parsed exactly
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	raw := string(data)
+	if raw == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(raw); err == nil {
+		raw = unquoted
+	}
+	parsed, err := Parse(raw)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, amounts are stored as BIGINT minor units
+func (a Amount) Value() (driver.Value, error) {
+	return int64(a), nil
+}
+
+// Scan implements sql.Scanner
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*a = Amount(v)
+	case int32:
+		*a = Amount(v)
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	case nil:
+		*a = 0
+	default:
+		return errors.Errorf("money.Amount.Scan: unsupported type %T", src)
+	}
+	return nil
+}
+
+func (a *Amount) scanString(s string) error {
+	minor, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "money.Amount.Scan")
+	}
+	*a = Amount(minor)
+	return nil
+}
+
+// round rounds r to the nearest integer, halves away from zero
+func round(r *big.Rat) Amount {
+	num := new(big.Int).Abs(r.Num())
+	denom := r.Denom()
+	quo, rem := new(big.Int).QuoRem(num, denom, new(big.Int))
+	if new(big.Int).Mul(rem, big.NewInt(2)).Cmp(denom) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return Amount(quo.Int64())
+}
@@ -0,0 +1,202 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+func TestAmount_Parse(t *testing.T) {
+	cases := map[string]Amount{
+		"0":                     0,
+		"12":                    1200,
+		"12.5":                  1250,
+		"12.05":                 1205,
+		".5":                    50,
+		"-3.10":                 -310,
+		"+7.01":                 701,
+		"92233720368547758.07":  9223372036854775807,
+		"150000000000.00":       15000000000000,
+		"-92233720368547758.07": -9223372036854775807,
+	}
+	for input, expected := range cases {
+		parsed, err := Parse(input)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, parsed, input)
+	}
+
+	for _, input := range []string{"", "abc", "1.234", "1.", "1e3", "--1", "1.2.3", "92233720368547758.08"} {
+		_, err := Parse(input)
+		require.Error(t, err, input)
+	}
+}
+
+func TestAmount_JSON(t *testing.T) {
+	type payload struct {
+		Amount *Amount `json:"amount"`
+	}
+
+	var fromNumber, fromString payload
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": 1999.99}`), &fromNumber))
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": "1999.99"}`), &fromString))
+	require.Equal(t, Amount(199999), *fromNumber.Amount)
+	require.Equal(t, Amount(199999), *fromString.Amount)
+	require.Error(t, json.Unmarshal([]byte(`{"amount": 0.001}`), &fromNumber))
+
+	encoded, err := json.Marshal(payload{Amount: fromNumber.Amount})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount": "1999.99"}`, string(encoded))
+}
+
+func TestAmount_Convert(t *testing.T) {
+	require.Equal(t, Amount(9200), Amount(10000).Convert(MustParseRate("0.92")))
+	require.Equal(t, Amount(160000000), Amount(10000).Convert(MustParseRate("16000")))
+	// 0.005 is rounded half away from zero
+	require.Equal(t, Amount(1), Amount(1).Convert(MustParseRate("0.5")))
+	require.Equal(t, Amount(-1), Amount(-1).Convert(MustParseRate("0.5")))
+	// a rate with more digits than a float64 holds is kept exactly
+	require.Equal(t, Amount(1234567890123456789), Amount(10000000000).Convert(MustParseRate("123456789.0123456789")))
+}
+
+func TestAmount_Round(t *testing.T) {
+	require.Equal(t, Amount(1250), Amount(1250).Round("USD"))
+	require.Equal(t, Amount(1300), Amount(1250).Round("JPY"))
+	require.Equal(t, Amount(1200), Amount(1249).Round("IDR"))
+	require.Equal(t, Amount(-1300), Amount(-1250).Round("IDR"))
+
+	require.True(t, Amount(1250).ValidFor("USD"))
+	require.False(t, Amount(1250).ValidFor("IDR"))
+	require.True(t, Amount(1200).ValidFor("IDR"))
+}
+
+func TestAmount_AllocateIn(t *testing.T) {
+	weights := []*big.Rat{big.NewRat(1, 1), big.NewRat(1, 1), big.NewRat(1, 1)}
+	require.Equal(t, []Amount{3400, 3300, 3300}, Amount(10000).AllocateIn("JPY", weights))
+	require.Equal(t, []Amount{3334, 3333, 3333}, Amount(10000).AllocateIn("USD", weights))
+	// an amount the currency cannot hold is still allocated to the cent
+	require.Equal(t, []Amount{3334, 3334, 3333}, Amount(10001).AllocateIn("JPY", weights))
+}
+
+func TestRate_Parse(t *testing.T) {
+	for input, expected := range map[string]string{
+		"16000":        "16000",
+		"0.92":         "0.92",
+		"17500.50":     "17500.5",
+		"0.0000000001": "0.0000000001",
+	} {
+		rate, err := ParseRate(input)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, rate.String(), input)
+	}
+
+	for _, input := range []string{"", "0", "-1", ".5", "1.", "1e3", "1/3", "0.00000000001"} {
+		_, err := ParseRate(input)
+		require.ErrorIs(t, err, ErrInvalidRate, input)
+	}
+}
+
+func TestRate_JSON(t *testing.T) {
+	type payload struct {
+		Rate Rate `json:"rate"`
+	}
+
+	var fromNumber, fromString payload
+	require.NoError(t, json.Unmarshal([]byte(`{"rate": 0.1}`), &fromNumber))
+	require.NoError(t, json.Unmarshal([]byte(`{"rate": "0.1"}`), &fromString))
+	require.Equal(t, 0, fromNumber.Rate.Rat().Cmp(big.NewRat(1, 10)))
+	require.Equal(t, 0, fromString.Rate.Rat().Cmp(big.NewRat(1, 10)))
+
+	encoded, err := json.Marshal(fromNumber)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"rate": "0.1"}`, string(encoded))
+}
+
+// property: formatting then parsing gives back the same amount
+func TestAmount_Property_StringRoundTrip(t *testing.T) {
+	property := func(minor int64) bool {
+		parsed, err := Parse(Amount(minor).String())
+		return err == nil && parsed == Amount(minor)
+	}
+	require.NoError(t, quick.Check(property, nil))
+}
+
+// property: JSON encoding round trips
+func TestAmount_Property_JSONRoundTrip(t *testing.T) {
+	property := func(minor int64) bool {
+		encoded, err := json.Marshal(Amount(minor))
+		if err != nil {
+			return false
+		}
+		var decoded Amount
+		return json.Unmarshal(encoded, &decoded) == nil && decoded == Amount(minor)
+	}
+	require.NoError(t, quick.Check(property, nil))
+}
+
+// property: addition is associative, commutative and subtraction is its inverse
+func TestAmount_Property_Arithmetic(t *testing.T) {
+	property := func(a, b, c int32) bool {
+		x, y, z := Amount(a), Amount(b), Amount(c)
+		return x.Add(y) == y.Add(x) &&
+			x.Add(y).Add(z) == x.Add(y.Add(z)) &&
+			x.Add(y).Sub(y) == x &&
+			Sum(x, y, z) == x.Add(y).Add(z) &&
+			x.Add(x.Neg()).IsZero()
+	}
+	require.NoError(t, quick.Check(property, nil))
+}
+
+// property: allocation never loses or creates a cent and every part is within
+// one minor unit of its exact proportional share
+func TestAmount_Property_Allocate(t *testing.T) {
+	config := &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(42))}
+	property := func(total int64, rawWeights []uint16) bool {
+		total %= 1_000_000_000_000
+		var weights []*big.Rat
+		for _, w := range rawWeights {
+			weights = append(weights, big.NewRat(int64(w)+1, 1))
+		}
+		if len(weights) == 0 {
+			return Amount(total).Allocate(weights) == nil
+		}
+
+		parts := Amount(total).Allocate(weights)
+		if Sum(parts...) != Amount(total) {
+			return false
+		}
+
+		weightSum := new(big.Rat)
+		for _, w := range weights {
+			weightSum.Add(weightSum, w)
+		}
+		for i, part := range parts {
+			exact := new(big.Rat).Quo(new(big.Rat).Mul(big.NewRat(total, 1), weights[i]), weightSum)
+			diff := new(big.Rat).Sub(big.NewRat(int64(part), 1), exact)
+			if diff.Abs(diff).Cmp(big.NewRat(1, 1)) >= 0 {
+				return false
+			}
+		}
+		return true
+	}
+	require.NoError(t, quick.Check(property, config))
+}
+
+// property: converting with a rate of 1 is the identity and conversion is monotonic
+func TestAmount_Property_Convert(t *testing.T) {
+	property := func(a, b int32, rate uint16) bool {
+		r := MustParseRate(fmt.Sprintf("%d.%02d", (int(rate)+1)/100, (int(rate)+1)%100))
+		x, y := Amount(a), Amount(b)
+		if x.Convert(MustParseRate("1")) != x {
+			return false
+		}
+		if x <= y {
+			return x.Convert(r) <= y.Convert(r)
+		}
+		return x.Convert(r) >= y.Convert(r)
+	}
+	require.NoError(t, quick.Check(property, nil))
+}
@@ -0,0 +1,128 @@
+package money
+
+import (
+	"database/sql/driver"
+	"github.com/pkg/errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the number of decimals kept for an exchange rate, the scale of its NUMERIC column
+const RateScale = 10
+
+var ErrInvalidRate = errors.New("rate must be a positive decimal number with at most 10 decimals")
+
+// Rate is an exact exchange rate read from its decimal form, 0.92 is exactly 92/100.
+// it is stored as NUMERIC and encoded in JSON as a decimal string such as "16250.5"
+type Rate struct {
+	value *big.Rat
+}
+
+// ParseRate reads a positive decimal string ("16000", "0.92") without going through float64
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	whole, fraction, hasDot := strings.Cut(s, ".")
+	if whole == "" || hasDot && fraction == "" || len(fraction) > RateScale {
+		return Rate{}, ErrInvalidRate
+	}
+	for _, part := range []string{whole, fraction} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return Rate{}, ErrInvalidRate
+			}
+		}
+	}
+
+	value, ok := new(big.Rat).SetString(s)
+	if !ok || value.Sign() <= 0 {
+		return Rate{}, ErrInvalidRate
+	}
+	return Rate{value: value}, nil
+}
+
+// MustParseRate is ParseRate for constant rates, it panics on an invalid rate
+func MustParseRate(s string) Rate {
+	rate, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return rate
+}
+
+// Rat returns the exact rate, 0 for the zero Rate
+func (r Rate) Rat() *big.Rat {
+	if r.value == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(r.value)
+}
+
+func (r Rate) IsZero() bool {
+	return r.value == nil || r.value.Sign() == 0
+}
+
+// String formats the rate with its significant decimals only
+func (r Rate) String() string {
+	if r.value == nil {
+		return "0"
+	}
+	s := r.value.FloatString(RateScale)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// MarshalJSON encodes the rate as a decimal string
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(r.String())), nil
+}
+
+// UnmarshalJSON accepts a decimal string or a plain JSON number, both are parsed exactly
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	raw := string(data)
+	if raw == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(raw); err == nil {
+		raw = unquoted
+	}
+	parsed, err := ParseRate(raw)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, rates are stored as NUMERIC
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan implements sql.Scanner
+func (r *Rate) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return r.scanString(string(v))
+	case string:
+		return r.scanString(v)
+	case int64:
+		return r.scanString(strconv.FormatInt(v, 10))
+	case nil:
+		*r = Rate{}
+	default:
+		return errors.Errorf("money.Rate.Scan: unsupported type %T", src)
+	}
+	return nil
+}
+
+func (r *Rate) scanString(s string) error {
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return errors.Wrap(err, "money.Rate.Scan")
+	}
+	*r = parsed
+	return nil
+}
@@ -0,0 +1,16 @@
+package utils
+
+import "time"
+
+// Backoff is the wait before the next attempt after the given number of failed attempts,
+// base doubled at every attempt and capped at max, also when the doubling overflows
+func Backoff(base time.Duration, attempts int, max time.Duration) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	wait := base << (attempts - 1)
+	if wait <= 0 || wait > max {
+		return max
+	}
+	return wait
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
 	"github.com/mrizkisaputra/expenses-api/pkg/contextutils"
 	"github.com/mrizkisaputra/expenses-api/pkg/httpErrors"
 	. "github.com/mrizkisaputra/expenses-api/pkg/validator"
@@ -99,6 +100,15 @@ func ReadImageRequest(ctx *gin.Context, fieldName string) (*multipart.FileHeader
 	return fh, nil
 }
 
+// ParseOptionalId is a function for parse an already validated optional uuid, nil when empty
+func ParseOptionalId(id string) *uuid.UUID {
+	if id == "" {
+		return nil
+	}
+	parsed := uuid.MustParse(id)
+	return &parsed
+}
+
 // LogErrorResponse is a function for write log response error
 func LogErrorResponse(ctx *gin.Context, logger *logrus.Logger, err error) {
 	logger.WithError(err).WithFields(logrus.Fields{
@@ -0,0 +1,10 @@
+package utils
+
+// Truncate keeps a text within the given number of characters, a cut text ends with "..."
+func Truncate(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return string(runes[:length-3]) + "..."
+}